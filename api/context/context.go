@@ -6,6 +6,9 @@ import (
 	"sync"
 )
 
+// ConstKeyRequestID is a context key of identifier of API request context was made for
+const ConstKeyRequestID = "request_id"
+
 // proxies holds set of pass-through functions
 var proxies = [...]func(target func()){
 	func(target func()) { target() }, // 00
@@ -434,3 +437,9 @@ func GetContext() map[string]interface{} {
 func MakeContext(target func()) {
 	RunInContext(target, nil)
 }
+
+// GetRequestID returns identifier of API request current call stack is made for or blank string otherwise
+func GetRequestID() string {
+	requestID, _ := GetContextValue(ConstKeyRequestID).(string)
+	return requestID
+}
@@ -9,9 +9,15 @@ const (
 	ConstRESTActionParameter = "action"
 
 	ConstSessionKeyAdminRights = "adminRights"   // session key used to flag that user have admin rights
+	ConstSessionKeyPermissions = "permissions"   // session key for list of permissions granted to user
+	ConstSessionKeyAPIClient   = "apiClient"     // session key for id of API client bearer token was issued to
 	ConstSessionCookieName     = "OTTEMOSESSION" // cookie name which should contain sessionID
 	ConstSessionKeyTimeZone    = "timeZone"      // session key for setting time zone
 
+	ConstContextKeyApplicationContext = "application_context" // call context key of API request application context
+
+	ConstPermissionAll = "*" // permission which covers any other one, admin session have it
+
 	ConstGETAuthParamName            = "auth"
 	ConstConfigPathStoreRootLogin    = "general.store.root_login"
 	ConstConfigPathStoreRootPassword = "general.store.root_password"
@@ -42,6 +48,40 @@ type StructRestRedirect struct {
 	DoRedirect bool
 }
 
+// StructRouteMeta is an optional API route description used to generate OpenAPI document
+//   - Request and Response are JSON schemas of request content and response "result" value
+//   - Permission is informational, handler should still be wrapped with PermissionHandler()
+type StructRouteMeta struct {
+	Summary     string
+	Description string
+	Tags        []string
+	Parameters  []StructRouteParameter
+	Request     map[string]interface{}
+	Response    map[string]interface{}
+	Permission  string
+	Deprecated  bool
+}
+
+// StructRouteParameter is an API route parameter description, route path parameters are described automatically
+type StructRouteParameter struct {
+	Name        string
+	In          string // "path", "query" or "header", "query" if blank
+	Type        string // JSON schema type, "string" if blank
+	Required    bool
+	Description string
+}
+
+// FuncResponseEncoder converts API response to given media type, response is a map with "result", "error" and
+// "redirect" keys
+type FuncResponseEncoder func(response map[string]interface{}) ([]byte, error)
+
+// FuncBearerTokenValidator validates "Authorization: Bearer" request token and returns values for request session
+type FuncBearerTokenValidator func(token string) (map[string]interface{}, error)
+
+// FuncPermissionsResolver returns current permissions of session user, false is returned if session has no user
+// resolver knows about, so permissions stored in session are used
+type FuncPermissionsResolver func(session InterfaceSession) ([]string, bool)
+
 // FuncAPIHandler is an API handler callback function
 type FuncAPIHandler func(context InterfaceApplicationContext) (interface{}, error)
 
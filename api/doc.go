@@ -6,5 +6,20 @@ application with new API endpoints as well as to interact with other components
 
 As application components (actor packages) should not interact between them directly them should use this package to make
 indirect calls between the. Interaction with a session manager also should happen through this package.
+
+Access to API handlers is controlled with permissions, names in a form "<resource>.<action>" like "orders.read" or
+"config.write". Handler registered with PermissionHandler() is allowed only for sessions granted with given permission,
+permissions of session user are taken from resolver registered with RegisterPermissionsResolver(), other sessions use
+permissions stored under [ConstSessionKeyPermissions] key. Admin session has all the permissions ("*"), "orders.*"
+form grants all "orders." permissions. IsAdminHandler() is left for handlers allowed to admin only.
+
+Machine clients could use "Authorization: Bearer <token>" request header instead of session cookie. StartSession() makes
+non persistent session for such requests with values returned by registered bearer token validator (see
+RegisterBearerTokenValidator()), typically [ConstSessionKeyPermissions] with token scopes. Requests with invalid token are
+rejected with 401 status.
+
+    Example:
+    --------
+        service.GET("orders", api.PermissionHandler(ConstPermissionRead, APIListOrders))
 */
 package api
@@ -0,0 +1,50 @@
+package api
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/ottemo/commerce/env"
+)
+
+// responseEncoders holds API response encoders by media type
+var (
+	responseEncoders      = make(map[string]FuncResponseEncoder)
+	responseEncodersMutex sync.RWMutex
+)
+
+// RegisterResponseEncoder registers encoder for given media type, REST service picks one by "Accept" request header
+//   - will cause error if there are couple candidates for that media type
+func RegisterResponseEncoder(mediaType string, encoder FuncResponseEncoder) error {
+	responseEncodersMutex.Lock()
+	defer responseEncodersMutex.Unlock()
+
+	if _, present := responseEncoders[mediaType]; present {
+		return env.ErrorNew(ConstErrorModule, ConstErrorLevel, "1dd53629-bb74-4de0-a557-c42f38a99fba", "response encoder for '"+mediaType+"' was already registered")
+	}
+	responseEncoders[mediaType] = encoder
+
+	return nil
+}
+
+// GetResponseEncoder returns encoder registered for media type or nil
+func GetResponseEncoder(mediaType string) FuncResponseEncoder {
+	responseEncodersMutex.RLock()
+	defer responseEncodersMutex.RUnlock()
+
+	return responseEncoders[mediaType]
+}
+
+// GetResponseMediaTypes returns sorted list of media types response encoders were registered for
+func GetResponseMediaTypes() []string {
+	responseEncodersMutex.RLock()
+	defer responseEncodersMutex.RUnlock()
+
+	result := make([]string, 0, len(responseEncoders))
+	for mediaType := range responseEncoders {
+		result = append(result, mediaType)
+	}
+	sort.Strings(result)
+
+	return result
+}
@@ -7,6 +7,7 @@ import (
 	"strings"
 	"time"
 
+	callcontext "github.com/ottemo/commerce/api/context"
 	"github.com/ottemo/commerce/env"
 	"github.com/ottemo/commerce/utils"
 )
@@ -15,8 +16,13 @@ import (
 // a secure session cookie in HTTPS, please set the environment variable
 // OTTEMOCOOKIE.  It accepts 1, t, T, TRUE, true, True, 0, f, F, FALSE, false,
 // False. Any other value returns an error.
+//   - request with "Authorization: Bearer" header gets non persistent session made by bearer token validator
 func StartSession(context InterfaceApplicationContext) (InterfaceSession, error) {
 
+	if token := GetBearerToken(context); token != "" {
+		return startTokenSession(token)
+	}
+
 	request := context.GetRequest()
 	// use secure cookies by default
 	var flagSecure = true
@@ -93,6 +99,10 @@ func NewSession() (InterfaceSession, error) {
 
 // GetSessionByID returns session instance by id or nil
 func GetSessionByID(sessionID string, create bool) (InterfaceSession, error) {
+	if currentSessionService == nil {
+		return nil, env.ErrorNew(ConstErrorModule, ConstErrorLevel, "63f48b32-c634-45fa-b80a-71908c0148ef", "session service is not registered")
+	}
+
 	sessionInstance, err := currentSessionService.Get(sessionID, create)
 
 	// "(*session.DefaultSession)(nil)" is not "nil", and we want to have exact nil
@@ -106,10 +116,16 @@ func GetSessionByID(sessionID string, create bool) (InterfaceSession, error) {
 // ValidateAdminRights returns nil if session contains admin rights
 func ValidateAdminRights(context InterfaceApplicationContext) error {
 
-	if IsAdminSession(context) {
+	if IsAdminSession(context) || isRootAuthRequest(context) {
 		return nil
 	}
 
+	return env.ErrorNew(ConstErrorModule, ConstErrorLevel, "2f3438ba-7fb7-4811-b8a5-7acf36910d3d", "no admin rights")
+}
+
+// isRootAuthRequest returns true if request contains root login and password in "auth" argument
+func isRootAuthRequest(context InterfaceApplicationContext) bool {
+
 	// it is un-secure as request can be intercepted by malefactor, so use it only if no other way to do auth
 	// (we are using it for "gulp build" local tool, so all data within one host)
 	if value := context.GetRequestArgument(ConstGETAuthParamName); value != "" {
@@ -121,15 +137,16 @@ func ValidateAdminRights(context InterfaceApplicationContext) error {
 			rootPassword := utils.InterfaceToString(env.ConfigGetValue(ConstConfigPathStoreRootPassword))
 
 			if login == rootLogin && password == rootPassword {
-				return nil
+				return true
 			}
 		}
 	}
 
-	return env.ErrorNew(ConstErrorModule, ConstErrorLevel, "2f3438ba-7fb7-4811-b8a5-7acf36910d3d", "no admin rights")
+	return false
 }
 
 // IsAdminHandler returns middleware API Handler that checks admin rights
+//   - use PermissionHandler() for handlers which could be allowed to non admin sessions
 func IsAdminHandler(next FuncAPIHandler) FuncAPIHandler {
 	return func(context InterfaceApplicationContext) (interface{}, error) {
 		isAdminErr := ValidateAdminRights(context)
@@ -202,3 +219,12 @@ func GetArgumentOrContentValue(context InterfaceApplicationContext, name string)
 
 	return nil
 }
+
+// GetCurrentApplicationContext returns application context of API request current call stack is made for
+//   - returns nil if function was called not within API handler (i.e. by scheduler or event bus worker)
+func GetCurrentApplicationContext() InterfaceApplicationContext {
+	if applicationContext, ok := callcontext.GetContextValue(ConstContextKeyApplicationContext).(InterfaceApplicationContext); ok {
+		return applicationContext
+	}
+	return nil
+}
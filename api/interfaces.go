@@ -41,14 +41,24 @@ type InterfaceRestService interface {
 	GetName() string
 
 	Run() error
-	GET(resource string, handler FuncAPIHandler)
-	PUT(resource string, handler FuncAPIHandler)
-	POST(resource string, handler FuncAPIHandler)
-	DELETE(resource string, handler FuncAPIHandler)
+	GET(resource string, handler FuncAPIHandler, meta ...*StructRouteMeta)
+	PUT(resource string, handler FuncAPIHandler, meta ...*StructRouteMeta)
+	POST(resource string, handler FuncAPIHandler, meta ...*StructRouteMeta)
+	DELETE(resource string, handler FuncAPIHandler, meta ...*StructRouteMeta)
+
+	Version(version string) InterfaceRouteGroup
 
 	http.Handler
 }
 
+// InterfaceRouteGroup is an interface to register API routes of given API version
+type InterfaceRouteGroup interface {
+	GET(resource string, handler FuncAPIHandler, meta ...*StructRouteMeta)
+	PUT(resource string, handler FuncAPIHandler, meta ...*StructRouteMeta)
+	POST(resource string, handler FuncAPIHandler, meta ...*StructRouteMeta)
+	DELETE(resource string, handler FuncAPIHandler, meta ...*StructRouteMeta)
+}
+
 // InterfaceApplicationContextSupport is an interface to assign/get application context to object
 type InterfaceApplicationContextSupport interface {
 	GetApplicationContext() InterfaceApplicationContext
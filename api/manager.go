@@ -6,9 +6,11 @@ import (
 
 // Package global variables
 var (
-	currentRestService          InterfaceRestService    // currently registered RESTFul service in system
-	currentSessionService       InterfaceSessionService // currently registered session service in system
-	callbacksOnRestServiceStart = []func() error{}      // set of callback function on RESTFul service start
+	currentRestService          InterfaceRestService     // currently registered RESTFul service in system
+	currentSessionService       InterfaceSessionService  // currently registered session service in system
+	callbacksOnRestServiceStart = []func() error{}       // set of callback function on RESTFul service start
+	bearerTokenValidator        FuncBearerTokenValidator // currently registered bearer token validator in system
+	permissionsResolver         FuncPermissionsResolver  // currently registered session permissions resolver in system
 )
 
 // RegisterOnRestServiceStart registers new callback on RESTFul service start
@@ -48,6 +50,27 @@ func RegisterSessionService(newService InterfaceSessionService) error {
 	return nil
 }
 
+// RegisterBearerTokenValidator registers validator of bearer tokens machine clients are using instead of sessions
+//   - will cause error if there are couple candidates for that role
+func RegisterBearerTokenValidator(validator FuncBearerTokenValidator) error {
+	if bearerTokenValidator == nil {
+		bearerTokenValidator = validator
+	} else {
+		return env.ErrorNew(ConstErrorModule, ConstErrorLevel, "b354cce3-40d2-4c71-bf10-50d2fff845ee", "bearer token validator was already registered")
+	}
+	return nil
+}
+
+// RegisterPermissionsResolver registers resolver of session user permissions
+func RegisterPermissionsResolver(resolver FuncPermissionsResolver) error {
+	if permissionsResolver == nil {
+		permissionsResolver = resolver
+	} else {
+		return env.ErrorNew(ConstErrorModule, ConstErrorLevel, "a5d57a88-1b34-4d35-9e80-87025b04af49", "permissions resolver was already registered")
+	}
+	return nil
+}
+
 // GetRestService returns currently using RESTFul service implementation
 func GetRestService() InterfaceRestService {
 	return currentRestService
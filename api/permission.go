@@ -0,0 +1,97 @@
+package api
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ottemo/commerce/env"
+	"github.com/ottemo/commerce/utils"
+)
+
+// permissions holds names of permissions API handlers were registered with
+var (
+	permissions      = make(map[string]bool)
+	permissionsMutex sync.RWMutex
+)
+
+// RegisterPermission adds permission to a list of known ones, PermissionHandler() does it automatically
+func RegisterPermission(permission string) {
+	permissionsMutex.Lock()
+	defer permissionsMutex.Unlock()
+
+	permissions[permission] = true
+}
+
+// GetPermissions returns sorted list of known permissions
+func GetPermissions() []string {
+	permissionsMutex.RLock()
+	defer permissionsMutex.RUnlock()
+
+	result := make([]string, 0, len(permissions))
+	for permission := range permissions {
+		result = append(result, permission)
+	}
+	sort.Strings(result)
+
+	return result
+}
+
+// MatchPermission checks if granted permission covers requested one
+//   - "*" covers any permission, "orders.*" covers any "orders." permission
+func MatchPermission(granted string, permission string) bool {
+	if granted == ConstPermissionAll || granted == permission {
+		return true
+	}
+	if strings.HasSuffix(granted, ".*") {
+		return strings.HasPrefix(permission, strings.TrimSuffix(granted, "*"))
+	}
+	return false
+}
+
+// GetSessionPermissions returns permissions granted to session, admin session have all of them
+//   - permissions of session user are taken from registered resolver, so their changes apply to live sessions
+func GetSessionPermissions(context InterfaceApplicationContext) []string {
+	if IsAdminSession(context) {
+		return []string{ConstPermissionAll}
+	}
+	if permissionsResolver != nil {
+		if result, ok := permissionsResolver(context.GetSession()); ok {
+			return result
+		}
+	}
+	return utils.InterfaceToStringArray(context.GetSession().Get(ConstSessionKeyPermissions))
+}
+
+// HasPermission returns true if session was granted with given permission
+func HasPermission(context InterfaceApplicationContext, permission string) bool {
+	for _, granted := range GetSessionPermissions(context) {
+		if MatchPermission(granted, permission) {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidatePermission returns nil if session was granted with given permission
+func ValidatePermission(context InterfaceApplicationContext, permission string) error {
+	if HasPermission(context, permission) || isRootAuthRequest(context) {
+		return nil
+	}
+
+	return env.ErrorNew(ConstErrorModule, ConstErrorLevel, "2ea54160-f5b6-464c-9436-12afb01e46db", "no '"+permission+"' permission")
+}
+
+// PermissionHandler returns middleware API Handler that checks session was granted with given permission
+func PermissionHandler(permission string, next FuncAPIHandler) FuncAPIHandler {
+	RegisterPermission(permission)
+
+	return func(context InterfaceApplicationContext) (interface{}, error) {
+		if err := ValidatePermission(context, permission); err != nil {
+			context.SetResponseStatusForbidden()
+			return nil, err
+		}
+
+		return next(context)
+	}
+}
@@ -0,0 +1,44 @@
+package api
+
+import (
+	"testing"
+)
+
+// TestMatchPermission checks granted permission matching rules
+func TestMatchPermission(t *testing.T) {
+	cases := []struct {
+		granted    string
+		permission string
+		expected   bool
+	}{
+		{"*", "config.write", true},
+		{"orders.read", "orders.read", true},
+		{"orders.read", "orders.refund", false},
+		{"orders.*", "orders.refund", true},
+		{"orders.*", "ordersx.read", false},
+		{"orders", "orders.read", false},
+		{"", "orders.read", false},
+	}
+
+	for _, item := range cases {
+		if result := MatchPermission(item.granted, item.permission); result != item.expected {
+			t.Errorf("'%s' matching '%s' is %v, expected %v", item.granted, item.permission, result, item.expected)
+		}
+	}
+}
+
+// TestPermissionHandlerRegistration checks PermissionHandler makes permission known
+func TestPermissionHandlerRegistration(t *testing.T) {
+	PermissionHandler("test.write", nil)
+	RegisterPermission("test.read")
+
+	found := 0
+	for _, permission := range GetPermissions() {
+		if permission == "test.read" || permission == "test.write" {
+			found++
+		}
+	}
+	if found != 2 {
+		t.Errorf("permissions are not registered: %v", GetPermissions())
+	}
+}
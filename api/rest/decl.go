@@ -3,10 +3,13 @@ package rest
 import (
 	"io"
 	"net/http"
+	"sync"
+	"time"
 
 	"github.com/julienschmidt/httprouter"
 	"github.com/ottemo/commerce/api"
 	"github.com/ottemo/commerce/env"
+	"github.com/ottemo/commerce/metrics"
 )
 
 // Package global constants
@@ -20,14 +23,109 @@ const (
 	ConstConfigPathAPILog        = "api.log"
 	ConstConfigPathAPILogEnable  = "api.log.enable"
 	ConstConfigPathAPILogExclude = "api.log.exclude"
+
+	ConstConfigPathAPIRateLimit        = "api.rate_limit"
+	ConstConfigPathAPIRateLimitEnable  = "api.rate_limit.enable"
+	ConstConfigPathAPIRateLimitRules   = "api.rate_limit.rules"
+	ConstConfigPathAPIRateLimitLockout = "api.rate_limit.lockout"
+
+	ConstMetricsPath = "/metrics" // path metrics are exposed on in Prometheus text format
+
+	ConstDefaultAPIVersion = "v1"            // version served by unversioned routes
+	ConstHeaderAPIVersion  = "X-API-Version" // header API version request was served by is returned in
+	ConstDefaultMediaType  = "application/json"
+
+	ConstOpenAPIPath    = "/openapi.json" // path generated OpenAPI document is served on
+	ConstOpenAPIVersion = "3.0.3"
+	ConstOpenAPITitle   = "Ottemo Commerce API"
+
+	ConstHeaderRequestID    = "X-Request-ID" // header request identifier is taken from and returned in
+	ConstRequestIDMaxLength = 128            // longer request identifiers given by client are replaced with generated ones
+
+	ConstRateLimitScopeIP      = "ip"      // rate limit rule counts requests of client address
+	ConstRateLimitScopeSession = "session" // rate limit rule counts requests of session (client address if there is no existing session)
+	ConstRateLimitCleanup      = time.Minute
+
+	// ConstDefaultRateLimitRules are request rate rules in "<method> <route> <count>/<period> [ip|session]" form
+	ConstDefaultRateLimitRules = `* * 1200/1m ip
+POST /visit/login 20/1m ip
+GET /visitors/forgot-password/:email 5/1m ip
+POST /cart/coupons 20/1m session
+GET /giftcards/:giftcode 20/1m ip
+POST /cart/giftcards/:giftcode 20/1m session`
+
+	// ConstDefaultLockoutRules are brute-force lockout rules in "<method> <route> <failures>/<period> <lockout> [ip|session]" form
+	ConstDefaultLockoutRules = `POST /visit/login 5/15m 15m ip
+GET /visitors/forgot-password/:email 5/1h 1h ip
+POST /cart/coupons 10/15m 15m ip
+GET /giftcards/:giftcode 10/15m 15m ip
+POST /cart/giftcards/:giftcode 10/15m 15m ip`
+)
+
+// Package global variables
+var (
+	requestsTotal   = metrics.NewCounter("http_requests_total", "Number of handled API requests.", "method", "route", "status")
+	requestDuration = metrics.NewHistogram("http_request_duration_seconds", "API request handling duration in seconds.", nil, "method", "route")
+	requestsLimited = metrics.NewCounter("http_requests_limited_total", "Number of API requests rejected by rate limiter.", "method", "route")
+
+	rateRulesCache   = make(map[string][]*StructRateRule) // parsed rules by config value
+	rateBuckets      = make(map[string]*rateBucket)       // token buckets by rule and client key
+	lockoutCounters  = make(map[string]*lockoutCounter)   // failed attempts counters by rule and client key
+	rateLastCleanup  time.Time
+	rateLimiterMutex sync.Mutex
 )
 
+// StructRateRule is a rate limit or lockout rule for routes matching method and route pattern ("*" matches any)
+//   - rate rule allows Count requests per Period, lockout rule blocks client for Lockout after Count failures within Period
+type StructRateRule struct {
+	Method  string
+	Route   string
+	Count   int
+	Period  time.Duration
+	Lockout time.Duration
+	Scope   string
+}
+
+// rateBucket is a token bucket refilled with Count tokens per Period of rule
+type rateBucket struct {
+	tokens  float64
+	updated time.Time
+	period  time.Duration
+}
+
+// lockoutCounter counts failed requests within rule period window
+type lockoutCounter struct {
+	failures    int
+	windowStart time.Time
+	lockedUntil time.Time
+	period      time.Duration
+}
+
+// requestAttempt is attached to rate limited request to flag its failure
+type requestAttempt struct {
+	failed bool
+}
+
 // DefaultRestService is a default implementer of InterfaceRestService
 // declared in "github.com/ottemo/commerce/api" package
 type DefaultRestService struct {
 	ListenOn string
 	Router   *httprouter.Router
 	Handlers []string
+	Routes   []*StructRoute
+}
+
+// StructRoute is a registered API route with its description
+type StructRoute struct {
+	Method string
+	Path   string
+	Meta   *api.StructRouteMeta
+}
+
+// statusRecorder is a response writer wrapper which remembers response status
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
 }
 
 // DefaultRestApplicationContext is a structure to hold API request related information
@@ -42,4 +140,12 @@ type DefaultRestApplicationContext struct {
 	Session       api.InterfaceSession
 	ContextValues map[string]interface{}
 	Result        interface{}
+
+	responseContentTypeSet bool // handler have chosen response content type itself
+}
+
+// routeGroup is an InterfaceRouteGroup implementer registering routes of API version
+type routeGroup struct {
+	service *DefaultRestService
+	version string
 }
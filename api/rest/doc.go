@@ -24,5 +24,37 @@ is a HTTP Request content. Notice that URL arguments are both - REST resource re
 
 Session specification addressed to "OTTEMOSESSION=[sessionID]" COOKIE value. Each request with unspecified session will
 be supplied with new one session. SessionID will be returned in mentioned COOKIE value.
+
+Each request is identified with "X-Request-ID" header value given by client (or generated one if it is absent or
+malformed), identifier is returned in the same response header. Logs and errors made while request handling are tagged
+with it, refer "context.GetRequestID()".
+
+Collected metrics are exposed on "/metrics" endpoint in Prometheus text format. Endpoint requires "Authorization: Bearer
+[token]" header with "metrics.token" ini value, it is disabled until the token is set.
+
+Routes registered directly are "v1" API version ones, they are served on both "/[resource]" and "/v1/[resource]" paths.
+Routes of newer versions are registered with Version() route group, "/v2/[resource]" request is served by "v2" route or by
+route of nearest older version if resource was not changed in "v2". Version request was served by is given in
+"X-API-Version" header.
+
+	Example:
+	  service.Version("v2").GET("product/:productID", APIGetProductV2)
+
+Response media type is negotiated by "Accept" request header among registered response encoders (see
+api.RegisterResponseEncoder()), "application/json", "application/xml" (also as "text/xml") and "text/csv" are supported
+by default. JSON is used if nothing acceptable was requested. Handler which sets response content type itself makes
+output on its own, raw []byte result is written as is.
+
+OpenAPI 3 document generated from registered routes is served on "/openapi.json". Route could be registered with optional
+description (summary, parameters, request and response schemas, required permission), routes without it are listed with
+path parameters only.
+
+	Example:
+	  service.GET("product/:productID", APIGetProduct, &api.StructRouteMeta{Summary: "Get product"})
+
+Requests are limited with token buckets per client address or session and route, rules are set in "api.rate_limit" config
+group. Lockout rules block client for a while after a number of failed requests (handler error or 4xx/5xx status) to
+protect login and code redemption endpoints from brute-force. Rejected requests get 429 status with "Retry-After" header.
+Set "rest.realIPHeader" ini value (i.e. "X-Forwarded-For") if service works behind reverse proxy.
 */
 package rest
@@ -0,0 +1,291 @@
+package rest
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"mime"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/ottemo/commerce/api"
+	"github.com/ottemo/commerce/env"
+)
+
+// registerEncoders registers default JSON, XML and CSV response encoders, "text/xml" is an alias of "application/xml"
+func registerEncoders() {
+	encoders := map[string]api.FuncResponseEncoder{
+		ConstDefaultMediaType: encodeJSON,
+		"application/xml":     encodeXML,
+		"text/xml":            encodeXML,
+		"text/csv":            encodeCSV,
+	}
+
+	for mediaType, encoder := range encoders {
+		if err := api.RegisterResponseEncoder(mediaType, encoder); err != nil {
+			_ = env.ErrorDispatch(err)
+		}
+	}
+}
+
+// getResponseEncoder returns encoder for response of given content type, nil means handler result is written as is
+//   - handler which set content type other than ConstDefaultMediaType makes output itself
+func getResponseEncoder(contentType string, contentTypeSet bool) api.FuncResponseEncoder {
+	if contentTypeSet && contentType != ConstDefaultMediaType {
+		return nil
+	}
+	return api.GetResponseEncoder(contentType)
+}
+
+// negotiateMediaType returns media type of registered encoder best matching "Accept" header value
+//   - ConstDefaultMediaType is returned for blank or not acceptable header value
+//   - ConstDefaultMediaType is returned for generic browser header accepting "text/html" along with "*/*",
+//     as browsers list XML there without asking for it
+func negotiateMediaType(accept string) string {
+	type acceptItem struct {
+		mediaType string
+		quality   float64
+	}
+
+	var items []acceptItem
+	acceptHTML, acceptAny := false, false
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		quality := 1.0
+		if value, present := params["q"]; present {
+			if quality, err = strconv.ParseFloat(value, 64); err != nil {
+				continue
+			}
+		}
+		if quality > 0 {
+			items = append(items, acceptItem{mediaType: mediaType, quality: quality})
+
+			switch mediaType {
+			case "text/html":
+				acceptHTML = true
+			case "*/*":
+				acceptAny = true
+			}
+		}
+	}
+
+	if acceptHTML && acceptAny {
+		return ConstDefaultMediaType
+	}
+	sort.SliceStable(items, func(i, j int) bool { return items[i].quality > items[j].quality })
+
+	for _, item := range items {
+		if item.mediaType == "*/*" {
+			return ConstDefaultMediaType
+		}
+
+		if strings.HasSuffix(item.mediaType, "/*") {
+			prefix := strings.TrimSuffix(item.mediaType, "*")
+			if strings.HasPrefix(ConstDefaultMediaType, prefix) {
+				return ConstDefaultMediaType
+			}
+			for _, mediaType := range api.GetResponseMediaTypes() {
+				if strings.HasPrefix(mediaType, prefix) {
+					return mediaType
+				}
+			}
+			continue
+		}
+
+		if api.GetResponseEncoder(item.mediaType) != nil {
+			return item.mediaType
+		}
+	}
+
+	return ConstDefaultMediaType
+}
+
+// normalizeValue converts value to generic form JSON decoder makes (maps, slices and scalars)
+func normalizeValue(value interface{}) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
+	var result interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&result); err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
+	return result, nil
+}
+
+// encodeJSON is a JSON response encoder
+func encodeJSON(response map[string]interface{}) ([]byte, error) {
+	return json.Marshal(response)
+}
+
+// encodeXML is an XML response encoder, response is put into "response" element
+//   - map keys are element names, array items are "item" elements
+func encodeXML(response map[string]interface{}) ([]byte, error) {
+	value, err := normalizeValue(response)
+	if err != nil {
+		return nil, err
+	}
+
+	buffer := bytes.NewBufferString(xml.Header)
+	if err := writeXMLElement(buffer, "response", value); err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// xmlElementName replaces symbols not allowed in XML element name with "_"
+func xmlElementName(name string) string {
+	result := []rune(name)
+	for index, symbol := range result {
+		allowed := unicode.IsLetter(symbol) || symbol == '_'
+		if index > 0 {
+			allowed = allowed || unicode.IsDigit(symbol) || symbol == '-' || symbol == '.'
+		}
+		if !allowed {
+			result[index] = '_'
+		}
+	}
+
+	if len(result) == 0 {
+		return "_"
+	}
+	return string(result)
+}
+
+// writeXMLElement writes normalized value as XML element of given name
+func writeXMLElement(buffer *bytes.Buffer, name string, value interface{}) error {
+	name = xmlElementName(name)
+
+	if value == nil {
+		buffer.WriteString("<" + name + "/>")
+		return nil
+	}
+
+	buffer.WriteString("<" + name + ">")
+	switch typedValue := value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(typedValue))
+		for key := range typedValue {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			if err := writeXMLElement(buffer, key, typedValue[key]); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for _, item := range typedValue {
+			if err := writeXMLElement(buffer, "item", item); err != nil {
+				return err
+			}
+		}
+	default:
+		if err := xml.EscapeText(buffer, []byte(fmt.Sprint(typedValue))); err != nil {
+			return err
+		}
+	}
+	buffer.WriteString("</" + name + ">")
+
+	return nil
+}
+
+// csvCell converts normalized value to CSV cell, nested values are JSON encoded
+func csvCell(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return ""
+	case map[string]interface{}, []interface{}:
+		data, _ := json.Marshal(value)
+		return string(data)
+	}
+	return fmt.Sprint(value)
+}
+
+// encodeCSV is a CSV response encoder, it outputs response "result" (or "error" if there is no result)
+//   - columns are sorted keys of result items, items which are not objects are put in "value" column
+func encodeCSV(response map[string]interface{}) ([]byte, error) {
+	content := response["result"]
+	if content == nil {
+		content = response["error"]
+	}
+
+	value, err := normalizeValue(content)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []interface{}
+	switch typedValue := value.(type) {
+	case nil:
+	case []interface{}:
+		rows = typedValue
+	default:
+		rows = []interface{}{typedValue}
+	}
+
+	columnsSet := make(map[string]bool)
+	for _, row := range rows {
+		if rowMap, ok := row.(map[string]interface{}); ok {
+			for key := range rowMap {
+				columnsSet[key] = true
+			}
+		} else {
+			columnsSet["value"] = true
+		}
+	}
+
+	columns := make([]string, 0, len(columnsSet))
+	for column := range columnsSet {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+
+	buffer := new(bytes.Buffer)
+	writer := csv.NewWriter(buffer)
+	if len(columns) > 0 {
+		if err := writer.Write(columns); err != nil {
+			return nil, env.ErrorDispatch(err)
+		}
+	}
+
+	for _, row := range rows {
+		record := make([]string, len(columns))
+		rowMap, isMap := row.(map[string]interface{})
+		for index, column := range columns {
+			if isMap {
+				record[index] = csvCell(rowMap[column])
+			} else if column == "value" {
+				record[index] = csvCell(row)
+			}
+		}
+		if err := writer.Write(record); err != nil {
+			return nil, env.ErrorDispatch(err)
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
+	return buffer.Bytes(), nil
+}
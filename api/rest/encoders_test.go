@@ -0,0 +1,73 @@
+package rest
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestNegotiateMediaType checks "Accept" header negotiation
+func TestNegotiateMediaType(t *testing.T) {
+	cases := map[string]string{
+		"":                                  ConstDefaultMediaType,
+		"*/*":                               ConstDefaultMediaType,
+		"text/csv":                          "text/csv",
+		"application/xml;q=0.9, text/csv":   "text/csv",
+		"application/xml, text/csv;q=0.5":   "application/xml",
+		"text/html, application/xml;q=0.8":  "application/xml",
+		"text/*":                            "text/csv",
+		"image/png":                         ConstDefaultMediaType,
+		"text/csv;q=0, application/xml;q=1": "application/xml",
+		"text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8": ConstDefaultMediaType,
+	}
+
+	for accept, expected := range cases {
+		if result := negotiateMediaType(accept); result != expected {
+			t.Errorf("'%s' negotiated to '%s', expected '%s'", accept, result, expected)
+		}
+	}
+}
+
+// TestEncoders checks XML and CSV encoders output
+func TestEncoders(t *testing.T) {
+	response := map[string]interface{}{
+		"result": []map[string]interface{}{
+			{"_id": "1", "name": "a & b", "price": 1.5},
+			{"_id": "2", "name": "c", "tags": []string{"x", "y"}},
+		},
+		"error":    nil,
+		"redirect": "",
+	}
+
+	data, err := encodeXML(response)
+	if err != nil {
+		t.Fatal("encodeXML", err)
+	}
+	if !strings.Contains(string(data), "<result><item><_id>1</_id><name>a &amp; b</name><price>1.5</price></item>") ||
+		!strings.Contains(string(data), "<error/>") {
+		t.Errorf("unexpected XML: %s", data)
+	}
+
+	data, err = encodeCSV(response)
+	if err != nil {
+		t.Fatal("encodeCSV", err)
+	}
+	expected := "_id,name,price,tags\n1,a & b,1.5,\n2,c,,\"[\"\"x\"\",\"\"y\"\"]\"\n"
+	if string(data) != expected {
+		t.Errorf("unexpected CSV: %s", data)
+	}
+
+	data, err = encodeCSV(map[string]interface{}{"result": "ok"})
+	if err != nil || string(data) != "value\nok\n" {
+		t.Errorf("unexpected CSV for scalar result: %s, %v", data, err)
+	}
+}
+
+// TestResponseEncoder checks encoder choice for negotiated and handler set content types
+func TestResponseEncoder(t *testing.T) {
+	if getResponseEncoder("text/xml", false) == nil || getResponseEncoder(ConstDefaultMediaType, true) == nil {
+		t.Error("registered encoder is not used")
+	}
+	if getResponseEncoder("text/xml", true) != nil || getResponseEncoder("image/png", false) != nil {
+		t.Error("encoder is used for handler made output")
+	}
+}
@@ -100,6 +100,7 @@ func (it *DefaultRestApplicationContext) GetResponseContentType() string {
 // SetResponseContentType changes response content type, returns error if not possible
 func (it *DefaultRestApplicationContext) SetResponseContentType(mimeType string) error {
 	it.ResponseWriter.Header().Set("Content-Type", mimeType)
+	it.responseContentTypeSet = true
 	return nil
 }
 
@@ -2,7 +2,6 @@ package rest
 
 import (
 	"encoding/json"
-	"encoding/xml"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -31,10 +30,12 @@ func (it *DefaultRestService) GetName() string {
 // 1. Starts the Session
 // 1. Handles the Referrer cookie
 // 1. Calls handler on context
-// 1. Handle redirects and response encoding (json/xml)
+// 1. Handle redirects and response encoding (media type negotiated by "Accept" header)
+//
+// Request is handled within a context holding request identifier, so logs and errors made during request are tagged with it
 func (it *DefaultRestService) wrappedHandler(handler api.FuncAPIHandler) httprouter.Handle {
 	// httprouter supposes other format of handler than we use, so we need wrapper
-	wrappedHandler := func(resp http.ResponseWriter, req *http.Request, params httprouter.Params) {
+	handleRequest := func(resp http.ResponseWriter, req *http.Request, params httprouter.Params) {
 
 		// catching API handler fails
 		defer func() {
@@ -180,11 +181,22 @@ func (it *DefaultRestService) wrappedHandler(handler api.FuncAPIHandler) httprou
 			}
 		}
 
+		// response media type negotiated by "Accept" header, handler could change it
+		mediaType := negotiateMediaType(req.Header.Get("Accept"))
+		resp.Header().Set("Content-Type", mediaType)
+		resp.Header().Add("Vary", "Accept")
+
 		// starting session for request
 		currentSession, err := api.StartSession(applicationContext)
 		if err != nil {
 			err = env.ErrorNew(ConstErrorModule, ConstErrorLevel, "c8a3bbf8-215f-4dff-b0e7-3d0d102ad02d", "Session init fail: "+err.Error())
 			_ = env.ErrorDispatch(err)
+
+			// handler is not called for request with invalid bearer token
+			if api.GetBearerToken(applicationContext) != "" {
+				resp.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+				applicationContext.SetResponseStatus(http.StatusUnauthorized)
+			}
 		}
 
 		utils.SyncScalarLock(currentSession.GetID())
@@ -209,6 +221,7 @@ func (it *DefaultRestService) wrappedHandler(handler api.FuncAPIHandler) httprou
 				env.Log(ConstDebugLogStorage, "REQUEST_"+debugRequestIdentifier, fmt.Sprintf("%s [%s]\n%#v\n", req.RequestURI, currentSession.GetID(), content))
 				env.LogEvent(env.LogFields{
 					"request_thread_id": debugRequestIdentifier,
+					"request_id":        context.GetRequestID(),
 					"session_id":        currentSession.GetID(),
 
 					"uri":          req.RequestURI,
@@ -229,23 +242,25 @@ func (it *DefaultRestService) wrappedHandler(handler api.FuncAPIHandler) httprou
 
 		// store admin credentials for later in-call use
 		var result interface{}
-		context.MakeContext(func() {
-			if callContext := context.GetContext(); callContext != nil {
-				callContext["is_admin"] = api.IsAdminSession(applicationContext)
-			} else {
-				err = env.ErrorNew(ConstErrorModule, env.ConstErrorLevelAPI, "6b94a499-9d71-403e-9f67-06fd90d6250d", "can not get context for API handler")
-			}
+		if callContext := context.GetContext(); callContext != nil {
+			callContext["is_admin"] = api.IsAdminSession(applicationContext)
+			callContext[api.ConstContextKeyApplicationContext] = applicationContext
+		} else {
+			err = env.ErrorNew(ConstErrorModule, env.ConstErrorLevelAPI, "6b94a499-9d71-403e-9f67-06fd90d6250d", "can not get context for API handler")
+		}
 
-			if err == nil {
-				// API handler processing
-				result, err = handler(applicationContext)
-			}
-		})
+		if err == nil {
+			// API handler processing
+			result, err = handler(applicationContext)
+		}
 
 		if err != nil {
+			markRequestFailed(req)
+
 			_ = env.ErrorDispatch(err)
 			env.LogEvent(env.LogFields{
 				"request_thread_id": debugRequestIdentifier,
+				"request_id":        context.GetRequestID(),
 				"session_id":        currentSession.GetID(),
 
 				"uri":        req.RequestURI,
@@ -275,11 +290,13 @@ func (it *DefaultRestService) wrappedHandler(handler api.FuncAPIHandler) httprou
 			}
 		}
 
-		// converting result to []byte if it is not already done
+		// converting result to []byte if it is not already done, raw []byte result is written with content type
+		// handler have set
 		if _, ok := result.([]byte); !ok {
 
-			// JSON encode
-			if resp.Header().Get("Content-Type") == "application/json" {
+			// encoding with registered encoder unless handler have chosen other content type and made output itself
+			encoder := getResponseEncoder(resp.Header().Get("Content-Type"), applicationContext.responseContentTypeSet)
+			if encoder != nil {
 				var errorMsg map[string]interface{}
 				if err != nil {
 					if _, ok := err.(env.InterfaceOttemoError); !ok {
@@ -314,6 +331,7 @@ func (it *DefaultRestService) wrappedHandler(handler api.FuncAPIHandler) httprou
 
 					logFields := env.LogFields{
 						"request_thread_id": debugRequestIdentifier,
+						"request_id":        context.GetRequestID(),
 						"session_id":        currentSession.GetID(),
 						"uri":               req.RequestURI,
 						"resp_time":         responseTime,
@@ -322,13 +340,12 @@ func (it *DefaultRestService) wrappedHandler(handler api.FuncAPIHandler) httprou
 					env.LogEvent(logFields, "response")
 				}
 
-				result, _ = json.Marshal(response)
-			}
-
-			// XML encode
-			if resp.Header().Get("Content-Type") == "text/xml" && result != nil {
-				xmlResult, _ := xml.MarshalIndent(result, "", "    ")
-				result = []byte(xml.Header + string(xmlResult))
+				encoded, encodeErr := encoder(response)
+				if encodeErr != nil {
+					_ = env.ErrorDispatch(encodeErr)
+					encoded, _ = json.Marshal(response)
+				}
+				result = encoded
 			}
 		}
 
@@ -343,39 +360,44 @@ func (it *DefaultRestService) wrappedHandler(handler api.FuncAPIHandler) httprou
 		}
 	}
 
+	wrappedHandler := func(resp http.ResponseWriter, req *http.Request, params httprouter.Params) {
+		requestID := getRequestID(req)
+		resp.Header().Set(ConstHeaderRequestID, requestID)
+
+		context.RunInContext(func() {
+			handleRequest(resp, req, params)
+		}, map[string]interface{}{context.ConstKeyRequestID: requestID})
+	}
+
 	return wrappedHandler
 }
 
-// GET is a wrapper for the HTTP GET verb
-func (it *DefaultRestService) GET(resource string, handler api.FuncAPIHandler) {
-	path := "/" + resource
-	it.Router.GET(path, it.wrappedHandler(handler))
+// handle registers API handler for given method and router path
+func (it *DefaultRestService) handle(method string, path string, handler api.FuncAPIHandler, meta []*api.StructRouteMeta) {
+	it.Router.Handle(method, path, measureHandler(method, path, limitHandler(method, path, it.wrappedHandler(handler))))
 
-	it.Handlers = append(it.Handlers, path+" {GET}")
+	it.Handlers = append(it.Handlers, path+" {"+method+"}")
+	it.addRoute(method, path, meta)
 }
 
-// PUT is a wrapper for the HTTP PUT verb
-func (it *DefaultRestService) PUT(resource string, handler api.FuncAPIHandler) {
-	path := "/" + resource
-	it.Router.PUT(path, it.wrappedHandler(handler))
+// GET is a wrapper for the HTTP GET verb
+func (it *DefaultRestService) GET(resource string, handler api.FuncAPIHandler, meta ...*api.StructRouteMeta) {
+	it.handle("GET", "/"+resource, handler, meta)
+}
 
-	it.Handlers = append(it.Handlers, path+" {PUT}")
+// PUT is a wrapper for the HTTP PUT verb
+func (it *DefaultRestService) PUT(resource string, handler api.FuncAPIHandler, meta ...*api.StructRouteMeta) {
+	it.handle("PUT", "/"+resource, handler, meta)
 }
 
 // POST is a wrapper for the HTTP POST verb
-func (it *DefaultRestService) POST(resource string, handler api.FuncAPIHandler) {
-	path := "/" + resource
-	it.Router.POST(path, it.wrappedHandler(handler))
-
-	it.Handlers = append(it.Handlers, path+" {POST}")
+func (it *DefaultRestService) POST(resource string, handler api.FuncAPIHandler, meta ...*api.StructRouteMeta) {
+	it.handle("POST", "/"+resource, handler, meta)
 }
 
 // DELETE is a wrapper for the HTTP DELETE verb
-func (it *DefaultRestService) DELETE(resource string, handler api.FuncAPIHandler) {
-	path := "/" + resource
-	it.Router.DELETE(path, it.wrappedHandler(handler))
-
-	it.Handlers = append(it.Handlers, path+" {DELETE}")
+func (it *DefaultRestService) DELETE(resource string, handler api.FuncAPIHandler, meta ...*api.StructRouteMeta) {
+	it.handle("DELETE", "/"+resource, handler, meta)
 }
 
 // ServeHTTP is an entry point for HTTP request, it takes control before request handled
@@ -399,6 +421,17 @@ func (it DefaultRestService) ServeHTTP(responseWriter http.ResponseWriter, reque
 
 		request.URL.Path = strings.Replace(request.URL.Path, "/commerce", "", -1)
 
+		if request.Method == "GET" && request.URL.Path == ConstMetricsPath {
+			serveMetrics(responseWriter, request)
+			return
+		}
+
+		// version request is served by is given to handler and client in header
+		var version string
+		request.URL.Path, version = it.resolveVersionPath(request.Method, request.URL.Path)
+		request.Header.Set(ConstHeaderAPIVersion, version)
+		responseWriter.Header().Set(ConstHeaderAPIVersion, version)
+
 		it.Router.ServeHTTP(responseWriter, request)
 	}
 }
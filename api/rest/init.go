@@ -13,6 +13,7 @@ import (
 // init performs the package self-initialization routine
 func init() {
 	var _ api.InterfaceApplicationContext = new(DefaultRestApplicationContext)
+	var _ api.InterfaceRouteGroup = new(routeGroup)
 
 	instance := new(DefaultRestService)
 
@@ -20,6 +21,8 @@ func init() {
 		_ = env.ErrorDispatch(err)
 	}
 	env.RegisterOnConfigIniStart(instance.startup)
+
+	registerEncoders()
 }
 
 // service pre-initialization stuff
@@ -42,6 +45,7 @@ func (it *DefaultRestService) startup() error {
 	}
 
 	it.Router.GET("/", it.rootPageHandler)
+	it.Router.GET(ConstOpenAPIPath, it.openAPIHandler)
 
 	if err := api.OnRestServiceStart(); err != nil {
 		_ = env.ErrorDispatch(err)
@@ -0,0 +1,46 @@
+package rest
+
+import (
+	"crypto/rand"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// getRequestID returns request identifier given by client in header or makes new one
+//   - given identifier is used only if it is not longer than ConstRequestIDMaxLength and has only safe symbols
+func getRequestID(req *http.Request) string {
+	if requestID := req.Header.Get(ConstHeaderRequestID); isValidRequestID(requestID) {
+		return requestID
+	}
+	return makeRequestID()
+}
+
+// isValidRequestID checks identifier to be safe for logs and headers: letters, digits and "-_.:" symbols
+func isValidRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > ConstRequestIDMaxLength {
+		return false
+	}
+
+	for _, symbol := range requestID {
+		switch {
+		case symbol >= 'a' && symbol <= 'z', symbol >= 'A' && symbol <= 'Z', symbol >= '0' && symbol <= '9':
+		case symbol == '-', symbol == '_', symbol == '.', symbol == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// makeRequestID returns new random request identifier in UUID v4 format
+func makeRequestID() string {
+	value := make([]byte, 16)
+	if _, err := rand.Read(value); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+
+	value[6] = (value[6] & 0x0f) | 0x40
+	value[8] = (value[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", value[0:4], value[4:6], value[6:8], value[8:10], value[10:])
+}
@@ -0,0 +1,67 @@
+package rest
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/ottemo/commerce/env"
+	"github.com/ottemo/commerce/metrics"
+)
+
+// WriteHeader remembers response status and passes it to wrapped writer
+func (it *statusRecorder) WriteHeader(status int) {
+	if it.status == 0 {
+		it.status = status
+	}
+	it.ResponseWriter.WriteHeader(status)
+}
+
+// Write passes data to wrapped writer, status is 200 if it was not set before
+func (it *statusRecorder) Write(data []byte) (int, error) {
+	if it.status == 0 {
+		it.status = http.StatusOK
+	}
+	return it.ResponseWriter.Write(data)
+}
+
+// measureHandler wraps route handler to collect request counts by status and durations
+//   - route is a registered path pattern, so requests for different items are counted together
+func measureHandler(method string, route string, handler httprouter.Handle) httprouter.Handle {
+	return func(resp http.ResponseWriter, req *http.Request, params httprouter.Params) {
+		startTime := time.Now()
+		recorder := &statusRecorder{ResponseWriter: resp}
+
+		defer func() {
+			status := recorder.status
+			if status == 0 {
+				status = http.StatusOK
+			}
+
+			requestDuration.ObserveDuration(startTime, method, route)
+			requestsTotal.Inc(method, route, strconv.Itoa(status))
+		}()
+
+		handler(recorder, req, params)
+	}
+}
+
+// serveMetrics outputs collected metrics
+//   - request should have "Authorization: Bearer [token]" header with "metrics.token" ini value
+//   - metrics are not served if "metrics.token" ini value is not set
+func serveMetrics(resp http.ResponseWriter, req *http.Request) {
+	token := env.IniValue("metrics.token")
+	if token == "" {
+		resp.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	if subtle.ConstantTimeCompare([]byte(req.Header.Get("Authorization")), []byte("Bearer "+token)) != 1 {
+		resp.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	metrics.Handler().ServeHTTP(resp, req)
+}
@@ -0,0 +1,67 @@
+package rest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ottemo/commerce/env"
+)
+
+// testIniConfig is an env.InterfaceIniConfig test implementation
+type testIniConfig struct {
+	values map[string]string
+}
+
+func (it *testIniConfig) SetWorkingSection(sectionName string) error { return nil }
+func (it *testIniConfig) SetValue(valueName string, value string) error {
+	it.values[valueName] = value
+	return nil
+}
+func (it *testIniConfig) GetSectionValue(sectionName string, valueName string, defaultValue string) string {
+	return it.GetValue(valueName, defaultValue)
+}
+func (it *testIniConfig) GetValue(valueName string, defaultValue string) string {
+	if value, present := it.values[valueName]; present {
+		return value
+	}
+	return defaultValue
+}
+func (it *testIniConfig) ListSections() []string                       { return nil }
+func (it *testIniConfig) ListItems() []string                          { return nil }
+func (it *testIniConfig) ListSectionItems(sectionName string) []string { return nil }
+
+// TestServeMetrics checks metrics endpoint is served only for requests with configured token
+func TestServeMetrics(t *testing.T) {
+	iniConfig := &testIniConfig{values: map[string]string{}}
+	if err := env.RegisterIniConfig(iniConfig); err != nil {
+		t.Fatal("RegisterIniConfig", err)
+	}
+
+	serve := func(authorization string) int {
+		req := httptest.NewRequest("GET", ConstMetricsPath, nil)
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		resp := httptest.NewRecorder()
+		serveMetrics(resp, req)
+		return resp.Code
+	}
+
+	if status := serve("Bearer "); status != http.StatusForbidden {
+		t.Errorf("metrics without configured token were served with %d status", status)
+	}
+
+	if err := iniConfig.SetValue("metrics.token", "secret"); err != nil {
+		t.Fatal(err)
+	}
+	for authorization, expected := range map[string]int{
+		"":              http.StatusUnauthorized,
+		"Bearer other":  http.StatusUnauthorized,
+		"Bearer secret": http.StatusOK,
+	} {
+		if status := serve(authorization); status != expected {
+			t.Errorf("'%s' authorization got %d status, expected %d", authorization, status, expected)
+		}
+	}
+}
@@ -0,0 +1,252 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/ottemo/commerce/api"
+	"github.com/ottemo/commerce/env"
+)
+
+// addRoute remembers registered route with its description for OpenAPI document
+func (it *DefaultRestService) addRoute(method string, path string, meta []*api.StructRouteMeta) {
+	route := &StructRoute{Method: method, Path: path}
+	if len(meta) > 0 {
+		route.Meta = meta[0]
+	}
+	it.Routes = append(it.Routes, route)
+}
+
+// openAPIPath converts router path to OpenAPI form and returns path parameter names
+//   - "/product/:productID/media/*mediaName" becomes "/product/{productID}/media/{mediaName}"
+func openAPIPath(path string) (string, []string) {
+	var names []string
+
+	segments := strings.Split(path, "/")
+	for index, segment := range segments {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			names = append(names, segment[1:])
+			segments[index] = "{" + segment[1:] + "}"
+		}
+	}
+
+	return strings.Join(segments, "/"), names
+}
+
+// openAPIOperationID makes operation identifier from route method and path, i.e. "getProductByProductID"
+func openAPIOperationID(method string, path string) string {
+	result := strings.ToLower(method)
+	for _, segment := range strings.FieldsFunc(path, func(r rune) bool { return r == '/' || r == '-' || r == '_' || r == '.' }) {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			segment = "By" + strings.Title(segment[1:])
+		}
+		result += strings.Title(segment)
+	}
+	return result
+}
+
+// openAPIParameter converts route parameter description to OpenAPI parameter object
+func openAPIParameter(parameter api.StructRouteParameter) map[string]interface{} {
+	result := map[string]interface{}{
+		"name":   parameter.Name,
+		"in":     parameter.In,
+		"schema": map[string]interface{}{"type": parameter.Type},
+	}
+	if parameter.In == "" {
+		result["in"] = "query"
+	}
+	if parameter.Type == "" {
+		result["schema"] = map[string]interface{}{"type": "string"}
+	}
+	if parameter.Required || parameter.In == "path" {
+		result["required"] = true
+	}
+	if parameter.Description != "" {
+		result["description"] = parameter.Description
+	}
+	return result
+}
+
+// openAPIOperation makes OpenAPI operation object for registered route
+func openAPIOperation(route *StructRoute, pathParameters []string) map[string]interface{} {
+	meta := route.Meta
+	if meta == nil {
+		meta = new(api.StructRouteMeta)
+	}
+
+	operation := map[string]interface{}{
+		"operationId": openAPIOperationID(route.Method, route.Path),
+		"summary":     meta.Summary,
+	}
+	if meta.Summary == "" {
+		operation["summary"] = route.Method + " " + route.Path
+	}
+	if meta.Description != "" {
+		operation["description"] = meta.Description
+	}
+	if meta.Deprecated {
+		operation["deprecated"] = true
+	}
+
+	if len(meta.Tags) > 0 {
+		operation["tags"] = meta.Tags
+	} else if segments := strings.SplitN(strings.TrimPrefix(route.Path, "/"), "/", 2); segments[0] != "" {
+		operation["tags"] = []string{segments[0]}
+	}
+
+	// path parameters are described automatically, given descriptions are overriding them
+	described := make(map[string]bool)
+	var parameters []interface{}
+	for _, parameter := range meta.Parameters {
+		described[parameter.Name] = true
+		parameters = append(parameters, openAPIParameter(parameter))
+	}
+	for _, name := range pathParameters {
+		if !described[name] {
+			parameters = append(parameters, openAPIParameter(api.StructRouteParameter{Name: name, In: "path"}))
+		}
+	}
+	if len(parameters) > 0 {
+		operation["parameters"] = parameters
+	}
+
+	if meta.Request != nil {
+		operation["requestBody"] = map[string]interface{}{
+			"content": map[string]interface{}{
+				"application/json": map[string]interface{}{"schema": meta.Request},
+			},
+		}
+	}
+
+	resultSchema := meta.Response
+	if resultSchema == nil {
+		resultSchema = map[string]interface{}{}
+	}
+	responses := map[string]interface{}{
+		"200": map[string]interface{}{
+			"description": "API call result, \"error\" is set if call failed",
+			"content": map[string]interface{}{
+				"application/json": map[string]interface{}{
+					"schema": map[string]interface{}{
+						"type": "object",
+						"properties": map[string]interface{}{
+							"result":   resultSchema,
+							"error":    map[string]interface{}{"$ref": "#/components/schemas/Error"},
+							"redirect": map[string]interface{}{"type": "string"},
+						},
+					},
+				},
+			},
+		},
+		"429": map[string]interface{}{"$ref": "#/components/responses/TooManyRequests"},
+	}
+
+	if meta.Permission != "" {
+		operation["x-permission"] = meta.Permission
+		operation["security"] = []interface{}{
+			map[string]interface{}{"sessionCookie": []string{}},
+			map[string]interface{}{"oauth2": []string{meta.Permission}},
+		}
+		responses["401"] = map[string]interface{}{"description": "invalid bearer token"}
+		responses["403"] = map[string]interface{}{"description": "'" + meta.Permission + "' permission is required"}
+	}
+	operation["responses"] = responses
+
+	return operation
+}
+
+// GetOpenAPIDocument generates OpenAPI 3 document for registered routes
+func (it *DefaultRestService) GetOpenAPIDocument() map[string]interface{} {
+	paths := make(map[string]interface{})
+	operationIDs := make(map[string]int)
+	for _, route := range it.Routes {
+		path, pathParameters := openAPIPath(route.Path)
+
+		pathItem, present := paths[path].(map[string]interface{})
+		if !present {
+			pathItem = make(map[string]interface{})
+			paths[path] = pathItem
+		}
+
+		operation := openAPIOperation(route, pathParameters)
+		if operationID := operation["operationId"].(string); operationIDs[operationID] > 0 {
+			operation["operationId"] = operationID + strconv.Itoa(operationIDs[operationID]+1)
+			operationIDs[operationID]++
+		} else {
+			operationIDs[operationID] = 1
+		}
+		pathItem[strings.ToLower(route.Method)] = operation
+	}
+
+	scopes := make(map[string]interface{})
+	for _, permission := range api.GetPermissions() {
+		scopes[permission] = permission + " permission"
+	}
+
+	return map[string]interface{}{
+		"openapi": ConstOpenAPIVersion,
+		"info": map[string]interface{}{
+			"title":   ConstOpenAPITitle,
+			"version": "1",
+		},
+		"paths": paths,
+		"components": map[string]interface{}{
+			"schemas": map[string]interface{}{
+				"Error": map[string]interface{}{
+					"type":     "object",
+					"nullable": true,
+					"properties": map[string]interface{}{
+						"message": map[string]interface{}{"type": "string"},
+						"level":   map[string]interface{}{"type": "integer"},
+						"code":    map[string]interface{}{"type": "string"},
+					},
+				},
+			},
+			"responses": map[string]interface{}{
+				"TooManyRequests": map[string]interface{}{
+					"description": "request rate limit exceeded",
+					"headers": map[string]interface{}{
+						"Retry-After": map[string]interface{}{
+							"description": "seconds to wait before next request",
+							"schema":      map[string]interface{}{"type": "integer"},
+						},
+					},
+				},
+			},
+			"securitySchemes": map[string]interface{}{
+				"sessionCookie": map[string]interface{}{
+					"type": "apiKey",
+					"in":   "cookie",
+					"name": api.ConstSessionCookieName,
+				},
+				"oauth2": map[string]interface{}{
+					"type": "oauth2",
+					"flows": map[string]interface{}{
+						"clientCredentials": map[string]interface{}{
+							"tokenUrl": "oauth/token",
+							"scopes":   scopes,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// openAPIHandler outputs generated OpenAPI document
+func (it *DefaultRestService) openAPIHandler(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	document, err := json.MarshalIndent(it.GetOpenAPIDocument(), "", "  ")
+	if err != nil {
+		_ = env.ErrorDispatch(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if _, err := w.Write(document); err != nil {
+		_ = env.ErrorDispatch(err)
+	}
+}
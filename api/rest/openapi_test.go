@@ -0,0 +1,51 @@
+package rest
+
+import (
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/ottemo/commerce/api"
+)
+
+// TestOpenAPIDocument checks registered routes are described in generated document
+func TestOpenAPIDocument(t *testing.T) {
+	service := &DefaultRestService{Router: httprouter.New()}
+	handler := func(context api.InterfaceApplicationContext) (interface{}, error) { return "ok", nil }
+
+	service.GET("product/:productID", handler)
+	service.PUT("product/:productID", handler, &api.StructRouteMeta{
+		Summary:    "Update product",
+		Parameters: []api.StructRouteParameter{{Name: "productID", In: "path", Description: "product id"}},
+		Request:    map[string]interface{}{"type": "object"},
+		Permission: "catalog.write",
+	})
+
+	paths, ok := service.GetOpenAPIDocument()["paths"].(map[string]interface{})
+	if !ok {
+		t.Fatal("document has no paths")
+	}
+
+	pathItem, ok := paths["/product/{productID}"].(map[string]interface{})
+	if !ok || len(pathItem) != 2 {
+		t.Fatalf("unexpected path item: %#v", paths)
+	}
+
+	operation := pathItem["get"].(map[string]interface{})
+	if operation["operationId"] != "getProductByProductID" || operation["summary"] != "GET /product/:productID" {
+		t.Errorf("unexpected operation: %#v", operation)
+	}
+	if parameters := operation["parameters"].([]interface{}); len(parameters) != 1 || parameters[0].(map[string]interface{})["required"] != true {
+		t.Errorf("path parameter is not described: %#v", parameters)
+	}
+	if _, present := operation["security"]; present {
+		t.Error("security is set for public route")
+	}
+
+	operation = pathItem["put"].(map[string]interface{})
+	if operation["summary"] != "Update product" || operation["x-permission"] != "catalog.write" || operation["requestBody"] == nil {
+		t.Errorf("route description is not applied: %#v", operation)
+	}
+	if parameters := operation["parameters"].([]interface{}); len(parameters) != 1 || parameters[0].(map[string]interface{})["description"] != "product id" {
+		t.Errorf("path parameter description is not applied: %#v", parameters)
+	}
+}
@@ -0,0 +1,341 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/ottemo/commerce/api"
+	"github.com/ottemo/commerce/env"
+	"github.com/ottemo/commerce/utils"
+)
+
+// requestAttemptKey is a request context key of requestAttempt
+type requestAttemptKey struct{}
+
+// ParseRateRules parses rate limit rules, one per line (or separated by ";"), "#" starts a comment
+//   - rate rule: "<method> <route> <count>/<period> [ip|session]", i.e. "POST /visit/login 20/1m ip"
+//   - lockout rule: "<method> <route> <failures>/<period> <lockout> [ip|session]", i.e. "POST /visit/login 5/15m 15m"
+//   - method and route could be "*" to match any, period and lockout are durations like "30s", "15m" or "1h"
+func ParseRateRules(value string, lockout bool) ([]*StructRateRule, error) {
+	var result []*StructRateRule
+
+	for _, line := range strings.FieldsFunc(value, func(r rune) bool { return r == '\n' || r == ';' }) {
+		if index := strings.Index(line, "#"); index >= 0 {
+			line = line[:index]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		minFields := 3
+		if lockout {
+			minFields = 4
+		}
+		if len(fields) < minFields || len(fields) > minFields+1 {
+			return nil, env.ErrorNew(ConstErrorModule, env.ConstErrorLevelAPI, "3a7c7294-12ec-4c83-9b7d-1fa4c9f7496c", "invalid rate limit rule '"+line+"'")
+		}
+
+		rule := &StructRateRule{Method: strings.ToUpper(fields[0]), Route: fields[1], Scope: ConstRateLimitScopeIP}
+		if rule.Route != "*" && !strings.HasPrefix(rule.Route, "/") {
+			rule.Route = "/" + rule.Route
+		}
+
+		rate := strings.SplitN(fields[2], "/", 2)
+		if len(rate) != 2 {
+			return nil, env.ErrorNew(ConstErrorModule, env.ConstErrorLevelAPI, "b0359fe0-3059-41fb-823f-995c801e2527", "rate should be in '<count>/<period>' form in rule '"+line+"'")
+		}
+		count, err := strconv.Atoi(rate[0])
+		if err != nil || count <= 0 {
+			return nil, env.ErrorNew(ConstErrorModule, env.ConstErrorLevelAPI, "d921fef1-76ab-40aa-9223-7695aa883a19", "count should be positive number in rule '"+line+"'")
+		}
+		rule.Count = count
+
+		if rule.Period, err = time.ParseDuration(rate[1]); err != nil || rule.Period <= 0 {
+			return nil, env.ErrorNew(ConstErrorModule, env.ConstErrorLevelAPI, "49f7dc1a-f4b8-4dfd-9b77-2b4b625a3e24", "period should be positive duration in rule '"+line+"'")
+		}
+
+		if lockout {
+			if rule.Lockout, err = time.ParseDuration(fields[3]); err != nil || rule.Lockout <= 0 {
+				return nil, env.ErrorNew(ConstErrorModule, env.ConstErrorLevelAPI, "b6a87b52-f5ce-4fbf-b15b-19afdb38b4cf", "lockout should be positive duration in rule '"+line+"'")
+			}
+		}
+
+		if len(fields) > minFields {
+			rule.Scope = strings.ToLower(fields[minFields])
+			if rule.Scope != ConstRateLimitScopeIP && rule.Scope != ConstRateLimitScopeSession {
+				return nil, env.ErrorNew(ConstErrorModule, env.ConstErrorLevelAPI, "2bcca9aa-6683-46f9-bea4-8f3dceea1e51", "scope should be 'ip' or 'session' in rule '"+line+"'")
+			}
+		}
+
+		result = append(result, rule)
+	}
+
+	return result, nil
+}
+
+// Match checks if rule is applicable to given method and registered route
+//   - versioned route "/v[N]/[resource]" matches rules of "/[resource]" route
+func (it *StructRateRule) Match(method string, route string) bool {
+	return (it.Method == "*" || it.Method == method) && (it.Route == "*" || it.Route == route || it.Route == stripAPIVersion(route))
+}
+
+// getRateRules returns parsed rules of given config path, defaults are used if config value is not available
+//   - should be called under rateLimiterMutex
+func getRateRules(path string, defaultValue string, lockout bool) []*StructRateRule {
+	value := defaultValue
+	if configValue := env.ConfigGetValue(path); configValue != nil {
+		value = utils.InterfaceToString(configValue)
+	}
+
+	cacheKey := path + "\n" + value
+	if rules, present := rateRulesCache[cacheKey]; present {
+		return rules
+	}
+
+	rules, err := ParseRateRules(value, lockout)
+	if err != nil {
+		_ = env.ErrorDispatch(err)
+	}
+
+	if len(rateRulesCache) > 16 {
+		rateRulesCache = make(map[string][]*StructRateRule)
+	}
+	rateRulesCache[cacheKey] = rules
+
+	return rules
+}
+
+// getClientIP returns request client address
+//   - if "rest.realIPHeader" ini value is set, address is taken from that header set by trusted reverse proxy
+func getClientIP(req *http.Request) string {
+	if header := env.IniValue("rest.realIPHeader"); header != "" {
+		values := strings.Split(req.Header.Get(header), ",")
+		if value := strings.TrimSpace(values[len(values)-1]); value != "" {
+			return value
+		}
+	}
+
+	if host, _, err := net.SplitHostPort(req.RemoteAddr); err == nil {
+		return host
+	}
+	return req.RemoteAddr
+}
+
+// getClientKey returns key request is counted by for given rule scope
+//   - session scope falls back to client address for requests without existing session or valid bearer token,
+//     so made up cookies do not give a fresh bucket
+func getClientKey(req *http.Request, scope string) string {
+	if scope == ConstRateLimitScopeSession {
+		if cookie, err := req.Cookie(api.ConstSessionCookieName); err == nil && cookie.Value != "" {
+			if session, err := api.GetSessionByID(cookie.Value, false); err == nil && session != nil {
+				return "session:" + session.GetID()
+			}
+		}
+
+		authorization := strings.TrimSpace(req.Header.Get("Authorization"))
+		if len(authorization) > 7 && strings.EqualFold(authorization[:7], "Bearer ") {
+			if token := strings.TrimSpace(authorization[7:]); api.IsBearerTokenValid(token) {
+				return "auth:" + token
+			}
+		}
+	}
+	return "ip:" + getClientIP(req)
+}
+
+// takeToken takes token from rule bucket of given key, returns time to wait for a token if bucket is empty
+//   - should be called under rateLimiterMutex
+func takeToken(key string, rule *StructRateRule, now time.Time) time.Duration {
+	bucket, present := rateBuckets[key]
+	if !present {
+		bucket = &rateBucket{tokens: float64(rule.Count), updated: now, period: rule.Period}
+		rateBuckets[key] = bucket
+	}
+
+	rate := float64(rule.Count) / rule.Period.Seconds()
+	bucket.tokens = math.Min(float64(rule.Count), bucket.tokens+now.Sub(bucket.updated).Seconds()*rate)
+	bucket.updated = now
+
+	if bucket.tokens >= 1 {
+		bucket.tokens--
+		return 0
+	}
+
+	return time.Duration((1 - bucket.tokens) / rate * float64(time.Second))
+}
+
+// lockedFor returns remaining lockout time for given key
+//   - should be called under rateLimiterMutex
+func lockedFor(key string, now time.Time) time.Duration {
+	if counter, present := lockoutCounters[key]; present && counter.lockedUntil.After(now) {
+		return counter.lockedUntil.Sub(now)
+	}
+	return 0
+}
+
+// registerFailure counts failed request for given key, key is locked out when rule failures count reached
+//   - should be called under rateLimiterMutex
+func registerFailure(key string, rule *StructRateRule, now time.Time) {
+	counter, present := lockoutCounters[key]
+	if !present {
+		counter = &lockoutCounter{windowStart: now, period: rule.Period}
+		lockoutCounters[key] = counter
+	}
+
+	if now.Sub(counter.windowStart) >= rule.Period {
+		counter.failures = 0
+		counter.windowStart = now
+	}
+
+	counter.failures++
+	if counter.failures >= rule.Count {
+		counter.lockedUntil = now.Add(rule.Lockout)
+		counter.failures = 0
+		counter.windowStart = now
+	}
+}
+
+// cleanupRateLimiter removes full buckets and outdated counters, it makes work once per ConstRateLimitCleanup
+//   - should be called under rateLimiterMutex
+func cleanupRateLimiter(now time.Time) {
+	if now.Sub(rateLastCleanup) < ConstRateLimitCleanup {
+		return
+	}
+	rateLastCleanup = now
+
+	for key, bucket := range rateBuckets {
+		if now.Sub(bucket.updated) >= bucket.period {
+			delete(rateBuckets, key)
+		}
+	}
+	for key, counter := range lockoutCounters {
+		if !counter.lockedUntil.After(now) && now.Sub(counter.windowStart) >= counter.period {
+			delete(lockoutCounters, key)
+		}
+	}
+}
+
+// markRequestFailed flags rate limited request as failed one, so it is counted by lockout rules
+func markRequestFailed(req *http.Request) {
+	if attempt, ok := req.Context().Value(requestAttemptKey{}).(*requestAttempt); ok {
+		attempt.failed = true
+	}
+}
+
+// writeTooManyRequests responds with 429 status and "Retry-After" header
+func writeTooManyRequests(resp http.ResponseWriter, retryAfter time.Duration) {
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+
+	resp.Header().Set("Retry-After", strconv.Itoa(seconds))
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(http.StatusTooManyRequests)
+
+	errorMsg := map[string]interface{}{
+		"message": "too many requests, retry after " + strconv.Itoa(seconds) + " seconds",
+		"level":   env.ConstErrorLevelAPI,
+		"code":    "00b160df-8eb4-4401-bebf-fa01e4568671",
+	}
+	body, err := json.Marshal(map[string]interface{}{"result": nil, "error": errorMsg, "redirect": ""})
+	if err != nil {
+		_ = env.ErrorDispatch(err)
+		return
+	}
+	if _, err := resp.Write(body); err != nil {
+		_ = env.ErrorDispatch(err)
+	}
+}
+
+// limitHandler wraps route handler with token bucket rate limiter and brute-force lockout
+//   - request is rejected with 429 status if any matching rate rule bucket is empty or client is locked out
+//   - request failed with handler error or 4xx/5xx status is counted by matching lockout rules
+func limitHandler(method string, route string, handler httprouter.Handle) httprouter.Handle {
+	return func(resp http.ResponseWriter, req *http.Request, params httprouter.Params) {
+		if value := env.ConfigGetValue(ConstConfigPathAPIRateLimitEnable); value != nil && !utils.InterfaceToBool(value) {
+			handler(resp, req, params)
+			return
+		}
+
+		now := time.Now()
+		var lockoutKeys []string
+		var lockoutRules []*StructRateRule
+		var rateRules []*StructRateRule
+		var retryAfter time.Duration
+
+		rateLimiterMutex.Lock()
+		cleanupRateLimiter(now)
+
+		for _, rule := range getRateRules(ConstConfigPathAPIRateLimitLockout, ConstDefaultLockoutRules, true) {
+			if rule.Match(method, route) {
+				lockoutRules = append(lockoutRules, rule)
+			}
+		}
+		for _, rule := range getRateRules(ConstConfigPathAPIRateLimitRules, ConstDefaultRateLimitRules, false) {
+			if rule.Match(method, route) {
+				rateRules = append(rateRules, rule)
+			}
+		}
+		rateLimiterMutex.Unlock()
+
+		// client keys are taken out of lock as session scope key looks up session storage
+		clientKeys := make(map[string]string)
+		for _, rules := range [][]*StructRateRule{lockoutRules, rateRules} {
+			for _, rule := range rules {
+				if _, present := clientKeys[rule.Scope]; !present {
+					clientKeys[rule.Scope] = getClientKey(req, rule.Scope)
+				}
+			}
+		}
+
+		rateLimiterMutex.Lock()
+		for _, rule := range lockoutRules {
+			key := "lockout|" + rule.Method + " " + rule.Route + "|" + clientKeys[rule.Scope]
+			if wait := lockedFor(key, now); wait > retryAfter {
+				retryAfter = wait
+			}
+			lockoutKeys = append(lockoutKeys, key)
+		}
+
+		if retryAfter == 0 {
+			for _, rule := range rateRules {
+				key := "rate|" + rule.Method + " " + rule.Route + "|" + clientKeys[rule.Scope]
+				if wait := takeToken(key, rule, now); wait > retryAfter {
+					retryAfter = wait
+				}
+			}
+		}
+		rateLimiterMutex.Unlock()
+
+		if retryAfter > 0 {
+			requestsLimited.Inc(method, route)
+			writeTooManyRequests(resp, retryAfter)
+			return
+		}
+
+		if len(lockoutRules) == 0 {
+			handler(resp, req, params)
+			return
+		}
+
+		attempt := new(requestAttempt)
+		recorder := &statusRecorder{ResponseWriter: resp}
+		handler(recorder, req.WithContext(context.WithValue(req.Context(), requestAttemptKey{}, attempt)), params)
+
+		if attempt.failed || recorder.status >= http.StatusBadRequest {
+			rateLimiterMutex.Lock()
+			for index, rule := range lockoutRules {
+				registerFailure(lockoutKeys[index], rule, time.Now())
+			}
+			rateLimiterMutex.Unlock()
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package rest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/ottemo/commerce/api"
+)
+
+// TestParseRateRules checks rate and lockout rules parsing
+func TestParseRateRules(t *testing.T) {
+	rules, err := ParseRateRules("* * 100/1m\npost visit/login 5/10s session # login ; GET /a 1/1h", false)
+	if err != nil {
+		t.Fatal("ParseRateRules", err)
+	}
+	if len(rules) != 3 {
+		t.Fatalf("3 rules expected, got %d", len(rules))
+	}
+	if rule := rules[1]; rule.Method != "POST" || rule.Route != "/visit/login" || rule.Count != 5 || rule.Period != 10*time.Second || rule.Scope != ConstRateLimitScopeSession {
+		t.Errorf("unexpected rule: %#v", rule)
+	}
+	if !rules[0].Match("GET", "/any") || !rules[1].Match("POST", "/visit/login") || rules[1].Match("GET", "/visit/login") {
+		t.Error("rules matching failed")
+	}
+
+	rules, err = ParseRateRules("POST /visit/login 5/15m 1h ip", true)
+	if err != nil || len(rules) != 1 || rules[0].Lockout != time.Hour {
+		t.Errorf("unexpected lockout rules: %v, %v", rules, err)
+	}
+
+	for _, value := range []string{"* * 100", "* * 0/1m", "* * 10/1x", "* * 10/1m browser", "* * 10/1m ip extra"} {
+		if _, err := ParseRateRules(value, false); err == nil {
+			t.Errorf("rule '%s' was accepted", value)
+		}
+	}
+	if _, err := ParseRateRules("POST /visit/login 5/15m", true); err == nil {
+		t.Error("lockout rule without lockout duration was accepted")
+	}
+}
+
+// TestLimitHandler checks requests over limit and locked out clients get 429 status with Retry-After header
+func TestLimitHandler(t *testing.T) {
+	status := http.StatusOK
+	handler := limitHandler("POST", "/visit/login", func(resp http.ResponseWriter, req *http.Request, params httprouter.Params) {
+		if status != http.StatusOK {
+			markRequestFailed(req)
+		}
+		resp.WriteHeader(http.StatusOK)
+	})
+
+	request := func(remoteAddr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/visit/login", nil)
+		req.RemoteAddr = remoteAddr
+		recorder := httptest.NewRecorder()
+		handler(recorder, req, nil)
+		return recorder
+	}
+
+	// default lockout rule allows 5 failed logins
+	status = http.StatusForbidden
+	for i := 0; i < 5; i++ {
+		if result := request("10.0.0.1:1000"); result.Code != http.StatusOK {
+			t.Fatalf("request %d was rejected with %d status", i, result.Code)
+		}
+	}
+
+	result := request("10.0.0.1:1001")
+	if result.Code != http.StatusTooManyRequests || result.Header().Get("Retry-After") == "" {
+		t.Errorf("locked out client got %d status, Retry-After '%s'", result.Code, result.Header().Get("Retry-After"))
+	}
+
+	// default rate rule allows 20 logins per minute
+	status = http.StatusOK
+	for i := 0; i < 20; i++ {
+		if result := request("10.0.0.2:1000"); result.Code != http.StatusOK {
+			t.Fatalf("request %d was rejected with %d status", i, result.Code)
+		}
+	}
+	if result := request("10.0.0.2:1000"); result.Code != http.StatusTooManyRequests {
+		t.Errorf("request over limit got %d status", result.Code)
+	}
+	if result := request("10.0.0.3:1000"); result.Code != http.StatusOK {
+		t.Errorf("other client request got %d status", result.Code)
+	}
+}
+
+// TestLimitHandlerSessionScope checks versioned route is limited by unversioned route rules and made up session
+// cookies are counted by client address
+func TestLimitHandlerSessionScope(t *testing.T) {
+	handler := limitHandler("POST", "/v2/cart/coupons", func(resp http.ResponseWriter, req *http.Request, params httprouter.Params) {
+		resp.WriteHeader(http.StatusOK)
+	})
+
+	request := func(cookie string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/v2/cart/coupons", nil)
+		req.RemoteAddr = "10.0.1.1:1000"
+		req.AddCookie(&http.Cookie{Name: api.ConstSessionCookieName, Value: cookie})
+		recorder := httptest.NewRecorder()
+		handler(recorder, req, nil)
+		return recorder
+	}
+
+	// default session rule allows 20 coupon applies per minute
+	for i := 0; i < 20; i++ {
+		if result := request("made-up-" + strconv.Itoa(i)); result.Code != http.StatusOK {
+			t.Fatalf("request %d was rejected with %d status", i, result.Code)
+		}
+	}
+	if result := request("made-up-20"); result.Code != http.StatusTooManyRequests {
+		t.Errorf("request over limit with new cookie got %d status", result.Code)
+	}
+}
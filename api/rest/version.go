@@ -0,0 +1,100 @@
+package rest
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/ottemo/commerce/api"
+	"github.com/ottemo/commerce/env"
+)
+
+// parseAPIVersion returns number of "v<number>" API version
+func parseAPIVersion(version string) (int, bool) {
+	if !strings.HasPrefix(version, "v") {
+		return 0, false
+	}
+
+	number, err := strconv.Atoi(version[1:])
+	if err != nil || number <= 0 || strconv.Itoa(number) != version[1:] {
+		return 0, false
+	}
+
+	return number, true
+}
+
+// Version returns route group for given API version ("v1", "v2", ...)
+//   - "v1" routes are the unversioned ones, they are served on both "/[resource]" and "/v1/[resource]" paths
+func (it *DefaultRestService) Version(version string) api.InterfaceRouteGroup {
+	if _, valid := parseAPIVersion(version); !valid {
+		_ = env.ErrorDispatch(env.ErrorNew(ConstErrorModule, ConstErrorLevel, "0d6ac436-8454-446c-b003-4d25456eb612", "invalid API version '"+version+"', routes are registered as '"+ConstDefaultAPIVersion+"' ones"))
+		version = ConstDefaultAPIVersion
+	}
+
+	return &routeGroup{service: it, version: version}
+}
+
+// resolveVersionPath returns router path for versioned request path and API version it is served by
+//   - "/v[N]/[resource]" is served by route of nearest version not above N, unversioned routes are "v1" ones
+func (it *DefaultRestService) resolveVersionPath(method string, path string) (string, string) {
+	segments := strings.SplitN(path, "/", 3)
+	if len(segments) < 3 || segments[0] != "" {
+		return path, ConstDefaultAPIVersion
+	}
+
+	number, valid := parseAPIVersion(segments[1])
+	if !valid {
+		return path, ConstDefaultAPIVersion
+	}
+
+	resource := "/" + segments[2]
+	for ; number > 1; number-- {
+		version := "v" + strconv.Itoa(number)
+		if handle, _, _ := it.Router.Lookup(method, "/"+version+resource); handle != nil {
+			return "/" + version + resource, version
+		}
+	}
+
+	return resource, ConstDefaultAPIVersion
+}
+
+// stripAPIVersion returns router path without "/v[N]" API version prefix
+func stripAPIVersion(path string) string {
+	segments := strings.SplitN(path, "/", 3)
+	if len(segments) < 3 || segments[0] != "" {
+		return path
+	}
+
+	if _, valid := parseAPIVersion(segments[1]); !valid {
+		return path
+	}
+
+	return "/" + segments[2]
+}
+
+// getPath returns router path of group resource
+func (it *routeGroup) getPath(resource string) string {
+	if it.version == ConstDefaultAPIVersion {
+		return "/" + resource
+	}
+	return "/" + it.version + "/" + resource
+}
+
+// GET registers handler for the HTTP GET verb within API version
+func (it *routeGroup) GET(resource string, handler api.FuncAPIHandler, meta ...*api.StructRouteMeta) {
+	it.service.handle("GET", it.getPath(resource), handler, meta)
+}
+
+// PUT registers handler for the HTTP PUT verb within API version
+func (it *routeGroup) PUT(resource string, handler api.FuncAPIHandler, meta ...*api.StructRouteMeta) {
+	it.service.handle("PUT", it.getPath(resource), handler, meta)
+}
+
+// POST registers handler for the HTTP POST verb within API version
+func (it *routeGroup) POST(resource string, handler api.FuncAPIHandler, meta ...*api.StructRouteMeta) {
+	it.service.handle("POST", it.getPath(resource), handler, meta)
+}
+
+// DELETE registers handler for the HTTP DELETE verb within API version
+func (it *routeGroup) DELETE(resource string, handler api.FuncAPIHandler, meta ...*api.StructRouteMeta) {
+	it.service.handle("DELETE", it.getPath(resource), handler, meta)
+}
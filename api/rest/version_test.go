@@ -0,0 +1,42 @@
+package rest
+
+import (
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/ottemo/commerce/api"
+)
+
+// TestVersionRoutes checks versioned routes registration and unversioned fallback
+func TestVersionRoutes(t *testing.T) {
+	for version, expected := range map[string]bool{"v1": true, "v12": true, "v0": false, "v01": false, "v": false, "visit": false} {
+		if _, valid := parseAPIVersion(version); valid != expected {
+			t.Errorf("'%s' version validity is %v", version, valid)
+		}
+	}
+
+	service := &DefaultRestService{Router: httprouter.New()}
+	handler := func(context api.InterfaceApplicationContext) (interface{}, error) { return "ok", nil }
+
+	service.GET("product/:productID", handler)
+	service.Version("v1").PUT("product/:productID", handler)
+	service.Version("v2").GET("product/:productID", handler)
+
+	var paths []string
+	for _, route := range service.Routes {
+		paths = append(paths, route.Method+" "+route.Path)
+	}
+	if len(paths) != 3 || paths[1] != "PUT /product/:productID" || paths[2] != "GET /v2/product/:productID" {
+		t.Errorf("unexpected routes: %v", paths)
+	}
+
+	for path, expected := range map[string]string{
+		"/product/1":    "/product/1",
+		"/v1/product/1": "/product/1",
+		"/visit":        "/visit",
+	} {
+		if result, version := service.resolveVersionPath("PUT", path); result != expected || version != ConstDefaultAPIVersion {
+			t.Errorf("'%s' resolved to '%s' of %s, expected '%s'", path, result, version, expected)
+		}
+	}
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/ottemo/commerce/api"
 	"github.com/ottemo/commerce/env"
+	"github.com/ottemo/commerce/metrics"
 )
 
 // Package global constants
@@ -26,6 +27,8 @@ const (
 // Package global variables
 var (
 	SessionService api.InterfaceSessionService
+
+	sessionsCreated = metrics.NewCounter("sessions_created_total", "Number of created sessions.")
 )
 
 // DefaultSession is a default implementer of InterfaceSession declared in
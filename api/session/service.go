@@ -4,6 +4,7 @@ import (
 	"crypto/rand"
 	"github.com/ottemo/commerce/api"
 	"github.com/ottemo/commerce/env"
+	"github.com/ottemo/commerce/metrics"
 	"os"
 	"time"
 )
@@ -14,11 +15,16 @@ const (
 
 // InitDefaultSessionService makes a new instance of DefaultSessionService
 //   - makes internal fields initialization
+//   - registers active sessions metric for new instance
 func InitDefaultSessionService() *DefaultSessionService {
 	sessionService := new(DefaultSessionService)
 	sessionService.sessions = make(map[string]*DefaultSessionContainer)
 	sessionService.storage = sessionService
 
+	metrics.NewGaugeFunc("sessions_active", "Number of sessions held in memory.", func() float64 {
+		return float64(sessionService.syncCount())
+	})
+
 	return sessionService
 }
 
@@ -278,6 +284,7 @@ func (it *DefaultSessionService) New() (api.InterfaceSession, error) {
 	if err != nil {
 		return nil, env.ErrorDispatch(err)
 	}
+	sessionsCreated.Inc()
 
 	return DefaultSession(sessionID), nil
 }
@@ -0,0 +1,104 @@
+package api
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+	"sync"
+
+	"github.com/ottemo/commerce/env"
+	"github.com/ottemo/commerce/utils"
+)
+
+// tokenSession is a non persistent InterfaceSession implementer for bearer token authorized requests
+type tokenSession struct {
+	id     string
+	values map[string]interface{}
+	mutex  sync.RWMutex
+}
+
+// GetBearerToken returns token from "Authorization: Bearer [token]" request header or blank string
+func GetBearerToken(context InterfaceApplicationContext) string {
+	authorization := strings.TrimSpace(utils.InterfaceToString(context.GetRequestSetting("Authorization")))
+	if len(authorization) > 7 && strings.EqualFold(authorization[:7], "Bearer ") {
+		return strings.TrimSpace(authorization[7:])
+	}
+	return ""
+}
+
+// IsBearerTokenValid returns true if given token is accepted by registered bearer token validator
+func IsBearerTokenValid(token string) bool {
+	if bearerTokenValidator == nil || token == "" {
+		return false
+	}
+
+	_, err := bearerTokenValidator(token)
+	return err == nil
+}
+
+// startTokenSession makes session for bearer token authorized request
+//   - session without values is returned along with error if token is not valid
+func startTokenSession(token string) (InterfaceSession, error) {
+	idBytes := make([]byte, 16)
+	if _, err := rand.Read(idBytes); err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+	session := &tokenSession{id: "token-" + hex.EncodeToString(idBytes), values: make(map[string]interface{})}
+
+	if bearerTokenValidator == nil {
+		return session, env.ErrorNew(ConstErrorModule, ConstErrorLevel, "86a7d702-4e17-4fe6-b8c7-fad7638295dd", "bearer token authorization is not supported")
+	}
+
+	values, err := bearerTokenValidator(token)
+	if err != nil {
+		return session, env.ErrorDispatch(err)
+	}
+	for key, value := range values {
+		session.values[key] = value
+	}
+
+	return session, nil
+}
+
+// GetID returns session id
+func (it *tokenSession) GetID() string {
+	return it.id
+}
+
+// Get returns session value by key
+func (it *tokenSession) Get(key string) interface{} {
+	it.mutex.RLock()
+	defer it.mutex.RUnlock()
+
+	return it.values[key]
+}
+
+// Set assigns value to session key, it lives till the end of request
+func (it *tokenSession) Set(key string, value interface{}) {
+	it.mutex.Lock()
+	defer it.mutex.Unlock()
+
+	it.values[key] = value
+}
+
+// IsEmpty checks if session contains data
+func (it *tokenSession) IsEmpty() bool {
+	it.mutex.RLock()
+	defer it.mutex.RUnlock()
+
+	return len(it.values) == 0
+}
+
+// Touch does nothing as session is not stored
+func (it *tokenSession) Touch() error {
+	return nil
+}
+
+// Close clears session values
+func (it *tokenSession) Close() error {
+	it.mutex.Lock()
+	defer it.mutex.Unlock()
+
+	it.values = make(map[string]interface{})
+	return nil
+}
@@ -17,9 +17,9 @@ func setupAPI() error {
 	service.GET("blog/posts/attributes", APIListPostAttributes)
 
 	// admin
-	service.POST("blog/post", api.IsAdminHandler(APICreateBlogPost))
-	service.PUT("blog/post/:id", api.IsAdminHandler(APIUpdateByID))
-	service.DELETE("blog/post/:id", api.IsAdminHandler(APIDeleteByID))
+	service.POST("blog/post", api.PermissionHandler(ConstPermissionWrite, APICreateBlogPost))
+	service.PUT("blog/post/:id", api.PermissionHandler(ConstPermissionWrite, APIUpdateByID))
+	service.DELETE("blog/post/:id", api.PermissionHandler(ConstPermissionWrite, APIDeleteByID))
 
 	return nil
 }
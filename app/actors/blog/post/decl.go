@@ -12,6 +12,8 @@ import (
 const (
 	ConstBlogPostCollectionName = "blog_post"
 
+	ConstPermissionWrite = "cms.write"
+
 	ConstErrorModule = "blog"
 	ConstErrorLevel  = env.ConstErrorLevelActor
 )
@@ -83,6 +83,8 @@ func (it *DefaultBlogPost) Delete() error {
 		return env.ErrorDispatch(err)
 	}
 
+	env.Event(post.ConstEventBlogPostDelete, map[string]interface{}{"id": it.GetID(), "post": it})
+
 	return nil
 }
 
@@ -119,6 +121,8 @@ func (it *DefaultBlogPost) Save() error {
 		return env.ErrorDispatch(err)
 	}
 
+	env.Event(post.ConstEventBlogPostSave, map[string]interface{}{"id": it.GetID(), "post": it})
+
 	return nil
 }
 
@@ -37,15 +37,15 @@ func setupAPI() error {
 	service.GET("category/:categoryID/mediapath/:mediaType", APIGetMediaPath)
 
 	// Admin Only
-	service.POST("category", api.IsAdminHandler(APICreateCategory))
-	service.PUT("category/:categoryID", api.IsAdminHandler(APIUpdateCategory))
-	service.DELETE("category/:categoryID", api.IsAdminHandler(APIDeleteCategory))
+	service.POST("category", api.PermissionHandler(ConstPermissionWrite, APICreateCategory))
+	service.PUT("category/:categoryID", api.PermissionHandler(ConstPermissionWrite, APIUpdateCategory))
+	service.DELETE("category/:categoryID", api.PermissionHandler(ConstPermissionWrite, APIDeleteCategory))
 
-	service.POST("category/:categoryID/product/:productID", api.IsAdminHandler(APIAddProductToCategory))
-	service.DELETE("category/:categoryID/product/:productID", api.IsAdminHandler(APIRemoveProductFromCategory))
+	service.POST("category/:categoryID/product/:productID", api.PermissionHandler(ConstPermissionWrite, APIAddProductToCategory))
+	service.DELETE("category/:categoryID/product/:productID", api.PermissionHandler(ConstPermissionWrite, APIRemoveProductFromCategory))
 
-	service.POST("category/:categoryID/media/:mediaType/:mediaName", api.IsAdminHandler(APIAddMediaForCategory))
-	service.DELETE("category/:categoryID/media/:mediaType/:mediaName", api.IsAdminHandler(APIRemoveMediaForCategory))
+	service.POST("category/:categoryID/media/:mediaType/:mediaName", api.PermissionHandler(ConstPermissionWrite, APIAddMediaForCategory))
+	service.DELETE("category/:categoryID/media/:mediaType/:mediaName", api.PermissionHandler(ConstPermissionWrite, APIRemoveMediaForCategory))
 
 	return nil
 }
@@ -92,6 +92,11 @@ func APIListCategories(context api.InterfaceApplicationContext) (interface{}, er
 		return nil, env.ErrorDispatch(err)
 	}
 
+	// next page cursor handle
+	if err := models.SetListNextCursor(context, categoryCollectionModel); err != nil {
+		_ = env.ErrorNew(ConstErrorModule, ConstErrorLevel, "64ac3efe-08c0-474c-a8d9-ccb59905a16e", err.Error())
+	}
+
 	mediaStorage, err := media.GetMediaStorage()
 	if err != nil {
 		return nil, env.ErrorDispatch(err)
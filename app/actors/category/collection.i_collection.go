@@ -109,3 +109,13 @@ func (it *DefaultCategoryCollection) ListFilterReset() error {
 func (it *DefaultCategoryCollection) ListLimit(offset int, limit int) error {
 	return it.listCollection.SetLimit(offset, limit)
 }
+
+// ListCursor switches selection to cursor based paging, blank cursor means first page
+func (it *DefaultCategoryCollection) ListCursor(cursor string) error {
+	return it.listCollection.SetCursor(cursor)
+}
+
+// ListNextCursor returns cursor to select page following the last listed one
+func (it *DefaultCategoryCollection) ListNextCursor() string {
+	return it.listCollection.GetNextCursor()
+}
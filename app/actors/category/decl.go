@@ -13,6 +13,8 @@ const (
 	ConstCollectionNameCategory                = "category"
 	ConstCollectionNameCategoryProductJunction = "category_product"
 
+	ConstPermissionWrite = "catalog.write"
+
 	ConstErrorModule = "category"
 	ConstErrorLevel  = env.ConstErrorLevelActor
 
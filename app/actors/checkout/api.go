@@ -695,5 +695,10 @@ func APISubmitCheckout(context api.InterfaceApplicationContext) (interface{}, er
 		}
 	}
 
-	return currentCheckout.Submit()
+	result, err := currentCheckout.Submit()
+	if err != nil {
+		checkoutsTotal.Inc(ConstCheckoutStatusFailed)
+	}
+
+	return result, err
 }
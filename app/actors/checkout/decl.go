@@ -6,12 +6,21 @@ import (
 	"github.com/ottemo/commerce/app/models/cart"
 	"github.com/ottemo/commerce/app/models/checkout"
 	"github.com/ottemo/commerce/env"
+	"github.com/ottemo/commerce/metrics"
 )
 
 // Package global constants
 const (
 	ConstErrorModule = "checkout"
 	ConstErrorLevel  = env.ConstErrorLevelActor
+
+	ConstCheckoutStatusSuccess = "success"
+	ConstCheckoutStatusFailed  = "failed"
+)
+
+// Package global variables
+var (
+	checkoutsTotal = metrics.NewCounter("checkouts_total", "Number of checkout submits by outcome.", "status")
 )
 
 // DefaultCheckout is a default implementer of InterfaceCheckout
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/ottemo/commerce/api"
+	"github.com/ottemo/commerce/db"
 	"github.com/ottemo/commerce/env"
 	"github.com/ottemo/commerce/utils"
 
@@ -893,8 +894,24 @@ func (it *DefaultCheckout) SubmitFinish(paymentInfo map[string]interface{}) (int
 		}
 	}
 
-	err := checkoutOrder.Save()
+	// order is saved together with cart deactivation unless it was already processed, checkout success
+	// notifications are made after commit, so they could not revert paid order
+	err := db.InTransaction(func() error {
+		if err := checkoutOrder.Save(); err != nil {
+			return env.ErrorDispatch(err)
+		}
+
+		if previousOrderStatus == order.ConstOrderStatusProcessed || previousOrderStatus == order.ConstOrderStatusCancelled {
+			return nil
+		}
+
+		return it.CheckoutSuccess(checkoutOrder, it.GetSession())
+	})
 	if err != nil {
+		// order was not stored, so the payment authorized for it is voided
+		if previousOrderStatus != order.ConstOrderStatusProcessed && previousOrderStatus != order.ConstOrderStatusCancelled {
+			it.voidPayment(checkoutOrder)
+		}
 		return nil, env.ErrorDispatch(err)
 	}
 
@@ -918,10 +935,5 @@ func (it *DefaultCheckout) SubmitFinish(paymentInfo map[string]interface{}) (int
 
 	result["items"] = orderItems
 
-	// return order in map if order has already been processed or marked completed by merchant
-	if previousOrderStatus == order.ConstOrderStatusProcessed || previousOrderStatus == order.ConstOrderStatusCancelled {
-		return result, nil
-	}
-
-	return result, it.CheckoutSuccess(checkoutOrder, it.GetSession())
+	return result, nil
 }
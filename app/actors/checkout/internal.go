@@ -2,7 +2,9 @@ package checkout
 
 import (
 	"github.com/ottemo/commerce/api"
+	"github.com/ottemo/commerce/db"
 	"github.com/ottemo/commerce/env"
+	"github.com/ottemo/commerce/utils"
 
 	"github.com/ottemo/commerce/app/actors/discount/coupon"
 	"github.com/ottemo/commerce/app/actors/discount/giftcard"
@@ -29,6 +31,8 @@ func (it *DefaultCheckout) SendOrderConfirmationEmail() error {
 }
 
 // CheckoutSuccess will save the order and clear the shopping in the session.
+//   - being called within transaction, cart deactivation is committed together with the order, while session
+//     cleanup, "checkout.success" event and confirmation email are made after commit, so they could not revert order
 func (it *DefaultCheckout) CheckoutSuccess(checkoutOrder order.InterfaceOrder, session api.InterfaceSession) error {
 	var err error
 
@@ -58,22 +62,40 @@ func (it *DefaultCheckout) CheckoutSuccess(checkoutOrder order.InterfaceOrder, s
 		}
 	}
 
-	if session != nil {
-		session.Set(cart.ConstSessionKeyCurrentCart, nil)
-		session.Set(checkout.ConstSessionKeyCurrentCheckout, nil)
-		session.Set(coupon.ConstSessionKeyCurrentRedemptions, make([]string, 0))
-		session.Set(giftcard.ConstSessionKeyAppliedGiftCardCodes, make([]string, 0))
-	}
+	db.AfterCommit(func() {
+		if session != nil {
+			session.Set(cart.ConstSessionKeyCurrentCart, nil)
+			session.Set(checkout.ConstSessionKeyCurrentCheckout, nil)
+			session.Set(coupon.ConstSessionKeyCurrentRedemptions, make([]string, 0))
+			session.Set(giftcard.ConstSessionKeyAppliedGiftCardCodes, make([]string, 0))
+		}
 
-	// sending notifications
-	//----------------------
-	eventData := map[string]interface{}{"checkout": it, "order": checkoutOrder, "session": session, "cart": currentCart}
-	env.Event("checkout.success", eventData)
+		// sending notifications
+		//----------------------
+		eventData := map[string]interface{}{"checkout": it, "order": checkoutOrder, "session": session, "cart": currentCart}
+		env.Event("checkout.success", eventData)
 
-	err = it.SendOrderConfirmationEmail()
-	if err != nil {
-		_ = env.ErrorDispatch(err)
-	}
+		checkoutsTotal.Inc(ConstCheckoutStatusSuccess)
+
+		if err := it.SendOrderConfirmationEmail(); err != nil {
+			_ = env.ErrorDispatch(err)
+		}
+	})
 
 	return nil
 }
+
+// voidPayment voids payment authorized for checkout order which was not stored and returns order items to stock
+//   - void failure is logged, so the payment could be refunded manually
+func (it *DefaultCheckout) voidPayment(checkoutOrder order.InterfaceOrder) {
+	if paymentMethod := it.GetPaymentMethod(); paymentMethod != nil {
+		paymentInfo := utils.InterfaceToMap(checkoutOrder.Get("payment_info"))
+		if _, err := paymentMethod.Void(checkoutOrder, paymentInfo); err != nil {
+			env.LogError(env.ErrorNew(ConstErrorModule, ConstErrorLevel, "b0a3c65a-33bb-46b2-877a-b27935f705c7", "unable to void payment of not stored order "+checkoutOrder.GetID()+": "+err.Error()))
+		}
+	}
+
+	if err := checkoutOrder.SetStatus(order.ConstOrderStatusNew); err != nil {
+		_ = env.ErrorDispatch(err)
+	}
+}
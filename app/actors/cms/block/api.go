@@ -18,9 +18,9 @@ func setupAPI() error {
 	service.GET("cms/block/:blockID", APIGetCMSBlock)
 
 	// Admin Only
-	service.POST("cms/block", api.IsAdminHandler(APICreateCMSBlock))
-	service.PUT("cms/block/:blockID", api.IsAdminHandler(APIUpdateCMSBlock))
-	service.DELETE("cms/block/:blockID", api.IsAdminHandler(APIDeleteCMSBlock))
+	service.POST("cms/block", api.PermissionHandler(ConstPermissionWrite, APICreateCMSBlock))
+	service.PUT("cms/block/:blockID", api.PermissionHandler(ConstPermissionWrite, APIUpdateCMSBlock))
+	service.DELETE("cms/block/:blockID", api.PermissionHandler(ConstPermissionWrite, APIDeleteCMSBlock))
 
 	return nil
 }
@@ -66,7 +66,17 @@ func APIListCMSBlocks(context api.InterfaceApplicationContext) (interface{}, err
 		_ = env.ErrorNew(ConstErrorModule, ConstErrorLevel, "49379308-09cc-4df9-87c4-757ba9a2484a", err.Error())
 	}
 
-	return cmsBlockCollectionModel.List()
+	listItems, err := cmsBlockCollectionModel.List()
+	if err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
+	// next page cursor handle
+	if err := models.SetListNextCursor(context, cmsBlockCollectionModel); err != nil {
+		_ = env.ErrorNew(ConstErrorModule, ConstErrorLevel, "40ea4d9f-5132-4704-a282-eec3ff544cc5", err.Error())
+	}
+
+	return listItems, nil
 }
 
 // APIGetCMSBlock return specified CMS block information
@@ -84,3 +84,13 @@ func (it *DefaultCMSBlockCollection) ListFilterReset() error {
 func (it *DefaultCMSBlockCollection) ListLimit(offset int, limit int) error {
 	return it.listCollection.SetLimit(offset, limit)
 }
+
+// ListCursor switches selection to cursor based paging, blank cursor means first page
+func (it *DefaultCMSBlockCollection) ListCursor(cursor string) error {
+	return it.listCollection.SetCursor(cursor)
+}
+
+// ListNextCursor returns cursor to select page following the last listed one
+func (it *DefaultCMSBlockCollection) ListNextCursor() string {
+	return it.listCollection.GetNextCursor()
+}
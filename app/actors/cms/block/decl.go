@@ -12,6 +12,8 @@ import (
 const (
 	ConstCmsBlockCollectionName = "cms_block"
 
+	ConstPermissionWrite = "cms.write"
+
 	ConstErrorModule = "cms/block"
 	ConstErrorLevel  = env.ConstErrorLevelActor
 )
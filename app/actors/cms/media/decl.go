@@ -9,6 +9,8 @@ import (
 
 // Package global constants
 const (
+	ConstPermissionWrite = "cms.write"
+
 	ConstErrorModule = "cms/media"
 	ConstErrorLevel  = env.ConstErrorLevelActor
 
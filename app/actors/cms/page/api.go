@@ -16,9 +16,9 @@ func setupAPI() error {
 	service.GET("cms/page/:pageID", APIGetCMSPage)
 
 	// Admin Only
-	service.POST("cms/page", api.IsAdminHandler(APICreateCMSPage))
-	service.PUT("cms/page/:pageID", api.IsAdminHandler(APIUpdateCMSPage))
-	service.DELETE("cms/page/:pageID", api.IsAdminHandler(APIDeleteCMSPage))
+	service.POST("cms/page", api.PermissionHandler(ConstPermissionWrite, APICreateCMSPage))
+	service.PUT("cms/page/:pageID", api.PermissionHandler(ConstPermissionWrite, APIUpdateCMSPage))
+	service.DELETE("cms/page/:pageID", api.PermissionHandler(ConstPermissionWrite, APIDeleteCMSPage))
 
 	return nil
 }
@@ -70,7 +70,17 @@ func APIListCMSPages(context api.InterfaceApplicationContext) (interface{}, erro
 		_ = env.ErrorNew(ConstErrorModule, ConstErrorLevel, "0bfd8539-0c2b-4914-a379-ee5e92ec94ed", err.Error())
 	}
 
-	return cmsPageCollectionModel.List()
+	listItems, err := cmsPageCollectionModel.List()
+	if err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
+	// next page cursor handle
+	if err := models.SetListNextCursor(context, cmsPageCollectionModel); err != nil {
+		_ = env.ErrorNew(ConstErrorModule, ConstErrorLevel, "8323d087-a98a-4d60-8bbb-2fb9f6a58ae3", err.Error())
+	}
+
+	return listItems, nil
 }
 
 // APIGetCMSPage return specified CMS page information
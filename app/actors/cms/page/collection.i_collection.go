@@ -84,3 +84,13 @@ func (it *DefaultCMSPageCollection) ListFilterReset() error {
 func (it *DefaultCMSPageCollection) ListLimit(offset int, limit int) error {
 	return it.listCollection.SetLimit(offset, limit)
 }
+
+// ListCursor switches selection to cursor based paging, blank cursor means first page
+func (it *DefaultCMSPageCollection) ListCursor(cursor string) error {
+	return it.listCollection.SetCursor(cursor)
+}
+
+// ListNextCursor returns cursor to select page following the last listed one
+func (it *DefaultCMSPageCollection) ListNextCursor() string {
+	return it.listCollection.GetNextCursor()
+}
@@ -12,6 +12,8 @@ import (
 const (
 	ConstCmsPageCollectionName = "cms_page"
 
+	ConstPermissionWrite = "cms.write"
+
 	ConstErrorModule = "cms/page"
 	ConstErrorLevel  = env.ConstErrorLevelActor
 )
@@ -6,6 +6,8 @@ import (
 	"github.com/ottemo/commerce/db"
 	"github.com/ottemo/commerce/env"
 	"github.com/ottemo/commerce/utils"
+
+	"github.com/ottemo/commerce/app/models/cms"
 )
 
 // GetID returns id for cms block
@@ -59,6 +61,8 @@ func (it *DefaultCMSPage) Delete() error {
 		return env.ErrorDispatch(err)
 	}
 
+	env.Event(cms.ConstEventCMSPageDelete, map[string]interface{}{"id": it.GetID(), "page": it})
+
 	return env.ErrorDispatch(err)
 }
 
@@ -99,5 +103,7 @@ func (it *DefaultCMSPage) Save() error {
 		_ = env.ErrorNew(ConstErrorModule, ConstErrorLevel, "46908cea-a536-481d-8838-3af4a465973c", err.Error())
 	}
 
+	env.Event(cms.ConstEventCMSPageSave, map[string]interface{}{"id": it.GetID(), "page": it})
+
 	return nil
 }
@@ -25,15 +25,15 @@ func setupAPI() error {
 	service.DELETE("cart/coupons/:code", Remove)
 
 	// Admin Only
-	service.GET("coupons", api.IsAdminHandler(List))
-	service.POST("coupons", api.IsAdminHandler(Create))
-	service.GET("csv/coupons", api.IsAdminHandler(DownloadCSV))
-	service.POST("csv/coupons", api.IsAdminHandler(
+	service.GET("coupons", api.PermissionHandler(ConstPermissionRead, List))
+	service.POST("coupons", api.PermissionHandler(ConstPermissionWrite, Create))
+	service.GET("csv/coupons", api.PermissionHandler(ConstPermissionRead, DownloadCSV))
+	service.POST("csv/coupons", api.PermissionHandler(ConstPermissionWrite,
 		impex.ImportStartHandler(
 			api.AsyncHandler(UploadCSV, impex.ImportResultHandler))))
-	service.GET("coupons/:id", api.IsAdminHandler(GetByID))
-	service.PUT("coupons/:id", api.IsAdminHandler(UpdateByID))
-	service.DELETE("coupons/:id", api.IsAdminHandler(DeleteByID))
+	service.GET("coupons/:id", api.PermissionHandler(ConstPermissionRead, GetByID))
+	service.PUT("coupons/:id", api.PermissionHandler(ConstPermissionWrite, UpdateByID))
+	service.DELETE("coupons/:id", api.PermissionHandler(ConstPermissionWrite, DeleteByID))
 
 	return nil
 }
@@ -14,6 +14,9 @@ const (
 	ConstConfigPathDiscounts             = "general.discounts"
 	ConstConfigPathDiscountApplyPriority = "general.discounts.discount_apply_priority"
 
+	ConstPermissionRead  = "discounts.read"
+	ConstPermissionWrite = "discounts.write"
+
 	ConstErrorModule = "coupon"
 	ConstErrorLevel  = env.ConstErrorLevelActor
 )
@@ -26,8 +26,8 @@ func setupAPI() error {
 	service.DELETE("cart/giftcards/:giftcode", Remove)
 
 	// Admin Only
-	service.GET("giftcard/:id/history", api.IsAdminHandler(GetHistory))
-	service.POST("giftcard", api.IsAdminHandler(createFromAdmin))
+	service.GET("giftcard/:id/history", api.PermissionHandler(ConstPermissionRead, GetHistory))
+	service.POST("giftcard", api.PermissionHandler(ConstPermissionWrite, createFromAdmin))
 
 	return nil
 }
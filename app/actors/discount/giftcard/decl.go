@@ -19,6 +19,9 @@ const (
 	ConstConfigPathGiftCardAdminBuyerName       = "general.discounts.giftCard_admin_buyer_name"
 	ConstConfigPathGiftCardAdminBuyerEmail       = "general.discounts.giftCard_admin_buyer_email"
 
+	ConstPermissionRead  = "discounts.read"
+	ConstPermissionWrite = "discounts.write"
+
 	ConstErrorModule = "giftcard"
 	ConstErrorLevel  = env.ConstErrorLevelActor
 
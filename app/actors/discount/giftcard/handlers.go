@@ -10,7 +10,7 @@ import (
 	"github.com/ottemo/commerce/utils"
 )
 
-// orderProceedHandler is fired during order creation to check the cart for
+// orderProceedHandler is fired after order proceed commit to check the cart for
 // gift cards, if a card is present add it to the table gift_card.  Next step is
 // inspect the checkout object for applied discounts and record usage amounts in db
 func orderProceedHandler(event string, eventData map[string]interface{}) bool {
@@ -43,61 +43,67 @@ func orderProceedHandler(event string, eventData map[string]interface{}) bool {
 	orderAppliedDiscounts := orderProceed.GetDiscounts()
 
 	// check used gift card's to update amount or if this procedure was already done
-	if len(orderGiftCardApplying) == 0 && len(orderAppliedDiscounts) > 0 {
+	// gift card debit is made in own transaction, so a failure here could not revert the order
+	err = db.InTransaction(func() error {
+		if len(orderGiftCardApplying) == 0 && len(orderAppliedDiscounts) > 0 {
 
-		for _, orderAppliedDiscount := range orderAppliedDiscounts {
+			for _, orderAppliedDiscount := range orderAppliedDiscounts {
 
-			if err := giftCardCollection.ClearFilters(); err != nil {
-				_ = env.ErrorDispatch(err)
-			}
-			if err := giftCardCollection.AddFilter("code", "=", orderAppliedDiscount.Code); err != nil {
-				_ = env.ErrorDispatch(err)
-			}
+				if err := giftCardCollection.ClearFilters(); err != nil {
+					_ = env.ErrorDispatch(err)
+				}
+				if err := giftCardCollection.AddFilter("code", "=", orderAppliedDiscount.Code); err != nil {
+					_ = env.ErrorDispatch(err)
+				}
 
-			records, err := giftCardCollection.Load()
-			if err != nil {
-				_ = env.ErrorDispatch(err)
-				return false
-			}
+				records, err := giftCardCollection.Load()
+				if err != nil {
+					return env.ErrorDispatch(err)
+				}
 
-			// change amount, status and orders_used information for gift card
-			if len(records) > 0 {
-				giftCard := records[0]
+				// change amount, status and orders_used information for gift card
+				if len(records) > 0 {
+					giftCard := records[0]
 
-				// calculate the amount that will be on cart after apply and add order used record with orderID and amount
-				giftCardAmountAfterApply := utils.InterfaceToFloat64(giftCard["amount"]) + orderAppliedDiscount.Amount
+					// calculate the amount that will be on cart after apply and add order used record with orderID and amount
+					giftCardAmountAfterApply := utils.InterfaceToFloat64(giftCard["amount"]) + orderAppliedDiscount.Amount
 
-				ordersGiftCardUsedMap := utils.InterfaceToMap(giftCard["orders_used"])
-				ordersGiftCardUsedMap[orderID] = orderAppliedDiscount.Amount
+					ordersGiftCardUsedMap := utils.InterfaceToMap(giftCard["orders_used"])
+					ordersGiftCardUsedMap[orderID] = orderAppliedDiscount.Amount
 
-				giftCard["amount"] = giftCardAmountAfterApply
-				giftCard["status"] = ConstGiftCardStatusApplied
+					giftCard["amount"] = giftCardAmountAfterApply
+					giftCard["status"] = ConstGiftCardStatusApplied
 
-				if giftCardAmountAfterApply < 0 {
-					env.LogError(env.ErrorNew(ConstErrorModule, ConstErrorLevel, "987929ab-8d20-4413-a0aa-bb4baae02aeb", "Discount code, "+orderAppliedDiscount.Code+" has been over credited."))
-					giftCard["amount"] = 0
-					giftCard["status"] = ConstGiftCardStatusOverCredited
-				}
+					if giftCardAmountAfterApply < 0 {
+						env.LogError(env.ErrorNew(ConstErrorModule, ConstErrorLevel, "987929ab-8d20-4413-a0aa-bb4baae02aeb", "Discount code, "+orderAppliedDiscount.Code+" has been over credited."))
+						giftCard["amount"] = 0
+						giftCard["status"] = ConstGiftCardStatusOverCredited
+					}
 
-				if giftCardAmountAfterApply == 0 {
-					giftCard["status"] = ConstGiftCardStatusUsed
-				}
+					if giftCardAmountAfterApply == 0 {
+						giftCard["status"] = ConstGiftCardStatusUsed
+					}
 
-				giftCard["orders_used"] = ordersGiftCardUsedMap
+					giftCard["orders_used"] = ordersGiftCardUsedMap
 
-				_, err := giftCardCollection.Save(giftCard)
-				if err != nil {
-					_ = env.ErrorDispatch(err)
-					continue
+					_, err := giftCardCollection.Save(giftCard)
+					if err != nil {
+						return env.ErrorDispatch(err)
+					}
 				}
 			}
 		}
+		return nil
+	})
+	if err != nil {
+		return false
 	}
 
 	return true
 }
 
-// orderRollbackHandler inspects the order for presence of gift cards in the apply state
+// orderRollbackHandler inspects the order for presence of gift cards in the apply state, it is fired after order
+// rollback commit
 // - refill used amount on gift card and change status to 'refilled'
 func orderRollbackHandler(event string, eventData map[string]interface{}) bool {
 
@@ -127,27 +133,32 @@ func orderRollbackHandler(event string, eventData map[string]interface{}) bool {
 	}
 
 	// check all records from gift_cards and restoring their balance
-	for _, record := range records {
+	err = db.InTransaction(func() error {
+		for _, record := range records {
 
-		ordersUsage := utils.InterfaceToMap(record["orders_used"])
+			ordersUsage := utils.InterfaceToMap(record["orders_used"])
 
-		if refillAmount, present := ordersUsage[orderID]; present {
+			if refillAmount, present := ordersUsage[orderID]; present {
 
-			newAmount := utils.InterfaceToFloat64(record["amount"]) - utils.InterfaceToFloat64(refillAmount)
+				newAmount := utils.InterfaceToFloat64(record["amount"]) - utils.InterfaceToFloat64(refillAmount)
 
-			// refill gift card amount, change status and orders_used information
-			delete(ordersUsage, orderID)
+				// refill gift card amount, change status and orders_used information
+				delete(ordersUsage, orderID)
 
-			record["status"] = ConstGiftCardStatusRefilled
-			record["orders_used"] = ordersUsage
-			record["amount"] = newAmount
+				record["status"] = ConstGiftCardStatusRefilled
+				record["orders_used"] = ordersUsage
+				record["amount"] = newAmount
 
-			_, err := giftCardCollection.Save(record)
-			if err != nil {
-				_ = env.ErrorDispatch(err)
-				return false
+				_, err := giftCardCollection.Save(record)
+				if err != nil {
+					return env.ErrorDispatch(err)
+				}
 			}
 		}
+		return nil
+	})
+	if err != nil {
+		return false
 	}
 
 	return true
@@ -179,118 +190,127 @@ func checkoutSuccessHandler(event string, eventData map[string]interface{}) bool
 	var giftCardsToSendImmediately []string
 
 	// check cart for gift card's and save in table if they present
-	for _, cartItem := range cartProducts {
-		giftCardSku := cartItem.GetSku()
-
-		if strings.Contains(giftCardSku, giftCardSkuElement) {
-
-			recipientEmail := utils.InterfaceToString(orderProceed.Get("customer_email"))
-			recipientName := orderProceed.Get("customer_name")
-			giftCardAmount := float64(0)
-			deliveryDate := time.Now()
-			currentTime := time.Now()
-			customMessage := ""
-
-			// split item SKU with config gift card SKU value and sign "-" take last element
-			giftCardSplitedSKU := strings.Split(giftCardSku, giftCardSkuElement)
-			giftCardSplitedSKU = strings.Split(giftCardSplitedSKU[len(giftCardSplitedSKU)-1], "-")
-
-			// read recipient options
-			productOptions := cartItem.GetOptions()
-			if recipientEmailOption := utils.GetFirstMapValue(productOptions, "recipient_email", "Email", "recipient_mailbox"); recipientEmailOption != nil {
-
-				recipientEmailOption := utils.InterfaceToMap(recipientEmailOption)
-				emailValue, present := recipientEmailOption["value"]
-
-				if present {
-					email := utils.InterfaceToString(emailValue)
-					if utils.ValidEmailAddress(email) && email != "" {
-						recipientEmail = utils.InterfaceToString(emailValue)
+	// ("checkout.success" is emitted after order commit, so gift cards are created in own transaction)
+	err = db.InTransaction(func() error {
+		for _, cartItem := range cartProducts {
+			giftCardSku := cartItem.GetSku()
+
+			if strings.Contains(giftCardSku, giftCardSkuElement) {
+
+				recipientEmail := utils.InterfaceToString(orderProceed.Get("customer_email"))
+				recipientName := orderProceed.Get("customer_name")
+				giftCardAmount := float64(0)
+				deliveryDate := time.Now()
+				currentTime := time.Now()
+				customMessage := ""
+
+				// split item SKU with config gift card SKU value and sign "-" take last element
+				giftCardSplitedSKU := strings.Split(giftCardSku, giftCardSkuElement)
+				giftCardSplitedSKU = strings.Split(giftCardSplitedSKU[len(giftCardSplitedSKU)-1], "-")
+
+				// read recipient options
+				productOptions := cartItem.GetOptions()
+				if recipientEmailOption := utils.GetFirstMapValue(productOptions, "recipient_email", "Email", "recipient_mailbox"); recipientEmailOption != nil {
+
+					recipientEmailOption := utils.InterfaceToMap(recipientEmailOption)
+					emailValue, present := recipientEmailOption["value"]
+
+					if present {
+						email := utils.InterfaceToString(emailValue)
+						if utils.ValidEmailAddress(email) && email != "" {
+							recipientEmail = utils.InterfaceToString(emailValue)
+						}
 					}
 				}
-			}
 
-			if recipientNameOption := utils.GetFirstMapValue(productOptions, "recipient_name", "Recipient Name", "Name", "name"); recipientNameOption != nil {
+				if recipientNameOption := utils.GetFirstMapValue(productOptions, "recipient_name", "Recipient Name", "Name", "name"); recipientNameOption != nil {
 
-				recipientNameOption := utils.InterfaceToMap(recipientNameOption)
-				nameValue, present := recipientNameOption["value"]
+					recipientNameOption := utils.InterfaceToMap(recipientNameOption)
+					nameValue, present := recipientNameOption["value"]
 
-				if present && utils.InterfaceToString(nameValue) != "" {
-					recipientName = utils.InterfaceToString(nameValue)
+					if present && utils.InterfaceToString(nameValue) != "" {
+						recipientName = utils.InterfaceToString(nameValue)
+					}
 				}
-			}
 
-			if customMessageOption := utils.GetFirstMapValue(productOptions, "Message", "Gift Message", "Note", "message"); customMessageOption != nil {
-				customMessageOption := utils.InterfaceToMap(customMessageOption)
-				messageValue, present := customMessageOption["value"]
-				if present {
-					customMessage = utils.InterfaceToString(messageValue)
+				if customMessageOption := utils.GetFirstMapValue(productOptions, "Message", "Gift Message", "Note", "message"); customMessageOption != nil {
+					customMessageOption := utils.InterfaceToMap(customMessageOption)
+					messageValue, present := customMessageOption["value"]
+					if present {
+						customMessage = utils.InterfaceToString(messageValue)
+					}
 				}
-			}
 
-			if deliveryDateOption := utils.GetFirstMapValue(productOptions, "Date", "Delivery Date", "send_date", "Send Date", "date"); deliveryDateOption != nil {
-				deliveryDateOption := utils.InterfaceToMap(deliveryDateOption)
-				dateValue, present := deliveryDateOption["value"]
-				if present && !utils.IsZeroTime(utils.InterfaceToTime(dateValue)) {
-					deliveryDate = utils.InterfaceToTime(dateValue)
+				if deliveryDateOption := utils.GetFirstMapValue(productOptions, "Date", "Delivery Date", "send_date", "Send Date", "date"); deliveryDateOption != nil {
+					deliveryDateOption := utils.InterfaceToMap(deliveryDateOption)
+					dateValue, present := deliveryDateOption["value"]
+					if present && !utils.IsZeroTime(utils.InterfaceToTime(dateValue)) {
+						deliveryDate = utils.InterfaceToTime(dateValue)
+					}
 				}
-			}
 
-			// check is result value is number if not take amount as a price of item
-			if giftCardAmount = utils.InterfaceToFloat64(giftCardSplitedSKU[len(giftCardSplitedSKU)-1]); giftCardAmount <= 0 {
-				giftCardAmount = cartItem.GetPrice()
-			}
+				// check is result value is number if not take amount as a price of item
+				if giftCardAmount = utils.InterfaceToFloat64(giftCardSplitedSKU[len(giftCardSplitedSKU)-1]); giftCardAmount <= 0 {
+					giftCardAmount = cartItem.GetPrice()
+				}
 
-			for i := 0; i < cartItem.GetQty(); i++ {
+				for i := 0; i < cartItem.GetQty(); i++ {
 
-				// generate unique code by unix nano time
-				giftCardUniqueCode := utils.InterfaceToString(time.Now().UnixNano())
+					// generate unique code by unix nano time
+					giftCardUniqueCode := utils.InterfaceToString(time.Now().UnixNano())
 
-				giftCard := make(map[string]interface{})
+					giftCard := make(map[string]interface{})
 
-				giftCard["code"] = giftCardUniqueCode
-				giftCard["sku"] = giftCardSku
+					giftCard["code"] = giftCardUniqueCode
+					giftCard["sku"] = giftCardSku
 
-				giftCard["amount"] = giftCardAmount
+					giftCard["amount"] = giftCardAmount
 
-				giftCard["order_id"] = orderID
-				giftCard["visitor_id"] = visitorID
+					giftCard["order_id"] = orderID
+					giftCard["visitor_id"] = visitorID
 
-				giftCard["status"] = ConstGiftCardStatusNew
-				giftCard["orders_used"] = make(map[string]float64)
+					giftCard["status"] = ConstGiftCardStatusNew
+					giftCard["orders_used"] = make(map[string]float64)
 
-				giftCard["name"] = recipientName
-				giftCard["message"] = customMessage
+					giftCard["name"] = recipientName
+					giftCard["message"] = customMessage
 
-				giftCard["recipient_mailbox"] = recipientEmail
-				giftCard["delivery_date"] = deliveryDate
+					giftCard["recipient_mailbox"] = recipientEmail
+					giftCard["delivery_date"] = deliveryDate
 
-				giftCard["created_at"] = time.Now()
+					giftCard["created_at"] = time.Now()
 
-				giftCardID, err := giftCardCollection.Save(giftCard)
-				if err != nil {
-					_ = env.ErrorDispatch(err)
-					return false
-				}
-				if deliveryDate.Truncate(time.Hour).Before(currentTime) {
-					giftCardsToSendImmediately = append(giftCardsToSendImmediately, giftCardID)
+					giftCardID, err := giftCardCollection.Save(giftCard)
+					if err != nil {
+						return env.ErrorDispatch(err)
+					}
+					if deliveryDate.Truncate(time.Hour).Before(currentTime) {
+						giftCardsToSendImmediately = append(giftCardsToSendImmediately, giftCardID)
+					}
 				}
 			}
 		}
+		return nil
+	})
+	if err != nil {
+		return false
 	}
 
 	// run SendTask task to send immediately if delivery_date is today's date
+	// (after the gift cards transaction commit, so the task could load created gift cards)
 	if len(giftCardsToSendImmediately) > 0 {
 		params := map[string]interface{}{
 			"giftCards":          giftCardsToSendImmediately,
 			"ignoreDeliveryDate": true,
 		}
 
-		go func (params map[string]interface{}){
-			if err := SendTask(params); err != nil {
-				_ = env.ErrorNew(ConstErrorModule, ConstErrorLevel, "5786c5de-4ba3-4e3f-981c-58101331a7c6", err.Error())
-			}
-		}(params)
+		db.AfterCommit(func() {
+			go func(params map[string]interface{}) {
+				if err := SendTask(params); err != nil {
+					_ = env.ErrorNew(ConstErrorModule, ConstErrorLevel, "5786c5de-4ba3-4e3f-981c-58101331a7c6", err.Error())
+				}
+			}(params)
+		})
 	}
 
 	return true
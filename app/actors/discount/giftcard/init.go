@@ -4,6 +4,7 @@ import (
 	"github.com/ottemo/commerce/api"
 	"github.com/ottemo/commerce/app"
 	"github.com/ottemo/commerce/app/models/checkout"
+	"github.com/ottemo/commerce/app/models/order"
 	"github.com/ottemo/commerce/db"
 	"github.com/ottemo/commerce/env"
 )
@@ -86,8 +87,8 @@ func setupDB() error {
 func onAppStart() error {
 
 	env.EventRegisterListener("checkout.success", checkoutSuccessHandler)
-	env.EventRegisterListener("order.proceed", orderProceedHandler)
-	env.EventRegisterListener("order.rollback", orderRollbackHandler)
+	env.EventRegisterListener(order.ConstEventOrderProceed, orderProceedHandler)
+	env.EventRegisterListener(order.ConstEventOrderRollback, orderRollbackHandler)
 
 	if scheduler := env.GetScheduler(); scheduler != nil {
 		if err := scheduler.RegisterTask("sendGiftCards", SendTask); err != nil {
@@ -16,12 +16,12 @@ func setupAPI() error {
 	// Admin Only
 	//-----------
 
-	service.GET("saleprices", api.IsAdminHandler(listAllScheduled))
+	service.GET("saleprices", api.PermissionHandler(ConstPermissionRead, listAllScheduled))
 
-	service.POST("saleprice", api.IsAdminHandler(createSalePrice))
-	service.GET("saleprice/:id", api.IsAdminHandler(priceByID))
-	service.PUT("saleprice/:id", api.IsAdminHandler(updateByID))
-	service.DELETE("saleprice/:id", api.IsAdminHandler(deleteByID))
+	service.POST("saleprice", api.PermissionHandler(ConstPermissionWrite, createSalePrice))
+	service.GET("saleprice/:id", api.PermissionHandler(ConstPermissionRead, priceByID))
+	service.PUT("saleprice/:id", api.PermissionHandler(ConstPermissionWrite, updateByID))
+	service.DELETE("saleprice/:id", api.PermissionHandler(ConstPermissionWrite, deleteByID))
 
 	return nil
 }
@@ -58,6 +58,11 @@ func listAllScheduled(context api.InterfaceApplicationContext) (interface{}, err
 		return nil, env.ErrorDispatch(err)
 	}
 
+	// next page cursor handle
+	if err := models.SetListNextCursor(context, salePriceCollectionModel); err != nil {
+		_ = env.ErrorNew(ConstErrorModule, ConstErrorLevel, "6a7a30e5-0ef0-4d5d-a0cf-9db3cf3f1a44", err.Error())
+	}
+
 	return listItems, nil
 }
 
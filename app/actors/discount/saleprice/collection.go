@@ -136,6 +136,16 @@ func (it *DefaultSalePriceCollection) ListLimit(offset int, limit int) error {
 	return it.listCollection.SetLimit(offset, limit)
 }
 
+// ListCursor switches selection to cursor based paging, blank cursor means first page
+func (it *DefaultSalePriceCollection) ListCursor(cursor string) error {
+	return it.listCollection.SetCursor(cursor)
+}
+
+// ListNextCursor returns cursor to select page following the last listed one
+func (it *DefaultSalePriceCollection) ListNextCursor() string {
+	return it.listCollection.GetNextCursor()
+}
+
 // ---------------------------------------------------------------------------------------------------------------------
 //  implementation (package "github.com/ottemo/commerce/app/models/interfaces")
 // ---------------------------------------------------------------------------------------------------------------------
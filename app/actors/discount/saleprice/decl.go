@@ -19,6 +19,9 @@ const (
 	ConstConfigPathEnabled                = "general.sale_price.enabled"
 	ConstConfigPathSalePriceApplyPriority = "general.sale_price.priority"
 
+	ConstPermissionRead  = "discounts.read"
+	ConstPermissionWrite = "discounts.write"
+
 	ConstErrorModule = "saleprice"
 	ConstErrorLevel  = env.ConstErrorLevelActor
 )
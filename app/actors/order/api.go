@@ -3,6 +3,7 @@ package order
 import (
 	"encoding/csv"
 	"math"
+	"net/http"
 	"regexp"
 	"strings"
 	"time"
@@ -11,6 +12,7 @@ import (
 	"github.com/ottemo/commerce/app/models"
 	"github.com/ottemo/commerce/app/models/order"
 	"github.com/ottemo/commerce/app/models/visitor"
+	"github.com/ottemo/commerce/db"
 	"github.com/ottemo/commerce/env"
 	"github.com/ottemo/commerce/utils"
 )
@@ -25,17 +27,20 @@ func setupAPI() error {
 	service := api.GetRestService()
 
 	// Admin
-	service.GET("orders/attributes", api.IsAdminHandler(APIListOrderAttributes))
-	service.GET("orders", api.IsAdminHandler(APIListOrders))
-	service.POST("orders/exportToCSV", api.IsAdminHandler(APIExportOrders))
-	service.POST("orders/setStatus", api.IsAdminHandler(APIChangeOrderStatus))
-
-	service.GET("order/:orderID", api.IsAdminHandler(APIGetOrder))
-	service.PUT("order/:orderID", api.IsAdminHandler(APIUpdateOrder))
-	service.DELETE("order/:orderID", api.IsAdminHandler(APIDeleteOrder))
-	service.GET("order/:orderID/emailShipStatus", api.IsAdminHandler(APISendShipStatusEmail))
-	service.GET("order/:orderID/emailOrderConfirmation", api.IsAdminHandler(APISendOrderConfirmationEmail))
-	service.POST("order/:orderID/emailTrackingCode", api.IsAdminHandler(APIUpdateTrackingInfoAndSendEmail))
+	service.GET("orders/attributes", api.PermissionHandler(ConstPermissionRead, APIListOrderAttributes))
+	service.GET("orders", api.PermissionHandler(ConstPermissionRead, APIListOrders))
+	service.POST("orders/exportToCSV", api.PermissionHandler(ConstPermissionRead, APIExportOrders))
+	service.POST("orders/setStatus", api.PermissionHandler(ConstPermissionStatus, APIChangeOrderStatus))
+
+	service.GET("order/:orderID", api.PermissionHandler(ConstPermissionRead, APIGetOrder))
+	service.PUT("order/:orderID", api.PermissionHandler(ConstPermissionWrite, APIUpdateOrder))
+	service.DELETE("order/:orderID", api.PermissionHandler(ConstPermissionWrite, APIDeleteOrder))
+	service.GET("order/:orderID/emailShipStatus", api.PermissionHandler(ConstPermissionWrite, APISendShipStatusEmail))
+	service.GET("order/:orderID/emailOrderConfirmation", api.PermissionHandler(ConstPermissionWrite, APISendOrderConfirmationEmail))
+	service.POST("order/:orderID/emailTrackingCode", api.PermissionHandler(ConstPermissionWrite, APIUpdateTrackingInfoAndSendEmail))
+
+	// checked within handlers
+	api.RegisterPermission(ConstPermissionRefund)
 
 	// Public
 	service.GET("visit/orders", APIGetVisitorOrders)
@@ -116,7 +121,17 @@ func APIListOrders(context api.InterfaceApplicationContext) (interface{}, error)
 		_ = env.ErrorNew(ConstErrorModule, ConstErrorLevel, "5e81c9be-1df8-436b-8c22-b9b29949dd1b", err.Error())
 	}
 
-	return orderCollectionModel.List()
+	listItems, err := orderCollectionModel.List()
+	if err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
+	// next page cursor handle
+	if err := models.SetListNextCursor(context, orderCollectionModel); err != nil {
+		_ = env.ErrorNew(ConstErrorModule, ConstErrorLevel, "79ab0930-6a6b-4f04-8ee7-de246e0c6e18", err.Error())
+	}
+
+	return listItems, nil
 }
 
 // APIGetOrder return specified purchase order information
@@ -173,13 +188,26 @@ func APIUpdateOrder(context api.InterfaceApplicationContext) (interface{}, error
 		return nil, env.ErrorDispatch(err)
 	}
 
+	if status, present := requestData["status"]; present {
+		if err := validateStatusPermission(context, utils.InterfaceToString(status)); err != nil {
+			return nil, env.ErrorDispatch(err)
+		}
+	}
+
 	for attribute, value := range requestData {
 		if err := orderModel.Set(attribute, value); err != nil {
 			_ = env.ErrorNew(ConstErrorModule, ConstErrorLevel, "b563c756-92a7-4d08-b85b-d365c713cd69", err.Error())
 		}
 	}
 
-	if err := orderModel.Save(); err != nil {
+	if err := orderModel.Save(); db.IsVersionConflict(err) {
+		// order was changed by someone else, so current state returned to be reviewed
+		context.SetResponseStatus(http.StatusConflict)
+		if currentOrder, loadErr := order.LoadOrderByID(orderModel.GetID()); loadErr == nil {
+			return currentOrder.ToHashMap(), err
+		}
+		return nil, err
+	} else if err != nil {
 		_ = env.ErrorNew(ConstErrorModule, ConstErrorLevel, "9a5c8363-baad-4060-a287-4d88b46878a6", err.Error())
 	}
 
@@ -273,8 +301,16 @@ func APIGetVisitorOrders(context api.InterfaceApplicationContext) (interface{},
 	}
 
 	result, err := orderCollection.List()
+	if err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
+	// next page cursor handle
+	if err := models.SetListNextCursor(context, orderCollection); err != nil {
+		_ = env.ErrorNew(ConstErrorModule, ConstErrorLevel, "3ab74177-e777-4c8e-b31a-8e96c78baff2", err.Error())
+	}
 
-	return result, env.ErrorDispatch(err)
+	return result, nil
 }
 
 // APISendOrderConfirmationEmail will send out an order confirmation email to the visitor specficied in the orderID
@@ -483,6 +519,10 @@ func APIChangeOrderStatus(context api.InterfaceApplicationContext) (interface{},
 	}
 	status := utils.InterfaceToString(statusValue)
 
+	if err := validateStatusPermission(context, status); err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
 	orderIDsValue, present := requestData["order_id"]
 	if !present {
 		context.SetResponseStatusBadRequest()
@@ -498,6 +538,18 @@ func APIChangeOrderStatus(context api.InterfaceApplicationContext) (interface{},
 	return "ok", nil
 }
 
+// validateStatusPermission checks session rights to set given order status
+//   - cancellation returns order to customer, so it requires refund permission
+func validateStatusPermission(context api.InterfaceApplicationContext, status string) error {
+	if status == order.ConstOrderStatusCancelled {
+		if err := api.ValidatePermission(context, ConstPermissionRefund); err != nil {
+			context.SetResponseStatusForbidden()
+			return env.ErrorDispatch(err)
+		}
+	}
+	return nil
+}
+
 // change the order status and persist new status to the db
 //    - status is the new order status to be saved
 func updateOrderStatus(orderIDs []interface{}, status string) error {
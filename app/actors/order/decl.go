@@ -113,6 +113,11 @@ const (
 
 	ConstConfigPathLastIncrementID = "internal.order.increment_id"
 
+	ConstPermissionRead   = "orders.read"
+	ConstPermissionWrite  = "orders.write"
+	ConstPermissionStatus = "orders.status"
+	ConstPermissionRefund = "orders.refund"
+
 	ConstErrorModule = "order"
 	ConstErrorLevel  = env.ConstErrorLevelActor
 )
@@ -141,7 +146,8 @@ type DefaultOrderItem struct {
 
 // DefaultOrder is a default implementer of InterfaceOrder
 type DefaultOrder struct {
-	id string
+	id      string
+	version int
 
 	IncrementID string
 	Status      string
@@ -130,6 +130,9 @@ func setupDB() error {
 		if err := collection.AddColumn("notes", db.TypeArrayOf(db.ConstTypeVarchar), false); err != nil {
 			_ = env.ErrorNew(ConstErrorModule, ConstErrorLevel, "f4484325-2b27-4551-bf8e-f58d6a0c6cd7", err.Error())
 		}
+		if err := db.AddVersionColumn(collection); err != nil {
+			_ = env.ErrorNew(ConstErrorModule, ConstErrorLevel, "f7c6fe25-eb12-4abe-8d0b-c9b4e36246b6", err.Error())
+		}
 
 		collection, err = dbEngine.GetCollection(ConstCollectionNameOrderItems)
 		if err != nil {
@@ -88,3 +88,13 @@ func (it *DefaultOrderItemCollection) ListFilterReset() error {
 func (it *DefaultOrderItemCollection) ListLimit(offset int, limit int) error {
 	return it.listCollection.SetLimit(offset, limit)
 }
+
+// ListCursor switches selection to cursor based paging, blank cursor means first page
+func (it *DefaultOrderItemCollection) ListCursor(cursor string) error {
+	return it.listCollection.SetCursor(cursor)
+}
+
+// ListNextCursor returns cursor to select page following the last listed one
+func (it *DefaultOrderItemCollection) ListNextCursor() string {
+	return it.listCollection.GetNextCursor()
+}
@@ -17,6 +17,9 @@ func (it *DefaultOrder) Get(attribute string) interface{} {
 	case "_id", "id":
 		return it.id
 
+	case db.ConstVersionColumn:
+		return it.version
+
 	case "increment_id":
 		return it.IncrementID
 
@@ -119,6 +122,9 @@ func (it *DefaultOrder) Set(attribute string, value interface{}) error {
 		_ = env.ErrorNew(ConstErrorModule, ConstErrorLevel, "751bc438-198f-4997-8a84-042e763f2f25", err.Error())
 	}
 
+	case db.ConstVersionColumn:
+		it.version = utils.InterfaceToInt(value)
+
 	case "increment_id":
 		it.IncrementID = utils.InterfaceToString(value)
 
@@ -317,6 +323,7 @@ func (it *DefaultOrder) ToHashMap() map[string]interface{} {
 	result := make(map[string]interface{})
 
 	result["_id"] = it.id
+	result[db.ConstVersionColumn] = it.version
 
 	result["increment_id"] = it.Get("increment_id")
 	result["status"] = it.Get("status")
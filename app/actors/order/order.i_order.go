@@ -250,6 +250,7 @@ func (it *DefaultOrder) GetStatus() string {
 
 // SetStatus changes status for current order
 //   - if status change no supposing stock operations, order instance will not be saved automatically
+//   - "order.status" event is emitted with "order", "oldStatus" and "newStatus" values after status change is committed
 func (it *DefaultOrder) SetStatus(newStatus string) error {
 	var err error
 
@@ -277,95 +278,116 @@ func (it *DefaultOrder) SetStatus(newStatus string) error {
 		}
 	}
 
-	return env.ErrorDispatch(err)
+	if err != nil {
+		return env.ErrorDispatch(err)
+	}
+
+	eventData := map[string]interface{}{"order": it, "oldStatus": oldStatus, "newStatus": newStatus}
+	db.AfterCommit(func() {
+		env.Event("order.status", eventData)
+	})
+
+	return nil
 }
 
 // Proceed subtracts order items from stock, changes status to new if status was not set yet, saves order
+//   - all the changes are made within a DB transaction, so any failure reverts stock and order changes
+//   - "order.proceed" event is emitted after commit, in-transaction listeners are subscribed to "transaction.order.proceed"
 func (it *DefaultOrder) Proceed() error {
+	return db.InTransaction(func() error {
+		if it.Status == "" {
+			it.Status = order.ConstOrderStatusNew
+		}
 
-	if it.Status == "" {
-		it.Status = order.ConstOrderStatusNew
-	}
-
-	var err error
-	stockManager := product.GetRegisteredStock()
-	if stockManager != nil {
-		for _, orderItem := range it.GetItems() {
-			options := orderItem.GetOptions()
-
-			currProductOptions := make(map[string]interface{})
-			for optionName, optionValue := range options {
-				if optionValue, ok := optionValue.(map[string]interface{}); ok {
-					if value, present := optionValue["value"]; present {
-						currProductOptions[optionName] = value
+		var err error
+		stockManager := product.GetRegisteredStock()
+		if stockManager != nil {
+			for _, orderItem := range it.GetItems() {
+				options := orderItem.GetOptions()
+
+				currProductOptions := make(map[string]interface{})
+				for optionName, optionValue := range options {
+					if optionValue, ok := optionValue.(map[string]interface{}); ok {
+						if value, present := optionValue["value"]; present {
+							currProductOptions[optionName] = value
+						}
 					}
 				}
+
+				err := stockManager.UpdateProductQty(orderItem.GetProductID(), currProductOptions, -1*orderItem.GetQty())
+				if err != nil {
+					return env.ErrorDispatch(err)
+				}
+
 			}
+		}
 
-			err := stockManager.UpdateProductQty(orderItem.GetProductID(), currProductOptions, -1*orderItem.GetQty())
+		// checking order's incrementID, if not set - assigning new one
+		if it.GetIncrementID() == "" {
+			err = it.NewIncrementID()
 			if err != nil {
 				return env.ErrorDispatch(err)
 			}
-
 		}
-	}
 
-	// checking order's incrementID, if not set - assigning new one
-	if it.GetIncrementID() == "" {
-		err = it.NewIncrementID()
+		err = it.Save()
 		if err != nil {
 			return env.ErrorDispatch(err)
 		}
-	}
 
-	err = it.Save()
-	if err != nil {
-		return env.ErrorDispatch(err)
-	}
+		eventData := map[string]interface{}{"order": it}
+		env.Event(order.ConstEventOrderProceedTransaction, eventData)
+		db.AfterCommit(func() {
+			env.Event(order.ConstEventOrderProceed, eventData)
+		})
 
-	eventData := map[string]interface{}{"order": it}
-	env.Event("order.proceed", eventData)
-
-	return nil
+		return nil
+	})
 }
 
 // Rollback returns order items to stock, modifieds the order status to declined
-// if status was not set yet, then saves order
+// if status was not set yet, then saves order (within a DB transaction)
+//   - "order.rollback" event is emitted after commit, in-transaction listeners are subscribed to "transaction.order.rollback"
 func (it *DefaultOrder) Rollback() error {
-	if it.Status == "" {
-		it.Status = order.ConstOrderStatusDeclined
-	}
+	return db.InTransaction(func() error {
+		if it.Status == "" {
+			it.Status = order.ConstOrderStatusDeclined
+		}
 
-	var err error
-	stockManager := product.GetRegisteredStock()
-	if stockManager != nil {
-		for _, orderItem := range it.GetItems() {
-			options := orderItem.GetOptions()
-
-			currProductOptions := make(map[string]interface{})
-			for optionName, optionValue := range options {
-				if optionValue, ok := optionValue.(map[string]interface{}); ok {
-					if value, present := optionValue["value"]; present {
-						currProductOptions[optionName] = value
+		var err error
+		stockManager := product.GetRegisteredStock()
+		if stockManager != nil {
+			for _, orderItem := range it.GetItems() {
+				options := orderItem.GetOptions()
+
+				currProductOptions := make(map[string]interface{})
+				for optionName, optionValue := range options {
+					if optionValue, ok := optionValue.(map[string]interface{}); ok {
+						if value, present := optionValue["value"]; present {
+							currProductOptions[optionName] = value
+						}
 					}
 				}
-			}
-			err := stockManager.UpdateProductQty(orderItem.GetProductID(), currProductOptions, orderItem.GetQty())
-			if err != nil {
-				return env.ErrorDispatch(err)
+				err := stockManager.UpdateProductQty(orderItem.GetProductID(), currProductOptions, orderItem.GetQty())
+				if err != nil {
+					return env.ErrorDispatch(err)
+				}
 			}
 		}
-	}
 
-	err = it.Save()
-	if err != nil {
-		return env.ErrorDispatch(err)
-	}
+		err = it.Save()
+		if err != nil {
+			return env.ErrorDispatch(err)
+		}
 
-	eventData := map[string]interface{}{"order": it}
-	env.Event("order.rollback", eventData)
+		eventData := map[string]interface{}{"order": it}
+		env.Event(order.ConstEventOrderRollbackTransaction, eventData)
+		db.AfterCommit(func() {
+			env.Event(order.ConstEventOrderRollback, eventData)
+		})
 
-	return nil
+		return nil
+	})
 }
 
 // DuplicateOrder used to create checkout from order with changing params
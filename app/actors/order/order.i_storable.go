@@ -72,79 +72,83 @@ func (it *DefaultOrder) Load(ID string) error {
 	return nil
 }
 
-// Delete removes current order from DB
+// Delete removes current order and its items from DB within a transaction
 func (it *DefaultOrder) Delete() error {
-	if it.GetID() == "" {
-		return env.ErrorNew(ConstErrorModule, ConstErrorLevel, "05e07871-2c78-4852-ab06-505bf8d708c1", "order id is not set")
-	}
+	return db.InTransaction(func() error {
+		if it.GetID() == "" {
+			return env.ErrorNew(ConstErrorModule, ConstErrorLevel, "05e07871-2c78-4852-ab06-505bf8d708c1", "order id is not set")
+		}
 
-	// deleting order items
-	orderItemsCollection, err := db.GetCollection(ConstCollectionNameOrderItems)
-	if err != nil {
-		return env.ErrorDispatch(err)
-	}
+		// deleting order items
+		orderItemsCollection, err := db.GetCollection(ConstCollectionNameOrderItems)
+		if err != nil {
+			return env.ErrorDispatch(err)
+		}
 
-	err = orderItemsCollection.AddFilter("order_id", "=", it.GetID())
-	if err != nil {
-		return env.ErrorDispatch(err)
-	}
+		err = orderItemsCollection.AddFilter("order_id", "=", it.GetID())
+		if err != nil {
+			return env.ErrorDispatch(err)
+		}
 
-	_, err = orderItemsCollection.Delete()
-	if err != nil {
-		return env.ErrorDispatch(err)
-	}
+		_, err = orderItemsCollection.Delete()
+		if err != nil {
+			return env.ErrorDispatch(err)
+		}
 
-	// deleting order
-	orderCollection, err := db.GetCollection(ConstCollectionNameOrder)
-	if err != nil {
-		return env.ErrorDispatch(err)
-	}
-	err = orderCollection.DeleteByID(it.GetID())
+		// deleting order
+		orderCollection, err := db.GetCollection(ConstCollectionNameOrder)
+		if err != nil {
+			return env.ErrorDispatch(err)
+		}
+		err = orderCollection.DeleteByID(it.GetID())
 
-	return env.ErrorDispatch(err)
+		return env.ErrorDispatch(err)
+	})
 }
 
-// Save stores current order in DB
+// Save stores current order and its items in DB within a transaction
 func (it *DefaultOrder) Save() error {
+	return db.InTransaction(func() error {
+		orderCollection, err := db.GetCollection(ConstCollectionNameOrder)
+		if err != nil {
+			return env.ErrorDispatch(err)
+		}
 
-	orderCollection, err := db.GetCollection(ConstCollectionNameOrder)
-	if err != nil {
-		return env.ErrorDispatch(err)
-	}
-
-	orderItemsCollection, err := db.GetCollection(ConstCollectionNameOrderItems)
-	if err != nil {
-		return env.ErrorDispatch(err)
-	}
-
-	// packing data before save
-	orderStoringValues := it.ToHashMap()
-
-	it.UpdatedAt = time.Now()
+		orderItemsCollection, err := db.GetCollection(ConstCollectionNameOrderItems)
+		if err != nil {
+			return env.ErrorDispatch(err)
+		}
 
-	newID, err := orderCollection.Save(orderStoringValues)
-	if err != nil {
-		return env.ErrorDispatch(err)
-	}
-	if err := it.SetID(newID); err != nil {
-		_ = env.ErrorNew(ConstErrorModule, ConstErrorLevel, "9e1abe0d-20a0-4bea-8f81-40fddaacde3f", err.Error())
-	}
+		// packing data before save
+		orderStoringValues := it.ToHashMap()
 
-	// storing order items
-	for _, orderItem := range it.GetItems() {
-		if err := orderItem.Set("order_id", newID); err != nil {
-			_ = env.ErrorNew(ConstErrorModule, ConstErrorLevel, "90202b42-0cd0-4e08-a11a-6f1f5981d246", err.Error())
-		}
-		orderItemStoringValues := orderItem.ToHashMap()
+		it.UpdatedAt = time.Now()
 
-		newID, err := orderItemsCollection.Save(orderItemStoringValues)
+		newID, err := orderCollection.Save(orderStoringValues)
 		if err != nil {
 			return env.ErrorDispatch(err)
 		}
-		if err := orderItem.SetID(newID); err != nil {
-			_ = env.ErrorNew(ConstErrorModule, ConstErrorLevel, "95eb6404-df35-4386-8a74-268df577194d", err.Error())
+		it.version = db.GetRecordVersion(orderStoringValues)
+		if err := it.SetID(newID); err != nil {
+			_ = env.ErrorNew(ConstErrorModule, ConstErrorLevel, "9e1abe0d-20a0-4bea-8f81-40fddaacde3f", err.Error())
 		}
-	}
 
-	return nil
+		// storing order items
+		for _, orderItem := range it.GetItems() {
+			if err := orderItem.Set("order_id", newID); err != nil {
+				_ = env.ErrorNew(ConstErrorModule, ConstErrorLevel, "90202b42-0cd0-4e08-a11a-6f1f5981d246", err.Error())
+			}
+			orderItemStoringValues := orderItem.ToHashMap()
+
+			newID, err := orderItemsCollection.Save(orderItemStoringValues)
+			if err != nil {
+				return env.ErrorDispatch(err)
+			}
+			if err := orderItem.SetID(newID); err != nil {
+				_ = env.ErrorNew(ConstErrorModule, ConstErrorLevel, "95eb6404-df35-4386-8a74-268df577194d", err.Error())
+			}
+		}
+
+		return nil
+	})
 }
@@ -97,3 +97,13 @@ func (it *DefaultOrderCollection) ListFilterReset() error {
 func (it *DefaultOrderCollection) ListLimit(offset int, limit int) error {
 	return it.listCollection.SetLimit(offset, limit)
 }
+
+// ListCursor switches selection to cursor based paging, blank cursor means first page
+func (it *DefaultOrderCollection) ListCursor(cursor string) error {
+	return it.listCollection.SetCursor(cursor)
+}
+
+// ListNextCursor returns cursor to select page following the last listed one
+func (it *DefaultOrderCollection) ListNextCursor() string {
+	return it.listCollection.GetNextCursor()
+}
@@ -0,0 +1,398 @@
+package apiclient
+
+import (
+	"encoding/base64"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ottemo/commerce/api"
+	"github.com/ottemo/commerce/app/models"
+	"github.com/ottemo/commerce/db"
+	"github.com/ottemo/commerce/env"
+	"github.com/ottemo/commerce/utils"
+)
+
+// setupAPI setups package related API endpoint routines
+func setupAPI() error {
+
+	service := api.GetRestService()
+
+	clientSchema := map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"_id":          map[string]interface{}{"type": "string"},
+			"client_id":    map[string]interface{}{"type": "string"},
+			"name":         map[string]interface{}{"type": "string"},
+			"scopes":       map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+			"enabled":      map[string]interface{}{"type": "boolean"},
+			"expires_at":   map[string]interface{}{"type": "string", "format": "date-time"},
+			"created_at":   map[string]interface{}{"type": "string", "format": "date-time"},
+			"last_used_at": map[string]interface{}{"type": "string", "format": "date-time"},
+		},
+	}
+	clientRequestSchema := map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"name":       map[string]interface{}{"type": "string"},
+			"scopes":     map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+			"enabled":    map[string]interface{}{"type": "boolean"},
+			"expires_at": map[string]interface{}{"type": "string", "format": "date-time"},
+		},
+	}
+	clientSecretSchema := map[string]interface{}{
+		"allOf": []interface{}{clientSchema, map[string]interface{}{
+			"type":       "object",
+			"properties": map[string]interface{}{"client_secret": map[string]interface{}{"type": "string"}},
+		}},
+	}
+
+	// Public
+	service.POST("oauth/token", APIIssueToken, &api.StructRouteMeta{
+		Summary:     "Issue bearer token",
+		Description: "OAuth2 client credentials grant, client credentials could be also given with \"Authorization: Basic\" header",
+		Tags:        []string{"oauth"},
+		Request: map[string]interface{}{
+			"type":     "object",
+			"required": []string{"grant_type"},
+			"properties": map[string]interface{}{
+				"grant_type":    map[string]interface{}{"type": "string", "enum": []string{ConstGrantTypeClientCredentials}},
+				"client_id":     map[string]interface{}{"type": "string"},
+				"client_secret": map[string]interface{}{"type": "string"},
+				"scope":         map[string]interface{}{"type": "string", "description": "space separated subset of client scopes"},
+			},
+		},
+		Response: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"access_token": map[string]interface{}{"type": "string"},
+				"token_type":   map[string]interface{}{"type": "string"},
+				"expires_in":   map[string]interface{}{"type": "integer"},
+				"scope":        map[string]interface{}{"type": "string"},
+			},
+		},
+	})
+
+	// Admin Only
+	service.GET("apiclients", api.PermissionHandler(ConstPermissionRead, APIListClients), &api.StructRouteMeta{
+		Summary:    "List API clients",
+		Response:   map[string]interface{}{"type": "array", "items": clientSchema},
+		Permission: ConstPermissionRead,
+	})
+	service.POST("apiclients", api.PermissionHandler(ConstPermissionWrite, APICreateClient), &api.StructRouteMeta{
+		Summary:     "Create API client",
+		Description: "client secret is returned only once",
+		Request:     clientRequestSchema,
+		Response:    clientSecretSchema,
+		Permission:  ConstPermissionWrite,
+	})
+	service.GET("apiclients/:clientID", api.PermissionHandler(ConstPermissionRead, APIGetClient), &api.StructRouteMeta{
+		Summary:    "Get API client",
+		Response:   clientSchema,
+		Permission: ConstPermissionRead,
+	})
+	service.PUT("apiclients/:clientID", api.PermissionHandler(ConstPermissionWrite, APIUpdateClient), &api.StructRouteMeta{
+		Summary:    "Update API client",
+		Request:    clientRequestSchema,
+		Response:   clientSchema,
+		Permission: ConstPermissionWrite,
+	})
+	service.DELETE("apiclients/:clientID", api.PermissionHandler(ConstPermissionWrite, APIDeleteClient), &api.StructRouteMeta{
+		Summary:    "Delete API client",
+		Permission: ConstPermissionWrite,
+	})
+	service.POST("apiclients/:clientID/secret", api.PermissionHandler(ConstPermissionWrite, APIRotateSecret), &api.StructRouteMeta{
+		Summary:     "Rotate API client secret",
+		Description: "new client secret is returned only once",
+		Response:    clientSecretSchema,
+		Permission:  ConstPermissionWrite,
+	})
+
+	return nil
+}
+
+// getClientCredentials returns client id and secret from "Authorization: Basic" header or request content
+func getClientCredentials(context api.InterfaceApplicationContext) (string, string) {
+	authorization := strings.TrimSpace(utils.InterfaceToString(context.GetRequestSetting("Authorization")))
+	if len(authorization) > 6 && strings.EqualFold(authorization[:6], "Basic ") {
+		if decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(authorization[6:])); err == nil {
+			if credentials := strings.SplitN(string(decoded), ":", 2); len(credentials) == 2 {
+				return credentials[0], credentials[1]
+			}
+		}
+	}
+
+	return utils.InterfaceToString(api.GetArgumentOrContentValue(context, "client_id")),
+		utils.InterfaceToString(api.GetArgumentOrContentValue(context, "client_secret"))
+}
+
+// toPublicRecord removes secret hash from API client record
+func toPublicRecord(record map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{})
+	for key, value := range record {
+		if key != "secret_hash" {
+			result[key] = value
+		}
+	}
+	return result
+}
+
+// loadRequestedClient loads API client record by "clientID" request argument
+func loadRequestedClient(context api.InterfaceApplicationContext) (db.InterfaceDBCollection, map[string]interface{}, error) {
+	clientID := context.GetRequestArgument("clientID")
+	if clientID == "" {
+		context.SetResponseStatusBadRequest()
+		return nil, nil, env.ErrorNew(ConstErrorModule, env.ConstErrorLevelAPI, "cd029a4f-66be-42ed-b45e-dd8a82fa4393", "client id should be specified")
+	}
+
+	collection, record, err := loadAPIClientRecord(clientID)
+	if err != nil {
+		return nil, nil, env.ErrorDispatch(err)
+	}
+	if record == nil {
+		context.SetResponseStatusNotFound()
+		return nil, nil, env.ErrorNew(ConstErrorModule, env.ConstErrorLevelAPI, "b33c91c4-5f4d-40d3-9824-ac17c51ad84e", "API client '"+clientID+"' not found")
+	}
+
+	return collection, record, nil
+}
+
+// applyClientValues validates and sets client values given in request content to client record
+//   - session should have each of scopes it grants to client
+func applyClientValues(context api.InterfaceApplicationContext, record map[string]interface{}) error {
+	requestData, err := api.GetRequestContentAsMap(context)
+	if err != nil {
+		return env.ErrorDispatch(err)
+	}
+
+	if value, present := requestData["name"]; present {
+		record["name"] = utils.InterfaceToString(value)
+	}
+	if value, present := requestData["enabled"]; present {
+		record["enabled"] = utils.InterfaceToBool(value)
+	}
+	if value, present := requestData["expires_at"]; present {
+		record["expires_at"] = utils.InterfaceToTime(value)
+	}
+
+	if value, present := requestData["scopes"]; present {
+		var scopes []string
+		for _, scope := range utils.InterfaceToStringArray(value) {
+			if scope = strings.TrimSpace(scope); scope == "" {
+				continue
+			}
+			if !api.HasPermission(context, scope) {
+				context.SetResponseStatusForbidden()
+				return env.ErrorNew(ConstErrorModule, env.ConstErrorLevelAPI, "29a14cc8-714b-4ecc-a30e-c0058032dba9", "scope '"+scope+"' can't be granted without having it")
+			}
+			scopes = append(scopes, scope)
+		}
+		record["scopes"] = scopes
+	}
+
+	if utils.InterfaceToString(record["name"]) == "" {
+		context.SetResponseStatusBadRequest()
+		return env.ErrorNew(ConstErrorModule, env.ConstErrorLevelAPI, "e4128c10-3daa-4503-be32-d0e74f1af539", "client name should be specified")
+	}
+
+	return nil
+}
+
+// APIIssueToken exchanges client credentials for bearer token (OAuth2 client credentials grant)
+//   - "grant_type" should be "client_credentials"
+//   - "client_id" and "client_secret" should be specified in request content or with "Authorization: Basic" header
+//   - "scope" could be used to request subset of client scopes (space separated)
+func APIIssueToken(context api.InterfaceApplicationContext) (interface{}, error) {
+	grantType := utils.InterfaceToString(api.GetArgumentOrContentValue(context, "grant_type"))
+	if grantType != ConstGrantTypeClientCredentials {
+		context.SetResponseStatusBadRequest()
+		return nil, env.ErrorNew(ConstErrorModule, env.ConstErrorLevelAPI, "e7af53e0-da5e-4fec-a7fa-7d9b24941ddf", "unsupported grant type '"+grantType+"'")
+	}
+
+	clientID, clientSecret := getClientCredentials(context)
+	if clientID == "" || clientSecret == "" {
+		context.SetResponseStatus(http.StatusUnauthorized)
+		return nil, env.ErrorNew(ConstErrorModule, env.ConstErrorLevelAPI, "632349a2-2eec-4793-9b16-57f51a59dcd1", "client credentials should be specified")
+	}
+
+	collection, record, err := loadAPIClientRecord(clientID)
+	if err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
+	// same response for unknown client and wrong secret
+	var client *StructAPIClient
+	if record != nil {
+		client = newAPIClient(record)
+	}
+	if client == nil || !client.CheckSecret(clientSecret) || !client.IsActive() {
+		context.SetResponseStatus(http.StatusUnauthorized)
+		return nil, env.ErrorNew(ConstErrorModule, env.ConstErrorLevelAPI, "042208ee-79a0-4c5d-b757-b4e0e2aa661a", "invalid client credentials")
+	}
+
+	scopes, err := client.GrantScopes(utils.InterfaceToString(api.GetArgumentOrContentValue(context, "scope")))
+	if err != nil {
+		context.SetResponseStatusBadRequest()
+		return nil, err
+	}
+
+	secret, err := getTokenSecret()
+	if err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
+	lifetime := getTokenLifetime()
+	token, claims, err := issueToken(client.ClientID, scopes, lifetime, secret)
+	if err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
+	record["last_used_at"] = time.Now()
+	if _, err := collection.Save(record); err != nil {
+		_ = env.ErrorDispatch(err)
+	}
+
+	return map[string]interface{}{
+		"access_token": token,
+		"token_type":   ConstTokenType,
+		"expires_in":   int(lifetime / time.Second),
+		"scope":        claims.Scope,
+	}, nil
+}
+
+// APIListClients returns a list of API clients
+func APIListClients(context api.InterfaceApplicationContext) (interface{}, error) {
+	collection, err := db.GetCollection(ConstCollectionNameAPIClient)
+	if err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
+	if offset, limit := models.GetListLimit(context); limit > 0 {
+		if err := collection.SetLimit(offset, limit); err != nil {
+			return nil, env.ErrorDispatch(err)
+		}
+	}
+
+	records, err := collection.Load()
+	if err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
+	result := make([]map[string]interface{}, 0, len(records))
+	for _, record := range records {
+		result = append(result, toPublicRecord(record))
+	}
+
+	return result, nil
+}
+
+// APIGetClient returns API client information
+//   - "clientID" should be specified in request argument
+func APIGetClient(context api.InterfaceApplicationContext) (interface{}, error) {
+	_, record, err := loadRequestedClient(context)
+	if err != nil {
+		return nil, err
+	}
+
+	return toPublicRecord(record), nil
+}
+
+// APICreateClient issues new API client credentials
+//   - "name" should be specified in request content, "scopes", "expires_at" and "enabled" are optional
+//   - "client_secret" is returned only once, it is stored as a hash
+func APICreateClient(context api.InterfaceApplicationContext) (interface{}, error) {
+	collection, err := db.GetCollection(ConstCollectionNameAPIClient)
+	if err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
+	clientID, err := makeRandomHex(12)
+	if err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+	clientSecret, err := makeRandomHex(32)
+	if err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
+	record := map[string]interface{}{
+		"client_id":   ConstClientIDPrefix + clientID,
+		"secret_hash": hashSecret(clientSecret),
+		"scopes":      []string{},
+		"enabled":     true,
+		"created_at":  time.Now(),
+	}
+	if err := applyClientValues(context, record); err != nil {
+		return nil, err
+	}
+
+	recordID, err := collection.Save(record)
+	if err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+	record["_id"] = recordID
+
+	result := toPublicRecord(record)
+	result["client_secret"] = clientSecret
+
+	return result, nil
+}
+
+// APIUpdateClient updates API client with values given in request content
+//   - "clientID" should be specified in request argument
+func APIUpdateClient(context api.InterfaceApplicationContext) (interface{}, error) {
+	collection, record, err := loadRequestedClient(context)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := applyClientValues(context, record); err != nil {
+		return nil, err
+	}
+
+	if _, err := collection.Save(record); err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
+	return toPublicRecord(record), nil
+}
+
+// APIDeleteClient removes API client, tokens issued to it stop working
+//   - "clientID" should be specified in request argument
+func APIDeleteClient(context api.InterfaceApplicationContext) (interface{}, error) {
+	collection, record, err := loadRequestedClient(context)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := collection.DeleteByID(utils.InterfaceToString(record["_id"])); err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
+	return "ok", nil
+}
+
+// APIRotateSecret replaces API client secret, new "client_secret" is returned only once
+//   - "clientID" should be specified in request argument
+//   - already issued tokens remain valid till they expire
+func APIRotateSecret(context api.InterfaceApplicationContext) (interface{}, error) {
+	collection, record, err := loadRequestedClient(context)
+	if err != nil {
+		return nil, err
+	}
+
+	clientSecret, err := makeRandomHex(32)
+	if err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+	record["secret_hash"] = hashSecret(clientSecret)
+
+	if _, err := collection.Save(record); err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
+	result := toPublicRecord(record)
+	result["client_secret"] = clientSecret
+
+	return result, nil
+}
@@ -0,0 +1,79 @@
+package apiclient
+
+import (
+	"github.com/ottemo/commerce/env"
+	"github.com/ottemo/commerce/utils"
+)
+
+// setupConfig setups package configuration values for a system
+func setupConfig() error {
+	config := env.GetConfig()
+	if config == nil {
+		err := env.ErrorNew(ConstErrorModule, env.ConstErrorLevelStartStop, "9fad5e63-c65b-4926-9949-5073d6d82173", "can't obtain config")
+		return env.ErrorDispatch(err)
+	}
+
+	err := config.RegisterItem(env.StructConfigItem{
+		Path:        ConstConfigPathAPIClient,
+		Value:       nil,
+		Type:        env.ConstConfigTypeGroup,
+		Editor:      "",
+		Options:     nil,
+		Label:       "API Clients",
+		Description: "",
+		Image:       "",
+	}, nil)
+	if err != nil {
+		return env.ErrorDispatch(err)
+	}
+
+	// token signing key is generated on setup rather than on first use, so instances would not race for it
+	tokenSecret, err := makeRandomHex(32)
+	if err != nil {
+		return env.ErrorDispatch(err)
+	}
+
+	err = config.RegisterItem(env.StructConfigItem{
+		Path:        ConstConfigPathTokenSecret,
+		Value:       tokenSecret,
+		Type:        env.ConstConfigTypeSecret,
+		Editor:      "password",
+		Options:     "",
+		Label:       "Token Signing Key",
+		Description: "key bearer tokens are signed with, generated on setup if blank, change revokes issued tokens",
+		Image:       "",
+	}, nil)
+	if err != nil {
+		return env.ErrorDispatch(err)
+	}
+
+	if utils.InterfaceToString(env.ConfigGetValue(ConstConfigPathTokenSecret)) == "" {
+		if err := config.SetValue(ConstConfigPathTokenSecret, tokenSecret); err != nil {
+			return env.ErrorDispatch(err)
+		}
+	}
+
+	// validateTokenLifetime checks token lifetime is positive amount of seconds
+	validateTokenLifetime := func(value interface{}) (interface{}, error) {
+		if utils.InterfaceToInt(value) <= 0 {
+			return nil, env.ErrorNew(ConstErrorModule, ConstErrorLevel, "592529f6-7dc3-4aab-ad0d-61fa3d2545ed", "token lifetime should be positive amount of seconds")
+		}
+		return utils.InterfaceToInt(value), nil
+	}
+
+	err = config.RegisterItem(env.StructConfigItem{
+		Path:        ConstConfigPathTokenLifetime,
+		Value:       ConstDefaultTokenLifetime,
+		Type:        env.ConstConfigTypeInteger,
+		Editor:      "integer",
+		Options:     "",
+		Label:       "Token Lifetime",
+		Description: "lifetime of issued bearer tokens in seconds",
+		Image:       "",
+	}, validateTokenLifetime)
+	if err != nil {
+		return env.ErrorDispatch(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,54 @@
+// Package apiclient implements API credentials for machine clients: admin issued client id and secret pairs with
+// scopes and expiry which are exchanged for signed bearer tokens with OAuth2 client credentials grant
+package apiclient
+
+import (
+	"time"
+
+	"github.com/ottemo/commerce/env"
+)
+
+// Package global constants
+const (
+	ConstCollectionNameAPIClient = "api_client"
+
+	ConstConfigPathAPIClient     = "general.api_client"
+	ConstConfigPathTokenSecret   = "general.api_client.token_secret"   // HMAC key tokens are signed with, generated on setup if blank
+	ConstConfigPathTokenLifetime = "general.api_client.token_lifetime" // token lifetime in seconds
+
+	ConstDefaultTokenLifetime = 3600
+	ConstTokenIssuer          = "ottemo"
+	ConstTokenAlgorithm       = "HS256"
+	ConstTokenType            = "Bearer"
+
+	ConstGrantTypeClientCredentials = "client_credentials"
+
+	ConstClientIDPrefix = "oc_"
+
+	ConstPermissionRead  = "apiclients.read"
+	ConstPermissionWrite = "apiclients.write"
+
+	ConstErrorModule = "apiclient"
+	ConstErrorLevel  = env.ConstErrorLevelActor
+)
+
+// StructAPIClient is a machine client allowed to obtain bearer tokens
+type StructAPIClient struct {
+	ID         string
+	ClientID   string
+	Name       string
+	SecretHash string
+	Scopes     []string
+	Enabled    bool
+	ExpiresAt  time.Time // zero time for credentials which are not expiring
+}
+
+// StructTokenClaims is a payload of issued bearer token
+type StructTokenClaims struct {
+	Issuer    string `json:"iss"`
+	Subject   string `json:"sub"` // client id token was issued to
+	Scope     string `json:"scope"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+	ID        string `json:"jti"`
+}
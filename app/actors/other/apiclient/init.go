@@ -0,0 +1,53 @@
+package apiclient
+
+import (
+	"github.com/ottemo/commerce/api"
+	"github.com/ottemo/commerce/db"
+	"github.com/ottemo/commerce/env"
+)
+
+// init makes package self-initialization routine
+func init() {
+	db.RegisterOnDatabaseStart(setupDB)
+	env.RegisterOnConfigStart(setupConfig)
+	api.RegisterOnRestServiceStart(setupAPI)
+
+	if err := api.RegisterBearerTokenValidator(validateToken); err != nil {
+		_ = env.ErrorDispatch(err)
+	}
+}
+
+// setupDB prepares system database for package usage
+func setupDB() error {
+	collection, err := db.GetCollection(ConstCollectionNameAPIClient)
+	if err != nil {
+		return env.ErrorDispatch(err)
+	}
+
+	if err := collection.AddColumn("client_id", db.ConstTypeVarchar, true); err != nil {
+		return env.ErrorDispatch(err)
+	}
+	if err := collection.AddColumn("secret_hash", db.ConstTypeVarchar, false); err != nil {
+		return env.ErrorDispatch(err)
+	}
+	if err := collection.AddColumn("name", db.ConstTypeVarchar, false); err != nil {
+		return env.ErrorDispatch(err)
+	}
+	if err := collection.AddColumn("scopes", db.TypeArrayOf(db.ConstTypeVarchar), false); err != nil {
+		return env.ErrorDispatch(err)
+	}
+	if err := collection.AddColumn("enabled", db.ConstTypeBoolean, true); err != nil {
+		return env.ErrorDispatch(err)
+	}
+	if err := collection.AddColumn("expires_at", db.ConstTypeDatetime, false); err != nil {
+		return env.ErrorDispatch(err)
+	}
+	if err := collection.AddColumn("created_at", db.ConstTypeDatetime, false); err != nil {
+		return env.ErrorDispatch(err)
+	}
+	if err := collection.AddColumn("last_used_at", db.ConstTypeDatetime, false); err != nil {
+		return env.ErrorDispatch(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,235 @@
+package apiclient
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/ottemo/commerce/api"
+	"github.com/ottemo/commerce/db"
+	"github.com/ottemo/commerce/env"
+	"github.com/ottemo/commerce/utils"
+)
+
+// makeRandomHex returns hex encoded random bytes of given length
+func makeRandomHex(length int) (string, error) {
+	value := make([]byte, length)
+	if _, err := rand.Read(value); err != nil {
+		return "", env.ErrorDispatch(err)
+	}
+	return hex.EncodeToString(value), nil
+}
+
+// hashSecret returns hash client secret is stored as
+func hashSecret(secret string) string {
+	hash := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(hash[:])
+}
+
+// newAPIClient makes API client from its DB record
+func newAPIClient(record map[string]interface{}) *StructAPIClient {
+	return &StructAPIClient{
+		ID:         utils.InterfaceToString(record["_id"]),
+		ClientID:   utils.InterfaceToString(record["client_id"]),
+		Name:       utils.InterfaceToString(record["name"]),
+		SecretHash: utils.InterfaceToString(record["secret_hash"]),
+		Scopes:     utils.InterfaceToStringArray(record["scopes"]),
+		Enabled:    utils.InterfaceToBool(record["enabled"]),
+		ExpiresAt:  utils.InterfaceToTime(record["expires_at"]),
+	}
+}
+
+// IsActive returns true if client is enabled and its credentials are not expired
+func (it *StructAPIClient) IsActive() bool {
+	return it.Enabled && (it.ExpiresAt.IsZero() || it.ExpiresAt.After(time.Now()))
+}
+
+// CheckSecret compares given secret with stored one in constant time
+func (it *StructAPIClient) CheckSecret(secret string) bool {
+	return subtle.ConstantTimeCompare([]byte(hashSecret(secret)), []byte(it.SecretHash)) == 1
+}
+
+// GrantScopes returns requested scopes client is allowed to, all client scopes are returned for blank request
+//   - scopes are space separated as in OAuth2, error is returned if any of requested ones is not allowed
+func (it *StructAPIClient) GrantScopes(requested string) ([]string, error) {
+	if strings.TrimSpace(requested) == "" {
+		return it.Scopes, nil
+	}
+
+	var result []string
+	for _, scope := range strings.Fields(requested) {
+		if !it.HasScope(scope) {
+			return nil, env.ErrorNew(ConstErrorModule, env.ConstErrorLevelAPI, "8b381c74-9278-47ea-9933-6fbadd9567d5", "scope '"+scope+"' is not allowed for client")
+		}
+		result = append(result, scope)
+	}
+
+	return result, nil
+}
+
+// HasScope returns true if client scopes cover given one
+func (it *StructAPIClient) HasScope(scope string) bool {
+	for _, granted := range it.Scopes {
+		if api.MatchPermission(granted, scope) {
+			return true
+		}
+	}
+	return false
+}
+
+// loadAPIClientRecord loads API client record by client id with collection it was loaded from
+func loadAPIClientRecord(clientID string) (db.InterfaceDBCollection, map[string]interface{}, error) {
+	collection, err := db.GetCollection(ConstCollectionNameAPIClient)
+	if err != nil {
+		return nil, nil, env.ErrorDispatch(err)
+	}
+
+	if err := collection.AddFilter("client_id", "=", clientID); err != nil {
+		return nil, nil, env.ErrorDispatch(err)
+	}
+
+	records, err := collection.Load()
+	if err != nil {
+		return nil, nil, env.ErrorDispatch(err)
+	}
+	if len(records) == 0 {
+		return collection, nil, nil
+	}
+
+	return collection, records[0], nil
+}
+
+// getTokenSecret returns token signing key, the key is generated on config setup
+func getTokenSecret() ([]byte, error) {
+	secret := utils.InterfaceToString(env.ConfigGetValue(ConstConfigPathTokenSecret))
+	if secret == "" {
+		return nil, env.ErrorNew(ConstErrorModule, ConstErrorLevel, "e30d95c6-c438-4cab-a64c-7ae1916708d2", "token signing key is not configured")
+	}
+
+	return []byte(secret), nil
+}
+
+// getTokenLifetime returns lifetime of issued tokens
+func getTokenLifetime() time.Duration {
+	lifetime := utils.InterfaceToInt(env.ConfigGetValue(ConstConfigPathTokenLifetime))
+	if lifetime <= 0 {
+		lifetime = ConstDefaultTokenLifetime
+	}
+	return time.Duration(lifetime) * time.Second
+}
+
+// signToken returns base64url encoded HMAC-SHA256 signature of token header and payload
+func signToken(unsigned string, secret []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(unsigned))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// issueToken makes signed JWT bearer token for given client and scopes
+func issueToken(clientID string, scopes []string, lifetime time.Duration, secret []byte) (string, *StructTokenClaims, error) {
+	tokenID, err := makeRandomHex(16)
+	if err != nil {
+		return "", nil, env.ErrorDispatch(err)
+	}
+
+	issuedAt := time.Now()
+	claims := &StructTokenClaims{
+		Issuer:    ConstTokenIssuer,
+		Subject:   clientID,
+		Scope:     strings.Join(scopes, " "),
+		IssuedAt:  issuedAt.Unix(),
+		ExpiresAt: issuedAt.Add(lifetime).Unix(),
+		ID:        tokenID,
+	}
+
+	header, err := json.Marshal(map[string]string{"alg": ConstTokenAlgorithm, "typ": "JWT"})
+	if err != nil {
+		return "", nil, env.ErrorDispatch(err)
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", nil, env.ErrorDispatch(err)
+	}
+
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+
+	return unsigned + "." + signToken(unsigned, secret), claims, nil
+}
+
+// parseToken checks token signature and expiration and returns its claims
+func parseToken(token string, secret []byte) (*StructTokenClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, env.ErrorNew(ConstErrorModule, env.ConstErrorLevelAPI, "bf092029-af53-4a68-856e-97cfa747525e", "malformed bearer token")
+	}
+
+	if !hmac.Equal([]byte(parts[2]), []byte(signToken(parts[0]+"."+parts[1], secret))) {
+		return nil, env.ErrorNew(ConstErrorModule, env.ConstErrorLevelAPI, "7ea438c8-0abc-4b54-aaea-7db5858dc41d", "invalid bearer token signature")
+	}
+
+	var header map[string]string
+	headerJSON, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil || json.Unmarshal(headerJSON, &header) != nil || header["alg"] != ConstTokenAlgorithm {
+		return nil, env.ErrorNew(ConstErrorModule, env.ConstErrorLevelAPI, "f1ca8d62-825c-464a-825a-dbe2507d13e1", "unsupported bearer token header")
+	}
+
+	claims := new(StructTokenClaims)
+	payloadJSON, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil || json.Unmarshal(payloadJSON, claims) != nil {
+		return nil, env.ErrorNew(ConstErrorModule, env.ConstErrorLevelAPI, "fb22e43b-d71e-475c-8f04-57df96a3f972", "malformed bearer token payload")
+	}
+
+	if claims.Issuer != ConstTokenIssuer || claims.Subject == "" {
+		return nil, env.ErrorNew(ConstErrorModule, env.ConstErrorLevelAPI, "599ad8be-0a74-4a7c-9f64-d5a102fd0789", "bearer token was not issued by this service")
+	}
+	if time.Now().Unix() >= claims.ExpiresAt {
+		return nil, env.ErrorNew(ConstErrorModule, env.ConstErrorLevelAPI, "983bd0c3-9657-4b3d-afb1-a972f69809bf", "bearer token expired")
+	}
+
+	return claims, nil
+}
+
+// validateToken is a bearer token validator registered in api package
+//   - client should still be active, token scopes client lost since issue are not granted
+func validateToken(token string) (map[string]interface{}, error) {
+	secret, err := getTokenSecret()
+	if err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
+	claims, err := parseToken(token, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	_, record, err := loadAPIClientRecord(claims.Subject)
+	if err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+	if record == nil {
+		return nil, env.ErrorNew(ConstErrorModule, env.ConstErrorLevelAPI, "b6609a0b-4b0c-460b-b1f4-648864451587", "bearer token client was removed")
+	}
+
+	client := newAPIClient(record)
+	if !client.IsActive() {
+		return nil, env.ErrorNew(ConstErrorModule, env.ConstErrorLevelAPI, "07778212-c7c3-4728-8ea8-79eddc0cf46b", "bearer token client is disabled or expired")
+	}
+
+	permissions := make([]string, 0)
+	for _, scope := range strings.Fields(claims.Scope) {
+		if client.HasScope(scope) {
+			permissions = append(permissions, scope)
+		}
+	}
+
+	return map[string]interface{}{
+		api.ConstSessionKeyPermissions: permissions,
+		api.ConstSessionKeyAPIClient:   client.ClientID,
+	}, nil
+}
@@ -0,0 +1,81 @@
+package apiclient
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestTokenRoundTrip checks issued token is accepted only with valid signature and before expiration
+func TestTokenRoundTrip(t *testing.T) {
+	secret := []byte("test secret")
+
+	token, _, err := issueToken("oc_test", []string{"orders.read", "catalog.*"}, time.Minute, secret)
+	if err != nil {
+		t.Fatal("issueToken", err)
+	}
+
+	claims, err := parseToken(token, secret)
+	if err != nil {
+		t.Fatal("parseToken", err)
+	}
+	if claims.Subject != "oc_test" || claims.Scope != "orders.read catalog.*" {
+		t.Errorf("unexpected claims: %#v", claims)
+	}
+
+	if _, err := parseToken(token, []byte("other secret")); err == nil {
+		t.Error("token signed with other secret was accepted")
+	}
+
+	parts := strings.Split(token, ".")
+	tampered, _, err := issueToken("oc_other", []string{"*"}, time.Minute, []byte("other secret"))
+	if err != nil {
+		t.Fatal("issueToken", err)
+	}
+	if _, err := parseToken(strings.Split(tampered, ".")[1]+"."+parts[1]+"."+parts[2], secret); err == nil {
+		t.Error("token with replaced header was accepted")
+	}
+	if _, err := parseToken(parts[0]+"."+strings.Split(tampered, ".")[1]+"."+parts[2], secret); err == nil {
+		t.Error("token with replaced payload was accepted")
+	}
+
+	expired, _, err := issueToken("oc_test", nil, -time.Minute, secret)
+	if err != nil {
+		t.Fatal("issueToken", err)
+	}
+	if _, err := parseToken(expired, secret); err == nil {
+		t.Error("expired token was accepted")
+	}
+}
+
+// TestClientCredentials checks client secret and scopes handling
+func TestClientCredentials(t *testing.T) {
+	client := newAPIClient(map[string]interface{}{
+		"client_id":   "oc_test",
+		"secret_hash": hashSecret("secret"),
+		"scopes":      []string{"orders.*", "catalog.read"},
+		"enabled":     true,
+	})
+
+	if !client.CheckSecret("secret") || client.CheckSecret("Secret") {
+		t.Error("secret check failed")
+	}
+	if !client.IsActive() {
+		t.Error("enabled client without expiration is not active")
+	}
+
+	client.ExpiresAt = time.Now().Add(-time.Second)
+	if client.IsActive() {
+		t.Error("expired client is active")
+	}
+
+	if scopes, err := client.GrantScopes(""); err != nil || len(scopes) != 2 {
+		t.Errorf("all client scopes expected for blank request, got %v, %v", scopes, err)
+	}
+	if scopes, err := client.GrantScopes("orders.refund catalog.read"); err != nil || len(scopes) != 2 {
+		t.Errorf("requested scopes expected, got %v, %v", scopes, err)
+	}
+	if _, err := client.GrantScopes("catalog.write"); err == nil {
+		t.Error("scope client does not have was granted")
+	}
+}
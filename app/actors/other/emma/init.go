@@ -13,7 +13,7 @@ func init() {
 }
 
 func appStart() error {
-	env.EventRegisterListener("checkout.success", checkoutSuccessHandler)
+	env.EventRegisterAsyncListener("checkout.success", "emma.checkoutSuccess", checkoutSuccessHandler)
 
 	emmaService = *newEmmaService()
 
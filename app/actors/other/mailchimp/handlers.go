@@ -18,32 +18,30 @@ import (
 
 // checkoutSuccessHandler handles the checkout success event to begin the subscription process if an order meets the
 // requirements
-func checkoutSuccessHandler(event string, eventData map[string]interface{}) bool {
+//   - handler is called in background, returned error makes it to be called again later
+func checkoutSuccessHandler(event string, eventData map[string]interface{}) error {
 
 	//If mailchimp is not enabled, ignore this handler and do nothing
 	if enabled := utils.InterfaceToBool(env.ConfigGetValue(ConstConfigPathMailchimpEnabled)); !enabled {
-		return true
+		return nil
 	}
 
-	// grab the order off event map
-	var checkoutOrder order.InterfaceOrder
-	if eventItem, present := eventData["order"]; present {
-		if typedItem, ok := eventItem.(order.InterfaceOrder); ok {
-			checkoutOrder = typedItem
-		}
+	// load the order stored in event map
+	orderID := utils.InterfaceToString(utils.InterfaceToMap(eventData["order"])["_id"])
+	if orderID == "" {
+		return nil
 	}
 
-	// inspect the order only if not nil
-	if checkoutOrder != nil {
-		go func(checkoutOrder order.InterfaceOrder) {
-			if err := processOrder(checkoutOrder); err != nil {
-				_ = env.ErrorNew(ConstErrorModule, env.ConstErrorLevelAPI, "f9bb4bb5-6c46-4a57-b769-8c76c966faf6", err.Error())
-			}
-		}(checkoutOrder)
+	checkoutOrder, err := order.LoadOrderByID(orderID)
+	if err != nil {
+		return env.ErrorDispatch(err)
 	}
 
-	return true
+	if err := processOrder(checkoutOrder); err != nil {
+		return env.ErrorNew(ConstErrorModule, env.ConstErrorLevelAPI, "f9bb4bb5-6c46-4a57-b769-8c76c966faf6", err.Error())
+	}
 
+	return nil
 }
 
 // processOrder is called from the checkout handler to process the order and call Subscribe if the trigger sku is in the
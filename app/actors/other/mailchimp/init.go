@@ -11,7 +11,7 @@ func init() {
 }
 
 func appStart() error {
-	env.EventRegisterListener("checkout.success", checkoutSuccessHandler)
+	env.EventRegisterAsyncListener("checkout.success", "mailchimp.checkoutSuccess", checkoutSuccessHandler)
 
 	return nil
 }
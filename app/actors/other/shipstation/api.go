@@ -2,6 +2,7 @@ package shipstation
 
 import (
 	"encoding/base64"
+	"encoding/xml"
 	"strings"
 	"time"
 
@@ -119,7 +120,12 @@ func listOrders(context api.InterfaceApplicationContext) (interface{}, error) {
 		response.Orders = append(response.Orders, responseOrder)
 	}
 
-	return response, nil
+	result, err := xml.MarshalIndent(response, "", "    ")
+	if err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
+	return append([]byte(xml.Header), result...), nil
 }
 
 // Convert an ottemo order and all possible orderitems into a shipstation order
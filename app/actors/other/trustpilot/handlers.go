@@ -67,31 +67,30 @@ type ServiceReview struct {
 }
 
 // checkoutSuccessHandler is a handler for checkout success event which sends order information to TrustPilot
-func checkoutSuccessHandler(event string, eventData map[string]interface{}) bool {
+//   - handler is called in background, returned error makes it to be called again later
+func checkoutSuccessHandler(event string, eventData map[string]interface{}) error {
 
-	var checkoutOrder order.InterfaceOrder
-	if eventItem, present := eventData["order"]; present {
-		if typedItem, ok := eventItem.(order.InterfaceOrder); ok {
-			checkoutOrder = typedItem
-		}
+	orderID := utils.InterfaceToString(utils.InterfaceToMap(eventData["order"])["_id"])
+	cartID := utils.InterfaceToString(utils.InterfaceToMap(eventData["cart"])["_id"])
+	if orderID == "" || cartID == "" {
+		return nil
 	}
 
-	var checkoutCart cart.InterfaceCart
-	if eventItem, present := eventData["cart"]; present {
-		if typedItem, ok := eventItem.(cart.InterfaceCart); ok {
-			checkoutCart = typedItem
-		}
+	checkoutOrder, err := order.LoadOrderByID(orderID)
+	if err != nil {
+		return env.ErrorDispatch(err)
 	}
 
-	if checkoutOrder != nil && checkoutCart != nil {
-		go func(checkoutOrder order.InterfaceOrder, checkoutCart cart.InterfaceCart) {
-			if err := SendOrderInfo(checkoutOrder, checkoutCart); err != nil {
-				_ = env.ErrorNew(ConstErrorModule, ConstErrorLevel, "c76b0bf2-f439-47f2-a59b-a6e3e4612c33", err.Error())
-			}
-		}(checkoutOrder, checkoutCart)
+	checkoutCart, err := cart.LoadCartByID(cartID)
+	if err != nil {
+		return env.ErrorDispatch(err)
 	}
 
-	return true
+	if err := SendOrderInfo(checkoutOrder, checkoutCart); err != nil {
+		return env.ErrorNew(ConstErrorModule, ConstErrorLevel, "c76b0bf2-f439-47f2-a59b-a6e3e4612c33", err.Error())
+	}
+
+	return nil
 }
 
 // SendOrderInfo Makes requests to the trustpilot api to obtain an access token, then a product review url, then a
@@ -0,0 +1,335 @@
+package webhook
+
+import (
+	"net/url"
+	"time"
+
+	"github.com/ottemo/commerce/api"
+	"github.com/ottemo/commerce/app/models"
+	"github.com/ottemo/commerce/db"
+	"github.com/ottemo/commerce/env"
+	"github.com/ottemo/commerce/utils"
+)
+
+// setupAPI setups package related API endpoint routines
+func setupAPI() error {
+
+	service := api.GetRestService()
+
+	// Admin Only
+	service.GET("webhooks", api.PermissionHandler(ConstPermissionRead, APIListWebhooks))
+	service.POST("webhook", api.PermissionHandler(ConstPermissionWrite, APICreateWebhook))
+	service.GET("webhook/:webhookID", api.PermissionHandler(ConstPermissionRead, APIGetWebhook))
+	service.PUT("webhook/:webhookID", api.PermissionHandler(ConstPermissionWrite, APIUpdateWebhook))
+	service.DELETE("webhook/:webhookID", api.PermissionHandler(ConstPermissionWrite, APIDeleteWebhook))
+
+	service.POST("webhook/:webhookID/test", api.PermissionHandler(ConstPermissionWrite, APITestWebhook))
+	service.GET("webhook/:webhookID/deliveries", api.PermissionHandler(ConstPermissionRead, APIListDeliveries))
+	service.POST("webhook/:webhookID/deliveries/:deliveryID/retry", api.PermissionHandler(ConstPermissionWrite, APIRetryDelivery))
+
+	return nil
+}
+
+// loadWebhookRecord loads webhook record by "webhookID" request argument
+func loadWebhookRecord(context api.InterfaceApplicationContext) (db.InterfaceDBCollection, map[string]interface{}, error) {
+	webhookID := context.GetRequestArgument("webhookID")
+	if webhookID == "" {
+		return nil, nil, env.ErrorNew(ConstErrorModule, env.ConstErrorLevelAPI, "cb328c6c-b878-4ac6-b19b-67193a6da7c3", "webhook id should be specified")
+	}
+
+	collection, err := db.GetCollection(ConstCollectionNameWebhook)
+	if err != nil {
+		return nil, nil, env.ErrorDispatch(err)
+	}
+
+	record, err := collection.LoadByID(webhookID)
+	if err != nil {
+		context.SetResponseStatusNotFound()
+		return nil, nil, env.ErrorDispatch(err)
+	}
+
+	return collection, record, nil
+}
+
+// applyWebhookValues validates and sets webhook values given in request content to webhook record
+func applyWebhookValues(context api.InterfaceApplicationContext, record map[string]interface{}) error {
+	requestData, err := api.GetRequestContentAsMap(context)
+	if err != nil {
+		return env.ErrorDispatch(err)
+	}
+
+	for _, key := range []string{"url", "event", "secret", "description"} {
+		if value, present := requestData[key]; present {
+			record[key] = utils.InterfaceToString(value)
+		}
+	}
+	if value, present := requestData["enabled"]; present {
+		record["enabled"] = utils.InterfaceToBool(value)
+	}
+
+	webhookURL, err := url.Parse(utils.InterfaceToString(record["url"]))
+	if err != nil || (webhookURL.Scheme != "http" && webhookURL.Scheme != "https") || webhookURL.Host == "" {
+		context.SetResponseStatusBadRequest()
+		return env.ErrorNew(ConstErrorModule, env.ConstErrorLevelAPI, "e0522d9c-49fb-41d9-a310-6c7c0b810505", "webhook url should be valid http or https url")
+	}
+	if err := checkDestination(webhookURL.Hostname()); err != nil {
+		context.SetResponseStatusBadRequest()
+		return err
+	}
+
+	// empty prefix would subscribe to every event including each API request
+	if utils.InterfaceToString(record["event"]) == "" {
+		context.SetResponseStatusBadRequest()
+		return env.ErrorNew(ConstErrorModule, env.ConstErrorLevelAPI, "227741d1-e8c4-48c3-96e9-759b55a2f245", "webhook event should be specified")
+	}
+
+	if utils.InterfaceToString(record["secret"]) == "" {
+		secret, err := makeSecret()
+		if err != nil {
+			return env.ErrorDispatch(err)
+		}
+		record["secret"] = secret
+	}
+
+	record["updated_at"] = time.Now()
+
+	return nil
+}
+
+// APIListWebhooks returns a list of registered webhooks
+//   - "event" argument could be used to filter results
+func APIListWebhooks(context api.InterfaceApplicationContext) (interface{}, error) {
+	collection, err := db.GetCollection(ConstCollectionNameWebhook)
+	if err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
+	if value := context.GetRequestArgument("event"); value != "" {
+		if err := collection.AddFilter("event", "=", value); err != nil {
+			return nil, env.ErrorDispatch(err)
+		}
+	}
+
+	if offset, limit := models.GetListLimit(context); limit > 0 {
+		if err := collection.SetLimit(offset, limit); err != nil {
+			return nil, env.ErrorDispatch(err)
+		}
+	}
+
+	records, err := collection.Load()
+	if err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
+	return records, nil
+}
+
+// APIGetWebhook returns webhook information
+//   - "webhookID" should be specified in request argument
+func APIGetWebhook(context api.InterfaceApplicationContext) (interface{}, error) {
+	_, record, err := loadWebhookRecord(context)
+	if err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
+// APICreateWebhook registers new webhook
+//   - "url" and "event" (event prefix, i.e. "order" for all order events) should be specified in request content
+//   - "secret" is generated if not specified, "enabled" is true by default
+func APICreateWebhook(context api.InterfaceApplicationContext) (interface{}, error) {
+	collection, err := db.GetCollection(ConstCollectionNameWebhook)
+	if err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
+	record := map[string]interface{}{
+		"enabled":    true,
+		"created_at": time.Now(),
+	}
+	if err := applyWebhookValues(context, record); err != nil {
+		return nil, err
+	}
+
+	webhookID, err := collection.Save(record)
+	if err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+	record["_id"] = webhookID
+
+	if err := loadWebhooks(); err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
+	return record, nil
+}
+
+// APIUpdateWebhook updates webhook with values given in request content
+//   - "webhookID" should be specified in request argument
+func APIUpdateWebhook(context api.InterfaceApplicationContext) (interface{}, error) {
+	collection, record, err := loadWebhookRecord(context)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := applyWebhookValues(context, record); err != nil {
+		return nil, err
+	}
+
+	if _, err := collection.Save(record); err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
+	if err := loadWebhooks(); err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
+	return record, nil
+}
+
+// APIDeleteWebhook removes webhook, its pending deliveries are not sent anymore
+//   - "webhookID" should be specified in request argument
+func APIDeleteWebhook(context api.InterfaceApplicationContext) (interface{}, error) {
+	collection, record, err := loadWebhookRecord(context)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := collection.DeleteByID(utils.InterfaceToString(record["_id"])); err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
+	if err := loadWebhooks(); err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
+	return "ok", nil
+}
+
+// getDeliveryResult returns delivery attempt result without receiver response body
+//   - response body is not returned to caller, so webhook could not be used to read internal resources
+func getDeliveryResult(delivery map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"_id":           delivery["_id"],
+		"event":         delivery["event"],
+		"status":        delivery["status"],
+		"attempts":      delivery["attempts"],
+		"response_code": delivery["response_code"],
+		"last_error":    delivery["last_error"],
+	}
+}
+
+// APITestWebhook sends test event to webhook and returns delivery result
+//   - "webhookID" should be specified in request argument
+//   - webhook host is checked again, as it could be resolved to other address since webhook was saved
+func APITestWebhook(context api.InterfaceApplicationContext) (interface{}, error) {
+	_, record, err := loadWebhookRecord(context)
+	if err != nil {
+		return nil, err
+	}
+	webhook := newWebhook(record)
+
+	webhookURL, err := url.Parse(webhook.URL)
+	if err != nil {
+		context.SetResponseStatusBadRequest()
+		return nil, env.ErrorDispatch(err)
+	}
+	if err := checkDestination(webhookURL.Hostname()); err != nil {
+		context.SetResponseStatusBadRequest()
+		return nil, err
+	}
+
+	payload, err := makePayload(ConstTestEvent, map[string]interface{}{"webhook_id": webhook.ID})
+	if err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
+	delivery, err := newDelivery(webhook, ConstTestEvent, payload)
+	if err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
+	if err := deliver(webhook, delivery); err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
+	return getDeliveryResult(delivery), nil
+}
+
+// APIListDeliveries returns webhook deliveries log, recent first
+//   - "webhookID" should be specified in request argument
+//   - "status" and "event" arguments could be used to filter results
+func APIListDeliveries(context api.InterfaceApplicationContext) (interface{}, error) {
+	_, record, err := loadWebhookRecord(context)
+	if err != nil {
+		return nil, err
+	}
+
+	collection, err := db.GetCollection(ConstCollectionNameWebhookDelivery)
+	if err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
+	if err := collection.AddFilter("webhook_id", "=", record["_id"]); err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
+	for _, argument := range []string{"status", "event"} {
+		if value := context.GetRequestArgument(argument); value != "" {
+			if err := collection.AddFilter(argument, "=", value); err != nil {
+				return nil, env.ErrorDispatch(err)
+			}
+		}
+	}
+
+	if err := collection.AddSort("created_at", true); err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
+	if offset, limit := models.GetListLimit(context); limit > 0 {
+		if err := collection.SetLimit(offset, limit); err != nil {
+			return nil, env.ErrorDispatch(err)
+		}
+	}
+
+	// target responses are not exposed, same as for single delivery result
+	columns := []string{"_id", "webhook_id", "event", "status", "attempts", "response_code", "last_error", "next_attempt_at", "created_at", "delivered_at"}
+	if err := collection.SetResultColumns(columns...); err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
+	records, err := collection.Load()
+	if err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
+	return records, nil
+}
+
+// APIRetryDelivery sends webhook delivery again, i.e. dead one, and returns delivery result
+//   - "webhookID" and "deliveryID" should be specified in request arguments
+func APIRetryDelivery(context api.InterfaceApplicationContext) (interface{}, error) {
+	_, record, err := loadWebhookRecord(context)
+	if err != nil {
+		return nil, err
+	}
+	webhook := newWebhook(record)
+
+	collection, err := db.GetCollection(ConstCollectionNameWebhookDelivery)
+	if err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
+	delivery, err := collection.LoadByID(context.GetRequestArgument("deliveryID"))
+	if err != nil || utils.InterfaceToString(delivery["webhook_id"]) != webhook.ID {
+		context.SetResponseStatusNotFound()
+		return nil, env.ErrorNew(ConstErrorModule, env.ConstErrorLevelAPI, "ae21865a-e3da-4598-a754-177c80ef4734", "webhook delivery not found")
+	}
+
+	if err := deliver(webhook, delivery); err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
+	return getDeliveryResult(delivery), nil
+}
@@ -0,0 +1,61 @@
+// Package webhook implements outbound webhooks which notify external services about event bus events
+package webhook
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ottemo/commerce/env"
+)
+
+// Package global constants
+const (
+	ConstCollectionNameWebhook         = "webhook"
+	ConstCollectionNameWebhookDelivery = "webhook_delivery"
+
+	ConstDeliveryStatusPending = "pending" // delivery is waiting for an attempt
+	ConstDeliveryStatusSuccess = "success" // receiver responded with 2xx status
+	ConstDeliveryStatusDead    = "dead"    // receiver failed to accept delivery within allowed attempts
+
+	ConstHeaderEvent     = "X-Ottemo-Event"
+	ConstHeaderDelivery  = "X-Ottemo-Delivery"
+	ConstHeaderSignature = "X-Ottemo-Signature" // "sha256=" prefixed hex HMAC of request body signed with webhook secret
+
+	ConstTestEvent = "webhook.test"
+
+	ConstMaxAttempts     = 8
+	ConstRetryDelay      = 30 * time.Second // delay before first retry, it doubles with each next attempt
+	ConstMaxRetryDelay   = 6 * time.Hour
+	ConstRequestTimeout  = 15 * time.Second
+	ConstDeliveryTimeout = 5 * time.Minute // time after which delivery started by died instance is attempted again
+	ConstRetryBatchSize  = 100
+	ConstMaxResponseSize = 1024 // amount of response body bytes kept in delivery log
+
+	ConstRetryTaskName = "webhookRetry"
+	ConstRetryCronExpr = "* * * * *"
+
+	ConstPermissionRead  = "webhooks.read"
+	ConstPermissionWrite = "webhooks.write"
+
+	ConstErrorModule = "webhook"
+	ConstErrorLevel  = env.ConstErrorLevelActor
+)
+
+// Package global variables
+var (
+	webhooks      []*StructWebhook        // enabled webhooks cache, reloaded on changes and with each retry task run
+	webhooksMutex sync.RWMutex            // synchronization of webhooks cache
+	prefixes      = make(map[string]bool) // event prefixes listener was registered for
+
+	checkConnections = true                // delivery connections to non public addresses are refused, ref. to newDeliveryClient()
+	deliveryClient   = newDeliveryClient() // HTTP client webhook requests are made with
+)
+
+// StructWebhook is an outbound webhook subscribed to events of given prefix
+type StructWebhook struct {
+	ID      string
+	URL     string
+	Event   string
+	Secret  string
+	Enabled bool
+}
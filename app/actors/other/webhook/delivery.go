@@ -0,0 +1,247 @@
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/ottemo/commerce/db"
+	"github.com/ottemo/commerce/env"
+	"github.com/ottemo/commerce/utils"
+)
+
+// signPayload returns value of signature header for payload signed with webhook secret
+func signPayload(secret string, payload string) string {
+	signer := hmac.New(sha256.New, []byte(secret))
+	_, _ = signer.Write([]byte(payload))
+
+	return "sha256=" + hex.EncodeToString(signer.Sum(nil))
+}
+
+// newDelivery stores delivery of event payload to webhook
+//   - delivery is claimed by current instance, so the retry task will not pick it up until delivery timeout
+func newDelivery(webhook *StructWebhook, event string, payload string) (map[string]interface{}, error) {
+	collection, err := db.GetCollection(ConstCollectionNameWebhookDelivery)
+	if err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
+	currentTime := time.Now()
+	record := map[string]interface{}{
+		"webhook_id":      webhook.ID,
+		"event":           event,
+		"payload":         payload,
+		"status":          ConstDeliveryStatusPending,
+		"attempts":        0,
+		"next_attempt_at": currentTime.Add(ConstDeliveryTimeout),
+		"created_at":      currentTime,
+	}
+
+	deliveryID, err := collection.Save(record)
+	if err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+	record["_id"] = deliveryID
+
+	return record, nil
+}
+
+// deliver makes an attempt to send delivery payload to webhook and logs attempt result to delivery record
+//   - failed delivery is scheduled for retry, or marked as dead after last attempt
+func deliver(webhook *StructWebhook, record map[string]interface{}) error {
+	collection, err := db.GetCollection(ConstCollectionNameWebhookDelivery)
+	if err != nil {
+		return env.ErrorDispatch(err)
+	}
+
+	responseCode, responseBody, err := send(webhook, record)
+
+	attempts := utils.InterfaceToInt(record["attempts"]) + 1
+	record["attempts"] = attempts
+	record["response_code"] = responseCode
+	record["response_body"] = responseBody
+
+	if err == nil {
+		record["status"] = ConstDeliveryStatusSuccess
+		record["last_error"] = ""
+		record["delivered_at"] = time.Now()
+	} else {
+		record["last_error"] = env.ErrorMessage(err)
+
+		if attempts >= ConstMaxAttempts {
+			record["status"] = ConstDeliveryStatusDead
+		} else {
+			record["status"] = ConstDeliveryStatusPending
+			record["next_attempt_at"] = time.Now().Add(getRetryDelay(attempts))
+		}
+	}
+
+	if _, err := collection.Save(record); err != nil {
+		return env.ErrorDispatch(err)
+	}
+
+	return nil
+}
+
+// newDeliveryClient makes HTTP client for webhook requests
+//   - each connection address is checked after host resolving, so a host re-resolved to internal address is refused
+//   - redirects are not followed, redirect response is a delivery error
+func newDeliveryClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: ConstRequestTimeout,
+		Control: func(network string, address string, connection syscall.RawConn) error {
+			if !checkConnections {
+				return nil
+			}
+
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return env.ErrorDispatch(err)
+			}
+			if ip := net.ParseIP(host); ip == nil || !isPublicAddress(ip) {
+				return env.ErrorNew(ConstErrorModule, ConstErrorLevel, "5b4e33e9-6ff4-41af-a167-7fda3815d2a4", "webhook connection to not public address "+host+" refused")
+			}
+
+			return nil
+		},
+	}
+
+	return &http.Client{
+		Timeout: ConstRequestTimeout,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: ConstRequestTimeout,
+		},
+		CheckRedirect: func(request *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// send makes HTTP request with delivery payload to webhook URL
+//   - response with status other than 2xx is an error
+func send(webhook *StructWebhook, record map[string]interface{}) (int, string, error) {
+	payload := utils.InterfaceToString(record["payload"])
+
+	request, err := http.NewRequest(http.MethodPost, webhook.URL, bytes.NewBufferString(payload))
+	if err != nil {
+		return 0, "", env.ErrorDispatch(err)
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(ConstHeaderEvent, utils.InterfaceToString(record["event"]))
+	request.Header.Set(ConstHeaderDelivery, utils.InterfaceToString(record["_id"]))
+	request.Header.Set(ConstHeaderSignature, signPayload(webhook.Secret, payload))
+
+	response, err := deliveryClient.Do(request)
+	if err != nil {
+		return 0, "", env.ErrorNew(ConstErrorModule, ConstErrorLevel, "621ff3b7-3c28-4126-9f86-5da5da235464", "webhook request failed: "+err.Error())
+	}
+	defer response.Body.Close()
+
+	responseBody, err := ioutil.ReadAll(io.LimitReader(response.Body, ConstMaxResponseSize))
+	if err != nil {
+		return response.StatusCode, "", env.ErrorDispatch(err)
+	}
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return response.StatusCode, string(responseBody), env.ErrorNew(ConstErrorModule, ConstErrorLevel, "d7140e42-dcd8-4d95-b8c4-2da243a8b7c4", "webhook responded with status "+strconv.Itoa(response.StatusCode))
+	}
+
+	return response.StatusCode, string(responseBody), nil
+}
+
+// getRetryDelay returns delay before next attempt, it doubles with each failed attempt
+func getRetryDelay(attempts int) time.Duration {
+	delay := ConstRetryDelay
+	for i := 1; i < attempts && delay < ConstMaxRetryDelay; i++ {
+		delay *= 2
+	}
+
+	if delay > ConstMaxRetryDelay {
+		delay = ConstMaxRetryDelay
+	}
+	return delay
+}
+
+// retryDeliveries is a scheduler task which makes next attempt for failed deliveries
+//   - webhooks cache is reloaded as well, so changes made by other instances are applied
+//   - delivery is claimed with version check, so other instances would not send it twice
+func retryDeliveries(params map[string]interface{}) error {
+	if err := loadWebhooks(); err != nil {
+		env.LogError(err)
+	}
+
+	collection, err := db.GetCollection(ConstCollectionNameWebhookDelivery)
+	if err != nil {
+		return env.ErrorDispatch(err)
+	}
+
+	currentTime := time.Now()
+
+	if err := collection.AddFilter("status", "=", ConstDeliveryStatusPending); err != nil {
+		return env.ErrorDispatch(err)
+	}
+	if err := collection.AddFilter("next_attempt_at", "<=", currentTime); err != nil {
+		return env.ErrorDispatch(err)
+	}
+	if err := collection.AddSort("next_attempt_at", false); err != nil {
+		return env.ErrorDispatch(err)
+	}
+	if err := collection.SetLimit(0, ConstRetryBatchSize); err != nil {
+		return env.ErrorDispatch(err)
+	}
+
+	// records are claimed with version check, so they are read from primary to not conflict with stale replica
+	var records []map[string]interface{}
+	err = db.ReadFromPrimary(func() error {
+		records, err = collection.Load()
+		return err
+	})
+	if err != nil {
+		return env.ErrorDispatch(err)
+	}
+
+	webhooksByID := make(map[string]*StructWebhook)
+	for _, record := range records {
+		record["next_attempt_at"] = currentTime.Add(ConstDeliveryTimeout)
+		if _, err := collection.Save(record); err != nil {
+			if !db.IsVersionConflict(err) {
+				env.LogError(err)
+			}
+			continue
+		}
+
+		webhookID := utils.InterfaceToString(record["webhook_id"])
+		webhook, present := webhooksByID[webhookID]
+		if !present {
+			if webhook, err = loadWebhook(webhookID); err != nil {
+				webhook = nil
+			}
+			webhooksByID[webhookID] = webhook
+		}
+
+		if webhook == nil || !webhook.Enabled {
+			record["status"] = ConstDeliveryStatusDead
+			record["last_error"] = "webhook was removed or disabled"
+			if _, err := collection.Save(record); err != nil {
+				env.LogError(err)
+			}
+			continue
+		}
+
+		if err := deliver(webhook, record); err != nil {
+			env.LogError(err)
+		}
+	}
+
+	return nil
+}
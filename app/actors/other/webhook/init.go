@@ -0,0 +1,126 @@
+package webhook
+
+import (
+	"github.com/ottemo/commerce/api"
+	"github.com/ottemo/commerce/app"
+	"github.com/ottemo/commerce/db"
+	"github.com/ottemo/commerce/env"
+)
+
+// init makes package self-initialization routine
+func init() {
+	db.RegisterOnDatabaseStart(setupDB)
+	db.RegisterOnDatabaseStart(loadWebhooks)
+	app.OnAppStart(onAppStart)
+	api.RegisterOnRestServiceStart(setupAPI)
+
+	if err := db.RegisterMigration("webhook", 1, "change webhook url column type to text", migrateURLColumn); err != nil {
+		_ = env.ErrorDispatch(err)
+	}
+}
+
+// onAppStart makes module initialization on application startup
+func onAppStart() error {
+	if scheduler := env.GetScheduler(); scheduler != nil {
+		if err := scheduler.RegisterTask(ConstRetryTaskName, retryDeliveries); err != nil {
+			return env.ErrorDispatch(err)
+		}
+		if _, err := scheduler.ScheduleRepeat(ConstRetryCronExpr, ConstRetryTaskName, nil); err != nil {
+			return env.ErrorDispatch(err)
+		}
+	}
+
+	return nil
+}
+
+// migrateURLColumn changes type of webhook url column from varchar to text, as URLs with query could be longer
+// than varchar column allows
+func migrateURLColumn() error {
+	collection, err := db.GetCollection(ConstCollectionNameWebhook)
+	if err != nil {
+		return env.ErrorDispatch(err)
+	}
+
+	if collection.GetColumnType("url") == db.ConstTypeText {
+		return nil
+	}
+
+	return db.ChangeColumnType(collection, "url", db.ConstTypeText, false)
+}
+
+// setupDB prepares system database for package usage
+func setupDB() error {
+	collection, err := db.GetCollection(ConstCollectionNameWebhook)
+	if err != nil {
+		return env.ErrorDispatch(err)
+	}
+
+	// existing varchar column is changed by migration, ref. to migrateURLColumn()
+	if !collection.HasColumn("url") {
+		if err := collection.AddColumn("url", db.ConstTypeText, false); err != nil {
+			return env.ErrorDispatch(err)
+		}
+	}
+	if err := collection.AddColumn("event", db.ConstTypeVarchar, true); err != nil {
+		return env.ErrorDispatch(err)
+	}
+	if err := collection.AddColumn("secret", db.ConstTypeVarchar, false); err != nil {
+		return env.ErrorDispatch(err)
+	}
+	if err := collection.AddColumn("enabled", db.ConstTypeBoolean, true); err != nil {
+		return env.ErrorDispatch(err)
+	}
+	if err := collection.AddColumn("description", db.ConstTypeText, false); err != nil {
+		return env.ErrorDispatch(err)
+	}
+	if err := collection.AddColumn("created_at", db.ConstTypeDatetime, false); err != nil {
+		return env.ErrorDispatch(err)
+	}
+	if err := collection.AddColumn("updated_at", db.ConstTypeDatetime, false); err != nil {
+		return env.ErrorDispatch(err)
+	}
+
+	collection, err = db.GetCollection(ConstCollectionNameWebhookDelivery)
+	if err != nil {
+		return env.ErrorDispatch(err)
+	}
+
+	if err := collection.AddColumn("webhook_id", db.ConstTypeID, true); err != nil {
+		return env.ErrorDispatch(err)
+	}
+	if err := collection.AddColumn("event", db.ConstTypeVarchar, true); err != nil {
+		return env.ErrorDispatch(err)
+	}
+	if err := collection.AddColumn("payload", db.ConstTypeText, false); err != nil {
+		return env.ErrorDispatch(err)
+	}
+	if err := collection.AddColumn("status", db.ConstTypeVarchar, true); err != nil {
+		return env.ErrorDispatch(err)
+	}
+	if err := collection.AddColumn("attempts", db.ConstTypeInteger, false); err != nil {
+		return env.ErrorDispatch(err)
+	}
+	if err := collection.AddColumn("response_code", db.ConstTypeInteger, false); err != nil {
+		return env.ErrorDispatch(err)
+	}
+	if err := collection.AddColumn("response_body", db.ConstTypeText, false); err != nil {
+		return env.ErrorDispatch(err)
+	}
+	if err := collection.AddColumn("last_error", db.ConstTypeText, false); err != nil {
+		return env.ErrorDispatch(err)
+	}
+	if err := collection.AddColumn("next_attempt_at", db.ConstTypeDatetime, true); err != nil {
+		return env.ErrorDispatch(err)
+	}
+	if err := collection.AddColumn("created_at", db.ConstTypeDatetime, true); err != nil {
+		return env.ErrorDispatch(err)
+	}
+	if err := collection.AddColumn("delivered_at", db.ConstTypeDatetime, false); err != nil {
+		return env.ErrorDispatch(err)
+	}
+	if err := db.AddVersionColumn(collection); err != nil {
+		return env.ErrorDispatch(err)
+	}
+
+	return nil
+}
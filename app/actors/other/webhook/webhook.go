@@ -0,0 +1,190 @@
+package webhook
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net"
+	"reflect"
+	"time"
+
+	"github.com/ottemo/commerce/db"
+	"github.com/ottemo/commerce/env"
+	"github.com/ottemo/commerce/utils"
+)
+
+// newWebhook makes webhook from its DB record
+func newWebhook(record map[string]interface{}) *StructWebhook {
+	return &StructWebhook{
+		ID:      utils.InterfaceToString(record["_id"]),
+		URL:     utils.InterfaceToString(record["url"]),
+		Event:   utils.InterfaceToString(record["event"]),
+		Secret:  utils.InterfaceToString(record["secret"]),
+		Enabled: utils.InterfaceToBool(record["enabled"]),
+	}
+}
+
+// loadWebhook loads webhook by its id
+func loadWebhook(webhookID string) (*StructWebhook, error) {
+	collection, err := db.GetCollection(ConstCollectionNameWebhook)
+	if err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
+	record, err := collection.LoadByID(webhookID)
+	if err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
+	return newWebhook(record), nil
+}
+
+// loadWebhooks reloads enabled webhooks cache and registers event bus listener for their events
+//   - listener is registered once for each event prefix, so the event bus prefix matching selects events to deliver
+//   - listener is a background one, so events emitted within transaction are delivered only after commit
+func loadWebhooks() error {
+	collection, err := db.GetCollection(ConstCollectionNameWebhook)
+	if err != nil {
+		return env.ErrorDispatch(err)
+	}
+
+	if err := collection.AddFilter("enabled", "=", true); err != nil {
+		return env.ErrorDispatch(err)
+	}
+
+	records, err := collection.Load()
+	if err != nil {
+		return env.ErrorDispatch(err)
+	}
+
+	result := make([]*StructWebhook, 0, len(records))
+	for _, record := range records {
+		result = append(result, newWebhook(record))
+	}
+
+	webhooksMutex.Lock()
+	defer webhooksMutex.Unlock()
+
+	webhooks = result
+	for _, webhook := range webhooks {
+		if !prefixes[webhook.Event] {
+			prefixes[webhook.Event] = true
+			env.EventRegisterAsyncListener(webhook.Event, "webhook."+webhook.Event, makeEventListener(webhook.Event))
+		}
+	}
+
+	return nil
+}
+
+// getWebhooks returns enabled webhooks subscribed to given event prefix
+func getWebhooks(prefix string) []*StructWebhook {
+	webhooksMutex.RLock()
+	defer webhooksMutex.RUnlock()
+
+	var result []*StructWebhook
+	for _, webhook := range webhooks {
+		if webhook.Event == prefix {
+			result = append(result, webhook)
+		}
+	}
+	return result
+}
+
+// makeEventListener returns event bus background listener which makes deliveries of event to webhooks of given prefix
+//   - failed deliveries are retried by the retry task, so they are not reported to event bus
+func makeEventListener(prefix string) env.FuncAsyncEventListener {
+	return func(event string, eventData map[string]interface{}) error {
+		subscribers := getWebhooks(prefix)
+		if len(subscribers) == 0 {
+			return nil
+		}
+
+		payload, err := makePayload(event, eventData)
+		if err != nil {
+			return env.ErrorDispatch(err)
+		}
+
+		for _, webhook := range subscribers {
+			record, err := newDelivery(webhook, event, payload)
+			if err != nil {
+				env.LogError(err)
+				continue
+			}
+
+			if err := deliver(webhook, record); err != nil {
+				env.LogError(err)
+			}
+		}
+
+		return nil
+	}
+}
+
+// makePayload makes JSON body of webhook request for an event
+//   - models are represented with their ToHashMap() result, other structs and values could not be converted to JSON
+//     are omitted (i.e. session and cart objects)
+func makePayload(event string, eventData map[string]interface{}) (string, error) {
+	data := make(map[string]interface{}, len(eventData))
+
+	for key, value := range eventData {
+		if object, ok := value.(interface {
+			ToHashMap() map[string]interface{}
+		}); ok {
+			value = object.ToHashMap()
+		} else if _, ok := value.(time.Time); !ok {
+			if kind := reflect.Indirect(reflect.ValueOf(value)).Kind(); kind == reflect.Struct || kind == reflect.Func || kind == reflect.Chan {
+				continue
+			}
+		}
+
+		if _, err := json.Marshal(value); err == nil {
+			data[key] = value
+		}
+	}
+
+	payload, err := json.Marshal(map[string]interface{}{
+		"event":      event,
+		"created_at": time.Now().UTC(),
+		"data":       data,
+	})
+	if err != nil {
+		return "", env.ErrorDispatch(err)
+	}
+
+	return string(payload), nil
+}
+
+// checkDestination checks webhook host not to be resolved to loopback, private or link-local address
+//   - webhooks are called from the server, so such destinations would expose internal services
+func checkDestination(host string) error {
+	addresses := []net.IP{net.ParseIP(host)}
+	if addresses[0] == nil {
+		var err error
+		if addresses, err = net.LookupIP(host); err != nil {
+			return env.ErrorNew(ConstErrorModule, env.ConstErrorLevelAPI, "5f2dda57-845e-4b41-abbd-3f0075be81ae", "can't resolve webhook host '"+host+"'")
+		}
+	}
+
+	for _, address := range addresses {
+		if !isPublicAddress(address) {
+			return env.ErrorNew(ConstErrorModule, env.ConstErrorLevelAPI, "a5ec964d-39d2-4bc3-a4c0-41912bed3422", "webhook host '"+host+"' is not a public address")
+		}
+	}
+
+	return nil
+}
+
+// isPublicAddress returns false for loopback, private, unspecified and link-local addresses
+func isPublicAddress(address net.IP) bool {
+	return !(address.IsLoopback() || address.IsPrivate() || address.IsUnspecified() ||
+		address.IsLinkLocalUnicast() || address.IsLinkLocalMulticast() || address.IsInterfaceLocalMulticast())
+}
+
+// makeSecret generates random secret to sign webhook payloads with
+func makeSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", env.ErrorDispatch(err)
+	}
+	return hex.EncodeToString(secret), nil
+}
@@ -0,0 +1,202 @@
+package webhook
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ottemo/commerce/db"
+	"github.com/ottemo/commerce/env"
+
+	_ "github.com/ottemo/commerce/db/memory"
+	_ "github.com/ottemo/commerce/env/eventbus"
+)
+
+// newTestWebhook stores enabled webhook for given url and event prefix
+//   - test servers are listening on loopback, so delivery connections check is disabled
+func newTestWebhook(t *testing.T, webhookURL string, event string) *StructWebhook {
+	checkConnections = false
+	t.Cleanup(func() { checkConnections = true })
+
+	if err := setupDB(); err != nil {
+		t.Fatal("setupDB", err)
+	}
+
+	collection, err := db.GetCollection(ConstCollectionNameWebhook)
+	if err != nil {
+		t.Fatal("db.GetCollection", err)
+	}
+
+	record := map[string]interface{}{"url": webhookURL, "event": event, "secret": "test secret", "enabled": true}
+	webhookID, err := collection.Save(record)
+	if err != nil {
+		t.Fatal("collection.Save", err)
+	}
+	record["_id"] = webhookID
+
+	if err := loadWebhooks(); err != nil {
+		t.Fatal("loadWebhooks", err)
+	}
+
+	return newWebhook(record)
+}
+
+func TestEventDelivery(t *testing.T) {
+	type request struct {
+		header http.Header
+		body   []byte
+	}
+	requests := make(chan request, 1)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		requests <- request{header: r.Header, body: body}
+	}))
+	defer server.Close()
+
+	newTestWebhook(t, server.URL, "test.webhook")
+
+	env.Event("test.other", map[string]interface{}{})
+	env.Event("test.webhook.fired", map[string]interface{}{"value": "test", "skipped": make(chan bool)})
+
+	var received request
+	select {
+	case received = <-requests:
+	case <-time.After(5 * time.Second):
+		t.Fatal("webhook was not called")
+	}
+
+	if signature := received.header.Get(ConstHeaderSignature); signature != signPayload("test secret", string(received.body)) {
+		t.Errorf("unexpected signature %q", signature)
+	}
+	if event := received.header.Get(ConstHeaderEvent); event != "test.webhook.fired" {
+		t.Errorf("unexpected event header %q", event)
+	}
+
+	var payload map[string]interface{}
+	if err := json.Unmarshal(received.body, &payload); err != nil {
+		t.Fatal("json.Unmarshal", err)
+	}
+	if data, ok := payload["data"].(map[string]interface{}); !ok || data["value"] != "test" || data["skipped"] != nil {
+		t.Errorf("unexpected payload %v", payload)
+	}
+
+	select {
+	case <-requests:
+		t.Error("webhook was called for not subscribed event")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestDeliveryRetry(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	webhook := newTestWebhook(t, server.URL, "test.retry")
+
+	record, err := newDelivery(webhook, "test.retry", "{}")
+	if err != nil {
+		t.Fatal("newDelivery", err)
+	}
+	if err := deliver(webhook, record); err != nil {
+		t.Fatal("deliver", err)
+	}
+
+	if record["status"] != ConstDeliveryStatusPending || record["response_code"] != http.StatusServiceUnavailable || record["attempts"] != 1 {
+		t.Errorf("failed delivery was not scheduled for retry: %v", record)
+	}
+
+	record["attempts"] = ConstMaxAttempts - 1
+	if err := deliver(webhook, record); err != nil {
+		t.Fatal("deliver", err)
+	}
+
+	if record["status"] != ConstDeliveryStatusDead {
+		t.Errorf("delivery was not marked as dead after last attempt: %v", record)
+	}
+}
+
+func TestCheckDestination(t *testing.T) {
+	for _, host := range []string{"127.0.0.1", "::1", "10.1.2.3", "192.168.0.10", "169.254.169.254", "0.0.0.0", "localhost"} {
+		if err := checkDestination(host); err == nil {
+			t.Errorf("host %q should be rejected", host)
+		}
+	}
+
+	if err := checkDestination("93.184.216.34"); err != nil {
+		t.Errorf("public address rejected: %v", err)
+	}
+}
+
+func TestDeliveryClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "http://127.0.0.1:1/internal", http.StatusFound)
+	}))
+	defer server.Close()
+
+	webhook := &StructWebhook{URL: server.URL, Secret: "test secret"}
+	record := map[string]interface{}{"_id": "test", "event": "test.client", "payload": "{}"}
+
+	if _, _, err := send(webhook, record); err == nil {
+		t.Error("connection to loopback address was not refused")
+	}
+
+	checkConnections = false
+	defer func() { checkConnections = true }()
+
+	if code, _, err := send(webhook, record); err == nil || code != http.StatusFound {
+		t.Errorf("redirect was followed, status %d, error %v", code, err)
+	}
+}
+
+func TestURLColumnMigration(t *testing.T) {
+	if err := setupDB(); err != nil {
+		t.Fatal("setupDB", err)
+	}
+
+	collection, err := db.GetCollection(ConstCollectionNameWebhook)
+	if err != nil {
+		t.Fatal("db.GetCollection", err)
+	}
+
+	// making url column as it was created before migration
+	if err := collection.RemoveColumn("url"); err != nil {
+		t.Fatal("collection.RemoveColumn", err)
+	}
+	if err := collection.AddColumn("url", db.ConstTypeVarchar, false); err != nil {
+		t.Fatal("collection.AddColumn", err)
+	}
+	webhookURL := "https://example.com/hook?token=" + strings.Repeat("x", 300)
+	webhookID, err := collection.Save(map[string]interface{}{"url": webhookURL, "event": "test.migration"})
+	if err != nil {
+		t.Fatal("collection.Save", err)
+	}
+
+	migrations, err := db.ApplyMigrations(false)
+	if err != nil {
+		t.Fatal("db.ApplyMigrations", err)
+	}
+	if len(migrations) != 1 || migrations[0].Module != "webhook" || !migrations[0].IsApplied {
+		t.Fatalf("unexpected applied migrations: %v", migrations)
+	}
+
+	if columnType := collection.GetColumnType("url"); columnType != db.ConstTypeText {
+		t.Errorf("url column type is %q after migration", columnType)
+	}
+	if collection.HasColumn("url" + db.ConstMigrationTempSuffix) {
+		t.Error("temporary column was not removed")
+	}
+	if record, err := collection.LoadByID(webhookID); err != nil || record["url"] != webhookURL {
+		t.Errorf("webhook url was not kept: %v, %v", record, err)
+	}
+
+	if migrations, err := db.ApplyMigrations(false); err != nil || len(migrations) != 0 {
+		t.Errorf("applied migration was applied again: %v, %v", migrations, err)
+	}
+}
@@ -6,10 +6,12 @@ import (
 	"image/jpeg"
 	"io/ioutil"
 	"mime"
+	"net/http"
 	"strings"
 	"time"
 
 	"github.com/ottemo/commerce/api"
+	"github.com/ottemo/commerce/db"
 	"github.com/ottemo/commerce/env"
 	"github.com/ottemo/commerce/media"
 	"github.com/ottemo/commerce/utils"
@@ -39,20 +41,20 @@ func setupAPI() error {
 	service.GET("product/:productID/related", APIListRelatedProducts)
 
 	// Admin Only
-	service.POST("product", api.IsAdminHandler(APICreateProduct))
-	service.PUT("product/:productID", api.IsAdminHandler(APIUpdateProduct))
-	service.DELETE("product/:productID", api.IsAdminHandler(APIDeleteProduct))
+	service.POST("product", api.PermissionHandler(ConstPermissionWrite, APICreateProduct))
+	service.PUT("product/:productID", api.PermissionHandler(ConstPermissionWrite, APIUpdateProduct))
+	service.DELETE("product/:productID", api.PermissionHandler(ConstPermissionWrite, APIDeleteProduct))
 
-	service.POST("products/attribute", api.IsAdminHandler(APICreateProductAttribute))
-	service.PUT("products/attribute/:attribute", api.IsAdminHandler(APIUpdateProductAttribute))
-	service.DELETE("products/attribute/:attribute", api.IsAdminHandler(APIDeleteProductsAttribute))
+	service.POST("products/attribute", api.PermissionHandler(ConstPermissionWrite, APICreateProductAttribute))
+	service.PUT("products/attribute/:attribute", api.PermissionHandler(ConstPermissionWrite, APIUpdateProductAttribute))
+	service.DELETE("products/attribute/:attribute", api.PermissionHandler(ConstPermissionWrite, APIDeleteProductsAttribute))
 
-	service.POST("product/:productID/media/:mediaType/:mediaName", api.IsAdminHandler(APIAddMediaForProduct))
-	service.DELETE("product/:productID/media/:mediaType/:mediaName", api.IsAdminHandler(APIRemoveMediaForProduct))
-	service.PUT("product/:productID/media/:mediaType/:mediaName", api.IsAdminHandler(APIRenameMediaForProduct))
+	service.POST("product/:productID/media/:mediaType/:mediaName", api.PermissionHandler(ConstPermissionWrite, APIAddMediaForProduct))
+	service.DELETE("product/:productID/media/:mediaType/:mediaName", api.PermissionHandler(ConstPermissionWrite, APIRemoveMediaForProduct))
+	service.PUT("product/:productID/media/:mediaType/:mediaName", api.PermissionHandler(ConstPermissionWrite, APIRenameMediaForProduct))
 
 	// TODO: remove after patching
-	service.GET("patch/options", api.IsAdminHandler(APIPatchOptions))
+	service.GET("patch/options", api.PermissionHandler(ConstPermissionRead, APIPatchOptions))
 
 	return nil
 }
@@ -484,6 +486,14 @@ func APIUpdateProduct(context api.InterfaceApplicationContext) (interface{}, err
 	}
 
 	err = productModel.Save()
+	if db.IsVersionConflict(err) {
+		// product was changed by someone else, so current state returned to be reviewed
+		context.SetResponseStatus(http.StatusConflict)
+		if currentProduct, loadErr := product.LoadProductByID(productID); loadErr == nil {
+			return currentProduct.ToHashMap(), err
+		}
+		return nil, err
+	}
 	if err != nil {
 		return nil, env.ErrorDispatch(err)
 	}
@@ -808,6 +818,11 @@ func APIListProducts(context api.InterfaceApplicationContext) (interface{}, erro
 		return nil, env.ErrorDispatch(err)
 	}
 
+	// next page cursor handle
+	if err := models.SetListNextCursor(context, productCollectionModel); err != nil {
+		_ = env.ErrorNew(ConstErrorModule, ConstErrorLevel, "28b7ef98-14c7-4cc2-bead-eb747b55348b", err.Error())
+	}
+
 	mediaStorage, err := media.GetMediaStorage()
 	if err != nil {
 		return nil, env.ErrorDispatch(err)
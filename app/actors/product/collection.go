@@ -123,6 +123,16 @@ func (it *DefaultProductCollection) ListLimit(offset int, limit int) error {
 	return it.listCollection.SetLimit(offset, limit)
 }
 
+// ListCursor switches selection to cursor based paging, blank cursor means first page
+func (it *DefaultProductCollection) ListCursor(cursor string) error {
+	return it.listCollection.SetCursor(cursor)
+}
+
+// ListNextCursor returns cursor to select page following the last listed one
+func (it *DefaultProductCollection) ListNextCursor() string {
+	return it.listCollection.GetNextCursor()
+}
+
 // ---------------------------------------------------------------------------------
 // InterfaceModel implementation (package "github.com/ottemo/commerce/app/models")
 // ---------------------------------------------------------------------------------
@@ -12,6 +12,9 @@ import (
 const (
 	ConstCollectionNameProduct = "product"
 
+	ConstPermissionRead  = "catalog.read"
+	ConstPermissionWrite = "catalog.write"
+
 	ConstErrorModule = "product"
 	ConstErrorLevel  = env.ConstErrorLevelActor
 
@@ -23,7 +26,8 @@ const (
 
 // DefaultProduct is a default implementer of InterfaceProduct
 type DefaultProduct struct {
-	id string
+	id      string
+	version int
 
 	Enabled bool
 
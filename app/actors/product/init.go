@@ -70,6 +70,9 @@ func setupDB() error {
 	if err := collection.AddColumn("related_pids", db.TypeArrayOf(db.ConstTypeID), false); err != nil {
 		return env.ErrorNew(ConstErrorModule, ConstErrorLevel, "0b63db43-4cb0-4f9e-85f6-d8850dadb4c9", err.Error())
 	}
+	if err := db.AddVersionColumn(collection); err != nil {
+		return env.ErrorNew(ConstErrorModule, ConstErrorLevel, "c918e4f2-5d5a-458b-bd68-b39492193271", err.Error())
+	}
 
 	if shouldFillVisibleField {
 		env.Log(ConstErrorModule, env.ConstLogPrefixInfo, "Field 'visible' have been added. Make all products visible.")
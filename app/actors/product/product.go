@@ -451,6 +451,8 @@ func (it *DefaultProduct) Get(attribute string) interface{} {
 	switch strings.ToLower(attribute) {
 	case "_id", "id":
 		return it.id
+	case db.ConstVersionColumn:
+		return it.version
 	case "enable", "enabled":
 		return it.Enabled
 	case "sku":
@@ -492,6 +494,8 @@ func (it *DefaultProduct) Set(attribute string, value interface{}) error {
 	switch lowerCaseAttribute {
 	case "_id", "id":
 		it.id = utils.InterfaceToString(value)
+	case db.ConstVersionColumn:
+		it.version = utils.InterfaceToInt(value)
 	case "enable", "enabled":
 		it.Enabled = utils.InterfaceToBool(value)
 	case "sku":
@@ -590,6 +594,7 @@ func (it *DefaultProduct) ToHashMap() map[string]interface{} {
 	result := it.customAttributes.ToHashMap()
 
 	result["_id"] = it.id
+	result[db.ConstVersionColumn] = it.version
 
 	result["enabled"] = it.Enabled
 
@@ -853,6 +858,8 @@ func (it *DefaultProduct) Delete() error {
 		return env.ErrorDispatch(err)
 	}
 
+	env.Event(product.ConstEventProductDelete, map[string]interface{}{"id": it.GetID(), "product": it})
+
 	return nil
 }
 
@@ -877,6 +884,7 @@ func (it *DefaultProduct) Save() error {
 	if err != nil {
 		return env.ErrorDispatch(err)
 	}
+	it.version = db.GetRecordVersion(valuesToStore)
 
 	// set new ID before saving external attributes, because external attributes requires it
 	err = it.SetID(newID)
@@ -889,6 +897,8 @@ func (it *DefaultProduct) Save() error {
 		return env.ErrorDispatch(err)
 	}
 
+	env.Event(product.ConstEventProductSave, map[string]interface{}{"id": it.GetID(), "product": it})
+
 	return nil
 }
 
@@ -6,13 +6,12 @@ import (
 	"strings"
 	"time"
 
-	"github.com/ottemo/commerce/db"
 	"github.com/ottemo/commerce/api"
+	"github.com/ottemo/commerce/db"
 	"github.com/ottemo/commerce/env"
 	"github.com/ottemo/commerce/utils"
 
 	"github.com/ottemo/commerce/app/actors/discount/giftcard"
-	"github.com/ottemo/commerce/app/models"
 	"github.com/ottemo/commerce/app/models/checkout"
 	"github.com/ottemo/commerce/app/models/order"
 )
@@ -23,13 +22,13 @@ func setupAPI() error {
 	// Admin only endpoint
 	service := api.GetRestService()
 
-	service.GET("reporting/product-performance", api.IsAdminHandler(listProductPerformance))
-	service.GET("reporting/customer-activity", api.IsAdminHandler(listCustomerActivity))
-	service.GET("reporting/payment-method", api.IsAdminHandler(listPaymentMethod))
-	service.GET("reporting/shipping-method", api.IsAdminHandler(listShippingMethod))
-	service.GET("reporting/location-country", api.IsAdminHandler(listLocationCountry))
-	service.GET("reporting/location-us", api.IsAdminHandler(listLocationUS))
-	service.GET("reporting/gift-cards", api.IsAdminHandler(listGiftCards))
+	service.GET("reporting/product-performance", api.PermissionHandler(ConstPermissionRead, listProductPerformance))
+	service.GET("reporting/customer-activity", api.PermissionHandler(ConstPermissionRead, listCustomerActivity))
+	service.GET("reporting/payment-method", api.PermissionHandler(ConstPermissionRead, listPaymentMethod))
+	service.GET("reporting/shipping-method", api.PermissionHandler(ConstPermissionRead, listShippingMethod))
+	service.GET("reporting/location-country", api.PermissionHandler(ConstPermissionRead, listLocationCountry))
+	service.GET("reporting/location-us", api.PermissionHandler(ConstPermissionRead, listLocationUS))
+	service.GET("reporting/gift-cards", api.PermissionHandler(ConstPermissionRead, listGiftCards))
 
 	return nil
 }
@@ -46,73 +45,77 @@ func listProductPerformance(context api.InterfaceApplicationContext) (interface{
 		return nil, env.ErrorDispatch(err)
 	}
 
-	foundOrders := order.GetOrdersCreatedBetween(startDate, endDate)
-	foundOrderIds := getOrderIds(foundOrders)
-	foundOrderItems := order.GetItemsForOrders(foundOrderIds)
-	aggregatedResults := aggregateOrderItems(foundOrderItems)
-
-	totalSales := getTotalSales(foundOrderItems)
-
-	response := map[string]interface{}{
-		"total_orders":    len(foundOrders),
-		"total_items":     len(foundOrderItems),
-		"total_sales":     totalSales,
-		"aggregate_items": aggregatedResults,
+	orderCollectionModel, err := order.GetOrderCollectionModel()
+	if err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+	orderCollection := orderCollectionModel.GetDBCollection()
+	if err := orderCollection.AddFilter("created_at", ">=", startDate); err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+	if err := orderCollection.AddFilter("created_at", "<", endDate); err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+	if err := orderCollection.SetResultColumns("_id"); err != nil {
+		return nil, env.ErrorDispatch(err)
 	}
 
-	return response, nil
-}
-
-// getOrderIds Create a list of order ids
-func getOrderIds(foundOrders []models.StructListItem) []string {
-	var orderIds []string
-	for _, foundOrder := range foundOrders {
-		orderIds = append(orderIds, foundOrder.ID)
+	totalOrders, err := orderCollection.Count()
+	if err != nil {
+		return nil, env.ErrorDispatch(err)
 	}
-	return orderIds
-}
 
-// aggregateOrderItems Takes a list of order ids and aggregates their price / qty by their sku
-func aggregateOrderItems(oitems []map[string]interface{}) []ProductPerfItem {
-	keyedResults := make(map[string]ProductPerfItem)
+	// order items of found orders aggregated by their sku
+	orderItemCollectionModel, err := order.GetOrderItemCollectionModel()
+	if err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+	orderItemCollection := orderItemCollectionModel.GetDBCollection()
+	if err := orderItemCollection.AddFilter("order_id", "in", orderCollection); err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
 
-	// Aggregate by sku
-	for _, oitem := range oitems {
-		sku := utils.InterfaceToString(oitem["sku"])
-		item, ok := keyedResults[sku]
+	aggregatedItems, err := orderItemCollection.Aggregate(
+		[]db.StructAggregateGroup{{Column: "sku"}},
+		[]db.StructAggregate{
+			{Function: db.ConstAggregateMax, Column: "name", Name: "name"},
+			{Function: db.ConstAggregateSum, Column: "price", Name: "gross_sales"},
+			{Function: db.ConstAggregateSum, Column: "qty", Name: "units_sold"},
+			{Function: db.ConstAggregateCount, Name: "items"},
+		})
+	if err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
 
-		// First time, set the static details
-		if !ok {
-			item.Name = utils.InterfaceToString(oitem["name"])
-			item.Sku = sku
+	var aggregatedResults ProductPerf
+	var totalItems int
+	var totalSales float64
+	for _, aggregatedItem := range aggregatedItems {
+		item := ProductPerfItem{
+			Name:       utils.InterfaceToString(aggregatedItem["name"]),
+			Sku:        utils.InterfaceToString(aggregatedItem["sku"]),
+			GrossSales: utils.InterfaceToFloat64(aggregatedItem["gross_sales"]),
+			UnitsSold:  utils.InterfaceToInt(aggregatedItem["units_sold"]),
 		}
 
-		item.GrossSales += utils.InterfaceToFloat64(oitem["price"])
-		item.UnitsSold += utils.InterfaceToInt(oitem["qty"])
-
-		keyedResults[sku] = item
-	}
+		totalItems += utils.InterfaceToInt(aggregatedItem["items"])
+		totalSales += item.GrossSales
 
-	// map to slice
-	var results ProductPerf
-	for _, item := range keyedResults {
 		// @TODO: Round money is bad
 		item.GrossSales = utils.RoundPrice(item.GrossSales)
-		results = append(results, item)
+		aggregatedResults = append(aggregatedResults, item)
 	}
 
-	sort.Sort(results)
+	sort.Sort(aggregatedResults)
 
-	return results
-}
-
-func getTotalSales(oitems []map[string]interface{}) float64 {
-	var totalSales float64
-	for _, oitem := range oitems {
-		totalSales += utils.InterfaceToFloat64(oitem["price"])
+	response := map[string]interface{}{
+		"total_orders":    totalOrders,
+		"total_items":     totalItems,
+		"total_sales":     totalSales,
+		"aggregate_items": aggregatedResults,
 	}
 
-	return totalSales
+	return response, nil
 }
 
 func listCustomerActivity(context api.InterfaceApplicationContext) (interface{}, error) {
@@ -126,29 +129,29 @@ func listCustomerActivity(context api.InterfaceApplicationContext) (interface{},
 
 	sortArg := utils.InterfaceToString(context.GetRequestArgument("sort"))
 
-	// Fetch orders
-	oModel, _ := order.GetOrderCollectionModel()
-	if err := oModel.ListAddExtraAttribute("created_at"); err != nil {
-		env.LogError(errors.New("35e5e271-a605-44f3-9bdc-0972cd9e14c8 : " + err.Error()))
-	}
-	if err := oModel.ListAddExtraAttribute("customer_name"); err != nil {
-		env.LogError(errors.New("30426172-a9a1-4d8c-978e-944acce18dde : " + err.Error()))
-	}
-	if err := oModel.ListAddExtraAttribute("customer_email"); err != nil {
-		env.LogError(errors.New("451cf083-dd39-4805-9732-115afd8bd347 : " + err.Error()))
-	}
-	if err := oModel.ListAddExtraAttribute("grand_total"); err != nil {
-		env.LogError(errors.New("d6c9dd6d-40ed-4820-9197-fadea124e46c : " + err.Error()))
-	}
-
-	err := ApplyDateRangeFilter(context, oModel.GetDBCollection())
+	aggregatedOrders, err := aggregateOrders(context,
+		[]db.StructAggregateGroup{{Column: "customer_email"}},
+		db.StructAggregate{Function: db.ConstAggregateMax, Column: "customer_name", Name: "customer_name"},
+		db.StructAggregate{Function: db.ConstAggregateMin, Column: "created_at", Name: "earliest_purchase"},
+		db.StructAggregate{Function: db.ConstAggregateMax, Column: "created_at", Name: "latest_purchase"})
 	if err != nil {
 		return nil, env.ErrorDispatch(err)
 	}
 
-	// This is the lite response StructListItem
-	foundOrders, _ := oModel.List()
-	aggregatedResults := aggregateCustomerActivity(foundOrders)
+	var aggregatedResults []CustomerActivityItem
+	for _, aggregatedOrder := range aggregatedOrders {
+		stats := makeStatItem(aggregatedOrder)
+
+		aggregatedResults = append(aggregatedResults, CustomerActivityItem{
+			Email:            utils.InterfaceToString(aggregatedOrder["customer_email"]),
+			Name:             utils.InterfaceToString(aggregatedOrder["customer_name"]),
+			TotalSales:       stats.TotalSales,
+			TotalOrders:      stats.TotalOrders,
+			AverageSales:     stats.AverageSales,
+			EarliestPurchase: utils.InterfaceToTime(aggregatedOrder["earliest_purchase"]),
+			LatestPurchase:   utils.InterfaceToTime(aggregatedOrder["latest_purchase"]),
+		})
+	}
 	resultCount := len(aggregatedResults)
 
 	// Sorting
@@ -177,114 +180,33 @@ func listCustomerActivity(context api.InterfaceApplicationContext) (interface{},
 	return response, nil
 }
 
-func aggregateCustomerActivity(foundOrders []models.StructListItem) []CustomerActivityItem {
-	keyedResults := make(map[string]CustomerActivityItem)
-
-	for _, o := range foundOrders {
-		email := utils.InterfaceToString(o.Extra["customer_email"])
-		createdAt := utils.InterfaceToTime(o.Extra["created_at"])
-		item, ok := keyedResults[email]
-
-		// First time, set some static props
-		if !ok {
-			item.Email = email
-			item.EarliestPurchase = createdAt
-			item.LatestPurchase = createdAt
-		} else {
-			if createdAt.Before(item.EarliestPurchase) {
-				item.EarliestPurchase = createdAt
-			}
-			if createdAt.After(item.LatestPurchase) {
-				item.LatestPurchase = createdAt
-			}
-		}
-
-		// Name might be empty, early records had a bug
-		if item.Name == "" {
-			item.Name = utils.InterfaceToString(o.Extra["customer_name"])
-		}
-
-		// Aggregated props
-		item.TotalSales += utils.InterfaceToFloat64(o.Extra["grand_total"])
-		item.TotalOrders++
-
-		// Save
-		keyedResults[email] = item
-	}
-
-	// map to slice
-	var results []CustomerActivityItem
-	for _, i := range keyedResults {
-		// Add in averaging stat now that aggregation is complete
-		i.AverageSales = i.TotalSales / float64(i.TotalOrders)
-
-		// Round money
-		i.TotalSales = utils.RoundPrice(i.TotalSales)
-		i.AverageSales = utils.RoundPrice(i.AverageSales)
-
-		results = append(results, i)
-	}
-
-	return results
-}
-
 func listPaymentMethod(context api.InterfaceApplicationContext) (interface{}, error) {
 	perfStart := time.Now()
 
-	// Fetch orders
-	oModel, _ := order.GetOrderCollectionModel()
-	if err := oModel.ListAddExtraAttribute("created_at"); err != nil {
-		env.LogError(errors.New("c63e01a1-2e2d-41a0-a8f9-09b55d25fadc : " + err.Error()))
-	}
-	if err := oModel.ListAddExtraAttribute("payment_method"); err != nil {
-		env.LogError(errors.New("4a53c0a8-67d3-4a1f-97c1-9c2d1f6e625c : " + err.Error()))
-	}
-	if err := oModel.ListAddExtraAttribute("grand_total"); err != nil {
-		env.LogError(errors.New("f7524ade-0983-45a6-9f31-ed11de180680 : " + err.Error()))
+	paymentMethodNames := map[string]string{}
+	for _, m := range checkout.GetRegisteredPaymentMethods() {
+		paymentMethodNames[m.GetCode()] = m.GetInternalName()
 	}
 
-	err := ApplyDateRangeFilter(context, oModel.GetDBCollection())
+	aggregatedOrders, err := aggregateOrders(context, []db.StructAggregateGroup{{Column: "payment_method"}})
 	if err != nil {
 		return nil, env.ErrorDispatch(err)
 	}
 
-	// This is the lite response StructListItem
-	foundOrders, _ := oModel.List()
-	aggregatedResults := aggregatePaymentMethod(foundOrders)
-
-	// Sorting
-	sort.Sort(StatsBySales(aggregatedResults))
-
-	// Calculate extra data points
-	var totalSales float64
-	for _, m := range aggregatedResults {
-		totalSales += m.TotalSales
-	}
-	totalSales = utils.RoundPrice(totalSales)
-
-	response := map[string]interface{}{
-		"aggregate_items": aggregatedResults,
-		"total_sales":     totalSales,
-		"perf_ms":         time.Now().Sub(perfStart).Seconds() * 1e3, // in milliseconds
-	}
-	return response, nil
-}
-
-func aggregatePaymentMethod(foundOrders []models.StructListItem) []StatItem {
-
-	paymentMethodNames := map[string]string{}
+	var aggregatedResults []StatItem
+	for _, aggregatedOrder := range aggregatedOrders {
+		item := makeStatItem(aggregatedOrder)
+		item.Key = utils.InterfaceToString(aggregatedOrder["payment_method"])
+		item.Name = paymentMethodNames[item.Key]
 
-	for _, m := range checkout.GetRegisteredPaymentMethods() {
-		code := m.GetCode()
-		paymentMethodNames[code] = m.GetInternalName()
+		aggregatedResults = append(aggregatedResults, item)
 	}
 
-	aggregateKey := "payment_method"
-
-	return aggregateGeneral(foundOrders, paymentMethodNames, aggregateKey)
+	return makeStatsResponse(perfStart, aggregatedResults), nil
 }
 
-func aggregateShippingMethod(foundOrders []models.StructListItem) []StatItem {
+func listShippingMethod(context api.InterfaceApplicationContext) (interface{}, error) {
+	perfStart := time.Now()
 
 	keyNameMap := map[string]string{}
 	for _, method := range checkout.GetRegisteredShippingMethods() {
@@ -298,203 +220,112 @@ func aggregateShippingMethod(foundOrders []models.StructListItem) []StatItem {
 		}
 	}
 
-	aggregateKey := "shipping_method"
-	return aggregateGeneral(foundOrders, keyNameMap, aggregateKey)
-}
-
-func aggregateGeneral(foundOrders []models.StructListItem, keyNameMap map[string]string, aggregateKey string) []StatItem {
-
-	keyedResults := make(map[string]StatItem)
-
-	for _, o := range foundOrders {
-		key := utils.InterfaceToString(o.Extra[aggregateKey])
-		item, ok := keyedResults[key]
-
-		// First time, set some static props
-		if !ok {
-			item.Key = key
-			item.Name = keyNameMap[key]
-		}
-
-		// Aggregated props
-		item.TotalSales += utils.InterfaceToFloat64(o.Extra["grand_total"])
-		item.TotalOrders++
-
-		// Save
-		keyedResults[key] = item
+	aggregatedOrders, err := aggregateOrders(context, []db.StructAggregateGroup{{Column: "shipping_method"}})
+	if err != nil {
+		return nil, env.ErrorDispatch(err)
 	}
 
-	// map to slice
-	var results []StatItem
-	for _, i := range keyedResults {
-		// Add in averaging stat now that aggregation is complete
-		i.AverageSales = i.TotalSales / float64(i.TotalOrders)
+	var aggregatedResults []StatItem
+	for _, aggregatedOrder := range aggregatedOrders {
+		item := makeStatItem(aggregatedOrder)
+		item.Key = utils.InterfaceToString(aggregatedOrder["shipping_method"])
+		item.Name = keyNameMap[item.Key]
 
-		// Round money
-		i.TotalSales = utils.RoundPrice(i.TotalSales)
-		i.AverageSales = utils.RoundPrice(i.AverageSales)
-
-		results = append(results, i)
+		aggregatedResults = append(aggregatedResults, item)
 	}
 
-	return results
+	return makeStatsResponse(perfStart, aggregatedResults), nil
 }
 
-func listShippingMethod(context api.InterfaceApplicationContext) (interface{}, error) {
+func listLocationCountry(context api.InterfaceApplicationContext) (interface{}, error) {
 	perfStart := time.Now()
 
-	// Fetch orders
-	oModel, _ := order.GetOrderCollectionModel()
-	if err := oModel.ListAddExtraAttribute("created_at"); err != nil {
-		env.LogError(errors.New("4ff4ac4c-8048-41e6-89b6-ab65c03ffbde : " + err.Error()))
-	}
-	if err := oModel.ListAddExtraAttribute("shipping_method"); err != nil {
-		env.LogError(errors.New("8104d737-81a3-4059-8a65-1c5a513930a8 : " + err.Error()))
-	}
-	if err := oModel.ListAddExtraAttribute("grand_total"); err != nil {
-		env.LogError(errors.New("d98106a4-63f9-4f7d-b854-74c6af6a0a33 : " + err.Error()))
-	}
-
-	err := ApplyDateRangeFilter(context, oModel.GetDBCollection())
+	aggregatedOrders, err := aggregateOrders(context, []db.StructAggregateGroup{{Column: "billing_address.country", Name: "country"}})
 	if err != nil {
 		return nil, env.ErrorDispatch(err)
 	}
 
-	// This is the lite response StructListItem
-	foundOrders, _ := oModel.List()
-	aggregatedResults := aggregateShippingMethod(foundOrders)
+	var aggregatedResults []StatItem
+	for _, aggregatedOrder := range aggregatedOrders {
+		item := makeStatItem(aggregatedOrder)
+		item.Name = utils.InterfaceToString(aggregatedOrder["country"])
 
-	// Sorting
-	sort.Sort(StatsBySales(aggregatedResults))
-
-	// Calculate extra data points
-	var totalSales float64
-	for _, m := range aggregatedResults {
-		totalSales += m.TotalSales
+		aggregatedResults = append(aggregatedResults, item)
 	}
-	totalSales = utils.RoundPrice(totalSales)
 
-	response := map[string]interface{}{
-		"aggregate_items": aggregatedResults,
-		"total_sales":     totalSales,
-		"perf_ms":         time.Now().Sub(perfStart).Seconds() * 1e3, // in milliseconds
-	}
-	return response, nil
+	return makeStatsResponse(perfStart, aggregatedResults), nil
 }
 
-func listLocationCountry(context api.InterfaceApplicationContext) (interface{}, error) {
+// list aggregate sales by state for sales in the US
+func listLocationUS(context api.InterfaceApplicationContext) (interface{}, error) {
 	perfStart := time.Now()
 
-	// Fetch orders
-	oModel, _ := order.GetOrderCollectionModel()
-	if err := oModel.ListAddExtraAttribute("created_at"); err != nil {
-		env.LogError(errors.New("fbcc01df-049c-418b-8a15-f3acb595a26f : " + err.Error()))
-	}
-	if err := oModel.ListAddExtraAttribute("billing_address"); err != nil {
-		env.LogError(errors.New("8d7c47d2-382e-4269-8633-fd4c7f5254fc : " + err.Error()))
-	}
-	if err := oModel.ListAddExtraAttribute("grand_total"); err != nil {
-		env.LogError(errors.New("26dbf8cd-b884-4d2d-af4d-d0328ec4e24a : " + err.Error()))
-	}
-
-	err := ApplyDateRangeFilter(context, oModel.GetDBCollection())
+	// grouping by country as well, because not all the db engines are able to filter by JSON column value
+	aggregatedOrders, err := aggregateOrders(context, []db.StructAggregateGroup{
+		{Column: "billing_address.country", Name: "country"},
+		{Column: "billing_address.state", Name: "state"},
+	})
 	if err != nil {
 		return nil, env.ErrorDispatch(err)
 	}
 
-	// This is the lite response StructListItem
-	foundOrders, _ := oModel.List()
-	aggregatedResults := aggregateLocationCountry(foundOrders)
+	var aggregatedResults []StatItem
+	for _, aggregatedOrder := range aggregatedOrders {
+		if utils.InterfaceToString(aggregatedOrder["country"]) != "US" {
+			continue
+		}
 
-	// Sorting
-	sort.Sort(StatsBySales(aggregatedResults))
+		item := makeStatItem(aggregatedOrder)
+		item.Name = utils.InterfaceToString(aggregatedOrder["state"])
 
-	// Calculate extra data points
-	var totalSales float64
-	for _, m := range aggregatedResults {
-		totalSales += m.TotalSales
+		aggregatedResults = append(aggregatedResults, item)
 	}
-	totalSales = utils.RoundPrice(totalSales)
 
-	response := map[string]interface{}{
-		"aggregate_items": aggregatedResults,
-		"total_sales":     totalSales,
-		"perf_ms":         time.Now().Sub(perfStart).Seconds() * 1e3, // in milliseconds
-	}
-	return response, nil
-}
-
-func aggregateLocationCountry(foundOrders []models.StructListItem) []StatItem {
-	return aggregateGeneralNested(foundOrders, "billing_address", "country")
+	return makeStatsResponse(perfStart, aggregatedResults), nil
 }
 
-func aggregateLocationUS(foundOrders []models.StructListItem) []StatItem {
-	return aggregateGeneralNested(foundOrders, "billing_address", "state")
-}
-
-func aggregateGeneralNested(foundOrders []models.StructListItem, aggKeyContainer string, aggKey string) []StatItem {
-	keyedResults := make(map[string]StatItem)
-
-	for _, o := range foundOrders {
-		container := utils.InterfaceToMap(o.Extra[aggKeyContainer])
-		key := utils.InterfaceToString(container[aggKey])
-
-		item, ok := keyedResults[key]
-		if !ok {
-			item.Name = key
-		}
-
-		// Aggregate props
-		item.TotalSales += utils.InterfaceToFloat64(o.Extra["grand_total"])
-		item.TotalOrders++
-
-		// Save
-		keyedResults[key] = item
+// aggregateOrders calculates sales totals of orders within request date range grouped by given keys
+//   - "total_sales" and "total_orders" aggregates are always calculated, extra aggregates could be specified
+func aggregateOrders(context api.InterfaceApplicationContext, groupBy []db.StructAggregateGroup, extraAggregates ...db.StructAggregate) ([]map[string]interface{}, error) {
+	orderCollectionModel, err := order.GetOrderCollectionModel()
+	if err != nil {
+		return nil, env.ErrorDispatch(err)
 	}
+	orderCollection := orderCollectionModel.GetDBCollection()
 
-	// map to slice
-	var results []StatItem
-	for _, i := range keyedResults {
-		// Add in averaging stat now that aggregation is complete
-		i.AverageSales = i.TotalSales / float64(i.TotalOrders)
-
-		// Round money
-		i.TotalSales = utils.RoundPrice(i.TotalSales)
-		i.AverageSales = utils.RoundPrice(i.AverageSales)
+	if err := ApplyDateRangeFilter(context, orderCollection); err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
 
-		results = append(results, i)
+	aggregates := []db.StructAggregate{
+		{Function: db.ConstAggregateSum, Column: "grand_total", Name: "total_sales"},
+		{Function: db.ConstAggregateCount, Name: "total_orders"},
 	}
 
-	return results
+	return orderCollection.Aggregate(groupBy, append(aggregates, extraAggregates...))
 }
 
-// list aggregate sales by state for sales in the US
-func listLocationUS(context api.InterfaceApplicationContext) (interface{}, error) {
-	perfStart := time.Now()
-
-	// Fetch orders
-	oModel, _ := order.GetOrderCollectionModel()
-	if err := oModel.ListAddExtraAttribute("created_at"); err != nil {
-		env.LogError(errors.New("afee38b6-7744-45d1-a202-fa3e38cebb77 : " + err.Error()))
-	}
-	if err := oModel.ListAddExtraAttribute("billing_address"); err != nil {
-		env.LogError(errors.New("d425730d-3298-44db-8312-686b6a31fa2b : " + err.Error()))
-	}
-	if err := oModel.ListFilterAdd("billing_address.country", "=", "US"); err != nil {
-		env.LogError(errors.New("ca701edb-dad9-41ff-afa5-b8b71e9dbef8 : " + err.Error()))
-	}
-	if err := oModel.ListAddExtraAttribute("grand_total"); err != nil {
-		env.LogError(errors.New("c2a7884f-30ec-46cf-9453-723fba5c4a72 : " + err.Error()))
+// makeStatItem makes StatItem from aggregateOrders(...) result record
+func makeStatItem(aggregatedOrder map[string]interface{}) StatItem {
+	item := StatItem{
+		TotalSales:  utils.InterfaceToFloat64(aggregatedOrder["total_sales"]),
+		TotalOrders: utils.InterfaceToInt(aggregatedOrder["total_orders"]),
 	}
 
-	err := ApplyDateRangeFilter(context, oModel.GetDBCollection())
-	if err != nil {
-		return nil, env.ErrorDispatch(err)
+	// Add in averaging stat
+	if item.TotalOrders > 0 {
+		item.AverageSales = item.TotalSales / float64(item.TotalOrders)
 	}
 
-	// This is the lite response StructListItem
-	foundOrders, _ := oModel.List()
-	aggregatedResults := aggregateLocationUS(foundOrders)
+	// Round money
+	item.TotalSales = utils.RoundPrice(item.TotalSales)
+	item.AverageSales = utils.RoundPrice(item.AverageSales)
+
+	return item
+}
+
+// makeStatsResponse sorts StatItems and makes response for stats endpoints
+func makeStatsResponse(perfStart time.Time, aggregatedResults []StatItem) map[string]interface{} {
 
 	// Sorting
 	sort.Sort(StatsBySales(aggregatedResults))
@@ -506,12 +337,11 @@ func listLocationUS(context api.InterfaceApplicationContext) (interface{}, error
 	}
 	totalSales = utils.RoundPrice(totalSales)
 
-	response := map[string]interface{}{
+	return map[string]interface{}{
 		"aggregate_items": aggregatedResults,
 		"total_sales":     totalSales,
 		"perf_ms":         time.Now().Sub(perfStart).Seconds() * 1e3, // in milliseconds
 	}
-	return response, nil
 }
 
 // listGiftCards returns information about gift cards
@@ -7,6 +7,8 @@ import (
 // Package global constants
 const (
 
+	ConstPermissionRead = "reports.read"
+
 	ConstErrorModule = "reporting"
 	ConstErrorLevel  = 6
 )
@@ -183,7 +183,9 @@ func APIGetVisitsDetails(context api.InterfaceApplicationContext) (interface{},
 		_ = env.ErrorNew(ConstErrorModule, ConstErrorLevel, "701b181a-19e9-49ba-8b37-595477daecbe", err.Error())
 	}
 
-	dbRecords, err := visitorInfoCollection.Load()
+	dbRecords, err := visitorInfoCollection.Aggregate(
+		[]db.StructAggregateGroup{{Column: "day", Bucket: getAggregateBucket(timeScope)}},
+		[]db.StructAggregate{{Function: db.ConstAggregateSum, Column: "visitors", Name: "visitors"}})
 	if err != nil {
 		return nil, env.ErrorDispatch(err)
 	}
@@ -386,7 +388,9 @@ func APIGetSalesDetails(context api.InterfaceApplicationContext) (interface{}, e
 		_ = env.ErrorNew(ConstErrorModule, ConstErrorLevel, "121d715e-564d-41d0-ae51-91fbd0bea342", err.Error())
 	}
 
-	dbRecords, err := visitorInfoCollection.Load()
+	dbRecords, err := visitorInfoCollection.Aggregate(
+		[]db.StructAggregateGroup{{Column: "day", Bucket: getAggregateBucket(timeScope)}},
+		[]db.StructAggregate{{Function: db.ConstAggregateSum, Column: "sales_amount", Name: "sales_amount"}})
 	if err != nil {
 		return nil, env.ErrorDispatch(err)
 	}
@@ -472,7 +476,10 @@ func APIGetBestsellers(context api.InterfaceApplicationContext) (interface{}, er
 		_ = env.ErrorNew(ConstErrorModule, ConstErrorLevel, "480fbb9d-6272-4da4-94e7-0763379870e2", err.Error())
 	}
 
-	collectionRecords, err := salesHistoryCollection.Load()
+	// count the products sales by product id
+	collectionRecords, err := salesHistoryCollection.Aggregate(
+		[]db.StructAggregateGroup{{Column: "product_id"}},
+		[]db.StructAggregate{{Function: db.ConstAggregateSum, Column: "count", Name: "count"}})
 	if err != nil {
 		return nil, env.ErrorDispatch(err)
 	}
@@ -481,11 +488,8 @@ func APIGetBestsellers(context api.InterfaceApplicationContext) (interface{}, er
 	productSales := make(map[string]int)
 	var productsToSort, bestSellers []map[string]interface{}
 
-	// count the products sales by product id
 	for _, item := range collectionRecords {
-		pid := utils.InterfaceToString(item["product_id"])
-		count := utils.InterfaceToInt(item["count"])
-		productSales[pid] = count + productSales[pid]
+		productSales[utils.InterfaceToString(item["product_id"])] = utils.InterfaceToInt(item["count"])
 	}
 
 	// populate the bestseller data
@@ -88,13 +88,38 @@ func initSalesHistory() error {
 		return env.ErrorDispatch(err)
 	}
 
-	// get order items collection
+	// order creation time by order id
+	orderCreatedAt := make(map[string]time.Time)
+	for _, orderRecord := range ordersForPeriod {
+		orderCreatedAt[utils.InterfaceToString(orderRecord["_id"])] = utils.InterfaceToTime(orderRecord["created_at"])
+	}
+
+	if len(orderCreatedAt) == 0 {
+		return nil
+	}
+
+	// the same orders collection is used as sub-query for order items
+	if err := dbOrderCollection.SetResultColumns("_id"); err != nil {
+		return env.ErrorDispatch(err)
+	}
+
+	// get order items quantities summarized by order and product
 	orderItemCollectionModel, err := order.GetOrderItemCollectionModel()
 	if err != nil {
 		return env.ErrorDispatch(err)
 	}
 
 	dbOrderItemCollection := orderItemCollectionModel.GetDBCollection()
+	if err := dbOrderItemCollection.AddFilter("order_id", "in", dbOrderCollection); err != nil {
+		return env.ErrorDispatch(err)
+	}
+
+	orderItems, err := dbOrderItemCollection.Aggregate(
+		[]db.StructAggregateGroup{{Column: "order_id"}, {Column: "product_id"}},
+		[]db.StructAggregate{{Function: db.ConstAggregateSum, Column: "qty", Name: "qty"}})
+	if err != nil {
+		return env.ErrorDispatch(err)
+	}
 
 	// get sales history collection
 	salesHistoryCollection, err := db.GetCollection(ConstCollectionNameRTSSalesHistory)
@@ -106,41 +131,21 @@ func initSalesHistory() error {
 
 	// collect data from all orders into salesHistoryData
 	// in format map[pid][time]qty
-	for _, order := range ordersForPeriod {
-
-		if err := dbOrderItemCollection.ClearFilters(); err != nil {
-			_ = env.ErrorNew(ConstErrorModule, ConstErrorLevel, "f3d10114-dd6c-45e2-b385-0e92913a8fcd", err.Error())
-		}
-		if err := dbOrderItemCollection.AddFilter("order_id", "=", order["_id"]); err != nil {
-			_ = env.ErrorNew(ConstErrorModule, ConstErrorLevel, "fc15ac2d-7d3f-466b-8450-4fad508e714e", err.Error())
-		}
-		if err := dbOrderItemCollection.SetResultColumns("product_id", "qty"); err != nil {
-			_ = env.ErrorNew(ConstErrorModule, ConstErrorLevel, "2d4b396c-b975-46ba-ac7d-5b227b8ae4ba", err.Error())
-		}
-		orderItems, err := dbOrderItemCollection.Load()
-		if err != nil {
-			return env.ErrorDispatch(err)
-		}
+	for _, orderItem := range orderItems {
 
 		// collect records by time with rounding top on hour basics -- all orders which are saved to sales_history
 		// would be rounded on one hour up order at time 17;16 -> 18;00
-		currentDateUnix := utils.InterfaceToTime(order["created_at"]).Truncate(time.Hour).Add(time.Hour).Unix()
-
-		for _, orderItem := range orderItems {
-			currentProductID := utils.InterfaceToString(orderItem["product_id"])
-			count := utils.InterfaceToInt(orderItem["qty"])
-
-			// collect data to salesHistoryData
-			if productInfo, present := salesHistoryData[currentProductID]; present {
-				if oldCounter, ok := productInfo[currentDateUnix]; ok {
-					salesHistoryData[currentProductID][currentDateUnix] = count + oldCounter
-				} else {
-					salesHistoryData[currentProductID][currentDateUnix] = count
-				}
-			} else {
-				salesHistoryData[currentProductID] = map[int64]int{currentDateUnix: count}
-			}
+		createdAt := orderCreatedAt[utils.InterfaceToString(orderItem["order_id"])]
+		currentDateUnix := createdAt.Truncate(time.Hour).Add(time.Hour).Unix()
+
+		currentProductID := utils.InterfaceToString(orderItem["product_id"])
+		count := utils.InterfaceToInt(orderItem["qty"])
+
+		// collect data to salesHistoryData
+		if _, present := salesHistoryData[currentProductID]; !present {
+			salesHistoryData[currentProductID] = make(map[int64]int)
 		}
+		salesHistoryData[currentProductID][currentDateUnix] += count
 	}
 
 	// save records to database
@@ -266,12 +271,20 @@ func initStatistic() error {
 		_ = env.ErrorNew(ConstErrorModule, ConstErrorLevel, "7b655d07-b069-4ce7-870f-5640eb8f7562", err.Error())
 	}
 
-	dbRecords, err = visitorInfoCollection.Load()
+	monthRecords, err := visitorInfoCollection.Aggregate(nil, []db.StructAggregate{
+		{Function: db.ConstAggregateSum, Column: "total_visits", Name: "total_visits"},
+		{Function: db.ConstAggregateSum, Column: "sales_amount", Name: "sales_amount"},
+		{Function: db.ConstAggregateSum, Column: "visitors", Name: "visitors"},
+		{Function: db.ConstAggregateSum, Column: "sales", Name: "sales"},
+		{Function: db.ConstAggregateSum, Column: "visit_checkout", Name: "visit_checkout"},
+		{Function: db.ConstAggregateSum, Column: "set_payment", Name: "set_payment"},
+		{Function: db.ConstAggregateSum, Column: "cart", Name: "cart"},
+	})
 	if err != nil {
 		return env.ErrorDispatch(err)
 	}
 
-	for _, item := range dbRecords {
+	for _, item := range monthRecords {
 		monthStatistic.TotalVisits += utils.InterfaceToInt(item["total_visits"])
 		monthStatistic.SalesAmount += utils.InterfaceToFloat64(item["sales_amount"])
 		monthStatistic.Visit += utils.InterfaceToInt(item["visitors"])
@@ -279,7 +292,6 @@ func initStatistic() error {
 		monthStatistic.VisitCheckout += utils.InterfaceToInt(item["visit_checkout"])
 		monthStatistic.SetPayment += utils.InterfaceToInt(item["set_payment"])
 		monthStatistic.Cart += utils.InterfaceToInt(item["cart"])
-
 	}
 
 	return nil
@@ -388,3 +400,11 @@ func CheckHourUpdateForStatistic() {
 
 	lastUpdate = time.Now()
 }
+
+// getAggregateBucket returns db aggregation date bucket for statistics time scope
+func getAggregateBucket(timeScope time.Duration) string {
+	if timeScope >= ConstTimeDay {
+		return db.ConstAggregateBucketDay
+	}
+	return db.ConstAggregateBucketHour
+}
@@ -0,0 +1,93 @@
+package search
+
+import (
+	"strings"
+
+	"github.com/ottemo/commerce/api"
+	"github.com/ottemo/commerce/env"
+	"github.com/ottemo/commerce/search"
+	"github.com/ottemo/commerce/utils"
+
+	"github.com/ottemo/commerce/app/models"
+)
+
+// setupAPI setups package related API endpoint routines
+func setupAPI() error {
+	service := api.GetRestService()
+
+	service.GET("search", APISearch)
+
+	// admin
+	service.POST("search/reindex", api.PermissionHandler(ConstPermissionWrite, APIReindex))
+
+	return nil
+}
+
+// APISearch returns ranked documents matching search query with facet values counts
+//   - query text should be specified in "q" argument, blank query matches all documents
+//   - "type" argument limits document types, comma separated (product, cms_page, blog_post)
+//   - "facet.[name]" arguments filter documents by facet values, comma separated
+//   - "limit" argument is "[offset],[limit]" or "[limit]"
+func APISearch(context api.InterfaceApplicationContext) (interface{}, error) {
+	searchIndex, err := getSearchIndex()
+	if err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
+	query := search.StructSearchQuery{
+		Text:   context.GetRequestArgument("q"),
+		Facets: make(map[string][]string),
+	}
+
+	for name, value := range context.GetRequestArguments() {
+		if strings.HasPrefix(name, ConstFacetArgumentPrefix) {
+			query.Facets[strings.TrimPrefix(name, ConstFacetArgumentPrefix)] = splitArgument(value)
+		}
+	}
+
+	if value := context.GetRequestArgument("type"); value != "" {
+		query.Types = splitArgument(value)
+	}
+
+	query.Offset, query.Limit = models.GetListLimit(context)
+	if query.Limit <= 0 {
+		query.Limit = ConstDefaultLimit
+	}
+	if query.Limit > ConstMaxLimit {
+		query.Limit = ConstMaxLimit
+	}
+
+	result, err := searchIndex.Search(query)
+	if err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
+	return result, nil
+}
+
+// APIReindex rebuilds search index
+//   - "type" argument limits document type to rebuild index for, all types are rebuilt if not specified
+func APIReindex(context api.InterfaceApplicationContext) (interface{}, error) {
+	documentType := context.GetRequestArgument("type")
+	if documentType != "" && !utils.IsInListStr(documentType, []string{search.ConstDocumentTypeProduct, search.ConstDocumentTypeCMSPage, search.ConstDocumentTypeBlogPost}) {
+		return nil, env.ErrorNew(ConstErrorModule, env.ConstErrorLevelAPI, "0c502cd3-b010-4fdf-9f95-4e1cda56c2e2", "unknown document type '"+documentType+"'")
+	}
+
+	result, err := reindex(documentType)
+	if err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
+	return result, nil
+}
+
+// splitArgument splits comma separated argument value
+func splitArgument(value string) []string {
+	var result []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
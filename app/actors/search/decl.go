@@ -0,0 +1,29 @@
+// Package search is a full-text search module, it keeps products, cms pages and blog posts indexed within search index
+// service declared in "github.com/ottemo/commerce/search" package and provides search API
+package search
+
+import (
+	"sync"
+
+	"github.com/ottemo/commerce/env"
+)
+
+// Package global constants
+const (
+	ConstDefaultLimit = 20  // hits amount returned by search API if limit was not specified
+	ConstMaxLimit     = 100 // maximal hits amount could be requested by search API
+
+	ConstFacetArgumentPrefix = "facet." // prefix of search API arguments to filter hits by facet values
+
+	ConstPermissionWrite = "catalog.write"
+
+	ConstErrorModule = "search"
+	ConstErrorLevel  = env.ConstErrorLevelActor
+)
+
+// Package global variables
+var (
+	appStarted      bool       // application start event happened
+	databaseStarted bool       // database start event happened
+	startMutex      sync.Mutex // synchronization of start flags
+)
@@ -0,0 +1,79 @@
+package search
+
+import (
+	"github.com/ottemo/commerce/api"
+	"github.com/ottemo/commerce/app"
+	"github.com/ottemo/commerce/db"
+	"github.com/ottemo/commerce/env"
+
+	"github.com/ottemo/commerce/app/models/blog/post"
+	"github.com/ottemo/commerce/app/models/cms"
+	"github.com/ottemo/commerce/app/models/product"
+)
+
+// init makes package self-initialization routine
+func init() {
+	env.EventRegisterListener(product.ConstEventProductSave, productSaveHandler)
+	env.EventRegisterListener(product.ConstEventProductDelete, productDeleteHandler)
+	env.EventRegisterListener(cms.ConstEventCMSPageSave, cmsPageSaveHandler)
+	env.EventRegisterListener(cms.ConstEventCMSPageDelete, cmsPageDeleteHandler)
+	env.EventRegisterListener(post.ConstEventBlogPostSave, blogPostSaveHandler)
+	env.EventRegisterListener(post.ConstEventBlogPostDelete, blogPostDeleteHandler)
+
+	api.RegisterOnRestServiceStart(setupAPI)
+	db.RegisterOnDatabaseStart(onDatabaseStart)
+	app.OnAppStart(onAppStart)
+	app.OnAppEnd(onAppEnd)
+}
+
+// onAppStart builds search index if it is blank and database is already started
+func onAppStart() error {
+	startMutex.Lock()
+	defer startMutex.Unlock()
+
+	appStarted = true
+	if databaseStarted {
+		startIndexing()
+	}
+
+	return nil
+}
+
+// onDatabaseStart builds search index if it is blank and application is already started
+//   - database could be connected asynchronously after application start
+func onDatabaseStart() error {
+	startMutex.Lock()
+	defer startMutex.Unlock()
+
+	databaseStarted = true
+	if appStarted {
+		startIndexing()
+	}
+
+	return nil
+}
+
+// startIndexing builds search index in background if it is blank
+//   - should be called under startMutex
+func startIndexing() {
+	searchIndex, err := getSearchIndex()
+	if err != nil || searchIndex.Count("") > 0 {
+		return
+	}
+
+	go func() {
+		if _, err := reindex(""); err != nil {
+			_ = env.ErrorDispatch(err)
+		}
+	}()
+}
+
+// onAppEnd writes pending search index changes to disk
+func onAppEnd() error {
+	searchIndex, err := getSearchIndex()
+	if err != nil {
+		return nil
+	}
+
+	return searchIndex.Flush()
+}
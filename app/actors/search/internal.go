@@ -0,0 +1,275 @@
+package search
+
+import (
+	"strings"
+
+	"github.com/ottemo/commerce/db"
+	"github.com/ottemo/commerce/env"
+	"github.com/ottemo/commerce/search"
+	"github.com/ottemo/commerce/utils"
+
+	blogPostActor "github.com/ottemo/commerce/app/actors/blog/post"
+	"github.com/ottemo/commerce/app/models/blog/post"
+	"github.com/ottemo/commerce/app/models/cms"
+	"github.com/ottemo/commerce/app/models/product"
+)
+
+// getSearchIndex returns registered search index service
+func getSearchIndex() (search.InterfaceSearchIndex, error) {
+	searchIndex, err := search.GetSearchIndex()
+	if err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+	return searchIndex, nil
+}
+
+// updateIndex indexes document or removes it from search index if it should not be searchable
+func updateIndex(document search.StructSearchDocument, isSearchable bool) {
+	searchIndex, err := search.GetSearchIndex()
+	if err != nil || document.ID == "" {
+		return
+	}
+
+	if isSearchable {
+		err = searchIndex.Index(document)
+	} else {
+		err = searchIndex.Remove(document.Type, document.ID)
+	}
+
+	if err != nil {
+		_ = env.ErrorDispatch(err)
+	}
+}
+
+// removeFromIndex removes document from search index
+func removeFromIndex(documentType string, documentID string) {
+	searchIndex, err := search.GetSearchIndex()
+	if err != nil || documentID == "" {
+		return
+	}
+
+	if err := searchIndex.Remove(documentType, documentID); err != nil {
+		_ = env.ErrorDispatch(err)
+	}
+}
+
+// getFacetValues converts attribute value to facet values
+func getFacetValues(value interface{}) []string {
+	var result []string
+
+	if stringValue, ok := value.(string); ok {
+		value = []interface{}{stringValue}
+	}
+
+	for _, item := range utils.InterfaceToArray(value) {
+		if stringValue := utils.InterfaceToString(item); stringValue != "" {
+			result = append(result, stringValue)
+		}
+	}
+
+	return result
+}
+
+// makeProductDocument makes search document for a product, layered attributes are used as facets
+func makeProductDocument(productModel product.InterfaceProduct) search.StructSearchDocument {
+	document := search.StructSearchDocument{
+		Type:  search.ConstDocumentTypeProduct,
+		ID:    productModel.GetID(),
+		Title: productModel.GetName(),
+		Content: strings.Join([]string{
+			productModel.GetSku(),
+			productModel.GetShortDescription(),
+			search.StripHTML(productModel.GetDescription()),
+		}, "\n"),
+		Facets: make(map[string][]string),
+		Data: map[string]interface{}{
+			"name":              productModel.GetName(),
+			"sku":               productModel.GetSku(),
+			"price":             productModel.GetPrice(),
+			"short_description": productModel.GetShortDescription(),
+			"default_image":     productModel.GetDefaultImage(),
+		},
+	}
+
+	for _, attribute := range productModel.GetAttributesInfo() {
+		if attribute.IsLayered {
+			if values := getFacetValues(productModel.Get(attribute.Attribute)); len(values) > 0 {
+				document.Facets[attribute.Attribute] = values
+			}
+		}
+	}
+
+	return document
+}
+
+// makeCMSPageDocument makes search document for a cms page
+func makeCMSPageDocument(cmsPageModel cms.InterfaceCMSPage) search.StructSearchDocument {
+	return search.StructSearchDocument{
+		Type:    search.ConstDocumentTypeCMSPage,
+		ID:      cmsPageModel.GetID(),
+		Title:   cmsPageModel.GetTitle(),
+		Content: search.StripHTML(cmsPageModel.GetContent()),
+		Data: map[string]interface{}{
+			"identifier": cmsPageModel.GetIdentifier(),
+			"title":      cmsPageModel.GetTitle(),
+		},
+	}
+}
+
+// makeBlogPostDocument makes search document for a blog post, tags are used as facet
+func makeBlogPostDocument(blogPostModel post.InterfaceBlogPost) search.StructSearchDocument {
+	return search.StructSearchDocument{
+		Type:    search.ConstDocumentTypeBlogPost,
+		ID:      blogPostModel.GetID(),
+		Title:   blogPostModel.GetTitle(),
+		Content: search.StripHTML(blogPostModel.GetExcerpt() + "\n" + blogPostModel.GetContent()),
+		Facets: map[string][]string{
+			"tags": getFacetValues(blogPostModel.GetTags()),
+		},
+		Data: map[string]interface{}{
+			"identifier":     blogPostModel.GetIdentifier(),
+			"title":          blogPostModel.GetTitle(),
+			"excerpt":        blogPostModel.GetExcerpt(),
+			"featured_image": blogPostModel.GetFeaturedImage(),
+			"created_at":     blogPostModel.GetCreatedAt(),
+		},
+	}
+}
+
+// reindex rebuilds search index for given document type, blank type rebuilds all types
+//   - returns amount of indexed documents by type
+func reindex(documentType string) (map[string]int, error) {
+	if dbEngine := db.GetDBEngine(); dbEngine == nil || !dbEngine.IsConnected() {
+		return nil, env.ErrorNew(ConstErrorModule, ConstErrorLevel, "6aa01412-3a84-4bdc-a2ea-78ddcc954434", "database is not connected")
+	}
+
+	searchIndex, err := getSearchIndex()
+	if err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
+	result := make(map[string]int)
+
+	if documentType == "" || documentType == search.ConstDocumentTypeProduct {
+		if err := searchIndex.Clear(search.ConstDocumentTypeProduct); err != nil {
+			return result, env.ErrorDispatch(err)
+		}
+
+		productCollectionModel, err := product.GetProductCollectionModel()
+		if err != nil {
+			return result, env.ErrorDispatch(err)
+		}
+
+		for _, productModel := range productCollectionModel.ListProducts() {
+			if productModel.GetEnabled() {
+				if err := searchIndex.Index(makeProductDocument(productModel)); err != nil {
+					return result, env.ErrorDispatch(err)
+				}
+				result[search.ConstDocumentTypeProduct]++
+			}
+		}
+	}
+
+	if documentType == "" || documentType == search.ConstDocumentTypeCMSPage {
+		if err := searchIndex.Clear(search.ConstDocumentTypeCMSPage); err != nil {
+			return result, env.ErrorDispatch(err)
+		}
+
+		cmsPageCollectionModel, err := cms.GetCMSPageCollectionModel()
+		if err != nil {
+			return result, env.ErrorDispatch(err)
+		}
+
+		for _, cmsPageModel := range cmsPageCollectionModel.ListCMSPages() {
+			if cmsPageModel.GetEnabled() {
+				if err := searchIndex.Index(makeCMSPageDocument(cmsPageModel)); err != nil {
+					return result, env.ErrorDispatch(err)
+				}
+				result[search.ConstDocumentTypeCMSPage]++
+			}
+		}
+	}
+
+	if documentType == "" || documentType == search.ConstDocumentTypeBlogPost {
+		if err := searchIndex.Clear(search.ConstDocumentTypeBlogPost); err != nil {
+			return result, env.ErrorDispatch(err)
+		}
+
+		collection, err := db.GetCollection(blogPostActor.ConstBlogPostCollectionName)
+		if err != nil {
+			return result, env.ErrorDispatch(err)
+		}
+
+		if err := collection.AddFilter("published", "=", true); err != nil {
+			return result, env.ErrorDispatch(err)
+		}
+
+		err = collection.Iterate(func(record map[string]interface{}) bool {
+			blogPostModel, err := post.GetBlogPostModel()
+			if err == nil {
+				err = blogPostModel.FromHashMap(record)
+			}
+			if err == nil {
+				err = searchIndex.Index(makeBlogPostDocument(blogPostModel))
+			}
+			if err != nil {
+				_ = env.ErrorDispatch(err)
+				return true
+			}
+
+			result[search.ConstDocumentTypeBlogPost]++
+			return true
+		})
+		if err != nil {
+			return result, env.ErrorDispatch(err)
+		}
+	}
+
+	if err := searchIndex.Flush(); err != nil {
+		return result, env.ErrorDispatch(err)
+	}
+
+	return result, nil
+}
+
+// productSaveHandler updates product within search index
+func productSaveHandler(event string, eventData map[string]interface{}) bool {
+	if productModel, ok := eventData["product"].(product.InterfaceProduct); ok {
+		updateIndex(makeProductDocument(productModel), productModel.GetEnabled())
+	}
+	return true
+}
+
+// productDeleteHandler removes product from search index
+func productDeleteHandler(event string, eventData map[string]interface{}) bool {
+	removeFromIndex(search.ConstDocumentTypeProduct, utils.InterfaceToString(eventData["id"]))
+	return true
+}
+
+// cmsPageSaveHandler updates cms page within search index
+func cmsPageSaveHandler(event string, eventData map[string]interface{}) bool {
+	if cmsPageModel, ok := eventData["page"].(cms.InterfaceCMSPage); ok {
+		updateIndex(makeCMSPageDocument(cmsPageModel), cmsPageModel.GetEnabled())
+	}
+	return true
+}
+
+// cmsPageDeleteHandler removes cms page from search index
+func cmsPageDeleteHandler(event string, eventData map[string]interface{}) bool {
+	removeFromIndex(search.ConstDocumentTypeCMSPage, utils.InterfaceToString(eventData["id"]))
+	return true
+}
+
+// blogPostSaveHandler updates blog post within search index
+func blogPostSaveHandler(event string, eventData map[string]interface{}) bool {
+	if blogPostModel, ok := eventData["post"].(post.InterfaceBlogPost); ok {
+		updateIndex(makeBlogPostDocument(blogPostModel), blogPostModel.IsPublished())
+	}
+	return true
+}
+
+// blogPostDeleteHandler removes blog post from search index
+func blogPostDeleteHandler(event string, eventData map[string]interface{}) bool {
+	removeFromIndex(search.ConstDocumentTypeBlogPost, utils.InterfaceToString(eventData["id"]))
+	return true
+}
@@ -25,7 +25,7 @@ func setupAPI() error {
 	service := api.GetRestService()
 
 	service.GET("seo/items", APIListSEOItems)
-	service.GET("seo/attributes", api.IsAdminHandler(APIListSeoAttributes))
+	service.GET("seo/attributes", api.PermissionHandler(ConstPermissionRead, APIListSeoAttributes))
 
 	service.GET("seo/url", APIGetSEOItem)
 	service.GET("seo/canonical/:id", APIGetSEOItemByID)
@@ -34,9 +34,9 @@ func setupAPI() error {
 	service.GET("seo/sitemap/sitemap.xml", APIGetSitemap)
 
 	// Admin Only
-	service.POST("seo/item", api.IsAdminHandler(APICreateSEOItem))
-	service.PUT("seo/item/:itemID", api.IsAdminHandler(APIUpdateSEOItem))
-	service.DELETE("seo/item/:itemID", api.IsAdminHandler(APIDeleteSEOItem))
+	service.POST("seo/item", api.PermissionHandler(ConstPermissionWrite, APICreateSEOItem))
+	service.PUT("seo/item/:itemID", api.PermissionHandler(ConstPermissionWrite, APIUpdateSEOItem))
+	service.DELETE("seo/item/:itemID", api.PermissionHandler(ConstPermissionWrite, APIDeleteSEOItem))
 
 	return nil
 }
@@ -77,6 +77,11 @@ func APIListSEOItems(context api.InterfaceApplicationContext) (interface{}, erro
 		return nil, env.ErrorDispatch(err)
 	}
 
+	// next page cursor handle
+	if err := models.SetListNextCursor(context, seoItemCollectionModel); err != nil {
+		_ = env.ErrorNew(ConstErrorModule, ConstErrorLevel, "0968c800-f231-4cb4-b7e7-f5ddae3fbcb3", err.Error())
+	}
+
 	return listItems, nil
 }
 
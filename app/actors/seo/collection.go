@@ -134,6 +134,16 @@ func (it *DefaultSEOCollection) ListLimit(offset int, limit int) error {
 	return it.listCollection.SetLimit(offset, limit)
 }
 
+// ListCursor switches selection to cursor based paging, blank cursor means first page
+func (it *DefaultSEOCollection) ListCursor(cursor string) error {
+	return it.listCollection.SetCursor(cursor)
+}
+
+// ListNextCursor returns cursor to select page following the last listed one
+func (it *DefaultSEOCollection) ListNextCursor() string {
+	return it.listCollection.GetNextCursor()
+}
+
 // -----------------------------------------------------------------------------------------------------
 // InterfaceSEOCollection implementation (package "github.com/ottemo/commerce/app/models/seo")
 // -----------------------------------------------------------------------------------------------------
@@ -13,6 +13,9 @@ const (
 	ConstSitemapFilePath  = "sitemap.xml"
 	ConstSitemapExpireSec = 60 * 60 * 24
 
+	ConstPermissionRead  = "seo.read"
+	ConstPermissionWrite = "seo.write"
+
 	ConstErrorModule = "seo"
 	ConstErrorLevel  = env.ConstErrorLevelActor
 )
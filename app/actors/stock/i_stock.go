@@ -399,54 +399,56 @@ func (it *DefaultStock) SetProductQty(productID string, options map[string]inter
 }
 
 // UpdateProductQty updates stock qty for a product-options pair on delta value which can be positive or negative
+//   - records are updated within a DB transaction, so partial updates are not possible
 func (it *DefaultStock) UpdateProductQty(productID string, options map[string]interface{}, deltaQty int) error {
+	return db.InTransaction(func() error {
+		// receiving database information
+		dbCollection, err := db.GetCollection(ConstCollectionNameStock)
+		if err != nil {
+			return env.ErrorDispatch(err)
+		}
 
-	// receiving database information
-	dbCollection, err := db.GetCollection(ConstCollectionNameStock)
-	if err != nil {
-		return env.ErrorDispatch(err)
-	}
+		err = dbCollection.AddFilter("product_id", "=", productID)
+		if err != nil {
+			return env.ErrorDispatch(err)
+		}
 
-	err = dbCollection.AddFilter("product_id", "=", productID)
-	if err != nil {
-		return env.ErrorDispatch(err)
-	}
+		dbRecords, err := dbCollection.Load()
+		if err != nil {
+			return env.ErrorDispatch(err)
+		}
 
-	dbRecords, err := dbCollection.Load()
-	if err != nil {
-		return env.ErrorDispatch(err)
-	}
+		// looking for records matching request
+		recordsProcessed := 0
+		for _, dbRecord := range dbRecords {
+			if !utils.StrKeysInMap(dbRecord, "_id", "qty", "options") {
+				_ = env.ErrorNew(ConstErrorModule, ConstErrorLevel, "f99c2f4b-ab56-4aa6-9950-00dcb09c15fd", "unexpected db result")
+				break
+			}
 
-	// looking for records matching request
-	recordsProcessed := 0
-	for _, dbRecord := range dbRecords {
-		if !utils.StrKeysInMap(dbRecord, "_id", "qty", "options") {
-			_ = env.ErrorNew(ConstErrorModule, ConstErrorLevel, "f99c2f4b-ab56-4aa6-9950-00dcb09c15fd", "unexpected db result")
-			break
-		}
+			recordOptions, ok := dbRecord["options"].(map[string]interface{})
 
-		recordOptions, ok := dbRecord["options"].(map[string]interface{})
+			// skipping un-matching records
+			if !ok || !utils.MatchMapAValuesToMapB(recordOptions, options) {
+				continue
+			}
 
-		// skipping un-matching records
-		if !ok || !utils.MatchMapAValuesToMapB(recordOptions, options) {
-			continue
+			// TODO: should work through collection update function
+			qty := utils.InterfaceToInt(dbRecord["qty"])
+			dbRecord["qty"] = qty + deltaQty
+			_, err := dbCollection.Save(dbRecord)
+			if err != nil {
+				return env.ErrorDispatch(err)
+			}
+			recordsProcessed++
 		}
 
-		// TODO: should work through collection update function
-		qty := utils.InterfaceToInt(dbRecord["qty"])
-		dbRecord["qty"] = qty + deltaQty
-		_, err := dbCollection.Save(dbRecord)
-		if err != nil {
-			return env.ErrorDispatch(err)
+		if recordsProcessed == 0 {
+			return env.ErrorDispatch(env.ErrorNew(ConstErrorModule, 1, "62214642-d384-4474-b45c-e5fe8424fc3a", "Was given a set of options that didn't match any stock options in the db"))
 		}
-		recordsProcessed++
-	}
 
-	if recordsProcessed == 0 {
-		return env.ErrorDispatch(env.ErrorNew(ConstErrorModule, 1, "62214642-d384-4474-b45c-e5fe8424fc3a", "Was given a set of options that didn't match any stock options in the db"))
-	}
-
-	return nil
+		return nil
+	})
 }
 
 // Load loads model from storage
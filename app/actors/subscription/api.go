@@ -18,10 +18,10 @@ func setupAPI() error {
 	service := api.GetRestService()
 
 	// Administrative
-	service.GET("subscriptions", api.IsAdminHandler(APIListSubscriptions))
-	service.GET("subscriptions/:id", api.IsAdminHandler(APIGetSubscription))
+	service.GET("subscriptions", api.PermissionHandler(ConstPermissionRead, APIListSubscriptions))
+	service.GET("subscriptions/:id", api.PermissionHandler(ConstPermissionRead, APIGetSubscription))
 	service.PUT("subscriptions/:id", APIUpdateSubscription)
-	service.GET("update/subscriptions", api.IsAdminHandler(APIUpdateSubscriptionInfo))
+	service.GET("update/subscriptions", api.PermissionHandler(ConstPermissionWrite, APIUpdateSubscriptionInfo))
 
 	// Public
 	service.GET("visit/subscriptions", APIListVisitorSubscriptions)
@@ -62,7 +62,17 @@ func APIListSubscriptions(context api.InterfaceApplicationContext) (interface{},
 		_ = env.ErrorNew(ConstErrorModule, ConstErrorLevel, "b46343e6-b707-40fe-a842-680b98456aca", err.Error())
 	}
 
-	return subscriptionCollectionModel.List()
+	listItems, err := subscriptionCollectionModel.List()
+	if err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
+	// next page cursor handle
+	if err := models.SetListNextCursor(context, subscriptionCollectionModel); err != nil {
+		_ = env.ErrorNew(ConstErrorModule, ConstErrorLevel, "7424994e-955c-41d8-b24f-1a65c73d124d", err.Error())
+	}
+
+	return listItems, nil
 }
 
 // APIListVisitorSubscriptions returns a list of subscriptions for visitor
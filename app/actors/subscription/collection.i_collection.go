@@ -85,3 +85,13 @@ func (it *DefaultSubscriptionCollection) ListFilterReset() error {
 func (it *DefaultSubscriptionCollection) ListLimit(offset int, limit int) error {
 	return it.listCollection.SetLimit(offset, limit)
 }
+
+// ListCursor switches selection to cursor based paging, blank cursor means first page
+func (it *DefaultSubscriptionCollection) ListCursor(cursor string) error {
+	return it.listCollection.SetCursor(cursor)
+}
+
+// ListNextCursor returns cursor to select page following the last listed one
+func (it *DefaultSubscriptionCollection) ListNextCursor() string {
+	return it.listCollection.GetNextCursor()
+}
@@ -12,6 +12,9 @@ import (
 
 // Package global constants
 const (
+	ConstPermissionRead  = "subscriptions.read"
+	ConstPermissionWrite = "subscriptions.write"
+
 	ConstErrorModule = "subscription"
 	ConstErrorLevel  = env.ConstErrorLevelActor
 
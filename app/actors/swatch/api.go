@@ -21,8 +21,8 @@ func setupAPI() error {
 	service.GET("swatch/media/extention", getDefaultExtention)
 
 	// Admin only
-	service.POST("swatch/media", api.IsAdminHandler(createSwatch))
-	service.DELETE("swatch/media/:mediaName", api.IsAdminHandler(deleteByName))
+	service.POST("swatch/media", api.PermissionHandler(ConstPermissionWrite, createSwatch))
+	service.DELETE("swatch/media/:mediaName", api.PermissionHandler(ConstPermissionWrite, deleteByName))
 
 	return nil
 }
@@ -7,6 +7,8 @@ import (
 
 // Package global constants
 const (
+	ConstPermissionWrite = "catalog.write"
+
 	ConstErrorModule = "swatch"
 
 	ConstStorageModel     = "swatch"
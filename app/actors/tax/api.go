@@ -16,8 +16,8 @@ func setupAPI() error {
 
 	service := api.GetRestService()
 
-	service.GET("taxes/csv", api.IsAdminHandler(APIDownloadTaxCSV))
-	service.POST("taxes/csv", api.IsAdminHandler(
+	service.GET("taxes/csv", api.PermissionHandler(ConstPermissionRead, APIDownloadTaxCSV))
+	service.POST("taxes/csv", api.PermissionHandler(ConstPermissionWrite,
 		impex.ImportStartHandler(
 			api.AsyncHandler(APIUploadTaxCSV, impex.ImportResultHandler))))
 
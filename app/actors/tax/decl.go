@@ -8,6 +8,9 @@ import (
 
 // Package global constants
 const (
+	ConstPermissionRead  = "taxes.read"
+	ConstPermissionWrite = "taxes.write"
+
 	ConstErrorModule = "tax"
 	ConstErrorLevel  = env.ConstErrorLevelActor
 
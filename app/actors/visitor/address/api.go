@@ -19,7 +19,7 @@ func setupAPI() error {
 
 	service.GET("visitor/:visitorID/addresses", APIListVisitorAddresses)
 
-	service.GET("visitors/addresses/attributes", api.IsAdminHandler(APIListVisitorAddressAttributes))
+	service.GET("visitors/addresses/attributes", api.PermissionHandler(ConstPermissionRead, APIListVisitorAddressAttributes))
 	service.DELETE("visitors/address/:addressID", APIDeleteVisitorAddress)
 	service.PUT("visitors/address/:addressID", APIUpdateVisitorAddress)
 	service.GET("visitors/address/:addressID", APIGetVisitorAddress)
@@ -219,7 +219,17 @@ func APIListVisitorAddresses(context api.InterfaceApplicationContext) (interface
 		_ = env.ErrorNew(ConstErrorModule, ConstErrorLevel, "fe8a4498-c21d-4492-a6dd-010fcfa52bec", err.Error())
 	}
 
-	return visitorAddressCollectionModel.List()
+	listItems, err := visitorAddressCollectionModel.List()
+	if err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
+	// next page cursor handle
+	if err := models.SetListNextCursor(context, visitorAddressCollectionModel); err != nil {
+		_ = env.ErrorNew(ConstErrorModule, ConstErrorLevel, "a37a1406-3252-479c-b094-19c052defcf2", err.Error())
+	}
+
+	return listItems, nil
 }
 
 // APIGetVisitorAddress returns visitor address information
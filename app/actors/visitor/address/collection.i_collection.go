@@ -95,3 +95,13 @@ func (it *DefaultVisitorAddressCollection) ListFilterReset() error {
 func (it *DefaultVisitorAddressCollection) ListLimit(offset int, limit int) error {
 	return it.listCollection.SetLimit(offset, limit)
 }
+
+// ListCursor switches selection to cursor based paging, blank cursor means first page
+func (it *DefaultVisitorAddressCollection) ListCursor(cursor string) error {
+	return it.listCollection.SetCursor(cursor)
+}
+
+// ListNextCursor returns cursor to select page following the last listed one
+func (it *DefaultVisitorAddressCollection) ListNextCursor() string {
+	return it.listCollection.GetNextCursor()
+}
@@ -10,6 +10,8 @@ import (
 const (
 	ConstCollectionNameVisitorAddress = "visitor_address"
 
+	ConstPermissionRead = "visitors.read"
+
 	ConstErrorModule = "visitor/address"
 	ConstErrorLevel  = env.ConstErrorLevelActor
 )
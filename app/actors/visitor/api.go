@@ -11,6 +11,7 @@ import (
 
 	"github.com/ottemo/commerce/api"
 	"github.com/ottemo/commerce/app"
+	"github.com/ottemo/commerce/db"
 	"github.com/ottemo/commerce/env"
 	"github.com/ottemo/commerce/utils"
 
@@ -26,17 +27,23 @@ func setupAPI() error {
 	service := api.GetRestService()
 
 	// Dashboard API
-	service.POST("visitor", api.IsAdminHandler(APICreateVisitor))
+	service.POST("visitor", api.PermissionHandler(ConstPermissionWrite, APICreateVisitor))
 	service.PUT("visitor/:visitorID", APIUpdateVisitor)
-	service.DELETE("visitor/:visitorID", api.IsAdminHandler(APIDeleteVisitor))
-	service.GET("visitor/:visitorID", api.IsAdminHandler(APIGetVisitor))
+	service.DELETE("visitor/:visitorID", api.PermissionHandler(ConstPermissionWrite, APIDeleteVisitor))
+	service.GET("visitor/:visitorID", api.PermissionHandler(ConstPermissionRead, APIGetVisitor))
 
-	service.GET("visitors", api.IsAdminHandler(APIListVisitors))
+	service.GET("visitors", api.PermissionHandler(ConstPermissionRead, APIListVisitors))
 	service.GET("visitors/attributes", APIListVisitorAttributes)
-	service.DELETE("visitors/attribute/:attribute", api.IsAdminHandler(APIDeleteVisitorAttribute))
-	service.PUT("visitors/attribute/:attribute", api.IsAdminHandler(APIUpdateVisitorAttribute))
-	service.POST("visitors/attribute", api.IsAdminHandler(APICreateVisitorAttribute))
-	service.GET("visitors/guests", api.IsAdminHandler(APIGetGuestsList))
+	service.DELETE("visitors/attribute/:attribute", api.PermissionHandler(ConstPermissionWrite, APIDeleteVisitorAttribute))
+	service.PUT("visitors/attribute/:attribute", api.PermissionHandler(ConstPermissionWrite, APIUpdateVisitorAttribute))
+	service.POST("visitors/attribute", api.PermissionHandler(ConstPermissionWrite, APICreateVisitorAttribute))
+	service.GET("visitors/guests", api.PermissionHandler(ConstPermissionRead, APIGetGuestsList))
+
+	service.GET("visitors/roles", api.PermissionHandler(ConstPermissionRoles, APIListRoles))
+	service.POST("visitors/roles", api.PermissionHandler(ConstPermissionRoles, APICreateRole))
+	service.PUT("visitors/roles/:code", api.PermissionHandler(ConstPermissionRoles, APIUpdateRole))
+	service.DELETE("visitors/roles/:code", api.PermissionHandler(ConstPermissionRoles, APIDeleteRole))
+	service.GET("visitors/permissions", api.PermissionHandler(ConstPermissionRoles, APIListPermissions))
 
 	// Storefront API
 	service.POST("visitors/register", APIRegisterVisitor)
@@ -51,7 +58,19 @@ func setupAPI() error {
 	service.GET("visit", APIGetVisit)
 	service.PUT("visit", APIUpdateVisitor)
 	service.GET("visit/logout", APILogout)
-	service.POST("visit/login", APILogin)
+	service.POST("visit/login", APILogin, &api.StructRouteMeta{
+		Summary:     "Log in visitor",
+		Description: "authorizes current session, session cookie is returned in response",
+		Request: map[string]interface{}{
+			"type":     "object",
+			"required": []string{"email", "password"},
+			"properties": map[string]interface{}{
+				"email":    map[string]interface{}{"type": "string", "format": "email"},
+				"password": map[string]interface{}{"type": "string"},
+			},
+		},
+		Response: map[string]interface{}{"type": "string"},
+	})
 	service.POST("visit/login-facebook", APIFacebookLogin)
 	service.POST("visit/login-google", APIGoogleLogin)
 
@@ -130,6 +149,10 @@ func APICreateVisitor(context api.InterfaceApplicationContext) (interface{}, err
 		return nil, env.ErrorDispatch(err)
 	}
 
+	if err := validateRightsChange(context, requestData); err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
 	if !utils.KeysInMapAndNotBlank(requestData, "email") {
 		return nil, env.ErrorNew(ConstErrorModule, env.ConstErrorLevelAPI, "a9610b78-add9-4ae5-b757-59462b646d2b", "No email address specified, please specify an email address.")
 	}
@@ -190,7 +213,19 @@ func APIUpdateVisitor(context api.InterfaceApplicationContext) (interface{}, err
 		return nil, env.ErrorDispatch(err)
 	}
 
-	if !api.IsAdminSession(context) {
+	if err := validateRightsChange(context, requestData); err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
+	// admin account could be changed by admin or account owner only
+	if visitorModel.IsAdmin() && visitor.GetCurrentVisitorID(context) != visitorID {
+		if err := api.ValidateAdminRights(context); err != nil {
+			context.SetResponseStatusForbidden()
+			return nil, env.ErrorDispatch(err)
+		}
+	}
+
+	if !api.HasPermission(context, ConstPermissionWrite) {
 		// Visitor. Not admin.
 		if visitor.GetCurrentVisitorID(context) != visitorID {
 			return nil, env.ErrorDispatch(err)
@@ -278,11 +313,19 @@ func APIDeleteVisitor(context api.InterfaceApplicationContext) (interface{}, err
 
 	// delete operation
 	//-----------------
-	visitorModel, err := visitor.GetVisitorModelAndSetID(visitorID)
+	visitorModel, err := visitor.LoadVisitorByID(visitorID)
 	if err != nil {
 		return nil, env.ErrorDispatch(err)
 	}
 
+	// admin account could be deleted by admin only
+	if visitorModel.IsAdmin() {
+		if err := api.ValidateAdminRights(context); err != nil {
+			context.SetResponseStatusForbidden()
+			return nil, env.ErrorDispatch(err)
+		}
+	}
+
 	err = visitorModel.Delete()
 	if err != nil {
 		return nil, env.ErrorDispatch(err)
@@ -339,7 +382,17 @@ func APIListVisitors(context api.InterfaceApplicationContext) (interface{}, erro
 		_ = env.ErrorNew(ConstErrorModule, env.ConstErrorLevelAPI, "57962242-bf9c-4c11-847a-a21656a378b1", err.Error())
 	}
 
-	return visitorCollectionModel.List()
+	listItems, err := visitorCollectionModel.List()
+	if err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
+	// next page cursor handle
+	if err := models.SetListNextCursor(context, visitorCollectionModel); err != nil {
+		_ = env.ErrorNew(ConstErrorModule, env.ConstErrorLevelAPI, "0040cd64-ab02-4e20-87b2-9a2beeddc549", err.Error())
+	}
+
+	return listItems, nil
 }
 
 // APIListVisitorAttributes returns a list of visitor attributes
@@ -838,8 +891,8 @@ func APILogin(context api.InterfaceApplicationContext) (interface{}, error) {
 		return nil, env.ErrorNew(ConstErrorModule, env.ConstErrorLevelAPI, "29fba7a4-bd85-400e-81c2-69189c50d0d0", "This account has not been verfied, please check your email account: ,"+visitorModel.GetEmail()+" for a verification link sent to you.")
 	}
 
-	if visitorModel.IsAdmin() {
-		context.GetSession().Set(api.ConstSessionKeyAdminRights, true)
+	if err := setSessionRights(context, visitorModel); err != nil {
+		return nil, env.ErrorDispatch(err)
 	}
 
 	return "ok", nil
@@ -952,8 +1005,8 @@ func APIFacebookLogin(context api.InterfaceApplicationContext) (interface{}, err
 	// api session updates
 	context.GetSession().Set(visitor.ConstSessionKeyVisitorID, visitorModel.GetID())
 
-	if visitorModel.IsAdmin() {
-		context.GetSession().Set(api.ConstSessionKeyAdminRights, true)
+	if err := setSessionRights(context, visitorModel); err != nil {
+		return nil, env.ErrorDispatch(err)
 	}
 
 	return "ok", nil
@@ -1053,8 +1106,8 @@ func APIGoogleLogin(context api.InterfaceApplicationContext) (interface{}, error
 	// api session updates
 	context.GetSession().Set(visitor.ConstSessionKeyVisitorID, visitorModel.GetID())
 
-	if visitorModel.IsAdmin() {
-		context.GetSession().Set(api.ConstSessionKeyAdminRights, true)
+	if err := setSessionRights(context, visitorModel); err != nil {
+		return nil, env.ErrorDispatch(err)
 	}
 
 	return "ok", nil
@@ -1090,3 +1143,127 @@ func APIMailToVisitor(context api.InterfaceApplicationContext) (interface{}, err
 
 	return "ok", nil
 }
+
+// APIListRoles returns roles visitors could be assigned with
+func APIListRoles(context api.InterfaceApplicationContext) (interface{}, error) {
+	collection, err := db.GetCollection(ConstCollectionNameVisitorRole)
+	if err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
+	if err := collection.AddSort("code", false); err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
+	records, err := collection.Load()
+	if err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
+	result := make([]map[string]interface{}, 0, len(records))
+	for _, record := range records {
+		result = append(result, map[string]interface{}{
+			"code":        record["code"],
+			"name":        record["name"],
+			"permissions": normalizePermissions(record["permissions"]),
+		})
+	}
+
+	return result, nil
+}
+
+// APICreateRole creates new visitor role
+//   - "code" attribute required, "name" and "permissions" attributes are optional
+func APICreateRole(context api.InterfaceApplicationContext) (interface{}, error) {
+	requestData, err := api.GetRequestContentAsMap(context)
+	if err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
+	code := strings.TrimSpace(utils.InterfaceToString(requestData["code"]))
+	if code == "" || strings.ContainsAny(code, " ,") {
+		context.SetResponseStatusBadRequest()
+		return nil, env.ErrorNew(ConstErrorModule, env.ConstErrorLevelAPI, "620158b3-40c3-4551-ae50-59fac343dcde", "role code should be specified without spaces and commas")
+	}
+
+	collection, record, err := loadRole(code)
+	if err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+	if record != nil {
+		context.SetResponseStatus(http.StatusConflict)
+		return nil, env.ErrorNew(ConstErrorModule, env.ConstErrorLevelAPI, "7d435f5b-bab3-447d-977a-6f2c707ec8d9", "role '"+code+"' already exists")
+	}
+
+	record = map[string]interface{}{
+		"code":        code,
+		"name":        utils.InterfaceToString(requestData["name"]),
+		"permissions": normalizePermissions(requestData["permissions"]),
+	}
+	if _, err := collection.Save(record); err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+	resetRightsCache("")
+
+	return record, nil
+}
+
+// APIUpdateRole updates name and/or permissions of visitor role
+//   - role code should be specified in "code" argument
+//   - visitors get updated permissions within ConstRightsCacheLifetime
+func APIUpdateRole(context api.InterfaceApplicationContext) (interface{}, error) {
+	requestData, err := api.GetRequestContentAsMap(context)
+	if err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
+	code := context.GetRequestArgument("code")
+	collection, record, err := loadRole(code)
+	if err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+	if record == nil {
+		context.SetResponseStatusNotFound()
+		return nil, env.ErrorNew(ConstErrorModule, env.ConstErrorLevelAPI, "691b972b-2fd9-4254-8b31-5abe9e9ebe4a", "role '"+code+"' not found")
+	}
+
+	if value, present := requestData["name"]; present {
+		record["name"] = utils.InterfaceToString(value)
+	}
+	if value, present := requestData["permissions"]; present {
+		record["permissions"] = normalizePermissions(value)
+	}
+
+	if _, err := collection.Save(record); err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+	resetRightsCache("")
+
+	return record, nil
+}
+
+// APIDeleteRole deletes visitor role, visitors assigned with it lose its permissions within ConstRightsCacheLifetime
+//   - role code should be specified in "code" argument
+func APIDeleteRole(context api.InterfaceApplicationContext) (interface{}, error) {
+	code := context.GetRequestArgument("code")
+	collection, record, err := loadRole(code)
+	if err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+	if record == nil {
+		context.SetResponseStatusNotFound()
+		return nil, env.ErrorNew(ConstErrorModule, env.ConstErrorLevelAPI, "2b3b32cf-b5b2-406f-8a25-802329150cc3", "role '"+code+"' not found")
+	}
+
+	if err := collection.DeleteByID(utils.InterfaceToString(record["_id"])); err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+	resetRightsCache("")
+
+	return "ok", nil
+}
+
+// APIListPermissions returns permissions API handlers are checking, to be used within roles
+func APIListPermissions(context api.InterfaceApplicationContext) (interface{}, error) {
+	return api.GetPermissions(), nil
+}
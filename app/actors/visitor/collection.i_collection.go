@@ -96,3 +96,13 @@ func (it *DefaultVisitorCollection) ListFilterReset() error {
 func (it *DefaultVisitorCollection) ListLimit(offset int, limit int) error {
 	return it.listCollection.SetLimit(offset, limit)
 }
+
+// ListCursor switches selection to cursor based paging, blank cursor means first page
+func (it *DefaultVisitorCollection) ListCursor(cursor string) error {
+	return it.listCollection.SetCursor(cursor)
+}
+
+// ListNextCursor returns cursor to select page following the last listed one
+func (it *DefaultVisitorCollection) ListNextCursor() string {
+	return it.listCollection.GetNextCursor()
+}
@@ -2,6 +2,7 @@
 package visitor
 
 import (
+	"sync"
 	"time"
 
 	"github.com/ottemo/commerce/app/helpers/attributes"
@@ -12,12 +13,20 @@ import (
 
 // Package global constants
 const (
-	ConstCollectionNameVisitor = "visitor"
+	ConstCollectionNameVisitor     = "visitor"
+	ConstCollectionNameVisitorRole = "visitor_role"
 
 	ConstEmailVerifyExpire = 60 * 60 * 24
 
 	ConstEmailPasswordResetExpire = 30 * 60
 
+	ConstPermissionRead  = "visitors.read"
+	ConstPermissionWrite = "visitors.write"
+	ConstPermissionRoles = "visitors.roles"
+
+	ConstRightsCacheLifetime = 30 * time.Second // time resolved visitor permissions are used before reload
+	ConstRightsCacheSize     = 10000            // amount of visitors cache is reset after
+
 	ConstErrorModule = "visitor"
 	ConstErrorLevel  = env.ConstErrorLevelActor
 
@@ -25,6 +34,18 @@ const (
 	ConstConfigPathLostPasswordEmailTemplate = "general.mail.lost_password_email_template"
 )
 
+// Package global variables
+var (
+	rightsCache      = make(map[string]*rightsCacheItem) // resolved permissions of visitors by visitor id
+	rightsCacheMutex sync.RWMutex                        // synchronization of rightsCache
+)
+
+// rightsCacheItem is a resolved permissions of visitor
+type rightsCacheItem struct {
+	permissions []string
+	expiresAt   time.Time
+}
+
 // DefaultVisitor is a default implementer of InterfaceVisitor
 type DefaultVisitor struct {
 	id string
@@ -45,6 +66,7 @@ type DefaultVisitor struct {
 	VerificationKey string
 
 	Admin bool
+	Roles []string
 
 	CreatedAt time.Time
 
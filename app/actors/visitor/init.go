@@ -25,6 +25,10 @@ func init() {
 	db.RegisterOnDatabaseStart(setupDB)
 	api.RegisterOnRestServiceStart(setupAPI)
 	env.RegisterOnConfigStart(setupConfig)
+
+	if err := api.RegisterPermissionsResolver(resolveSessionPermissions); err != nil {
+		_ = env.ErrorNew(ConstErrorModule, ConstErrorLevel, "815c808f-aeec-44a8-8d24-6f98f14a3997", err.Error())
+	}
 }
 
 // setupDB prepares system database for package usage
@@ -75,6 +79,24 @@ func setupDB() error {
 	if err := collection.AddColumn("created_at", db.ConstTypeDatetime, false); err != nil {
 		return env.ErrorNew(ConstErrorModule, ConstErrorLevel, "a0689e88-acab-4134-b8eb-713345d07ff5", err.Error())
 	}
+	if err := collection.AddColumn("roles", db.TypeArrayOf(db.ConstTypeVarchar), false); err != nil {
+		return env.ErrorNew(ConstErrorModule, ConstErrorLevel, "092462a2-dbef-41a8-8800-6d8ab912848d", err.Error())
+	}
+
+	collection, err = db.GetCollection(ConstCollectionNameVisitorRole)
+	if err != nil {
+		return env.ErrorDispatch(err)
+	}
+
+	if err := collection.AddColumn("code", db.TypeWPrecision(db.ConstTypeVarchar, 100), true); err != nil {
+		return env.ErrorNew(ConstErrorModule, ConstErrorLevel, "a5666b73-daad-4627-91e6-291aed59d2f4", err.Error())
+	}
+	if err := collection.AddColumn("name", db.TypeWPrecision(db.ConstTypeVarchar, 255), false); err != nil {
+		return env.ErrorNew(ConstErrorModule, ConstErrorLevel, "d8eb76e1-bfbd-4db0-9862-07f136574847", err.Error())
+	}
+	if err := collection.AddColumn("permissions", db.TypeArrayOf(db.ConstTypeVarchar), false); err != nil {
+		return env.ErrorNew(ConstErrorModule, ConstErrorLevel, "d28aaac4-f646-432e-bca5-90e0ac36154d", err.Error())
+	}
 
 	return nil
 }
@@ -0,0 +1,190 @@
+package visitor
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ottemo/commerce/api"
+	"github.com/ottemo/commerce/app/models/visitor"
+	"github.com/ottemo/commerce/db"
+	"github.com/ottemo/commerce/env"
+	"github.com/ottemo/commerce/utils"
+)
+
+// loadRole returns role record by code with collection it was loaded from
+func loadRole(code string) (db.InterfaceDBCollection, map[string]interface{}, error) {
+	collection, err := db.GetCollection(ConstCollectionNameVisitorRole)
+	if err != nil {
+		return nil, nil, env.ErrorDispatch(err)
+	}
+
+	if err := collection.AddFilter("code", "=", code); err != nil {
+		return nil, nil, env.ErrorDispatch(err)
+	}
+
+	records, err := collection.Load()
+	if err != nil {
+		return nil, nil, env.ErrorDispatch(err)
+	}
+	if len(records) == 0 {
+		return collection, nil, nil
+	}
+
+	return collection, records[0], nil
+}
+
+// normalizePermissions converts value to sorted list of unique non blank permissions
+func normalizePermissions(value interface{}) []string {
+	unique := make(map[string]bool)
+	for _, permission := range utils.InterfaceToStringArray(value) {
+		if permission = strings.TrimSpace(permission); permission != "" {
+			unique[permission] = true
+		}
+	}
+
+	result := make([]string, 0, len(unique))
+	for permission := range unique {
+		result = append(result, permission)
+	}
+	sort.Strings(result)
+
+	return result
+}
+
+// GetRolesPermissions returns permissions granted with given roles
+func GetRolesPermissions(roles []string) ([]string, error) {
+	if len(roles) == 0 {
+		return []string{}, nil
+	}
+
+	collection, err := db.GetCollection(ConstCollectionNameVisitorRole)
+	if err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
+	if err := collection.AddFilter("code", "in", roles); err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
+	records, err := collection.Load()
+	if err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
+	var result []interface{}
+	for _, record := range records {
+		for _, permission := range utils.InterfaceToStringArray(record["permissions"]) {
+			result = append(result, permission)
+		}
+	}
+
+	return normalizePermissions(result), nil
+}
+
+// setSessionRights grants session with rights of logged in visitor
+//   - admin visitor have all permissions, others have permissions of their roles resolved on each request
+func setSessionRights(context api.InterfaceApplicationContext, visitorModel visitor.InterfaceVisitor) error {
+	if visitorModel.IsAdmin() {
+		context.GetSession().Set(api.ConstSessionKeyAdminRights, true)
+		return nil
+	}
+
+	resetRightsCache(visitorModel.GetID())
+
+	return nil
+}
+
+// getVisitorPermissions returns permissions of visitor roles, they are cached for ConstRightsCacheLifetime
+func getVisitorPermissions(visitorID string) ([]string, error) {
+	rightsCacheMutex.RLock()
+	item, present := rightsCache[visitorID]
+	rightsCacheMutex.RUnlock()
+
+	if present && item.expiresAt.After(time.Now()) {
+		return item.permissions, nil
+	}
+
+	collection, err := db.GetCollection(ConstCollectionNameVisitor)
+	if err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+	if err := collection.AddFilter("_id", "=", visitorID); err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+	records, err := collection.Load()
+	if err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
+	// removed visitor has no permissions
+	permissions := []string{}
+	if len(records) > 0 {
+		if permissions, err = GetRolesPermissions(utils.InterfaceToStringArray(records[0]["roles"])); err != nil {
+			return nil, env.ErrorDispatch(err)
+		}
+	}
+
+	rightsCacheMutex.Lock()
+	if len(rightsCache) >= ConstRightsCacheSize {
+		rightsCache = make(map[string]*rightsCacheItem)
+	}
+	rightsCache[visitorID] = &rightsCacheItem{permissions: permissions, expiresAt: time.Now().Add(ConstRightsCacheLifetime)}
+	rightsCacheMutex.Unlock()
+
+	return permissions, nil
+}
+
+// resetRightsCache drops cached permissions of visitor, or of all visitors for blank visitor id
+//   - other application instances are picking changes up after ConstRightsCacheLifetime
+func resetRightsCache(visitorID string) {
+	rightsCacheMutex.Lock()
+	defer rightsCacheMutex.Unlock()
+
+	if visitorID == "" {
+		rightsCache = make(map[string]*rightsCacheItem)
+	} else {
+		delete(rightsCache, visitorID)
+	}
+}
+
+// resolveSessionPermissions is a permissions resolver registered in api package
+//   - permissions of signed in visitor are resolved from visitor roles, so role changes apply to live sessions
+func resolveSessionPermissions(session api.InterfaceSession) ([]string, bool) {
+	if session == nil {
+		return nil, false
+	}
+
+	visitorID := utils.InterfaceToString(session.Get(visitor.ConstSessionKeyVisitorID))
+	if visitorID == "" {
+		return nil, false
+	}
+
+	permissions, err := getVisitorPermissions(visitorID)
+	if err != nil {
+		env.LogError(err)
+		return []string{}, true
+	}
+
+	return permissions, true
+}
+
+// validateRightsChange checks session is allowed to change rights related attributes given in request
+//   - "is_admin" requires admin rights, "roles" requires roles permission
+func validateRightsChange(context api.InterfaceApplicationContext, requestData map[string]interface{}) error {
+	if _, present := requestData["is_admin"]; present {
+		if err := api.ValidateAdminRights(context); err != nil {
+			context.SetResponseStatusForbidden()
+			return env.ErrorDispatch(err)
+		}
+	}
+
+	if _, present := requestData["roles"]; present {
+		if err := api.ValidatePermission(context, ConstPermissionRoles); err != nil {
+			context.SetResponseStatusForbidden()
+			return env.ErrorDispatch(err)
+		}
+	}
+
+	return nil
+}
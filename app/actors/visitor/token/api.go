@@ -191,7 +191,17 @@ func APIListVisitorCards(context api.InterfaceApplicationContext) (interface{},
 		_ = env.ErrorNew(ConstErrorModule, env.ConstErrorLevelAPI, "c78e4f66-5722-4849-bc69-f006c91900f8", "token_id was not specified")
 	}
 
-	return visitorCardCollectionModel.List()
+	listItems, err := visitorCardCollectionModel.List()
+	if err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
+	// next page cursor handle
+	if err := models.SetListNextCursor(context, visitorCardCollectionModel); err != nil {
+		_ = env.ErrorNew(ConstErrorModule, env.ConstErrorLevelAPI, "a5695a91-1062-4493-b166-eb231dc06847", err.Error())
+	}
+
+	return listItems, nil
 }
 
 // APIDeleteToken deletes credit card token by provided token_id
@@ -86,3 +86,13 @@ func (it *DefaultVisitorCardCollection) ListFilterReset() error {
 func (it *DefaultVisitorCardCollection) ListLimit(offset int, limit int) error {
 	return it.listCollection.SetLimit(offset, limit)
 }
+
+// ListCursor switches selection to cursor based paging, blank cursor means first page
+func (it *DefaultVisitorCardCollection) ListCursor(cursor string) error {
+	return it.listCollection.SetCursor(cursor)
+}
+
+// ListNextCursor returns cursor to select page following the last listed one
+func (it *DefaultVisitorCardCollection) ListNextCursor() string {
+	return it.listCollection.GetNextCursor()
+}
@@ -50,6 +50,8 @@ func (it *DefaultVisitor) Get(attribute string) interface{} {
 		return it.GoogleID
 	case "is_admin":
 		return it.IsAdmin()
+	case "roles":
+		return it.GetRoles()
 	case "created_at":
 		return it.CreatedAt
 	}
@@ -82,6 +84,8 @@ func (it *DefaultVisitor) Set(attribute string, value interface{}) error {
 		it.GoogleID = utils.InterfaceToString(value)
 	case "is_admin":
 		it.Admin = utils.InterfaceToBool(value)
+	case "roles":
+		it.Roles = normalizePermissions(value)
 	case "created_at":
 		it.CreatedAt = utils.InterfaceToTime(value)
 
@@ -233,6 +237,7 @@ func (it *DefaultVisitor) ToHashMap() map[string]interface{} {
 	result["last_name"] = it.LastName
 
 	result["is_admin"] = it.Admin
+	result["roles"] = it.GetRoles()
 	result["created_at"] = it.CreatedAt
 
 	result["billing_address"] = nil
@@ -392,6 +397,19 @@ func (it *DefaultVisitor) GetAttributesInfo() []models.StructAttributeInfo {
 			Options:    "",
 			Default:    "false",
 		},
+		models.StructAttributeInfo{
+			Model:      visitor.ConstModelNameVisitor,
+			Collection: ConstCollectionNameVisitor,
+			Attribute:  "roles",
+			Type:       db.TypeArrayOf(db.ConstTypeVarchar),
+			IsRequired: false,
+			IsStatic:   true,
+			Label:      "Roles",
+			Group:      "General",
+			Editors:    "multi_select",
+			Options:    "",
+			Default:    "",
+		},
 	}
 
 	customAttributesInfo := it.ModelCustomAttributes.GetAttributesInfo()
@@ -61,6 +61,7 @@ func (it *DefaultVisitor) Delete() error {
 	if err != nil {
 		return env.ErrorDispatch(err)
 	}
+	resetRightsCache(it.GetID())
 
 	return nil
 }
@@ -136,5 +137,8 @@ func (it *DefaultVisitor) Save() error {
 		return env.ErrorDispatch(err)
 	}
 
+	// visitor roles could be changed
+	resetRightsCache(it.GetID())
+
 	return nil
 }
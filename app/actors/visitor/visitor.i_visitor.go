@@ -85,6 +85,14 @@ func (it *DefaultVisitor) IsAdmin() bool {
 	return it.Admin
 }
 
+// GetRoles returns codes of roles visitor was assigned with
+func (it *DefaultVisitor) GetRoles() []string {
+	if it.Roles == nil {
+		return []string{}
+	}
+	return it.Roles
+}
+
 // IsGuest returns true if instance represents guest visitor
 func (it *DefaultVisitor) IsGuest() bool {
 	return it.GetGoogleID() == "" && it.GetFacebookID() == "" && it.GetEmail() == ""
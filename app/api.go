@@ -25,6 +25,9 @@ func setupAPI() error {
 	service.POST("app/location", setSessionTimeZone)
 	service.GET("app/location", getSessionTimeZone)
 
+	service.GET("app/migrations", api.PermissionHandler(ConstPermissionRead, restListMigrations))
+	service.POST("app/migrations", api.PermissionHandler(ConstPermissionWrite, restApplyMigrations))
+
 	return nil
 }
 
@@ -121,10 +124,12 @@ func restSendEmail(context api.InterfaceApplicationContext) (interface{}, error)
 }
 
 // WEB REST API function to get info about current rights
+//   - "permissions" is a list of permissions granted to session, "*" means any
 func restRightsInfo(context api.InterfaceApplicationContext) (interface{}, error) {
 	result := make(map[string]interface{})
 
 	result["is_admin"] = api.IsAdminSession(context)
+	result["permissions"] = api.GetSessionPermissions(context)
 
 	return result, nil
 }
@@ -250,3 +255,21 @@ func setSessionTimeZone(context api.InterfaceApplicationContext) (interface{}, e
 
 	return nil, env.ErrorNew(ConstErrorModule, env.ConstErrorLevelAPI, "a33f0f5d-2110-4208-8fb6-023da3ffd241", "time zone should be specified")
 }
+
+// restListMigrations returns state of registered database migrations
+func restListMigrations(context api.InterfaceApplicationContext) (interface{}, error) {
+	return db.GetMigrations()
+}
+
+// restApplyMigrations applies pending database migrations and returns them
+//   - "dry_run" argument allows to get pending migrations without applying
+func restApplyMigrations(context api.InterfaceApplicationContext) (interface{}, error) {
+	dryRun := utils.InterfaceToBool(context.GetRequestArgument("dry_run"))
+
+	result, err := db.ApplyMigrations(dryRun)
+	if err != nil {
+		return result, env.ErrorDispatch(err)
+	}
+
+	return result, nil
+}
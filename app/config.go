@@ -470,6 +470,76 @@ func setupConfig() error {
 		return env.ErrorDispatch(err)
 	}
 
+	err = config.RegisterItem(env.StructConfigItem{
+		Path:        rest.ConstConfigPathAPIRateLimit,
+		Value:       nil,
+		Type:        env.ConstConfigTypeGroup,
+		Editor:      "",
+		Options:     nil,
+		Label:       "Rate Limit",
+		Description: "API request rate limiting and brute-force protection",
+		Image:       "",
+	}, nil)
+
+	if err != nil {
+		return env.ErrorDispatch(err)
+	}
+
+	err = config.RegisterItem(env.StructConfigItem{
+		Path:        rest.ConstConfigPathAPIRateLimitEnable,
+		Value:       true,
+		Type:        env.ConstConfigTypeBoolean,
+		Editor:      "boolean",
+		Options:     nil,
+		Label:       "Enable Rate Limit",
+		Description: "requests over the limits are rejected with 429 status and Retry-After header",
+		Image:       "",
+	}, func(value interface{}) (interface{}, error) { return utils.InterfaceToBool(value), nil })
+
+	if err != nil {
+		return env.ErrorDispatch(err)
+	}
+
+	err = config.RegisterItem(env.StructConfigItem{
+		Path:        rest.ConstConfigPathAPIRateLimitRules,
+		Value:       rest.ConstDefaultRateLimitRules,
+		Type:        env.ConstConfigTypeText,
+		Editor:      "multiline_text",
+		Options:     nil,
+		Label:       "Rate Rules",
+		Description: "rule per line: <method> <route> <count>/<period> [ip|session], i.e. \"POST /visit/login 20/1m ip\", \"*\" matches any method or route",
+		Image:       "",
+	}, func(value interface{}) (interface{}, error) {
+		if _, err := rest.ParseRateRules(utils.InterfaceToString(value), false); err != nil {
+			return nil, env.ErrorDispatch(err)
+		}
+		return utils.InterfaceToString(value), nil
+	})
+
+	if err != nil {
+		return env.ErrorDispatch(err)
+	}
+
+	err = config.RegisterItem(env.StructConfigItem{
+		Path:        rest.ConstConfigPathAPIRateLimitLockout,
+		Value:       rest.ConstDefaultLockoutRules,
+		Type:        env.ConstConfigTypeText,
+		Editor:      "multiline_text",
+		Options:     nil,
+		Label:       "Lockout Rules",
+		Description: "rule per line: <method> <route> <failures>/<period> <lockout> [ip|session], i.e. \"POST /visit/login 5/15m 15m ip\" locks client out for 15 minutes after 5 failed logins within 15 minutes",
+		Image:       "",
+	}, func(value interface{}) (interface{}, error) {
+		if _, err := rest.ParseRateRules(utils.InterfaceToString(value), true); err != nil {
+			return nil, env.ErrorDispatch(err)
+		}
+		return utils.InterfaceToString(value), nil
+	})
+
+	if err != nil {
+		return env.ErrorDispatch(err)
+	}
+
 	APIURIs := map[string]string{}
 
 	// sorting handlers before output
@@ -47,6 +47,9 @@ const (
 
 	ConstConfigPathVerfifyEmail = ConstConfigPathAppGroup + ".verifyemail"
 
+	ConstPermissionRead  = "system.read"
+	ConstPermissionWrite = "system.write"
+
 	ConstErrorModule = "app"
 	ConstErrorLevel  = env.ConstErrorLevelService
 
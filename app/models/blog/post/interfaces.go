@@ -13,6 +13,9 @@ import (
 const (
 	ConstModelNameBlogPost = "BlogPost"
 
+	ConstEventBlogPostSave   = "blog.post.save"   // event data: "id" - post id, "post" - InterfaceBlogPost
+	ConstEventBlogPostDelete = "blog.post.delete" // event data: "id" - post id, "post" - InterfaceBlogPost
+
 	ConstErrorModule = "blog"
 	ConstErrorLevel  = env.ConstErrorLevelModel
 )
@@ -13,6 +13,9 @@ const (
 	ConstModelNameCMSBlock           = "CMSBlock"
 	ConstModelNameCMSBlockCollection = "CMSBlockCollection"
 
+	ConstEventCMSPageSave   = "cms.page.save"   // event data: "id" - page id, "page" - InterfaceCMSPage
+	ConstEventCMSPageDelete = "cms.page.delete" // event data: "id" - page id, "page" - InterfaceCMSPage
+
 	ConstErrorModule = "cms"
 	ConstErrorLevel  = env.ConstErrorLevelModel
 )
@@ -10,6 +10,8 @@ const (
 	ConstErrorLevel  = env.ConstErrorLevelModel
 
 	ConstCollectionListLimit = 20
+
+	ConstListNextCursorHeader = "X-Next-Cursor" // response header to pass cursor of the following list page
 )
 
 // StructListItem represents type to hold business layer object information within collection
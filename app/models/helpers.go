@@ -247,12 +247,32 @@ func ApplyFilters(context api.InterfaceApplicationContext, collection db.Interfa
 				}
 			}
 
+		// cursor is applied after the sort, as it depends on sort order
+		case "cursor":
+
 		default:
 			if err := addFilterToCollection(attributeName, attributeValue, "default"); err != nil {
 				return env.ErrorNew(ConstErrorModule, ConstErrorLevel, "ce1985e3-e96d-4724-ba4e-0381393e9e1a", "Unable to add filter to collection:" + err.Error())
 			}
 		}
 	}
+
+	// cursor based paging required, blank cursor means first page
+	if cursor, present := context.GetRequestArguments()["cursor"]; present {
+		if err := collection.SetCursor(cursor); err != nil {
+			return env.ErrorNew(ConstErrorModule, ConstErrorLevel, "7dbdbd78-4d8b-4519-87ba-1862d98b97e9", "unable to set cursor: "+err.Error())
+		}
+	}
+
+	return nil
+}
+
+// SetListNextCursor puts cursor of the page following listed one into response header, so the client could request
+// it with "cursor" argument, does nothing if collection was not requested with cursor
+func SetListNextCursor(context api.InterfaceApplicationContext, collection InterfaceCollection) error {
+	if nextCursor := collection.ListNextCursor(); nextCursor != "" {
+		return context.SetResponseSetting(ConstListNextCursorHeader, nextCursor)
+	}
 	return nil
 }
 
@@ -61,6 +61,9 @@ type InterfaceCollection interface {
 	ListFilterReset() error
 
 	ListLimit(offset int, limit int) error
+
+	ListCursor(cursor string) error
+	ListNextCursor() string
 }
 
 // InterfaceCustomAttributes represents interface to access business layer implementation object custom attributes
@@ -23,6 +23,13 @@ const (
 
 	ConstErrorModule = "order"
 	ConstErrorLevel  = env.ConstErrorLevelModel
+
+	ConstEventOrderProceed  = "order.proceed"  // event data: "order" - InterfaceOrder, emitted after proceed commit
+	ConstEventOrderRollback = "order.rollback" // event data: "order" - InterfaceOrder, emitted after rollback commit
+
+	// events emitted within proceed and rollback transaction, changes listeners make within "db.InTransaction" join it
+	ConstEventOrderProceedTransaction  = "transaction.order.proceed"  // event data: "order" - InterfaceOrder
+	ConstEventOrderRollbackTransaction = "transaction.order.rollback" // event data: "order" - InterfaceOrder
 )
 
 // InterfaceOrderItem represents interface to access business layer implementation of purchase order item object
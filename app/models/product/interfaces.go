@@ -14,6 +14,9 @@ const (
 	ConstErrorModule = "product"
 	ConstErrorLevel  = env.ConstErrorLevelModel
 
+	ConstEventProductSave   = "product.save"   // event data: "id" - product id, "product" - InterfaceProduct
+	ConstEventProductDelete = "product.delete" // event data: "id" - product id, "product" - InterfaceProduct
+
 	ConstOptionProductIDs = "_ids"
 	ConstOptionImageName  = "image_name"
 )
@@ -53,6 +53,7 @@ type InterfaceVisitor interface {
 	UpdateResetPassword(key string, passwd string) error
 
 	IsAdmin() bool
+	GetRoles() []string
 	IsGuest() bool
 
 	IsVerified() bool
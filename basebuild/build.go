@@ -8,11 +8,12 @@ import (
 	_ "github.com/ottemo/commerce/env/ini"      // INI Configuration service
 	_ "github.com/ottemo/commerce/env/logger"   // File-based Logging service
 
-	_ "github.com/ottemo/commerce/api/context"   // Context runtime transfer service
-	_ "github.com/ottemo/commerce/api/rest"      // RESTful API service
-	_ "github.com/ottemo/commerce/api/session"   // Session Management service
-	_ "github.com/ottemo/commerce/impex"         // Import/Export service
-	_ "github.com/ottemo/commerce/media/fsmedia" // Media Storage service
+	_ "github.com/ottemo/commerce/api/context"    // Context runtime transfer service
+	_ "github.com/ottemo/commerce/api/rest"       // RESTful API service
+	_ "github.com/ottemo/commerce/api/session"    // Session Management service
+	_ "github.com/ottemo/commerce/impex"          // Import/Export service
+	_ "github.com/ottemo/commerce/media/fsmedia"  // Media Storage service
+	_ "github.com/ottemo/commerce/search/fsindex" // Search Index service
 
 	_ "github.com/ottemo/commerce/app/actors/category"        // Category module
 	_ "github.com/ottemo/commerce/app/actors/cms"             // CMS Page/Block module
@@ -48,8 +49,10 @@ import (
 
 	_ "github.com/ottemo/commerce/app/actors/reporting" // Reporting
 	_ "github.com/ottemo/commerce/app/actors/rts"       // Real Time Statistics service
+	_ "github.com/ottemo/commerce/app/actors/search"    // Full-text search
 	_ "github.com/ottemo/commerce/app/actors/seo"       // URL Rewrite support
 
+	_ "github.com/ottemo/commerce/app/actors/other/apiclient"    // API clients and OAuth2 bearer tokens
 	_ "github.com/ottemo/commerce/app/actors/other/emma"         // Emma integration
 	_ "github.com/ottemo/commerce/app/actors/other/friendmail"   // email friend extension
 	_ "github.com/ottemo/commerce/app/actors/other/grouping"     // products grouping extension
@@ -57,6 +60,7 @@ import (
 	_ "github.com/ottemo/commerce/app/actors/other/shipstation"  // Shipstation integration
 	_ "github.com/ottemo/commerce/app/actors/other/trustpilot"   // TrustPilot integration
 	_ "github.com/ottemo/commerce/app/actors/other/vantagepoint" // VantagePoint integration
+	_ "github.com/ottemo/commerce/app/actors/other/webhook"      // Outbound webhooks
 
 	_ "github.com/ottemo/commerce/app/actors/blog" // Blog module
 )
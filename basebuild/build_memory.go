@@ -0,0 +1,8 @@
+// +build memory
+
+package basebuild
+
+import (
+	// in-memory database service, for tests and demo purposes
+	_ "github.com/ottemo/commerce/db/memory"
+)
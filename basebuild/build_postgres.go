@@ -0,0 +1,9 @@
+// +build postgres
+
+package basebuild
+
+import (
+	// PostgreSQL based database service
+	_ "github.com/lib/pq"
+	_ "github.com/ottemo/commerce/db/postgres"
+)
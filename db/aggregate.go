@@ -0,0 +1,154 @@
+package db
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/ottemo/commerce/env"
+	"github.com/ottemo/commerce/utils"
+)
+
+// aggregateNameValidator checks names of aggregation result keys and JSON paths as they are used within database queries
+var aggregateNameValidator = regexp.MustCompile("^[A-Za-z_][A-Za-z0-9_]*$")
+
+// StructAggregateGroup describes a key records are grouped by within Aggregate(...) call
+//   - column could be a path within JSON column, like "billing_address.country"
+//   - bucket truncates datetime column value to ConstAggregateBucket... period (in UTC)
+//   - name is a result key, column name with "." replaced to "_" by default
+type StructAggregateGroup struct {
+	Column string
+	Bucket string
+	Name   string
+}
+
+// StructAggregate describes aggregate function calculated over records group within Aggregate(...) call
+//   - column could be omitted for ConstAggregateCount function
+//   - name is a result key, "<function>_<column>" by default
+type StructAggregate struct {
+	Function string
+	Column   string
+	Name     string
+}
+
+// PrepareAggregate validates Aggregate(...) call arguments and returns their copies with default names filled
+//   - supposed to be used by database engines
+func PrepareAggregate(collection InterfaceDBCollection, groupBy []StructAggregateGroup, aggregates []StructAggregate) ([]StructAggregateGroup, []StructAggregate, error) {
+	if len(aggregates) == 0 {
+		return nil, nil, env.ErrorNew(ConstErrorModule, ConstErrorLevel, "83b272a3-184b-4716-9eb1-2e47a227fbb8", "at least one aggregate function should be specified")
+	}
+
+	usedNames := make(map[string]bool)
+	checkName := func(name string) error {
+		if !aggregateNameValidator.MatchString(name) {
+			return env.ErrorNew(ConstErrorModule, ConstErrorLevel, "f0a6d0b0-8bac-4d14-ac7a-190aa336b932", "invalid aggregate result name '"+name+"'")
+		}
+		if usedNames[name] {
+			return env.ErrorNew(ConstErrorModule, ConstErrorLevel, "4331f513-70c8-446b-835c-fc91b2981c1f", "duplicate aggregate result name '"+name+"'")
+		}
+		usedNames[name] = true
+		return nil
+	}
+
+	resultGroupBy := make([]StructAggregateGroup, 0, len(groupBy))
+	for _, group := range groupBy {
+		columnPath := strings.SplitN(group.Column, ".", 2)
+		if !collection.HasColumn(columnPath[0]) {
+			return nil, nil, env.ErrorNew(ConstErrorModule, ConstErrorLevel, "11e95745-8eaa-44c5-bdf9-bb81a7062ee7", "can't find column '"+group.Column+"'")
+		}
+		if len(columnPath) > 1 {
+			if collection.GetColumnType(columnPath[0]) != ConstTypeJSON {
+				return nil, nil, env.ErrorNew(ConstErrorModule, ConstErrorLevel, "00a18e1d-b09f-4d31-94f6-f3daa3fa7c73", "column '"+columnPath[0]+"' is not a JSON column")
+			}
+			for _, pathItem := range strings.Split(columnPath[1], ".") {
+				if !aggregateNameValidator.MatchString(pathItem) {
+					return nil, nil, env.ErrorNew(ConstErrorModule, ConstErrorLevel, "4f55272f-0619-410e-83c8-fc66a3bab470", "invalid column path '"+group.Column+"'")
+				}
+			}
+		}
+
+		switch group.Bucket {
+		case "":
+		case ConstAggregateBucketHour, ConstAggregateBucketDay, ConstAggregateBucketMonth, ConstAggregateBucketYear:
+			if len(columnPath) > 1 || collection.GetColumnType(group.Column) != ConstTypeDatetime {
+				return nil, nil, env.ErrorNew(ConstErrorModule, ConstErrorLevel, "a83e9fd5-9c63-4a32-86d5-d5e413dc0ca4", "date bucket is applicable to datetime column only, '"+group.Column+"' given")
+			}
+		default:
+			return nil, nil, env.ErrorNew(ConstErrorModule, ConstErrorLevel, "938c249b-2be2-47b7-aaf5-f13cb4a8e12a", "unknown date bucket '"+group.Bucket+"'")
+		}
+
+		if group.Name == "" {
+			group.Name = strings.Replace(group.Column, ".", "_", -1)
+		}
+		if err := checkName(group.Name); err != nil {
+			return nil, nil, err
+		}
+
+		resultGroupBy = append(resultGroupBy, group)
+	}
+
+	resultAggregates := make([]StructAggregate, 0, len(aggregates))
+	for _, aggregate := range aggregates {
+		aggregate.Function = strings.ToLower(aggregate.Function)
+
+		switch aggregate.Function {
+		case ConstAggregateCount:
+		case ConstAggregateSum, ConstAggregateAvg, ConstAggregateMin, ConstAggregateMax:
+			if aggregate.Column == "" {
+				return nil, nil, env.ErrorNew(ConstErrorModule, ConstErrorLevel, "17759654-bcb2-4466-9b51-985153d9723a", "column should be specified for '"+aggregate.Function+"' aggregate")
+			}
+		default:
+			return nil, nil, env.ErrorNew(ConstErrorModule, ConstErrorLevel, "8ccb3d77-c629-4ef1-8bcd-faec9ebd460a", "unknown aggregate function '"+aggregate.Function+"'")
+		}
+
+		if aggregate.Column != "" && !collection.HasColumn(aggregate.Column) {
+			return nil, nil, env.ErrorNew(ConstErrorModule, ConstErrorLevel, "900677e4-4425-422b-9856-3a9b2fd97180", "can't find column '"+aggregate.Column+"'")
+		}
+
+		if aggregate.Name == "" {
+			aggregate.Name = aggregate.Function
+			if aggregate.Column != "" {
+				aggregate.Name += "_" + aggregate.Column
+			}
+		}
+		if err := checkName(aggregate.Name); err != nil {
+			return nil, nil, err
+		}
+
+		resultAggregates = append(resultAggregates, aggregate)
+	}
+
+	return resultGroupBy, resultAggregates, nil
+}
+
+// ConvertAggregateRow converts database engine aggregation result row to Go types
+//   - date buckets are converted to time.Time, counts to int, sums and averages to float64,
+//     other values are converted according to the column type
+func ConvertAggregateRow(collection InterfaceDBCollection, groupBy []StructAggregateGroup, aggregates []StructAggregate, row map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(groupBy)+len(aggregates))
+
+	for _, group := range groupBy {
+		value := row[group.Name]
+		switch {
+		case group.Bucket != "":
+			result[group.Name] = utils.InterfaceToTime(value).UTC()
+		case strings.Contains(group.Column, "."):
+			result[group.Name] = value
+		default:
+			result[group.Name] = ConvertTypeFromDbToGo(value, collection.GetColumnType(group.Column))
+		}
+	}
+
+	for _, aggregate := range aggregates {
+		value := row[aggregate.Name]
+		switch aggregate.Function {
+		case ConstAggregateCount:
+			result[aggregate.Name] = utils.InterfaceToInt(value)
+		case ConstAggregateSum, ConstAggregateAvg:
+			result[aggregate.Name] = utils.InterfaceToFloat64(value)
+		default:
+			result[aggregate.Name] = ConvertTypeFromDbToGo(value, collection.GetColumnType(aggregate.Column))
+		}
+	}
+
+	return result
+}
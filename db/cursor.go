@@ -0,0 +1,95 @@
+package db
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+
+	"github.com/ottemo/commerce/env"
+	"github.com/ottemo/commerce/utils"
+)
+
+// cursorType is a decoded representation of the collection cursor
+type cursorType struct {
+	Sort   []string      `json:"s"`
+	Values []interface{} `json:"v"`
+}
+
+// EncodeCursor makes an opaque cursor pointing to given record within given sort order
+//   - sort columns are column names, descending order columns are prefixed with "-"
+//   - returns blank string if record is nil
+func EncodeCursor(sortColumns []string, record map[string]interface{}) string {
+	if record == nil {
+		return ""
+	}
+
+	cursor := cursorType{Sort: sortColumns, Values: make([]interface{}, 0, len(sortColumns))}
+	for _, sortColumn := range sortColumns {
+		cursor.Values = append(cursor.Values, record[strings.TrimPrefix(sortColumn, "-")])
+	}
+
+	return base64.RawURLEncoding.EncodeToString([]byte(utils.EncodeToJSONString(cursor)))
+}
+
+// ApplyCursor adds filters to collection to select records following the cursor (keyset pagination)
+//   - sort columns should be the same as were used to make the cursor, the last one should be unique ("_id")
+//   - filter is made within ConstFilterGroupCursor filter group which is rewritten on each call
+func ApplyCursor(collection InterfaceDBCollection, sortColumns []string, cursor string) error {
+	cursorJSON, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return env.ErrorNew(ConstErrorModule, ConstErrorLevel, "556c5602-9cd6-4f82-a6bf-d360fce51394", "invalid cursor")
+	}
+
+	var decodedCursor cursorType
+	if err := json.Unmarshal(cursorJSON, &decodedCursor); err != nil {
+		return env.ErrorNew(ConstErrorModule, ConstErrorLevel, "a63fa0f4-afc6-40db-b669-9e798dbbed8c", "invalid cursor")
+	}
+
+	cursorSort := decodedCursor.Sort
+	cursorValues := decodedCursor.Values
+	if len(cursorSort) != len(sortColumns) || len(cursorValues) != len(sortColumns) {
+		return env.ErrorNew(ConstErrorModule, ConstErrorLevel, "52392444-3f50-4ea9-be0e-7f56d383a781", "cursor does not match collection sort order")
+	}
+	for idx, sortColumn := range sortColumns {
+		if cursorSort[idx] != sortColumn {
+			return env.ErrorNew(ConstErrorModule, ConstErrorLevel, "035877d3-42e2-4066-965a-1a9865ea48e4", "cursor does not match collection sort order")
+		}
+	}
+
+	// removing previous cursor filters, errors are not important as groups may not exist
+	_ = collection.RemoveFilterGroup(ConstFilterGroupCursor)
+	for idx := range sortColumns {
+		_ = collection.RemoveFilterGroup(ConstFilterGroupCursor + utils.InterfaceToString(idx))
+	}
+	if err := collection.SetupFilterGroup(ConstFilterGroupCursor, true, ""); err != nil {
+		return env.ErrorDispatch(err)
+	}
+
+	// (a > x) OR (a = x AND b > y) OR (a = x AND b = y AND _id > z)
+	for idx, sortColumn := range sortColumns {
+		groupName := ConstFilterGroupCursor + utils.InterfaceToString(idx)
+		if err := collection.SetupFilterGroup(groupName, false, ConstFilterGroupCursor); err != nil {
+			return env.ErrorDispatch(err)
+		}
+
+		for prevIdx := 0; prevIdx < idx; prevIdx++ {
+			columnName := strings.TrimPrefix(sortColumns[prevIdx], "-")
+			columnValue := ConvertTypeFromDbToGo(cursorValues[prevIdx], collection.GetColumnType(columnName))
+			if err := collection.AddGroupFilter(groupName, columnName, "=", columnValue); err != nil {
+				return env.ErrorDispatch(err)
+			}
+		}
+
+		operator := ">"
+		if strings.HasPrefix(sortColumn, "-") {
+			operator = "<"
+		}
+		columnName := strings.TrimPrefix(sortColumn, "-")
+		columnValue := ConvertTypeFromDbToGo(cursorValues[idx], collection.GetColumnType(columnName))
+		if err := collection.AddGroupFilter(groupName, columnName, operator, columnValue); err != nil {
+			return env.ErrorDispatch(err)
+		}
+	}
+
+	return nil
+}
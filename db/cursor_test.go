@@ -0,0 +1,24 @@
+package db
+
+import (
+	"encoding/base64"
+	"testing"
+)
+
+func TestEncodeCursor(t *testing.T) {
+	if cursor := EncodeCursor([]string{"_id"}, nil); cursor != "" {
+		t.Errorf("cursor of nil record should be blank, got %q", cursor)
+	}
+
+	record := map[string]interface{}{"_id": "a1", "created_at": 10, "name": "foo"}
+	cursor := EncodeCursor([]string{"-created_at", "_id"}, record)
+
+	cursorJSON, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := `{"s":["-created_at","_id"],"v":[10,"a1"]}`; string(cursorJSON) != expected {
+		t.Errorf("unexpected cursor content: %s != %s", cursorJSON, expected)
+	}
+}
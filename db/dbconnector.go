@@ -57,8 +57,19 @@ func (it *DBConnector) Connect() error {
 		it.log("After DB connect error:")
 	}
 
+	replicaConnector, _ := it.connector.(InterfaceDBReplicaConnector)
+	var replicaParams []interface{}
+	if replicaConnector != nil {
+		replicaParams = replicaConnector.GetReplicaParams(connectionParams)
+		it.connectReplicas(replicaConnector, replicaParams)
+	}
+
 	go func() {
 		for range ticker.C {
+			if replicaConnector != nil {
+				it.validateReplicas(replicaConnector, replicaParams)
+			}
+
 			if err := it.connector.Ping(); err != nil {
 				it.connector.SetConnected(false)
 				it.log("DB connection lost. Reconnect in 60 seconds.")
@@ -77,6 +88,41 @@ func (it *DBConnector) Connect() error {
 	return err
 }
 
+// connectReplicas makes initial connection to read replicas, replica failed to connect is not available for reads
+func (it *DBConnector) connectReplicas(replicaConnector InterfaceDBReplicaConnector, replicaParams []interface{}) {
+	for replicaIdx, params := range replicaParams {
+		if err := replicaConnector.ConnectReplica(replicaIdx, params); err != nil {
+			replicaConnector.SetReplicaAvailable(replicaIdx, false)
+			it.log("Can't connect to DB replica " + utils.InterfaceToString(replicaIdx) + ": " + err.Error())
+		} else {
+			replicaConnector.SetReplicaAvailable(replicaIdx, true)
+			it.log("DB replica " + utils.InterfaceToString(replicaIdx) + " connection established.")
+		}
+	}
+}
+
+// validateReplicas pings read replicas, excludes failed ones from reads and reconnects them
+func (it *DBConnector) validateReplicas(replicaConnector InterfaceDBReplicaConnector, replicaParams []interface{}) {
+	for replicaIdx, params := range replicaParams {
+		err := replicaConnector.PingReplica(replicaIdx)
+		if err != nil {
+			if replicaConnector.IsReplicaAvailable(replicaIdx) {
+				replicaConnector.SetReplicaAvailable(replicaIdx, false)
+				it.log("DB replica " + utils.InterfaceToString(replicaIdx) + " connection lost: " + err.Error())
+			}
+
+			if err = replicaConnector.ConnectReplica(replicaIdx, params); err != nil {
+				continue
+			}
+		}
+
+		if !replicaConnector.IsReplicaAvailable(replicaIdx) {
+			replicaConnector.SetReplicaAvailable(replicaIdx, true)
+			it.log("DB replica " + utils.InterfaceToString(replicaIdx) + " connection restored.")
+		}
+	}
+}
+
 // log outputs messages to stdout and connector endpoint
 func (it *DBConnector) log(message string) {
 	// output to stdout
@@ -0,0 +1,79 @@
+package db
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// testReplicaConnector is a InterfaceDBReplicaConnector test implementation with switchable replica health
+type testReplicaConnector struct {
+	isReplicaAlive     []bool
+	isReplicaAvailable []bool
+}
+
+func (it *testReplicaConnector) GetConnectionParams() interface{}                { return nil }
+func (it *testReplicaConnector) Connect(connectionParams interface{}) error      { return nil }
+func (it *testReplicaConnector) AfterConnect(connectionParams interface{}) error { return nil }
+func (it *testReplicaConnector) Ping() error                                     { return nil }
+func (it *testReplicaConnector) GetValidationInterval() time.Duration            { return time.Second }
+func (it *testReplicaConnector) Reconnect(connectionParams interface{}) error    { return nil }
+func (it *testReplicaConnector) IsConnected() bool                               { return true }
+func (it *testReplicaConnector) SetConnected(connected bool)                     {}
+func (it *testReplicaConnector) GetEngineName() string                           { return "test" }
+func (it *testReplicaConnector) LogConnection(message string)                    {}
+
+func (it *testReplicaConnector) GetReplicaParams(connectionParams interface{}) []interface{} {
+	return []interface{}{"replica0", "replica1"}
+}
+
+func (it *testReplicaConnector) ConnectReplica(replicaIdx int, replicaParams interface{}) error {
+	return it.PingReplica(replicaIdx)
+}
+
+func (it *testReplicaConnector) PingReplica(replicaIdx int) error {
+	if !it.isReplicaAlive[replicaIdx] {
+		return errors.New("replica is down")
+	}
+	return nil
+}
+
+func (it *testReplicaConnector) IsReplicaAvailable(replicaIdx int) bool {
+	return it.isReplicaAvailable[replicaIdx]
+}
+
+func (it *testReplicaConnector) SetReplicaAvailable(replicaIdx int, available bool) {
+	it.isReplicaAvailable[replicaIdx] = available
+}
+
+func TestReplicaFailover(t *testing.T) {
+	replicaConnector := &testReplicaConnector{
+		isReplicaAlive:     []bool{true, false},
+		isReplicaAvailable: []bool{false, false},
+	}
+	connector := NewDBConnector(replicaConnector)
+	replicaParams := replicaConnector.GetReplicaParams(nil)
+
+	checkAvailable := func(expected ...bool) {
+		for replicaIdx, isAvailable := range expected {
+			if replicaConnector.IsReplicaAvailable(replicaIdx) != isAvailable {
+				t.Fatalf("replica %d availability should be %v", replicaIdx, isAvailable)
+			}
+		}
+	}
+
+	connector.connectReplicas(replicaConnector, replicaParams)
+	checkAvailable(true, false)
+
+	// failed replica is excluded, restored one is included back
+	replicaConnector.isReplicaAlive = []bool{false, true}
+	connector.validateReplicas(replicaConnector, replicaParams)
+	checkAvailable(false, true)
+
+	connector.validateReplicas(replicaConnector, replicaParams)
+	checkAvailable(false, true)
+
+	replicaConnector.isReplicaAlive = []bool{true, true}
+	connector.validateReplicas(replicaConnector, replicaParams)
+	checkAvailable(true, true)
+}
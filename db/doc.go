@@ -24,5 +24,84 @@ For database type specification you should refer this package for possible types
 	collection.AddColumn("bonus_code", db.ConstTypeInteger, false)
 	collection.AddColumn("bonus_amount", db.ConstTypeInteger, false)
 
+Large collections could be paged with cursors instead of offsets. "GetNextCursor" returns opaque cursor of the last
+loaded record, "SetCursor" makes the following load to start after it. The cursor keeps the sort order it was made
+within, so the collection sort should not change between pages.
+
+	Example:
+	--------
+	collection.AddSort("created_at", true)
+	collection.SetLimit(0, 50)
+	if err := collection.SetCursor(cursor); err != nil {
+		return env.ErrorDispatch(err)
+	}
+	records, err := collection.Load()
+	nextCursor := collection.GetNextCursor()
+
+Records could be summarized on database side by "Aggregate" function, it calculates count, sum, avg, min and max
+over filtered records grouped by columns, JSON column paths or datetime column buckets (hour, day, month, year).
+
+	Example:
+	--------
+	salesByDay, err := orderCollection.Aggregate(
+		[]db.StructAggregateGroup{{Column: "created_at", Bucket: db.ConstAggregateBucketDay, Name: "day"}},
+		[]db.StructAggregate{{Function: db.ConstAggregateSum, Column: "grand_total"}, {Function: db.ConstAggregateCount}})
+
+Several collection modifications can be grouped into one atomic unit with "InTransaction" helper. Transaction is bound
+to the call context (refer "api/context" package), nested "InTransaction" calls are joining the outer one. MongoDB
+engine emulates transactions by restoring modified documents on rollback, so it provides no isolation.
+
+	Example:
+	--------
+	err := db.InTransaction(func() error {
+		if _, err := orderCollection.Save(orderValues); err != nil {
+			return env.ErrorDispatch(err)
+		}
+		return stockCollection.DeleteByID(stockID)
+	})
+
+Notifications about changes made within transaction should be sent with "AfterCommit", which defers given function
+until the outer transaction commits and drops it on rollback.
+
+Schema changes are made by versioned migrations registered per module with "RegisterMigration". Pending migrations
+are applied in version order on database start and recorded in "db_migration" collection. Application started with
+"--dbMigrate" argument exits after migrations, "--dbMigrationsDryRun" argument lists pending migrations instead of
+applying them and exits. "RenameColumn", "ChangeColumnType" and "BackfillColumn" helpers are intended for migrations,
+first two copy values in batches to a new column before the old one is removed.
+Migrations are not atomic: MySQL commits schema changes implicitly and SQLite column type cache is not restored on
+rollback, so a failed migration could leave part of its changes applied and should be safe to re-apply.
+
+	Example:
+	--------
+	db.RegisterMigration("order", 1, "rename order notes column", func() error {
+		collection, err := db.GetCollection("order")
+		if err != nil {
+			return env.ErrorDispatch(err)
+		}
+		return db.RenameColumn(collection, "notes", "customer_notes")
+	})
+
+SQL engines could be given read replicas within ini config ("db.mysql.replicas", "db.postgres.replicas"). "Load",
+"LoadByID", "Iterate", "Count", "Distinct" and "Aggregate" are routed to replicas in turn, while writes and reads within
+transaction go to primary. Replicas are checked each "GetValidationInterval" and excluded from routing until they
+respond again, so reads failover to other replicas or primary. Replicas could lag behind primary, so a code which has
+to read its own writes should do it within transaction or "ReadFromPrimary":
+
+	err := db.ReadFromPrimary(func() error {
+		record, err := collection.LoadByID(id)
+		...
+	})
+
+Concurrent edits of a record could be guarded with "AddVersionColumn". Collection "Save" then increments "_version"
+column and writes new value back to the saved item. Item carrying non zero "_version" is saved only if stored record
+has the same version, otherwise "IsVersionConflict" error is returned and nothing changed.
+
+	Example:
+	--------
+	record["price"] = 10
+	if _, err := collection.Save(record); db.IsVersionConflict(err) {
+		// record was changed since it was loaded, reload it and retry
+	}
+
 */
 package db
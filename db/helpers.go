@@ -110,3 +110,120 @@ func TypeIsString(dataType string) bool {
 func TypeIsFloat(dataType string) bool {
 	return utils.DataTypeIsFloat(dataType)
 }
+
+// RenameColumn renames collection column keeping its values, helper for migrations
+//   - old column is removed only after values were copied to the new one
+func RenameColumn(collection InterfaceDBCollection, columnName string, newColumnName string) error {
+	if !collection.HasColumn(columnName) {
+		return env.ErrorNew(ConstErrorModule, ConstErrorLevel, "0fc37719-60e6-4a6f-b93b-ccdafd8f03e8", "column '"+columnName+"' not exists")
+	}
+	if collection.HasColumn(newColumnName) {
+		return env.ErrorNew(ConstErrorModule, ConstErrorLevel, "1e30fe3e-a6b4-4253-9cf6-8a462e4d457e", "column '"+newColumnName+"' already exists")
+	}
+
+	columnType := collection.GetColumnType(columnName)
+	if err := collection.AddColumn(newColumnName, columnType, false); err != nil {
+		return env.ErrorDispatch(err)
+	}
+
+	if err := copyColumn(collection, columnName, newColumnName, columnType); err != nil {
+		return env.ErrorDispatch(err)
+	}
+
+	return env.ErrorDispatch(collection.RemoveColumn(columnName))
+}
+
+// ChangeColumnType changes collection column type converting its values to a new type, helper for migrations
+//   - converted values are copied to temporary column first, so the column is re-created only after conversion
+//     succeeded, temporary column left by failed run is re-created
+func ChangeColumnType(collection InterfaceDBCollection, columnName string, columnType string, indexed bool) error {
+	if !collection.HasColumn(columnName) {
+		return env.ErrorNew(ConstErrorModule, ConstErrorLevel, "c9eb5239-60a0-4c0b-97b4-f429d103d1a3", "column '"+columnName+"' not exists")
+	}
+
+	tempColumnName := columnName + ConstMigrationTempSuffix
+	if collection.HasColumn(tempColumnName) {
+		if err := collection.RemoveColumn(tempColumnName); err != nil {
+			return env.ErrorDispatch(err)
+		}
+	}
+	if err := collection.AddColumn(tempColumnName, columnType, false); err != nil {
+		return env.ErrorDispatch(err)
+	}
+	if err := copyColumn(collection, columnName, tempColumnName, columnType); err != nil {
+		return env.ErrorDispatch(err)
+	}
+
+	if err := collection.RemoveColumn(columnName); err != nil {
+		return env.ErrorDispatch(err)
+	}
+	if err := collection.AddColumn(columnName, columnType, indexed); err != nil {
+		return env.ErrorDispatch(err)
+	}
+	if err := copyColumn(collection, tempColumnName, columnName, columnType); err != nil {
+		return env.ErrorDispatch(err)
+	}
+
+	return env.ErrorDispatch(collection.RemoveColumn(tempColumnName))
+}
+
+// copyColumn copies column values converted to given type to other column of each collection record
+//   - records are loaded by ConstMigrationBatchSize ones ordered by id
+func copyColumn(collection InterfaceDBCollection, columnName string, toColumnName string, columnType string) error {
+	if err := collection.ClearSort(); err != nil {
+		return env.ErrorDispatch(err)
+	}
+	if err := collection.AddSort("_id", false); err != nil {
+		return env.ErrorDispatch(err)
+	}
+	defer func() {
+		_ = collection.SetLimit(0, 0)
+		_ = collection.ClearSort()
+	}()
+
+	for offset := 0; ; offset += ConstMigrationBatchSize {
+		if err := collection.SetLimit(offset, ConstMigrationBatchSize); err != nil {
+			return env.ErrorDispatch(err)
+		}
+
+		records, err := collection.Load()
+		if err != nil {
+			return env.ErrorDispatch(err)
+		}
+
+		for _, record := range records {
+			record[toColumnName] = ConvertTypeFromDbToGo(record[columnName], columnType)
+
+			if _, err := collection.Save(record); err != nil {
+				return env.ErrorDispatch(err)
+			}
+		}
+
+		if len(records) < ConstMigrationBatchSize {
+			return nil
+		}
+	}
+}
+
+// BackfillColumn sets column value for each collection record to a value returned by given function,
+// records are filtered by collection filters, helper for migrations
+func BackfillColumn(collection InterfaceDBCollection, columnName string, valueFunc func(record map[string]interface{}) interface{}) error {
+	if !collection.HasColumn(columnName) {
+		return env.ErrorNew(ConstErrorModule, ConstErrorLevel, "3eb5736e-28ad-4cba-8480-9ddd0d20bc97", "column '"+columnName+"' not exists")
+	}
+
+	records, err := collection.Load()
+	if err != nil {
+		return env.ErrorDispatch(err)
+	}
+
+	for _, record := range records {
+		record[columnName] = valueFunc(record)
+
+		if _, err := collection.Save(record); err != nil {
+			return env.ErrorDispatch(err)
+		}
+	}
+
+	return nil
+}
@@ -19,6 +19,37 @@ const (
 	ConstTypeDatetime = utils.ConstDataTypeDatetime
 	ConstTypeJSON     = utils.ConstDataTypeJSON
 
+	ConstCollectionNameMigrations = "db_migration" // collection to record applied migrations
+	ConstMigrationsLogStorage     = "migrations.log"
+
+	ConstCmdArgMigrate          = "--dbMigrate"          // command line argument to apply migrations and exit
+	ConstCmdArgMigrationsDryRun = "--dbMigrationsDryRun" // command line argument to list pending migrations without applying and exit
+
+	ConstMigrationBatchSize  = 500          // records amount migration helpers load at once
+	ConstMigrationTempSuffix = "_migrating" // suffix of temporary column migration helpers copy values to
+
+	ConstFilterGroupCursor = "cursor" // filter group name used for keyset pagination, ref. to ApplyCursor(...)
+
+	ConstAggregateCount = "count" // aggregate functions, ref. to StructAggregate
+	ConstAggregateSum   = "sum"
+	ConstAggregateAvg   = "avg"
+	ConstAggregateMin   = "min"
+	ConstAggregateMax   = "max"
+
+	ConstAggregateBucketHour  = "hour" // datetime grouping periods, ref. to StructAggregateGroup
+	ConstAggregateBucketDay   = "day"
+	ConstAggregateBucketMonth = "month"
+	ConstAggregateBucketYear  = "year"
+
+	ConstVersionColumn            = "_version"                             // record version column maintained by Save, ref. to AddVersionColumn(...)
+	ConstVersionAbsent            = -1                                     // record version making Save to insert record only if it is not stored yet
+	ConstErrorCodeVersionConflict = "5444cdfe-e33f-4c0c-b060-f8f83dce44b3" // error code of Save made over outdated record version
+
+	ConstContextKeyTransactionDepth    = "db.transaction.depth"    // call context key holding transaction nesting level
+	ConstContextKeyTransactionRollback = "db.transaction.rollback" // call context key flagging transaction to be rolled back
+	ConstContextKeyTransactionCommit   = "db.transaction.commit"   // call context key holding functions to call after commit
+	ConstContextKeyReadPrimary         = "db.read.primary"         // call context key flagging reads to go to primary, ref. to ReadFromPrimary(...)
+
 	ConstErrorModule = "db"
 	ConstErrorLevel  = env.ConstErrorLevelService
 )
@@ -33,6 +64,10 @@ type InterfaceDBEngine interface {
 	HasCollection(Name string) bool
 
 	RawQuery(query string) (map[string]interface{}, error)
+
+	BeginTransaction() error
+	CommitTransaction() error
+	RollbackTransaction() error
 }
 
 // InterfaceDBCollection interface to access particular table/collection of database
@@ -49,6 +84,7 @@ type InterfaceDBCollection interface {
 
 	Count() (int, error)
 	Distinct(columnName string) ([]interface{}, error)
+	Aggregate(groupBy []StructAggregateGroup, aggregates []StructAggregate) ([]map[string]interface{}, error)
 
 	SetupFilterGroup(groupName string, orSequence bool, parentGroup string) error
 	RemoveFilterGroup(groupName string) error
@@ -66,6 +102,9 @@ type InterfaceDBCollection interface {
 
 	SetLimit(offset int, limit int) error
 
+	SetCursor(cursor string) error
+	GetNextCursor() string
+
 	ListColumns() map[string]string
 	GetColumnType(columnName string) string
 	HasColumn(columnName string) bool
@@ -90,3 +129,14 @@ type InterfaceDBConnector interface {
 	GetEngineName() string
 	LogConnection(message string)
 }
+
+// InterfaceDBReplicaConnector is an optional InterfaceDBConnector extension for engines able to route reads to replicas
+//   - unavailable replicas are not used for reads, primary connection is used if there are no available replicas
+type InterfaceDBReplicaConnector interface {
+	GetReplicaParams(connectionParams interface{}) []interface{}
+	ConnectReplica(replicaIdx int, replicaParams interface{}) error
+	PingReplica(replicaIdx int) error
+
+	IsReplicaAvailable(replicaIdx int) bool
+	SetReplicaAvailable(replicaIdx int, available bool)
+}
@@ -16,13 +16,15 @@ func RegisterOnDatabaseStart(callback func() error) {
 }
 
 // OnDatabaseStart fires database service start event (callback handling)
+//   - pending migrations are applied after all the callbacks, so modules have their collections set up
 func OnDatabaseStart() error {
 	for _, callback := range callbacksOnDatabaseStart {
 		if err := callback(); err != nil {
+			_ = finishStartMigrations(nil, err)
 			return env.ErrorDispatch(err)
 		}
 	}
-	return nil
+	return env.ErrorDispatch(finishStartMigrations(ApplyMigrations(IsMigrationsDryRun())))
 }
 
 // RegisterDBEngine registers database service in the system
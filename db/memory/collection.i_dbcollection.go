@@ -0,0 +1,612 @@
+package memory
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/ottemo/commerce/db"
+	"github.com/ottemo/commerce/env"
+	"github.com/ottemo/commerce/utils"
+)
+
+// LoadByID loads record from DB by it's id
+func (it *DBCollection) LoadByID(id string) (map[string]interface{}, error) {
+	acquireTables(false)
+	defer releaseTables(false)
+
+	table := it.getTable(false)
+
+	record, present := table.records[id]
+	if !present {
+		return nil, env.ErrorNew(ConstErrorModule, ConstErrorLevel, "baf2077f-1d5e-451f-bb3f-85f26cc25bad", "not found")
+	}
+
+	return it.makeResultRow(table, record), nil
+}
+
+// Load loads records from DB for current collection and filter if it set
+func (it *DBCollection) Load() ([]map[string]interface{}, error) {
+	var result []map[string]interface{}
+
+	err := it.Iterate(func(row map[string]interface{}) bool {
+		result = append(result, row)
+		return true
+	})
+
+	return result, env.ErrorDispatch(err)
+}
+
+// Iterate applies [iterator] function to each record, stops on return false
+//   - records are selected before iteration, so iterator function is free to modify collection
+func (it *DBCollection) Iterate(iteratorFunc func(record map[string]interface{}) bool) error {
+	it.lastRecord = nil
+
+	matcher, err := it.makeRecordMatcher()
+	if err != nil {
+		return env.ErrorDispatch(err)
+	}
+
+	acquireTables(false)
+	table := it.getTable(false)
+	records := it.selectRecords(table, matcher, true)
+	rows := make([]map[string]interface{}, 0, len(records))
+	for _, record := range records {
+		rows = append(rows, it.makeResultRow(table, record))
+	}
+	releaseTables(false)
+
+	for _, row := range rows {
+		if it.isCursorSet {
+			it.lastRecord = row
+		}
+
+		if !iteratorFunc(row) {
+			break
+		}
+	}
+
+	return nil
+}
+
+// Distinct returns distinct values of specified attribute
+func (it *DBCollection) Distinct(columnName string) ([]interface{}, error) {
+	if !it.HasColumn(columnName) {
+		return nil, env.ErrorNew(ConstErrorModule, ConstErrorLevel, "9323546d-5940-4ab5-a6f5-f2b62fcd3f33", "there is no column "+columnName+" found")
+	}
+
+	matcher, err := it.makeRecordMatcher()
+	if err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
+	acquireTables(false)
+	defer releaseTables(false)
+
+	table := it.getTable(false)
+	columnType := table.columns[columnName]
+
+	isInList := func(list []interface{}, value interface{}) bool {
+		for _, listItem := range list {
+			if compareValues(listItem, value, columnType) == 0 {
+				return true
+			}
+		}
+		return false
+	}
+
+	// distinct column values, limit is applied to them rather than to records
+	var values []interface{}
+	for _, record := range it.selectRecords(table, matcher, false) {
+		if value := record[columnName]; value != nil && !isInList(values, value) {
+			values = append(values, value)
+		}
+	}
+
+	if it.Limit > 0 {
+		if it.Offset >= len(values) {
+			values = nil
+		} else {
+			values = values[it.Offset:]
+		}
+		if it.Limit < len(values) {
+			values = values[:it.Limit]
+		}
+	}
+
+	var result []interface{}
+	for _, value := range values {
+		value = convertValueToType(columnType, value)
+
+		// if value is array then we need to make distinct within array
+		if arrayValue, ok := value.([]interface{}); ok {
+			for _, arrayItem := range arrayValue {
+				if !isInList(result, arrayItem) {
+					result = append(result, arrayItem)
+				}
+			}
+		} else {
+			result = append(result, value)
+		}
+	}
+
+	return result, nil
+}
+
+// Count returns count of rows matching current select statement
+func (it *DBCollection) Count() (int, error) {
+	matcher, err := it.makeRecordMatcher()
+	if err != nil {
+		return 0, env.ErrorDispatch(err)
+	}
+
+	acquireTables(false)
+	defer releaseTables(false)
+
+	table := it.getTable(false)
+
+	count := 0
+	for _, id := range table.ids {
+		if matcher(table.records[id], table.columns) {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+// Aggregate calculates aggregate functions over records matching current select statement grouped by given keys
+//   - result is ordered by group keys, sort and limit of collection are not applied
+func (it *DBCollection) Aggregate(groupBy []db.StructAggregateGroup, aggregates []db.StructAggregate) ([]map[string]interface{}, error) {
+	groupBy, aggregates, err := db.PrepareAggregate(it, groupBy, aggregates)
+	if err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
+	matcher, err := it.makeRecordMatcher()
+	if err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
+	acquireTables(false)
+	table := it.getTable(false)
+
+	// group keys types to order result by, JSON path values are of unknown type
+	groupTypes := make([]string, 0, len(groupBy))
+	for _, group := range groupBy {
+		switch {
+		case group.Bucket != "":
+			groupTypes = append(groupTypes, db.ConstTypeDatetime)
+		case strings.Contains(group.Column, "."):
+			groupTypes = append(groupTypes, "")
+		default:
+			groupTypes = append(groupTypes, table.columns[group.Column])
+		}
+	}
+
+	// grouping records by group keys values
+	var groupKeys []string
+	groupValues := make(map[string][]interface{})
+	groupRecords := make(map[string][]map[string]interface{})
+
+	if len(groupBy) == 0 {
+		// aggregation without grouping makes one row even if there are no records
+		groupKeys = append(groupKeys, "")
+	}
+
+	for _, id := range table.ids {
+		record := table.records[id]
+		if !matcher(record, table.columns) {
+			continue
+		}
+
+		values := make([]interface{}, 0, len(groupBy))
+		for _, group := range groupBy {
+			values = append(values, getAggregateGroupValue(group, record))
+		}
+
+		groupKey := utils.EncodeToJSONString(values)
+		if _, present := groupValues[groupKey]; !present && len(groupBy) > 0 {
+			groupKeys = append(groupKeys, groupKey)
+		}
+		groupValues[groupKey] = values
+		groupRecords[groupKey] = append(groupRecords[groupKey], record)
+	}
+
+	// calculating aggregates within groups
+	rows := make([]map[string]interface{}, 0, len(groupKeys))
+	for _, groupKey := range groupKeys {
+		row := make(map[string]interface{})
+		for idx, group := range groupBy {
+			row[group.Name] = groupValues[groupKey][idx]
+		}
+
+		for _, aggregate := range aggregates {
+			columnType := table.columns[aggregate.Column]
+
+			var value interface{}
+			valuesCount := 0
+			for _, record := range groupRecords[groupKey] {
+				recordValue := record[aggregate.Column]
+				if aggregate.Function == db.ConstAggregateCount {
+					valuesCount++
+					continue
+				}
+				if recordValue == nil {
+					continue
+				}
+
+				switch aggregate.Function {
+				case db.ConstAggregateSum, db.ConstAggregateAvg:
+					value = utils.InterfaceToFloat64(value) + utils.InterfaceToFloat64(recordValue)
+				case db.ConstAggregateMin:
+					if value == nil || compareValues(recordValue, value, columnType) < 0 {
+						value = recordValue
+					}
+				case db.ConstAggregateMax:
+					if value == nil || compareValues(recordValue, value, columnType) > 0 {
+						value = recordValue
+					}
+				}
+				valuesCount++
+			}
+
+			switch aggregate.Function {
+			case db.ConstAggregateCount:
+				value = valuesCount
+			case db.ConstAggregateAvg:
+				if valuesCount > 0 {
+					value = utils.InterfaceToFloat64(value) / float64(valuesCount)
+				}
+			}
+
+			row[aggregate.Name] = value
+		}
+
+		rows = append(rows, row)
+	}
+	releaseTables(false)
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		for idx, group := range groupBy {
+			if comparison := compareValues(rows[i][group.Name], rows[j][group.Name], groupTypes[idx]); comparison != 0 {
+				return comparison < 0
+			}
+		}
+		return false
+	})
+
+	result := make([]map[string]interface{}, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, db.ConvertAggregateRow(it, groupBy, aggregates, row))
+	}
+
+	return result, nil
+}
+
+// Save stores record in DB for current collection
+//   - record with existing "_id" is updated with not nil values, otherwise new record inserted
+func (it *DBCollection) Save(item map[string]interface{}) (string, error) {
+
+	// prevents saving of blank records
+	if len(item) == 0 {
+		return "", nil
+	}
+
+	// we should make new _id column if it was not set
+	if idValue, present := item["_id"]; present && idValue != nil {
+		item["_id"] = makeUUID(utils.InterfaceToString(idValue))
+	} else {
+		item["_id"] = makeUUID("")
+	}
+	id := item["_id"].(string)
+
+	acquireTables(true)
+	defer releaseTables(true)
+
+	table := it.getTable(true)
+
+	for columnName, value := range item {
+		if _, present := table.columns[columnName]; !present && value != nil {
+			return "", env.ErrorNew(ConstErrorModule, ConstErrorLevel, "1a5e0fc4-1a55-4aa7-a28c-6f8fb9fa4b7e", "can't find column '"+columnName+"' in '"+it.Name+"' collection")
+		}
+	}
+
+	storedRecord, isStored := table.records[id]
+
+	// optimistic concurrency control, ref. to db.AddVersionColumn
+	if _, present := table.columns[db.ConstVersionColumn]; present {
+		storedVersion := db.GetRecordVersion(storedRecord)
+		if version := db.GetRecordVersion(item); isStored && version != 0 && version != storedVersion {
+			return "", db.NewVersionConflictError(it.Name, id)
+		}
+		item[db.ConstVersionColumn] = storedVersion + 1
+	}
+
+	// records are replaced rather than modified, ref. to memoryTable
+	record := make(map[string]interface{}, len(table.columns))
+	if isStored {
+		for columnName, value := range storedRecord {
+			record[columnName] = value
+		}
+	} else {
+		table.ids = append(table.ids, id)
+	}
+
+	for columnName, value := range item {
+		if value != nil {
+			record[columnName] = convertValueToType(table.columns[columnName], value)
+		}
+	}
+	table.records[id] = record
+
+	return id, nil
+}
+
+// Delete removes records that matches current select statement from DB
+//   - returns amount of affected rows
+func (it *DBCollection) Delete() (int, error) {
+	matcher, err := it.makeRecordMatcher()
+	if err != nil {
+		return 0, env.ErrorDispatch(err)
+	}
+
+	acquireTables(true)
+	defer releaseTables(true)
+
+	table := it.getTable(false)
+
+	affected := 0
+	ids := make([]string, 0, len(table.ids))
+	for _, id := range table.ids {
+		if matcher(table.records[id], table.columns) {
+			delete(table.records, id)
+			affected++
+		} else {
+			ids = append(ids, id)
+		}
+	}
+	table.ids = ids
+
+	return affected, nil
+}
+
+// DeleteByID removes record from DB by is's id
+func (it *DBCollection) DeleteByID(id string) error {
+	acquireTables(true)
+	defer releaseTables(true)
+
+	table := it.getTable(false)
+	if _, present := table.records[id]; !present {
+		return nil
+	}
+
+	delete(table.records, id)
+
+	ids := make([]string, 0, len(table.ids))
+	for _, tableID := range table.ids {
+		if tableID != id {
+			ids = append(ids, tableID)
+		}
+	}
+	table.ids = ids
+
+	return nil
+}
+
+// SetupFilterGroup setups filter group params for collection
+func (it *DBCollection) SetupFilterGroup(groupName string, orSequence bool, parentGroup string) error {
+	if _, present := it.FilterGroups[parentGroup]; !present && parentGroup != "" {
+		if parentGroup == ConstFilterGroupDefault {
+			// create default group if not present and required as parent
+			it.getFilterGroup(ConstFilterGroupDefault)
+		} else {
+			return env.ErrorNew(ConstErrorModule, ConstErrorLevel, "015b80b9-3f2d-4372-9967-4c4e37e2b173", "invalid parent group")
+		}
+	}
+
+	filterGroup := it.getFilterGroup(groupName)
+	filterGroup.OrSequence = orSequence
+	filterGroup.ParentGroup = parentGroup
+
+	return nil
+}
+
+// RemoveFilterGroup removes filter group for collection
+func (it *DBCollection) RemoveFilterGroup(groupName string) error {
+	if _, present := it.FilterGroups[groupName]; !present {
+		return env.ErrorNew(ConstErrorModule, ConstErrorLevel, "e0d1d0c6-be7e-44f8-a3f8-88872c9bc574", "invalid group name")
+	}
+
+	delete(it.FilterGroups, groupName)
+	return nil
+}
+
+// AddGroupFilter adds selection filter to specific filter group (all filter groups will be joined before db query)
+func (it *DBCollection) AddGroupFilter(groupName string, columnName string, operator string, value interface{}) error {
+	return it.updateFilterGroup(groupName, columnName, operator, value)
+}
+
+// AddStaticFilter adds selection filter that will not be cleared by ClearFilters() function
+func (it *DBCollection) AddStaticFilter(columnName string, operator string, value interface{}) error {
+	return it.updateFilterGroup(ConstFilterGroupStatic, columnName, operator, value)
+}
+
+// AddFilter adds selection filter to current collection object
+func (it *DBCollection) AddFilter(columnName string, operator string, value interface{}) error {
+	return it.updateFilterGroup(ConstFilterGroupDefault, columnName, operator, value)
+}
+
+// ClearFilters removes all filters that were set for current collection, except static
+func (it *DBCollection) ClearFilters() error {
+	for filterGroup := range it.FilterGroups {
+		if filterGroup != ConstFilterGroupStatic {
+			delete(it.FilterGroups, filterGroup)
+		}
+	}
+
+	return nil
+}
+
+// AddSort adds sorting for current collection
+func (it *DBCollection) AddSort(columnName string, desc bool) error {
+	if !it.HasColumn(columnName) {
+		return env.ErrorNew(ConstErrorModule, ConstErrorLevel, "b85796c9-2f6b-45b8-be76-8541478dc639", "can't find column '"+columnName+"'")
+	}
+
+	if desc {
+		it.Order = append(it.Order, "-"+columnName)
+	} else {
+		it.Order = append(it.Order, columnName)
+	}
+
+	return nil
+}
+
+// ClearSort removes any sorting that was set for current collection
+func (it *DBCollection) ClearSort() error {
+	it.Order = make([]string, 0)
+	return nil
+}
+
+// SetResultColumns limits column selection for Load() and LoadByID()function
+func (it *DBCollection) SetResultColumns(columns ...string) error {
+	for _, columnName := range columns {
+		if !it.HasColumn(columnName) {
+			return env.ErrorNew(ConstErrorModule, ConstErrorLevel, "27a7f12e-7757-4a39-ad93-bd4a625d38a8", "there is no column "+columnName+" found")
+		}
+
+		it.ResultColumns = append(it.ResultColumns, columnName)
+	}
+
+	return nil
+}
+
+// SetLimit results pagination
+func (it *DBCollection) SetLimit(offset int, limit int) error {
+	it.Offset = offset
+	it.Limit = limit
+
+	return nil
+}
+
+// SetCursor switches collection to keyset pagination, so records following the cursor are selected
+//   - "_id" column is added to sort order to make it unique
+//   - blank cursor means first page
+func (it *DBCollection) SetCursor(cursor string) error {
+	if !utils.IsInListStr("_id", it.getSortColumns()) && !utils.IsInListStr("-_id", it.getSortColumns()) {
+		if err := it.AddSort("_id", false); err != nil {
+			return env.ErrorDispatch(err)
+		}
+	}
+	it.isCursorSet = true
+
+	if cursor == "" {
+		return nil
+	}
+
+	return db.ApplyCursor(it, it.getSortColumns(), cursor)
+}
+
+// GetNextCursor returns cursor pointing to the last loaded record or blank string if there were no records
+func (it *DBCollection) GetNextCursor() string {
+	if !it.isCursorSet {
+		return ""
+	}
+	return db.EncodeCursor(it.getSortColumns(), it.lastRecord)
+}
+
+// ListColumns returns attributes(columns) available for current collection
+func (it *DBCollection) ListColumns() map[string]string {
+	acquireTables(false)
+	defer releaseTables(false)
+
+	result := make(map[string]string)
+	for columnName, columnType := range it.getTable(false).columns {
+		result[columnName] = columnType
+	}
+
+	return result
+}
+
+// GetColumnType returns type of attribute in current collection, or if not present ""
+func (it *DBCollection) GetColumnType(columnName string) string {
+	if columnName == "_id" {
+		return db.ConstTypeID
+	}
+
+	acquireTables(false)
+	defer releaseTables(false)
+
+	return it.getTable(false).columns[columnName]
+}
+
+// HasColumn checks attribute(column) presence in current collection
+func (it *DBCollection) HasColumn(columnName string) bool {
+	acquireTables(false)
+	defer releaseTables(false)
+
+	_, present := it.getTable(false).columns[columnName]
+	return present
+}
+
+// AddColumn adds new attribute(column) to current collection
+func (it *DBCollection) AddColumn(columnName string, columnType string, indexed bool) error {
+
+	// checking column name
+	if !ConstNameValidator.MatchString(columnName) {
+		return env.ErrorNew(ConstErrorModule, ConstErrorLevel, "f3d6280d-f1e7-48b2-963e-85abac676055", "not valid column name for DB engine: "+columnName)
+	}
+
+	acquireTables(true)
+	defer releaseTables(true)
+
+	table := it.getTable(true)
+
+	// checking if column already present
+	if currentType, present := table.columns[columnName]; present {
+		if currentType != columnType {
+			return env.ErrorNew(ConstErrorModule, ConstErrorLevel, "d05f9b64-0ac6-4dea-9e91-31bd27bdeab6", "column '"+columnName+"' already exists with type '"+currentType+"' for '"+it.Name+"' collection. Requested type '"+columnType+"'")
+		}
+		return nil
+	}
+
+	table.columns[columnName] = columnType
+	table.indexed[columnName] = indexed
+
+	return nil
+}
+
+// RemoveColumn removes attribute(column) to current collection
+func (it *DBCollection) RemoveColumn(columnName string) error {
+	if columnName == "_id" {
+		return env.ErrorNew(ConstErrorModule, ConstErrorLevel, "06c92f1e-a074-464b-9713-1cdb505e143d", "you can't remove _id column")
+	}
+
+	acquireTables(true)
+	defer releaseTables(true)
+
+	table := it.getTable(false)
+	if _, present := table.columns[columnName]; !present {
+		return env.ErrorNew(ConstErrorModule, ConstErrorLevel, "ef887d53-3c52-4c5a-9488-f28eaf801b0a", "column '"+columnName+"' not exists in '"+it.Name+"' collection")
+	}
+
+	delete(table.columns, columnName)
+	delete(table.indexed, columnName)
+
+	// records are replaced rather than modified, ref. to memoryTable
+	for id, storedRecord := range table.records {
+		if _, present := storedRecord[columnName]; !present {
+			continue
+		}
+
+		record := make(map[string]interface{}, len(storedRecord))
+		for recordColumn, value := range storedRecord {
+			if recordColumn != columnName {
+				record[recordColumn] = value
+			}
+		}
+		table.records[id] = record
+	}
+
+	return nil
+}
@@ -0,0 +1,351 @@
+package memory
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ottemo/commerce/db"
+	"github.com/ottemo/commerce/env"
+	"github.com/ottemo/commerce/utils"
+)
+
+// recordMatcherType checks stored record to match collection filters
+type recordMatcherType func(record map[string]interface{}, columns map[string]string) bool
+
+// getTable returns collection table, should be called within acquireTables(...) lock
+//   - blank table is returned for not existing collection, it is stored if create flag set
+func (it *DBCollection) getTable(create bool) *memoryTable {
+	table, present := dbEngine.tables[it.Name]
+	if !present {
+		table = &memoryTable{
+			columns: map[string]string{"_id": db.ConstTypeID},
+			indexed: map[string]bool{"_id": true},
+			ids:     make([]string, 0),
+			records: make(map[string]map[string]interface{}),
+		}
+		if create {
+			dbEngine.tables[it.Name] = table
+		}
+	}
+	return table
+}
+
+// makeFilter validates [column, operator, value] combination and returns filter for it
+//   - internal usage function for AddFilter and AddStaticFilter routines
+func (it *DBCollection) makeFilter(columnName string, operator string, value interface{}) (StructDBFilter, error) {
+	if !it.HasColumn(columnName) {
+		return StructDBFilter{}, env.ErrorNew(ConstErrorModule, ConstErrorLevel, "72b6f644-e72e-42d6-8e0c-d215e0bca78d", "can't find column '"+columnName+"'")
+	}
+
+	operator = strings.ToUpper(operator)
+	allowedOperators := []string{"=", "!=", "<>", ">", ">=", "<", "<=", "LIKE", "IN"}
+
+	if !utils.IsInListStr(operator, allowedOperators) {
+		return StructDBFilter{}, env.ErrorNew(ConstErrorModule, ConstErrorLevel, "99c2ffb6-4aea-4da0-bef2-fb1ec5636382", "unknown operator '"+operator+"' for column '"+columnName+"', allowed: '"+strings.Join(allowedOperators, "', ")+"'")
+	}
+
+	if operator == "LIKE" {
+		if _, ok := value.(string); !ok {
+			value = ""
+		}
+	}
+
+	return StructDBFilter{Column: columnName, Operator: operator, Value: value}, nil
+}
+
+// makeRecordMatcher returns function checking records to match collection filters
+//   - sub-collections used within "IN" filters are loaded here, so matcher could be used within tables lock
+func (it *DBCollection) makeRecordMatcher() (recordMatcherType, error) {
+	filterGroups := make(map[string]*StructDBFilterGroup, len(it.FilterGroups))
+	for groupName, filterGroup := range it.FilterGroups {
+		resolvedGroup := *filterGroup
+		resolvedGroup.FilterValues = make([]StructDBFilter, 0, len(filterGroup.FilterValues))
+
+		for _, filter := range filterGroup.FilterValues {
+			if subCollection, ok := filter.Value.(*DBCollection); ok {
+				values, err := subCollection.getSubQueryValues()
+				if err != nil {
+					return nil, env.ErrorDispatch(err)
+				}
+				filter.Value = values
+			}
+			resolvedGroup.FilterValues = append(resolvedGroup.FilterValues, filter)
+		}
+
+		filterGroups[groupName] = &resolvedGroup
+	}
+
+	likePatterns := make(map[string]*regexp.Regexp)
+
+	var matchGroups func(parentGroupName string, orSequence bool, record map[string]interface{}, columns map[string]string) (bool, bool)
+	matchGroups = func(parentGroupName string, orSequence bool, record map[string]interface{}, columns map[string]string) (bool, bool) {
+		result, isSet := !orSequence, false
+
+		collect := func(matched bool) {
+			isSet = true
+			if orSequence {
+				result = result || matched
+			} else {
+				result = result && matched
+			}
+		}
+
+		for groupName, filterGroup := range filterGroups {
+			if filterGroup.ParentGroup != parentGroupName || groupName == parentGroupName {
+				continue
+			}
+
+			groupResult, groupIsSet := matchGroups(groupName, filterGroup.OrSequence, record, columns)
+			for _, filter := range filterGroup.FilterValues {
+				matched := matchFilter(filter, record[filter.Column], columns[filter.Column], likePatterns)
+				if !groupIsSet {
+					groupResult, groupIsSet = matched, true
+				} else if filterGroup.OrSequence {
+					groupResult = groupResult || matched
+				} else {
+					groupResult = groupResult && matched
+				}
+			}
+
+			if groupIsSet {
+				collect(groupResult)
+			}
+		}
+
+		return result, isSet
+	}
+
+	return func(record map[string]interface{}, columns map[string]string) bool {
+		result, _ := matchGroups("", false, record, columns)
+		return result
+	}, nil
+}
+
+// matchFilter checks stored value to match the filter
+//   - filters on array columns are matching if any of the value items is within filter value items
+//   - null value is not matching any filter
+func matchFilter(filter StructDBFilter, value interface{}, columnType string, likePatterns map[string]*regexp.Regexp) bool {
+	if value == nil {
+		return false
+	}
+
+	// array column - special case
+	if db.TypeIsArray(columnType) {
+		for _, filterItem := range strings.Split(valueToLikeString(filter.Value), ", ") {
+			for _, valueItem := range utils.InterfaceToArray(value) {
+				if strings.EqualFold(utils.InterfaceToString(valueItem), filterItem) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	// regular columns - default case
+	switch filter.Operator {
+	case "LIKE":
+		pattern := utils.InterfaceToString(filter.Value)
+		if !strings.Contains(pattern, "%") {
+			return strings.Contains(strings.ToLower(valueToLikeString(value)), strings.ToLower(pattern))
+		}
+
+		likeRegexp, present := likePatterns[pattern]
+		if !present {
+			regexpPattern := regexp.QuoteMeta(strings.Trim(strings.Trim(pattern, "'"), "\""))
+			regexpPattern = strings.Replace(regexpPattern, "%", ".*", -1)
+			regexpPattern = strings.Replace(regexpPattern, "_", ".", -1)
+
+			likeRegexp = regexp.MustCompile("(?is)^" + regexpPattern + "$")
+			likePatterns[pattern] = likeRegexp
+		}
+		return likeRegexp.MatchString(valueToLikeString(value))
+
+	case "IN":
+		for _, item := range utils.InterfaceToArray(filter.Value) {
+			if compareValues(value, convertValueToType(columnType, item), columnType) == 0 {
+				return true
+			}
+		}
+		return false
+	}
+
+	comparison := compareValues(value, convertValueToType(columnType, filter.Value), columnType)
+	switch filter.Operator {
+	case "=":
+		return comparison == 0
+	case "!=", "<>":
+		return comparison != 0
+	case ">":
+		return comparison > 0
+	case ">=":
+		return comparison >= 0
+	case "<":
+		return comparison < 0
+	case "<=":
+		return comparison <= 0
+	}
+
+	return false
+}
+
+// selectRecords returns stored records matching collection filters in collection sort order,
+// should be called within acquireTables(...) lock with matcher made before the lock
+func (it *DBCollection) selectRecords(table *memoryTable, matcher recordMatcherType, applyLimit bool) []map[string]interface{} {
+	var result []map[string]interface{}
+
+	for _, id := range table.ids {
+		if record := table.records[id]; matcher(record, table.columns) {
+			result = append(result, record)
+		}
+	}
+
+	if len(it.Order) > 0 {
+		sort.SliceStable(result, func(i, j int) bool {
+			for _, sortColumn := range it.Order {
+				columnName := strings.TrimPrefix(sortColumn, "-")
+				comparison := compareValues(result[i][columnName], result[j][columnName], table.columns[columnName])
+				if comparison != 0 {
+					if strings.HasPrefix(sortColumn, "-") {
+						return comparison > 0
+					}
+					return comparison < 0
+				}
+			}
+			return false
+		})
+	}
+
+	if applyLimit && it.Limit > 0 {
+		if it.Offset >= len(result) {
+			return nil
+		}
+		result = result[it.Offset:]
+		if it.Limit < len(result) {
+			result = result[:it.Limit]
+		}
+	}
+
+	return result
+}
+
+// makeResultRow makes record copy with result columns, null values are converted to zero values
+// of column type as sqlite engine does
+func (it *DBCollection) makeResultRow(table *memoryTable, record map[string]interface{}) map[string]interface{} {
+	resultColumns := it.ResultColumns
+
+	// sort columns are required to make next cursor
+	if it.isCursorSet && len(resultColumns) > 0 {
+		resultColumns = append([]string{}, resultColumns...)
+		for _, sortColumn := range it.Order {
+			if sortColumn = strings.TrimPrefix(sortColumn, "-"); !utils.IsInListStr(sortColumn, resultColumns) {
+				resultColumns = append(resultColumns, sortColumn)
+			}
+		}
+	}
+
+	if len(resultColumns) == 0 {
+		for columnName := range table.columns {
+			resultColumns = append(resultColumns, columnName)
+		}
+	}
+
+	result := make(map[string]interface{}, len(resultColumns))
+	for _, columnName := range resultColumns {
+		columnType := table.columns[columnName]
+		if value := record[columnName]; value != nil {
+			result[columnName] = convertValueToType(columnType, value)
+		} else {
+			result[columnName] = db.ConvertTypeFromDbToGo(nil, columnType)
+		}
+	}
+
+	return result
+}
+
+// getSubQueryValues returns values of the first result column ("_id" by default) to be used within "IN" filter
+func (it *DBCollection) getSubQueryValues() ([]interface{}, error) {
+	columnName := "_id"
+	if len(it.ResultColumns) > 0 {
+		columnName = it.ResultColumns[0]
+	}
+
+	matcher, err := it.makeRecordMatcher()
+	if err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
+	acquireTables(false)
+	defer releaseTables(false)
+
+	var result []interface{}
+	for _, record := range it.selectRecords(it.getTable(false), matcher, true) {
+		if value := record[columnName]; value != nil {
+			result = append(result, value)
+		}
+	}
+
+	return result, nil
+}
+
+// getAggregateGroupValue returns value of aggregation group key for the stored record
+func getAggregateGroupValue(group db.StructAggregateGroup, record map[string]interface{}) interface{} {
+	columnPath := strings.Split(group.Column, ".")
+	value := record[columnPath[0]]
+
+	for _, pathItem := range columnPath[1:] {
+		mapValue, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		value = mapValue[pathItem]
+	}
+
+	if group.Bucket == "" || value == nil {
+		return value
+	}
+
+	timeValue := utils.InterfaceToTime(value).UTC()
+	unixValue := timeValue.Unix()
+	switch group.Bucket {
+	case db.ConstAggregateBucketHour:
+		return time.Unix(unixValue-unixValue%3600, 0).UTC()
+	case db.ConstAggregateBucketDay:
+		return time.Unix(unixValue-unixValue%86400, 0).UTC()
+	case db.ConstAggregateBucketMonth:
+		return time.Date(timeValue.Year(), timeValue.Month(), 1, 0, 0, 0, 0, time.UTC)
+	case db.ConstAggregateBucketYear:
+		return time.Date(timeValue.Year(), 1, 1, 0, 0, 0, 0, time.UTC)
+	}
+
+	return value
+}
+
+// getSortColumns returns sort columns in db.EncodeCursor(...) format
+func (it *DBCollection) getSortColumns() []string {
+	return append([]string{}, it.Order...)
+}
+
+// getFilterGroup returns filter group, creates new one if not exists
+func (it *DBCollection) getFilterGroup(groupName string) *StructDBFilterGroup {
+	filterGroup, present := it.FilterGroups[groupName]
+	if !present {
+		filterGroup = &StructDBFilterGroup{Name: groupName, FilterValues: make([]StructDBFilter, 0)}
+		it.FilterGroups[groupName] = filterGroup
+	}
+	return filterGroup
+}
+
+// updateFilterGroup adds filter(combination of [column, operator, value]) in named filter group
+func (it *DBCollection) updateFilterGroup(groupName string, columnName string, operator string, value interface{}) error {
+	filter, err := it.makeFilter(columnName, operator, value)
+	if err != nil {
+		return err
+	}
+
+	filterGroup := it.getFilterGroup(groupName)
+	filterGroup.FilterValues = append(filterGroup.FilterValues, filter)
+
+	return nil
+}
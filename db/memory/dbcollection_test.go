@@ -0,0 +1,433 @@
+package memory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ottemo/commerce/api/context"
+	"github.com/ottemo/commerce/db"
+	"github.com/ottemo/commerce/env"
+	"github.com/ottemo/commerce/utils"
+
+	_ "github.com/ottemo/commerce/env/errorbus" // error codes are required to detect version conflicts
+)
+
+// makeTestCollection creates collection with given columns and records
+func makeTestCollection(t *testing.T, name string, columns map[string]string, records []map[string]interface{}) db.InterfaceDBCollection {
+	dbCollection, err := dbEngine.GetCollection(name)
+	if err != nil {
+		t.Fatal("dbEngine.GetCollection", err)
+	}
+
+	for column, columnType := range columns {
+		if err := dbCollection.AddColumn(column, columnType, false); err != nil {
+			t.Fatal("dbCollection.AddColumn", err)
+		}
+	}
+
+	for _, record := range records {
+		if _, err := dbCollection.Save(record); err != nil {
+			t.Fatal("dbCollection.Save", err)
+		}
+	}
+
+	return dbCollection
+}
+
+// loadColumn returns column values of collection records
+func loadColumn(t *testing.T, dbCollection db.InterfaceDBCollection, column string) []interface{} {
+	records, err := dbCollection.Load()
+	if err != nil {
+		t.Fatal("dbCollection.Load", err)
+	}
+
+	var result []interface{}
+	for _, record := range records {
+		result = append(result, record[column])
+	}
+	return result
+}
+
+func TestSave(t *testing.T) {
+	dbCollection := makeTestCollection(t, "testSave", map[string]string{"sku": "varchar(100)", "qty": "int", "tags": "[]text", "options": "json", "created_at": "datetime"}, nil)
+
+	createdAt := time.Date(2019, 3, 10, 12, 30, 15, 500, time.UTC)
+	options := map[string]interface{}{"color": "red"}
+
+	id, err := dbCollection.Save(map[string]interface{}{"sku": "a", "qty": "2", "tags": []string{"x", "y"}, "options": options, "created_at": createdAt})
+	if err != nil {
+		t.Fatal("dbCollection.Save", err)
+	}
+	if len(id) != 24 {
+		t.Errorf("unexpected id '%s'", id)
+	}
+
+	// stored values should not be affected by saved item modification
+	options["color"] = "blue"
+
+	record, err := dbCollection.LoadByID(id)
+	if err != nil {
+		t.Fatal("dbCollection.LoadByID", err)
+	}
+	if record["_id"] != id || record["qty"] != 2 || utils.InterfaceToString(record["tags"]) != utils.InterfaceToString([]interface{}{"x", "y"}) {
+		t.Errorf("unexpected record %v", record)
+	}
+	if utils.InterfaceToMap(record["options"])["color"] != "red" {
+		t.Errorf("unexpected options %v", record["options"])
+	}
+	if !utils.InterfaceToTime(record["created_at"]).Equal(createdAt.Truncate(time.Second)) {
+		t.Errorf("unexpected created_at %v", record["created_at"])
+	}
+
+	// partial update keeps not given values
+	if _, err := dbCollection.Save(map[string]interface{}{"_id": id, "qty": 3, "sku": nil}); err != nil {
+		t.Fatal("dbCollection.Save", err)
+	}
+	if record, _ := dbCollection.LoadByID(id); record["sku"] != "a" || record["qty"] != 3 {
+		t.Errorf("unexpected record after update %v", record)
+	}
+
+	if _, err := dbCollection.Save(map[string]interface{}{"unknown": 1}); err == nil {
+		t.Error("saving of unknown column should fail")
+	}
+
+	if err := dbCollection.DeleteByID(id); err != nil {
+		t.Fatal("dbCollection.DeleteByID", err)
+	}
+	if _, err := dbCollection.LoadByID(id); err == nil {
+		t.Error("deleted record should not be found")
+	}
+}
+
+func TestVersion(t *testing.T) {
+	dbCollection := makeTestCollection(t, "testVersion", map[string]string{"sku": "varchar(100)"}, nil)
+	if err := db.AddVersionColumn(dbCollection); err != nil {
+		t.Fatal("db.AddVersionColumn", err)
+	}
+
+	item := map[string]interface{}{"sku": "a"}
+	id, err := dbCollection.Save(item)
+	if err != nil {
+		t.Fatal("dbCollection.Save", err)
+	}
+	if db.GetRecordVersion(item) != 1 {
+		t.Errorf("unexpected version of new record %v", item)
+	}
+
+	// two writers read the same version, the second one should get a conflict
+	first, _ := dbCollection.LoadByID(id)
+	second, _ := dbCollection.LoadByID(id)
+
+	first["sku"] = "b"
+	if _, err := dbCollection.Save(first); err != nil || db.GetRecordVersion(first) != 2 {
+		t.Fatal("dbCollection.Save", err, first)
+	}
+
+	second["sku"] = "c"
+	if _, err := dbCollection.Save(second); !db.IsVersionConflict(err) {
+		t.Fatal("outdated record save should be a version conflict", err)
+	}
+	if record, _ := dbCollection.LoadByID(id); record["sku"] != "b" || db.GetRecordVersion(record) != 2 {
+		t.Errorf("unexpected record after conflict %v", record)
+	}
+
+	// save without version overwrites stored record
+	if _, err := dbCollection.Save(map[string]interface{}{"_id": id, "sku": "d"}); err != nil {
+		t.Fatal("dbCollection.Save", err)
+	}
+	if record, _ := dbCollection.LoadByID(id); record["sku"] != "d" || db.GetRecordVersion(record) != 3 {
+		t.Errorf("unexpected record after blind save %v", record)
+	}
+
+	// absent version inserts record only once
+	newID := "5c8b2e7a9ccee8613b5f16e8"
+	if _, err := dbCollection.Save(map[string]interface{}{"_id": newID, "sku": "e", "_version": db.ConstVersionAbsent}); err != nil {
+		t.Fatal("dbCollection.Save", err)
+	}
+	if _, err := dbCollection.Save(map[string]interface{}{"_id": newID, "sku": "f", "_version": db.ConstVersionAbsent}); !db.IsVersionConflict(err) {
+		t.Fatal("second insert should be a version conflict", err)
+	}
+}
+
+func TestFilters(t *testing.T) {
+	dbCollection := makeTestCollection(t, "testFilters", map[string]string{"sku": "varchar(100)", "qty": "int", "enabled": "bool", "tags": "[]text"}, []map[string]interface{}{
+		{"sku": "Apple", "qty": 1, "enabled": true, "tags": []string{"fruit", "red"}},
+		{"sku": "Banana", "qty": 5, "enabled": false, "tags": []string{"fruit"}},
+		{"sku": "Carrot", "qty": 10, "enabled": true, "tags": []string{"vegetable"}},
+		{"sku": "Daikon", "qty": 15},
+	})
+
+	testCases := []struct {
+		setup    func() error
+		expected []interface{}
+	}{
+		{func() error { return dbCollection.AddFilter("qty", ">=", "5") }, []interface{}{"Banana", "Carrot", "Daikon"}},
+		{func() error { return dbCollection.AddFilter("enabled", "!=", true) }, []interface{}{"Banana"}},
+		{func() error { return dbCollection.AddFilter("sku", "like", "an") }, []interface{}{"Banana"}},
+		{func() error { return dbCollection.AddFilter("sku", "LIKE", "%o_") }, []interface{}{"Carrot", "Daikon"}},
+		{func() error { return dbCollection.AddFilter("qty", "in", []int{1, 15}) }, []interface{}{"Apple", "Daikon"}},
+		{func() error { return dbCollection.AddFilter("tags", "=", "red, vegetable") }, []interface{}{"Apple", "Carrot"}},
+		{func() error {
+			if err := dbCollection.SetupFilterGroup("or", true, ""); err != nil {
+				return err
+			}
+			if err := dbCollection.AddGroupFilter("or", "qty", "<", 5); err != nil {
+				return err
+			}
+			if err := dbCollection.AddGroupFilter("or", "qty", ">", 10); err != nil {
+				return err
+			}
+			return dbCollection.AddFilter("enabled", "=", true)
+		}, []interface{}{"Apple"}},
+	}
+
+	for idx, testCase := range testCases {
+		if err := dbCollection.ClearFilters(); err != nil {
+			t.Fatal("dbCollection.ClearFilters", err)
+		}
+		if err := testCase.setup(); err != nil {
+			t.Fatalf("case %d: %v", idx, err)
+		}
+		if result := loadColumn(t, dbCollection, "sku"); utils.InterfaceToString(result) != utils.InterfaceToString(testCase.expected) {
+			t.Errorf("case %d: expected %v, got %v", idx, testCase.expected, result)
+		}
+	}
+
+	// static filter is kept after ClearFilters, sub-collection could be used as "IN" filter value
+	subCollection, _ := dbEngine.GetCollection("testFilters")
+	if err := subCollection.AddFilter("sku", "=", "Carrot"); err != nil {
+		t.Fatal("subCollection.AddFilter", err)
+	}
+	if err := subCollection.SetResultColumns("qty"); err != nil {
+		t.Fatal("subCollection.SetResultColumns", err)
+	}
+
+	if err := dbCollection.AddStaticFilter("qty", "in", subCollection); err != nil {
+		t.Fatal("dbCollection.AddStaticFilter", err)
+	}
+	if err := dbCollection.ClearFilters(); err != nil {
+		t.Fatal("dbCollection.ClearFilters", err)
+	}
+	if count, err := dbCollection.Count(); err != nil || count != 1 {
+		t.Errorf("expected 1 record, got %v (%v)", count, err)
+	}
+
+	if err := dbCollection.AddFilter("unknown", "=", 1); err == nil {
+		t.Error("filter on unknown column should fail")
+	}
+	if err := dbCollection.AddFilter("qty", "~", 1); err == nil {
+		t.Error("filter with unknown operator should fail")
+	}
+}
+
+func TestSortLimitDistinct(t *testing.T) {
+	dbCollection := makeTestCollection(t, "testSort", map[string]string{"sku": "varchar(100)", "price": "money", "tags": "[]text"}, []map[string]interface{}{
+		{"sku": "b", "price": 2.5, "tags": []string{"x", "y"}},
+		{"sku": "a", "price": 10, "tags": []string{"y", "z"}},
+		{"sku": "c", "price": 2.5},
+		{"sku": "a", "price": 1},
+	})
+
+	if err := dbCollection.AddSort("price", true); err != nil {
+		t.Fatal("dbCollection.AddSort", err)
+	}
+	if err := dbCollection.AddSort("sku", false); err != nil {
+		t.Fatal("dbCollection.AddSort", err)
+	}
+	if result := utils.InterfaceToString(loadColumn(t, dbCollection, "sku")); result != utils.InterfaceToString([]interface{}{"a", "b", "c", "a"}) {
+		t.Errorf("unexpected sort order %v", result)
+	}
+
+	if err := dbCollection.SetLimit(1, 2); err != nil {
+		t.Fatal("dbCollection.SetLimit", err)
+	}
+	if result := utils.InterfaceToString(loadColumn(t, dbCollection, "sku")); result != utils.InterfaceToString([]interface{}{"b", "c"}) {
+		t.Errorf("unexpected limited result %v", result)
+	}
+	if count, _ := dbCollection.Count(); count != 4 {
+		t.Errorf("count should ignore limit, got %d", count)
+	}
+
+	if err := dbCollection.SetLimit(0, 0); err != nil {
+		t.Fatal("dbCollection.SetLimit", err)
+	}
+	if result, _ := dbCollection.Distinct("sku"); utils.InterfaceToString(result) != utils.InterfaceToString([]interface{}{"a", "b", "c"}) {
+		t.Errorf("unexpected distinct values %v", result)
+	}
+	if result, _ := dbCollection.Distinct("tags"); utils.InterfaceToString(result) != utils.InterfaceToString([]interface{}{"y", "z", "x"}) {
+		t.Errorf("unexpected distinct array values %v", result)
+	}
+
+	if err := dbCollection.AddSort("unknown", false); err == nil {
+		t.Error("sort on unknown column should fail")
+	}
+}
+
+func TestCursor(t *testing.T) {
+	dbCollection := makeTestCollection(t, "testCursor", map[string]string{"qty": "int"}, []map[string]interface{}{
+		{"qty": 3}, {"qty": 1}, {"qty": 2}, {"qty": 1},
+	})
+
+	var result []interface{}
+	cursor := ""
+	for page := 0; page < 3; page++ {
+		pageCollection, _ := dbEngine.GetCollection("testCursor")
+		if err := pageCollection.AddSort("qty", false); err != nil {
+			t.Fatal("pageCollection.AddSort", err)
+		}
+		if err := pageCollection.SetCursor(cursor); err != nil {
+			t.Fatal("pageCollection.SetCursor", err)
+		}
+		if err := pageCollection.SetLimit(0, 2); err != nil {
+			t.Fatal("pageCollection.SetLimit", err)
+		}
+		result = append(result, loadColumn(t, pageCollection, "qty")...)
+		cursor = pageCollection.GetNextCursor()
+	}
+
+	if utils.InterfaceToString(result) != utils.InterfaceToString([]interface{}{1, 1, 2, 3}) {
+		t.Errorf("unexpected paginated result %v", result)
+	}
+	if count, _ := dbCollection.Count(); count != 4 {
+		t.Errorf("unexpected records count %d", count)
+	}
+}
+
+func TestAggregate(t *testing.T) {
+	dayStart := time.Date(2019, 3, 10, 0, 0, 0, 0, time.UTC)
+	dbCollection := makeTestCollection(t, "testAggregate", map[string]string{"sku": "varchar(100)", "qty": "int", "created_at": "datetime"}, []map[string]interface{}{
+		{"sku": "b", "qty": 5, "created_at": dayStart.Add(26 * time.Hour)},
+		{"sku": "a", "qty": 1, "created_at": dayStart.Add(time.Hour)},
+		{"sku": "a", "qty": 2, "created_at": dayStart.Add(2 * time.Hour)},
+	})
+
+	result, err := dbCollection.Aggregate(
+		[]db.StructAggregateGroup{{Column: "created_at", Bucket: db.ConstAggregateBucketDay, Name: "day"}},
+		[]db.StructAggregate{{Function: db.ConstAggregateSum, Column: "qty"}, {Function: db.ConstAggregateCount}, {Function: db.ConstAggregateMax, Column: "sku"}})
+	if err != nil {
+		t.Fatal("dbCollection.Aggregate", err)
+	}
+
+	if len(result) != 2 {
+		t.Fatalf("expected 2 groups, got %v", result)
+	}
+	if day := utils.InterfaceToTime(result[0]["day"]); !day.Equal(dayStart) {
+		t.Errorf("unexpected day bucket %v", day)
+	}
+	if result[0]["sum_qty"] != 3.0 || result[0]["count"] != 2 || result[0]["max_sku"] != "a" {
+		t.Errorf("unexpected aggregation result %v", result[0])
+	}
+	if result[1]["sum_qty"] != 5.0 || result[1]["count"] != 1 {
+		t.Errorf("unexpected aggregation result %v", result[1])
+	}
+
+	if err := dbCollection.AddFilter("qty", ">", 100); err != nil {
+		t.Fatal("dbCollection.AddFilter", err)
+	}
+	result, err = dbCollection.Aggregate(nil, []db.StructAggregate{{Function: db.ConstAggregateCount}})
+	if err != nil || len(result) != 1 || result[0]["count"] != 0 {
+		t.Errorf("unexpected aggregation without groups %v (%v)", result, err)
+	}
+}
+
+func TestTransaction(t *testing.T) {
+	dbCollection := makeTestCollection(t, "testTransaction", map[string]string{"qty": "int"}, []map[string]interface{}{{"qty": 1}})
+
+	if err := dbEngine.BeginTransaction(); err == nil {
+		t.Error("transaction should not start without call context")
+	}
+
+	context.MakeContext(func() {
+		if err := dbEngine.BeginTransaction(); err != nil {
+			t.Fatal("dbEngine.BeginTransaction", err)
+		}
+		if _, err := dbCollection.Save(map[string]interface{}{"qty": 2}); err != nil {
+			t.Error("dbCollection.Save", err)
+		}
+		if err := dbCollection.AddColumn("sku", "varchar(100)", false); err != nil {
+			t.Error("dbCollection.AddColumn", err)
+		}
+		if count, _ := dbCollection.Count(); count != 2 {
+			t.Errorf("transaction changes should be visible within transaction, got %d records", count)
+		}
+		if err := dbEngine.RollbackTransaction(); err != nil {
+			t.Error("dbEngine.RollbackTransaction", err)
+		}
+	})
+
+	if count, _ := dbCollection.Count(); count != 1 {
+		t.Errorf("rolled back changes should be discarded, got %d records", count)
+	}
+	if dbCollection.HasColumn("sku") {
+		t.Error("rolled back column should be discarded")
+	}
+}
+
+func TestAfterCommit(t *testing.T) {
+	var calls []string
+
+	err := db.InTransaction(func() error {
+		db.AfterCommit(func() {
+			calls = append(calls, "committed")
+			if db.IsInTransaction() {
+				t.Error("after commit function should be called out of transaction")
+			}
+		})
+		if len(calls) != 0 {
+			t.Error("after commit function was called before commit")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal("db.InTransaction", err)
+	}
+
+	err = db.InTransaction(func() error {
+		db.AfterCommit(func() { calls = append(calls, "rolled back") })
+		return env.ErrorNew("test", env.ConstErrorLevelAPI, "", "rollback")
+	})
+	if err == nil {
+		t.Error("transaction error expected")
+	}
+
+	db.AfterCommit(func() { calls = append(calls, "immediate") })
+
+	if len(calls) != 2 || calls[0] != "committed" || calls[1] != "immediate" {
+		t.Errorf("unexpected after commit calls %v", calls)
+	}
+}
+
+func TestChangeColumnType(t *testing.T) {
+	var records []map[string]interface{}
+	for i := 0; i < db.ConstMigrationBatchSize+10; i++ {
+		records = append(records, map[string]interface{}{"qty": utils.InterfaceToString(i)})
+	}
+	dbCollection := makeTestCollection(t, "testChangeColumnType", map[string]string{"qty": db.ConstTypeVarchar}, records)
+
+	if err := db.ChangeColumnType(dbCollection, "qty", db.ConstTypeInteger, true); err != nil {
+		t.Fatal("db.ChangeColumnType", err)
+	}
+
+	if columnType := dbCollection.GetColumnType("qty"); columnType != db.ConstTypeInteger {
+		t.Errorf("column type is %q", columnType)
+	}
+	if dbCollection.HasColumn("qty" + db.ConstMigrationTempSuffix) {
+		t.Error("temporary column was not removed")
+	}
+
+	values := loadColumn(t, dbCollection, "qty")
+	if len(values) != len(records) {
+		t.Fatalf("%d records after change, expected %d", len(values), len(records))
+	}
+	sum := 0
+	for _, value := range values {
+		intValue, ok := value.(int)
+		if !ok {
+			t.Fatalf("value %v was not converted to int", value)
+		}
+		sum += intValue
+	}
+	if expected := (len(records) - 1) * len(records) / 2; sum != expected {
+		t.Errorf("values sum is %d after change, expected %d", sum, expected)
+	}
+}
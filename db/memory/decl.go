@@ -0,0 +1,86 @@
+package memory
+
+import (
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/ottemo/commerce/env"
+)
+
+// Package global constants
+const (
+	ConstConnectionValidateInterval = time.Second * 10 // timer interval to ping connection
+
+	ConstFilterGroupStatic  = "static"  // name for static filter, ref. to AddStaticFilter(...)
+	ConstFilterGroupDefault = "default" // name for default filter, ref. to by AddFilter(...)
+
+	ConstContextKeyTransaction = "db.memory.transaction" // call context key to mark transaction owner
+
+	ConstErrorModule = "db/memory"
+	ConstErrorLevel  = env.ConstErrorLevelService
+)
+
+// Package global variables
+var (
+	// dbEngine is an instance of database engine (one per application)
+	dbEngine *DBEngine
+
+	// ConstNameValidator is a regex expression used to check collection and column names
+	ConstNameValidator = regexp.MustCompile("^[A-Za-z_][A-Za-z0-9_]*$")
+)
+
+// StructDBFilter is a structure to hold single [column, operator, value] filter
+type StructDBFilter struct {
+	Column   string
+	Operator string
+	Value    interface{}
+}
+
+// StructDBFilterGroup is a structure to hold information of named collection filter
+type StructDBFilterGroup struct {
+	Name         string
+	FilterValues []StructDBFilter
+	ParentGroup  string
+	OrSequence   bool
+}
+
+// DBCollection is a InterfaceDBCollection implementer
+type DBCollection struct {
+	Name string
+
+	ResultColumns []string
+	FilterGroups  map[string]*StructDBFilterGroup
+	Order         []string // sort columns, descending order columns are prefixed with "-"
+
+	Offset int
+	Limit  int
+
+	isCursorSet bool                   // keyset pagination mode, ref. to SetCursor(...)
+	lastRecord  map[string]interface{} // last iterated record, used to make next cursor
+}
+
+// DBEngine is a InterfaceDBEngine implementer
+type DBEngine struct {
+	tables      map[string]*memoryTable
+	tablesMutex sync.RWMutex
+
+	// transactionMutex is exclusively locked while transaction is running
+	transactionMutex sync.RWMutex
+	// transactionSnapshot is a tables state at transaction begin, restored on rollback
+	transactionSnapshot map[string]*memoryTable
+
+	isConnected bool
+}
+
+// memoryTable holds collection columns and records
+//   - records are not modified in place but replaced, so table copy made by copy() is independent
+type memoryTable struct {
+	columns map[string]string
+	indexed map[string]bool
+	ids     []string // record ids in insertion order
+	records map[string]map[string]interface{}
+}
+
+// connectionParamsType describes params required to connect to DB
+type connectionParamsType struct{}
@@ -0,0 +1,15 @@
+// Copyright 2019 Ottemo. All rights reserved.
+
+/*
+Package memory is an in-memory implementation of "InterfaceDBEngine" declared in "github.com/ottemo/commerce/db" package.
+
+Package keeps collections within application memory, so data is lost on application exit. It is supposed to be used
+for Go tests and demo purposes as it does not require neither database server nor disk access. Filtering, sorting,
+pagination and column types semantics are following the "github.com/ottemo/commerce/db/sqlite" package.
+
+In order to use the engine build application with "memory" tag:
+
+	go build -tags memory
+	go test -tags memory github.com/ottemo/commerce/test
+*/
+package memory
@@ -0,0 +1,61 @@
+package memory
+
+import (
+	"time"
+
+	"github.com/ottemo/commerce/env"
+)
+
+// ------------------------------------------------------------------------------------
+// InterfaceDBConnector implementation (package "github.com/ottemo/commerce/db/interfaces")
+// ------------------------------------------------------------------------------------
+
+// GetConnectionParams returns configured DB connection params, in-memory engine have none
+func (it *DBEngine) GetConnectionParams() interface{} {
+	return connectionParamsType{}
+}
+
+// Connect establishes DB connection, in-memory engine is always available
+func (it *DBEngine) Connect(srcConnectionParams interface{}) error {
+	return nil
+}
+
+// AfterConnect makes initialization of DB engine
+func (it *DBEngine) AfterConnect(srcConnectionParams interface{}) error {
+	return nil
+}
+
+// Ping checks connection alive
+func (it *DBEngine) Ping() error {
+	return nil
+}
+
+// GetValidationInterval returns delay between Ping
+func (it *DBEngine) GetValidationInterval() time.Duration {
+	return ConstConnectionValidateInterval
+}
+
+// Reconnect tries to reconnect to DB, collections are kept as they are
+func (it *DBEngine) Reconnect(srcConnectionParams interface{}) error {
+	return nil
+}
+
+// IsConnected returns connection status
+func (it *DBEngine) IsConnected() bool {
+	return it.isConnected
+}
+
+// SetConnected sets connection status
+func (it *DBEngine) SetConnected(connected bool) {
+	it.isConnected = connected
+}
+
+// GetEngineName returns DBEngine name (InterfaceDBConnector)
+func (it *DBEngine) GetEngineName() string {
+	return it.GetName()
+}
+
+// LogConnection outputs message to log
+func (it *DBEngine) LogConnection(message string) {
+	env.Log("memory.log", "DEBUG", message)
+}
@@ -0,0 +1,127 @@
+package memory
+
+import (
+	"github.com/ottemo/commerce/api/context"
+	"github.com/ottemo/commerce/db"
+	"github.com/ottemo/commerce/env"
+)
+
+// GetName returns current DB engine name
+func (it *DBEngine) GetName() string {
+	return "Memory"
+}
+
+// HasCollection checks if collection already exists
+func (it *DBEngine) HasCollection(collectionName string) bool {
+	acquireTables(false)
+	defer releaseTables(false)
+
+	_, present := it.tables[collectionName]
+	return present
+}
+
+// CreateCollection creates collection by it's name
+func (it *DBEngine) CreateCollection(collectionName string) error {
+	if !ConstNameValidator.MatchString(collectionName) {
+		return env.ErrorNew(ConstErrorModule, ConstErrorLevel, "734f1331-bcf0-48f6-afa5-5a04c573a70c", "not valid collection name for DB engine")
+	}
+
+	acquireTables(true)
+	defer releaseTables(true)
+
+	if _, present := it.tables[collectionName]; present {
+		return env.ErrorNew(ConstErrorModule, ConstErrorLevel, "bcaf7486-de22-4086-9935-c4422abe20ad", "collection '"+collectionName+"' already exists")
+	}
+
+	it.tables[collectionName] = &memoryTable{
+		columns: map[string]string{"_id": db.ConstTypeID},
+		indexed: map[string]bool{"_id": true},
+		ids:     make([]string, 0),
+		records: make(map[string]map[string]interface{}),
+	}
+
+	return nil
+}
+
+// GetCollection returns collection by name or creates new one
+func (it *DBEngine) GetCollection(collectionName string) (db.InterfaceDBCollection, error) {
+	if !ConstNameValidator.MatchString(collectionName) {
+		return nil, env.ErrorNew(ConstErrorModule, ConstErrorLevel, "dec837af-2c6a-43e1-8b6b-5ab0f001cf76", "not valid collection name for DB engine")
+	}
+
+	if !it.HasCollection(collectionName) {
+		if err := it.CreateCollection(collectionName); err != nil {
+			return nil, env.ErrorDispatch(err)
+		}
+	}
+
+	collection := &DBCollection{
+		Name:          collectionName,
+		FilterGroups:  make(map[string]*StructDBFilterGroup),
+		Order:         make([]string, 0),
+		ResultColumns: make([]string, 0),
+	}
+
+	return collection, nil
+}
+
+// RawQuery is not supported by in-memory engine
+func (it *DBEngine) RawQuery(query string) (map[string]interface{}, error) {
+	return nil, env.ErrorNew(ConstErrorModule, ConstErrorLevel, "888ae2f9-598a-448f-9d1f-05c6afa1e86a", "raw queries are not supported by in-memory engine")
+}
+
+// BeginTransaction starts a transaction bound to current call context, other routines statements are
+// waiting for the transaction to finish
+func (it *DBEngine) BeginTransaction() error {
+	if context.GetContext() == nil {
+		return env.ErrorNew(ConstErrorModule, ConstErrorLevel, "e4e508a4-d4b0-49e9-8a74-c510230ff6f0", "transaction requires call context")
+	}
+	if isInTransaction() {
+		return env.ErrorNew(ConstErrorModule, ConstErrorLevel, "92502eb2-a5ba-43c0-b940-67cdf1b80788", "transaction already started")
+	}
+
+	it.transactionMutex.Lock()
+	context.SetContextValue(ConstContextKeyTransaction, true)
+
+	it.tablesMutex.Lock()
+	it.transactionSnapshot = make(map[string]*memoryTable, len(it.tables))
+	for tableName, table := range it.tables {
+		it.transactionSnapshot[tableName] = table.copy()
+	}
+	it.tablesMutex.Unlock()
+
+	return nil
+}
+
+// CommitTransaction commits transaction started within current call context
+func (it *DBEngine) CommitTransaction() error {
+	if !isInTransaction() {
+		return env.ErrorNew(ConstErrorModule, ConstErrorLevel, "9c432000-dd4c-4713-b3cf-857b1b2f4ecb", "there is no started transaction")
+	}
+
+	it.tablesMutex.Lock()
+	it.transactionSnapshot = nil
+	it.tablesMutex.Unlock()
+
+	context.SetContextValue(ConstContextKeyTransaction, false)
+	it.transactionMutex.Unlock()
+
+	return nil
+}
+
+// RollbackTransaction rolls back transaction started within current call context
+func (it *DBEngine) RollbackTransaction() error {
+	if !isInTransaction() {
+		return env.ErrorNew(ConstErrorModule, ConstErrorLevel, "cb8a7b48-3fcf-44b7-90c4-8102e6957fb4", "there is no started transaction")
+	}
+
+	it.tablesMutex.Lock()
+	it.tables = it.transactionSnapshot
+	it.transactionSnapshot = nil
+	it.tablesMutex.Unlock()
+
+	context.SetContextValue(ConstContextKeyTransaction, false)
+	it.transactionMutex.Unlock()
+
+	return nil
+}
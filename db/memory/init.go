@@ -0,0 +1,21 @@
+package memory
+
+import (
+	"github.com/ottemo/commerce/db"
+	"github.com/ottemo/commerce/env"
+)
+
+// init makes package self-initialization routine
+func init() {
+	dbEngine = new(DBEngine)
+	dbEngine.tables = make(map[string]*memoryTable)
+
+	var _ db.InterfaceDBEngine = dbEngine
+
+	var dbConnector = db.NewDBConnector(dbEngine)
+	env.RegisterOnConfigIniStart(dbConnector.ConnectAsync)
+
+	if err := db.RegisterDBEngine(dbEngine); err != nil {
+		_ = env.ErrorDispatch(err)
+	}
+}
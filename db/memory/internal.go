@@ -0,0 +1,202 @@
+package memory
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ottemo/commerce/api/context"
+	"github.com/ottemo/commerce/db"
+	"github.com/ottemo/commerce/env"
+	"github.com/ottemo/commerce/utils"
+)
+
+// isInTransaction checks if current call context have started a transaction
+func isInTransaction() bool {
+	return utils.InterfaceToBool(context.GetContextValue(ConstContextKeyTransaction))
+}
+
+// acquireTables locks tables for reading or writing, statements made outside of started transaction
+// are waiting for the transaction to finish
+func acquireTables(write bool) {
+	if !isInTransaction() {
+		dbEngine.transactionMutex.RLock()
+	}
+	if write {
+		dbEngine.tablesMutex.Lock()
+	} else {
+		dbEngine.tablesMutex.RLock()
+	}
+}
+
+// releaseTables unlocks tables previously locked by acquireTables
+func releaseTables(write bool) {
+	if write {
+		dbEngine.tablesMutex.Unlock()
+	} else {
+		dbEngine.tablesMutex.RUnlock()
+	}
+	if !isInTransaction() {
+		dbEngine.transactionMutex.RUnlock()
+	}
+}
+
+// copy makes table copy independent from the original one
+func (it *memoryTable) copy() *memoryTable {
+	result := &memoryTable{
+		columns: make(map[string]string, len(it.columns)),
+		indexed: make(map[string]bool, len(it.indexed)),
+		ids:     make([]string, len(it.ids)),
+		records: make(map[string]map[string]interface{}, len(it.records)),
+	}
+
+	for columnName, columnType := range it.columns {
+		result.columns[columnName] = columnType
+	}
+	for columnName, isIndexed := range it.indexed {
+		result.indexed[columnName] = isIndexed
+	}
+	copy(result.ids, it.ids)
+	for id, record := range it.records {
+		result.records[id] = record
+	}
+
+	return result
+}
+
+// convertValueToType converts value to be stored in a column of given type, result is not sharing
+// maps and slices with the given value
+//   - conversion follows sqlite engine value round-trip, so datetime values are truncated to seconds
+func convertValueToType(columnType string, value interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
+
+	switch {
+	case db.TypeIsArray(columnType):
+		if _, ok := value.(string); ok {
+			return db.ConvertTypeFromDbToGo(value, columnType)
+		}
+
+		itemType := strings.TrimPrefix(columnType, "[]")
+		result := make([]interface{}, 0)
+		for _, item := range utils.InterfaceToArray(value) {
+			result = append(result, convertValueToType(itemType, item))
+		}
+		return result
+
+	case columnType == db.ConstTypeJSON:
+		if _, ok := value.(string); !ok {
+			value = utils.EncodeToJSONString(value)
+		}
+		return db.ConvertTypeFromDbToGo(value, columnType)
+
+	case columnType == db.ConstTypeDatetime:
+		return time.Unix(utils.InterfaceToTime(value).Unix(), 0)
+
+	case columnType == db.ConstTypeID:
+		return utils.InterfaceToString(value)
+	}
+
+	return db.ConvertTypeFromDbToGo(value, columnType)
+}
+
+// compareValues compares stored values of given column type, returns -1, 0 or 1
+//   - nil value is less than any other value
+func compareValues(left interface{}, right interface{}, columnType string) int {
+	switch {
+	case left == nil && right == nil:
+		return 0
+	case left == nil:
+		return -1
+	case right == nil:
+		return 1
+	}
+
+	switch {
+	case db.TypeIsArray(columnType):
+		break
+
+	case strings.HasPrefix(columnType, db.ConstTypeBoolean):
+		leftBool, rightBool := utils.InterfaceToBool(left), utils.InterfaceToBool(right)
+		switch {
+		case leftBool == rightBool:
+			return 0
+		case rightBool:
+			return -1
+		}
+		return 1
+
+	case strings.HasPrefix(columnType, db.ConstTypeInteger), db.TypeIsFloat(columnType):
+		return compareFloats(utils.InterfaceToFloat64(left), utils.InterfaceToFloat64(right))
+
+	case columnType == db.ConstTypeDatetime:
+		leftTime, rightTime := utils.InterfaceToTime(left), utils.InterfaceToTime(right)
+		switch {
+		case leftTime.Before(rightTime):
+			return -1
+		case leftTime.After(rightTime):
+			return 1
+		}
+		return 0
+
+	default:
+		// values of unknown type, like JSON path values
+		leftFloat, leftIsFloat := left.(float64)
+		rightFloat, rightIsFloat := right.(float64)
+		if leftIsFloat && rightIsFloat {
+			return compareFloats(leftFloat, rightFloat)
+		}
+	}
+
+	return strings.Compare(utils.InterfaceToString(left), utils.InterfaceToString(right))
+}
+
+// compareFloats compares float values, returns -1, 0 or 1
+func compareFloats(left float64, right float64) int {
+	switch {
+	case left < right:
+		return -1
+	case left > right:
+		return 1
+	}
+	return 0
+}
+
+// valueToLikeString returns string representation of stored value for LIKE comparison
+//   - datetime values are represented as unix time as sqlite engine does
+func valueToLikeString(value interface{}) string {
+	switch typedValue := value.(type) {
+	case time.Time:
+		return strconv.FormatInt(typedValue.Unix(), 10)
+	case []interface{}:
+		items := make([]string, 0, len(typedValue))
+		for _, item := range typedValue {
+			items = append(items, utils.InterfaceToString(item))
+		}
+		return strings.Join(items, ", ")
+	}
+	return utils.InterfaceToString(value)
+}
+
+// makeUUID generates new UUID for _id column
+func makeUUID(id string) string {
+
+	if len(id) != 24 {
+		timeStamp := strconv.FormatInt(time.Now().Unix(), 16)
+
+		randomBytes := make([]byte, 8)
+		if _, err := rand.Reader.Read(randomBytes); err != nil {
+			_ = env.ErrorDispatch(err)
+		}
+
+		randomHex := make([]byte, 16)
+		hex.Encode(randomHex, randomBytes)
+
+		id = timeStamp + string(randomHex)
+	}
+
+	return id
+}
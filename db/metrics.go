@@ -0,0 +1,32 @@
+package db
+
+import (
+	"strings"
+	"time"
+
+	"github.com/ottemo/commerce/metrics"
+)
+
+// queryDuration is a histogram of database queries made by engines
+var queryDuration = metrics.NewHistogram("db_query_duration_seconds", "Database query duration in seconds.", nil, "engine", "operation")
+
+// ObserveQuery adds duration of query started at given time to database metrics
+//   - supposed to be deferred by DB engines: defer db.ObserveQuery("MySQL", "select", time.Now())
+func ObserveQuery(engine string, operation string, startTime time.Time) {
+	queryDuration.ObserveDuration(startTime, engine, operation)
+}
+
+// GetQueryOperation returns lowercased SQL statement kind to be used as metric label, "other" for unknown ones
+func GetQueryOperation(SQL string) string {
+	fields := strings.Fields(SQL)
+	if len(fields) == 0 {
+		return "other"
+	}
+
+	operation := strings.ToLower(fields[0])
+	switch operation {
+	case "select", "insert", "update", "delete", "replace", "create", "alter", "drop", "truncate", "pragma":
+		return operation
+	}
+	return "other"
+}
@@ -0,0 +1,247 @@
+package db
+
+import (
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ottemo/commerce/env"
+	"github.com/ottemo/commerce/utils"
+)
+
+// StructMigration represents versioned schema change of a module
+type StructMigration struct {
+	Module      string
+	Version     int
+	Description string
+	Apply       func() error
+}
+
+// StructMigrationInfo represents migration state
+type StructMigrationInfo struct {
+	Module      string    `json:"module"`
+	Version     int       `json:"version"`
+	Description string    `json:"description"`
+	IsApplied   bool      `json:"is_applied"`
+	AppliedAt   time.Time `json:"applied_at"`
+}
+
+// Package global variables
+var (
+	registeredMigrations = []*StructMigration{} // set of migrations registered by modules, in registration order
+	migrationsMutex      sync.Mutex             // prevents simultaneous migrations applying
+
+	migrationsResult   []StructMigrationInfo // result of migrations applied on database start
+	migrationsError    error                 // error of migrations applied on database start
+	migrationsFinished = make(chan bool)     // closed when migrations on database start are finished
+	migrationsOnce     sync.Once
+)
+
+// RegisterMigration registers versioned migration for a module, migrations of a module are applied in version order
+//   - should be called within package init() routine as migrations are applied on database start
+func RegisterMigration(module string, version int, description string, apply func() error) error {
+	if module == "" || version <= 0 || apply == nil {
+		return env.ErrorNew(ConstErrorModule, ConstErrorLevel, "614dfa40-d5c1-4d90-89a4-68ac93085749", "migration module, positive version and apply function should be specified")
+	}
+
+	for _, migration := range registeredMigrations {
+		if migration.Module == module && migration.Version == version {
+			return env.ErrorNew(ConstErrorModule, ConstErrorLevel, "7223a78f-5e1c-4dbd-80d5-fc2ebd320c12", "migration "+module+" v"+utils.InterfaceToString(version)+" already registered")
+		}
+	}
+
+	registeredMigrations = append(registeredMigrations, &StructMigration{
+		Module:      module,
+		Version:     version,
+		Description: description,
+		Apply:       apply,
+	})
+
+	return nil
+}
+
+// GetMigrations returns state of all registered migrations
+func GetMigrations() ([]StructMigrationInfo, error) {
+	applied, err := loadAppliedMigrations()
+	if err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
+	var result []StructMigrationInfo
+	for _, migration := range getOrderedMigrations() {
+		info := StructMigrationInfo{
+			Module:      migration.Module,
+			Version:     migration.Version,
+			Description: migration.Description,
+		}
+		if appliedAt, present := applied[migrationKey(migration.Module, migration.Version)]; present {
+			info.IsApplied = true
+			info.AppliedAt = appliedAt
+		}
+		result = append(result, info)
+	}
+
+	return result, nil
+}
+
+// ApplyMigrations applies pending migrations and returns them, on dry run migrations are only listed
+//   - each migration is applied within a transaction and recorded into migrations collection,
+//     the process stops on first failed migration
+//   - migrations are not atomic: MySQL commits schema changes implicitly and SQLite column type cache
+//     is not restored on rollback, so a failed migration may leave its schema changes applied;
+//     migration steps should check current schema (e.g. HasColumn) to be safely re-applied
+func ApplyMigrations(dryRun bool) ([]StructMigrationInfo, error) {
+	migrationsMutex.Lock()
+	defer migrationsMutex.Unlock()
+
+	applied, err := loadAppliedMigrations()
+	if err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
+	result := make([]StructMigrationInfo, 0)
+	for _, migration := range getOrderedMigrations() {
+		if _, present := applied[migrationKey(migration.Module, migration.Version)]; present {
+			continue
+		}
+
+		info := StructMigrationInfo{
+			Module:      migration.Module,
+			Version:     migration.Version,
+			Description: migration.Description,
+		}
+
+		if !dryRun {
+			err := InTransaction(func() error {
+				if err := migration.Apply(); err != nil {
+					return env.ErrorDispatch(err)
+				}
+				return recordMigration(migration)
+			})
+			if err != nil {
+				return result, env.ErrorNew(ConstErrorModule, ConstErrorLevel, "464c70da-5fb5-447e-a937-1fb13db706e5", "migration "+migration.Module+" v"+utils.InterfaceToString(migration.Version)+" failed: "+err.Error())
+			}
+
+			info.IsApplied = true
+			info.AppliedAt = time.Now()
+
+			env.Log(ConstMigrationsLogStorage, env.ConstLogPrefixInfo, "applied migration "+migration.Module+" v"+utils.InterfaceToString(migration.Version)+": "+migration.Description)
+		}
+
+		result = append(result, info)
+	}
+
+	return result, nil
+}
+
+// IsMigrationsDryRun returns true if application was started with migrations dry run command line argument
+func IsMigrationsDryRun() bool {
+	return utils.IsInListStr(ConstCmdArgMigrationsDryRun, os.Args)
+}
+
+// WaitMigrations blocks until migrations on database start are finished and returns their result
+func WaitMigrations() ([]StructMigrationInfo, error) {
+	<-migrationsFinished
+	return migrationsResult, migrationsError
+}
+
+// finishStartMigrations stores result of migrations made on database start and releases WaitMigrations callers
+func finishStartMigrations(result []StructMigrationInfo, err error) error {
+	migrationsOnce.Do(func() {
+		migrationsResult, migrationsError = result, err
+		close(migrationsFinished)
+	})
+	return err
+}
+
+// getOrderedMigrations returns registered migrations ordered by version within a module,
+// modules are following in order of their first migration registration
+func getOrderedMigrations() []*StructMigration {
+	moduleOrder := make(map[string]int)
+	for _, migration := range registeredMigrations {
+		if _, present := moduleOrder[migration.Module]; !present {
+			moduleOrder[migration.Module] = len(moduleOrder)
+		}
+	}
+
+	result := make([]*StructMigration, len(registeredMigrations))
+	copy(result, registeredMigrations)
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Module != result[j].Module {
+			return moduleOrder[result[i].Module] < moduleOrder[result[j].Module]
+		}
+		return result[i].Version < result[j].Version
+	})
+
+	return result
+}
+
+// migrationKey returns key to identify migration
+func migrationKey(module string, version int) string {
+	return module + ":" + utils.InterfaceToString(version)
+}
+
+// getMigrationsCollection returns migrations collection with setup columns
+func getMigrationsCollection() (InterfaceDBCollection, error) {
+	collection, err := GetCollection(ConstCollectionNameMigrations)
+	if err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
+	if !collection.HasColumn("module") {
+		if err := collection.AddColumn("module", TypeWPrecision(ConstTypeVarchar, 150), true); err != nil {
+			return nil, env.ErrorDispatch(err)
+		}
+		if err := collection.AddColumn("version", ConstTypeInteger, false); err != nil {
+			return nil, env.ErrorDispatch(err)
+		}
+		if err := collection.AddColumn("description", ConstTypeText, false); err != nil {
+			return nil, env.ErrorDispatch(err)
+		}
+		if err := collection.AddColumn("applied_at", ConstTypeDatetime, false); err != nil {
+			return nil, env.ErrorDispatch(err)
+		}
+	}
+
+	return collection, nil
+}
+
+// loadAppliedMigrations returns applying time of applied migrations by their key
+func loadAppliedMigrations() (map[string]time.Time, error) {
+	collection, err := getMigrationsCollection()
+	if err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
+	records, err := collection.Load()
+	if err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
+	result := make(map[string]time.Time)
+	for _, record := range records {
+		key := migrationKey(utils.InterfaceToString(record["module"]), utils.InterfaceToInt(record["version"]))
+		result[key] = utils.InterfaceToTime(record["applied_at"])
+	}
+
+	return result, nil
+}
+
+// recordMigration stores migration to migrations collection
+func recordMigration(migration *StructMigration) error {
+	collection, err := getMigrationsCollection()
+	if err != nil {
+		return env.ErrorDispatch(err)
+	}
+
+	_, err = collection.Save(map[string]interface{}{
+		"module":      migration.Module,
+		"version":     migration.Version,
+		"description": migration.Description,
+		"applied_at":  time.Now(),
+	})
+
+	return env.ErrorDispatch(err)
+}
@@ -0,0 +1,40 @@
+package db
+
+import (
+	"testing"
+)
+
+func TestMigrationsOrder(t *testing.T) {
+	defer func(saved []*StructMigration) { registeredMigrations = saved }(registeredMigrations)
+	registeredMigrations = []*StructMigration{}
+
+	noop := func() error { return nil }
+	for _, migration := range []StructMigration{
+		{Module: "order", Version: 2},
+		{Module: "stock", Version: 1},
+		{Module: "order", Version: 1},
+		{Module: "stock", Version: 3},
+		{Module: "stock", Version: 2},
+	} {
+		if err := RegisterMigration(migration.Module, migration.Version, "", noop); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expected := []string{"order:1", "order:2", "stock:1", "stock:2", "stock:3"}
+	for idx, migration := range getOrderedMigrations() {
+		if key := migrationKey(migration.Module, migration.Version); key != expected[idx] {
+			t.Errorf("unexpected migration at %d: %s != %s", idx, key, expected[idx])
+		}
+	}
+
+	if err := RegisterMigration("order", 2, "", noop); err == nil {
+		t.Error("duplicate migration version should not be registered")
+	}
+	if err := RegisterMigration("order", 0, "", noop); err == nil {
+		t.Error("non positive migration version should not be registered")
+	}
+	if err := RegisterMigration("order", 5, "", nil); err == nil {
+		t.Error("migration without apply function should not be registered")
+	}
+}
@@ -3,15 +3,20 @@ package mongo
 import (
 	"fmt"
 	"sort"
+	"time"
 
+	"gopkg.in/mgo.v2"
 	"gopkg.in/mgo.v2/bson"
 
 	"github.com/ottemo/commerce/db"
 	"github.com/ottemo/commerce/env"
+	"github.com/ottemo/commerce/utils"
 )
 
 // LoadByID loads one record from DB by record _id
 func (it *DBCollection) LoadByID(id string) (map[string]interface{}, error) {
+	defer db.ObserveQuery("MongoDB", "select", time.Now())
+
 	result := make(map[string]interface{})
 
 	err := it.collection.FindId(id).One(&result)
@@ -24,19 +29,36 @@ func (it *DBCollection) LoadByID(id string) (map[string]interface{}, error) {
 
 // Load loads records from DB for current collection and filter if it set
 func (it *DBCollection) Load() ([]map[string]interface{}, error) {
+	defer db.ObserveQuery("MongoDB", "select", time.Now())
+
 	var result []map[string]interface{}
 
 	err := it.prepareQuery().All(&result)
 
+	it.lastRecord = nil
+	if it.isCursorSet && len(result) > 0 {
+		it.lastRecord = result[len(result)-1]
+	}
+
 	return result, env.ErrorDispatch(err)
 }
 
 // Iterate applies [iterator] function to each record, stops on return false
 func (it *DBCollection) Iterate(iteratorFunc func(record map[string]interface{}) bool) error {
+	defer db.ObserveQuery("MongoDB", "select", time.Now())
+
 	record := make(map[string]interface{})
 
+	it.lastRecord = nil
 	iterator := it.prepareQuery().Iter()
 	for iterator.Next(&record) {
+		if it.isCursorSet {
+			it.lastRecord = make(map[string]interface{}, len(record))
+			for key, value := range record {
+				it.lastRecord[key] = value
+			}
+		}
+
 		proceed := iteratorFunc(record)
 
 		if !proceed {
@@ -52,11 +74,15 @@ func (it *DBCollection) Iterate(iteratorFunc func(record map[string]interface{})
 
 // Count returns count of rows matching current select statement
 func (it *DBCollection) Count() (int, error) {
+	defer db.ObserveQuery("MongoDB", "count", time.Now())
+
 	return it.collection.Find(it.makeSelector()).Count()
 }
 
 // Distinct returns distinct values of specified attribute
 func (it *DBCollection) Distinct(columnName string) ([]interface{}, error) {
+	defer db.ObserveQuery("MongoDB", "distinct", time.Now())
+
 	var result []interface{}
 
 	err := it.prepareQuery().Distinct(columnName, &result)
@@ -64,8 +90,49 @@ func (it *DBCollection) Distinct(columnName string) ([]interface{}, error) {
 	return result, env.ErrorDispatch(err)
 }
 
+// Aggregate calculates aggregate functions over records matching current select statement grouped by given keys
+//   - result is ordered by group keys, sort and limit of collection are not applied
+func (it *DBCollection) Aggregate(groupBy []db.StructAggregateGroup, aggregates []db.StructAggregate) ([]map[string]interface{}, error) {
+	defer db.ObserveQuery("MongoDB", "aggregate", time.Now())
+
+	groupBy, aggregates, err := db.PrepareAggregate(it, groupBy, aggregates)
+	if err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
+	it.executeSubqueries()
+
+	pipeline := []bson.D{
+		{bson.DocElem{Name: "$match", Value: it.makeSelector()}},
+		{bson.DocElem{Name: "$group", Value: it.makeGroupStage(groupBy, aggregates)}},
+		{bson.DocElem{Name: "$sort", Value: bson.D{bson.DocElem{Name: "_id", Value: 1}}}},
+	}
+
+	var records []bson.M
+	if err := it.collection.Pipe(pipeline).AllowDiskUse().All(&records); err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
+	result := make([]map[string]interface{}, 0, len(records))
+	for _, record := range records {
+		row := make(map[string]interface{}, len(record))
+		for key, value := range record {
+			row[key] = value
+		}
+		if groupKeys, ok := record["_id"].(bson.M); ok {
+			for key, value := range groupKeys {
+				row[key] = value
+			}
+		}
+		result = append(result, db.ConvertAggregateRow(it, groupBy, aggregates, row))
+	}
+
+	return result, nil
+}
+
 // Save stores record in DB for current collection
 func (it *DBCollection) Save(Item map[string]interface{}) (string, error) {
+	defer db.ObserveQuery("MongoDB", "save", time.Now())
 
 	// id verification/updating
 	//-----------------------
@@ -80,6 +147,28 @@ func (it *DBCollection) Save(Item map[string]interface{}) (string, error) {
 	}
 	Item["_id"] = id
 
+	// optimistic concurrency control, ref. to db.AddVersionColumn
+	//------------------------------------------------------------
+	var selector interface{} = id
+	isInsert := false
+	if it.HasColumn(db.ConstVersionColumn) {
+		expectedVersion := db.GetRecordVersion(Item)
+
+		storedVersion, isStored, err := it.getStoredVersion(id)
+		if err != nil {
+			return id, env.ErrorDispatch(err)
+		}
+
+		if expectedVersion != 0 {
+			if isStored && storedVersion != expectedVersion {
+				return id, db.NewVersionConflictError(it.Name, id)
+			}
+			selector = bson.M{"_id": id, db.ConstVersionColumn: expectedVersion}
+		}
+		Item[db.ConstVersionColumn] = storedVersion + 1
+		isInsert = !isStored
+	}
+
 	// sorting by attribute name
 	//--------------------------
 	bsonDocument := make(bson.D, 0, len(Item))
@@ -97,6 +186,24 @@ func (it *DBCollection) Save(Item map[string]interface{}) (string, error) {
 
 	// saving document to DB
 	//----------------------
+	if err := it.journalDocuments(id); err != nil {
+		return id, env.ErrorDispatch(err)
+	}
+
+	// versioned update should not touch record changed by someone else
+	if _, ok := selector.(bson.M); ok {
+		var err error
+		if isInsert {
+			err = it.collection.Insert(bsonDocument)
+		} else {
+			err = it.collection.Update(selector, bsonDocument)
+		}
+		if err == mgo.ErrNotFound || mgo.IsDup(err) {
+			return id, db.NewVersionConflictError(it.Name, id)
+		}
+		return id, env.ErrorDispatch(err)
+	}
+
 	changeInfo, err := it.collection.UpsertId(id, bsonDocument)
 
 	if changeInfo != nil && changeInfo.UpsertedId != nil {
@@ -108,6 +215,12 @@ func (it *DBCollection) Save(Item map[string]interface{}) (string, error) {
 
 // Delete removes records that matches current select statement from DB, returns amount of affected rows
 func (it *DBCollection) Delete() (int, error) {
+	defer db.ObserveQuery("MongoDB", "delete", time.Now())
+
+	if err := it.journalSelected(); err != nil {
+		return 0, env.ErrorDispatch(err)
+	}
+
 	changeInfo, err := it.collection.RemoveAll(it.makeSelector())
 
 	return changeInfo.Removed, env.ErrorDispatch(err)
@@ -115,6 +228,12 @@ func (it *DBCollection) Delete() (int, error) {
 
 // DeleteByID removes record from DB by is's id
 func (it *DBCollection) DeleteByID(id string) error {
+	defer db.ObserveQuery("MongoDB", "delete", time.Now())
+
+	if err := it.journalDocuments(id); err != nil {
+		return env.ErrorDispatch(err)
+	}
+
 	return it.collection.RemoveId(id)
 }
 
@@ -201,6 +320,32 @@ func (it *DBCollection) SetLimit(Offset int, Limit int) error {
 	return nil
 }
 
+// SetCursor switches collection to keyset pagination, so records following the cursor are selected
+//   - "_id" attribute is added to sort order to make it unique
+//   - blank cursor means first page
+func (it *DBCollection) SetCursor(cursor string) error {
+	if !utils.IsInListStr("_id", it.Sort) && !utils.IsInListStr("-_id", it.Sort) {
+		if err := it.AddSort("_id", false); err != nil {
+			return env.ErrorDispatch(err)
+		}
+	}
+	it.isCursorSet = true
+
+	if cursor == "" {
+		return nil
+	}
+
+	return db.ApplyCursor(it, it.Sort, cursor)
+}
+
+// GetNextCursor returns cursor pointing to the last loaded record or blank string if there were no records
+func (it *DBCollection) GetNextCursor() string {
+	if !it.isCursorSet {
+		return ""
+	}
+	return db.EncodeCursor(it.Sort, it.lastRecord)
+}
+
 // SetResultColumns limits column selection for Load() and LoadByID()function
 func (it *DBCollection) SetResultColumns(columns ...string) error {
 	for _, columnName := range columns {
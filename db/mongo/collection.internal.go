@@ -3,7 +3,10 @@ package mongo
 import (
 	"sort"
 	"strings"
+	"time"
 
+	"github.com/ottemo/commerce/api/context"
+	"github.com/ottemo/commerce/db"
 	"github.com/ottemo/commerce/env"
 	"github.com/ottemo/commerce/utils"
 	"gopkg.in/mgo.v2"
@@ -231,11 +234,147 @@ func (it *DBCollection) prepareQuery() *mgo.Query {
 		query = query.Limit(it.Limit)
 	}
 
+	it.executeSubqueries()
+
+	return query
+}
+
+// loads values of sub-collections used within filters of current collection
+func (it *DBCollection) executeSubqueries() {
 	for idx, subCollection := range it.subcollections {
 		if err := subCollection.prepareQuery().Distinct(subCollection.ResultAttributes[0], it.subresults[idx]); err != nil {
 			_ = env.ErrorDispatch(err)
 		}
 	}
+}
 
-	return query
+// returns aggregation pipeline $group stage for given keys and aggregate functions
+func (it *DBCollection) makeGroupStage(groupBy []db.StructAggregateGroup, aggregates []db.StructAggregate) bson.D {
+	var groupID interface{}
+	if len(groupBy) > 0 {
+		groupKeys := make(bson.D, 0, len(groupBy))
+		for _, group := range groupBy {
+			groupKeys = append(groupKeys, bson.DocElem{Name: group.Name, Value: makeGroupExpression(group)})
+		}
+		groupID = groupKeys
+	}
+
+	result := bson.D{bson.DocElem{Name: "_id", Value: groupID}}
+	for _, aggregate := range aggregates {
+		var expression interface{}
+		switch {
+		case aggregate.Function == db.ConstAggregateCount && aggregate.Column == "":
+			expression = bson.M{"$sum": 1}
+		case aggregate.Function == db.ConstAggregateCount:
+			// counts documents having not null column value, as SQL COUNT(column) does
+			notNull := bson.M{"$gt": []interface{}{"$" + aggregate.Column, nil}}
+			expression = bson.M{"$sum": bson.M{"$cond": []interface{}{notNull, 1, 0}}}
+		default:
+			expression = bson.M{"$" + aggregate.Function: "$" + aggregate.Column}
+		}
+		result = append(result, bson.DocElem{Name: aggregate.Name, Value: expression})
+	}
+
+	return result
+}
+
+// returns aggregation pipeline expression for group key, date buckets are made by subtracting
+// milliseconds passed since bucket start to be independent of MongoDB version
+func makeGroupExpression(group db.StructAggregateGroup) interface{} {
+	column := "$" + group.Column
+	epoch := time.Unix(0, 0).UTC()
+
+	millisecondsOf := func(period int64) bson.M {
+		return bson.M{"$mod": []interface{}{bson.M{"$subtract": []interface{}{column, epoch}}, period}}
+	}
+	daysBefore := func(dayOperator string) bson.M {
+		return bson.M{"$multiply": []interface{}{bson.M{"$subtract": []interface{}{bson.M{dayOperator: column}, 1}}, ConstMillisecondsInDay}}
+	}
+
+	switch group.Bucket {
+	case db.ConstAggregateBucketHour:
+		return bson.M{"$subtract": []interface{}{column, millisecondsOf(ConstMillisecondsInHour)}}
+	case db.ConstAggregateBucketDay:
+		return bson.M{"$subtract": []interface{}{column, millisecondsOf(ConstMillisecondsInDay)}}
+	case db.ConstAggregateBucketMonth:
+		return bson.M{"$subtract": []interface{}{column, bson.M{"$add": []interface{}{daysBefore("$dayOfMonth"), millisecondsOf(ConstMillisecondsInDay)}}}}
+	case db.ConstAggregateBucketYear:
+		return bson.M{"$subtract": []interface{}{column, bson.M{"$add": []interface{}{daysBefore("$dayOfYear"), millisecondsOf(ConstMillisecondsInDay)}}}}
+	}
+
+	return column
+}
+
+// returns transaction journal for current call context or nil if there is no started transaction
+func getTransactionJournal() *transactionJournal {
+	if journal, ok := context.GetContextValue(ConstContextKeyTransaction).(*transactionJournal); ok {
+		return journal
+	}
+	return nil
+}
+
+// journals documents state before modification if there is started transaction
+func (it *DBCollection) journalDocuments(ids ...string) error {
+	journal := getTransactionJournal()
+	if journal == nil {
+		return nil
+	}
+
+	for _, id := range ids {
+		key := it.Name + "." + id
+		if journal.journaled[key] {
+			continue
+		}
+
+		record := &transactionJournalRecord{collection: it.Name, id: id}
+
+		var document bson.D
+		err := it.collection.FindId(id).One(&document)
+		switch {
+		case err == nil:
+			record.document = document
+		case err != mgo.ErrNotFound:
+			return env.ErrorDispatch(err)
+		}
+
+		journal.records = append(journal.records, record)
+		journal.journaled[key] = true
+	}
+
+	return nil
+}
+
+// journals documents matching current select statement if there is started transaction
+func (it *DBCollection) journalSelected() error {
+	if getTransactionJournal() == nil {
+		return nil
+	}
+
+	var documents []struct {
+		ID string `bson:"_id"`
+	}
+	if err := it.collection.Find(it.makeSelector()).Select(bson.M{"_id": 1}).All(&documents); err != nil {
+		return env.ErrorDispatch(err)
+	}
+
+	ids := make([]string, 0, len(documents))
+	for _, document := range documents {
+		ids = append(ids, document.ID)
+	}
+
+	return it.journalDocuments(ids...)
+}
+
+// getStoredVersion returns version of stored record, isStored is false if there is no record with given id
+func (it *DBCollection) getStoredVersion(id string) (version int, isStored bool, err error) {
+	var document bson.M
+	err = it.collection.FindId(id).Select(bson.M{db.ConstVersionColumn: 1}).One(&document)
+	switch {
+	case err == mgo.ErrNotFound:
+		return 0, false, nil
+	case err != nil:
+		return 0, false, env.ErrorDispatch(err)
+	}
+
+	return utils.InterfaceToInt(document[db.ConstVersionColumn]), true, nil
 }
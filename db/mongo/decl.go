@@ -22,11 +22,16 @@ const (
 
 	ConstMongoDebug = false // flag which indicates to perform log on each operation
 
+	ConstMillisecondsInHour = int64(time.Hour / time.Millisecond) // date bucket sizes for aggregation pipeline
+	ConstMillisecondsInDay  = int64(24 * time.Hour / time.Millisecond)
+
 	ConstFilterGroupStatic  = "static"  // name for static filter, ref. to AddStaticFilter(...)
 	ConstFilterGroupDefault = "default" // name for default filter, ref. to by AddFilter(...)
 
 	ConstCollectionNameColumnInfo = "collection_column_info" // collection name to hold Ottemo types of attributes
 
+	ConstContextKeyTransaction = "db.mongo.transaction" // call context key to hold started transaction journal
+
 	ConstErrorModule = "db/mongo"
 	ConstErrorLevel  = env.ConstErrorLevelService
 )
@@ -57,6 +62,9 @@ type DBCollection struct {
 
 	Limit  int
 	Offset int
+
+	isCursorSet bool                   // keyset pagination mode, ref. to SetCursor(...)
+	lastRecord  map[string]interface{} // last loaded record, used to make next cursor
 }
 
 // DBEngine is a implementer of InterfaceDBEngine
@@ -70,6 +78,21 @@ type DBEngine struct {
 	isConnected bool
 }
 
+// transactionJournal holds documents state before modification within emulated transaction
+//   - mongo have no multi-document transactions, so rollback restores journaled documents back,
+//     there is no isolation from other routines
+type transactionJournal struct {
+	records   []*transactionJournalRecord
+	journaled map[string]bool
+}
+
+// transactionJournalRecord is a document state before first modification within transaction
+type transactionJournalRecord struct {
+	collection string
+	id         string
+	document   bson.D // nil if document was not existing
+}
+
 // connectionParamsType describes params required to connect to DB
 type connectionParamsType struct {
 	UseSSL      bool
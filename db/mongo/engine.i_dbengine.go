@@ -3,6 +3,7 @@ package mongo
 import (
 	"strings"
 
+	"github.com/ottemo/commerce/api/context"
 	"github.com/ottemo/commerce/db"
 	"github.com/ottemo/commerce/env"
 
@@ -69,3 +70,62 @@ func (it *DBEngine) RawQuery(query string) (map[string]interface{}, error) {
 	err := it.database.Run(bson.D{{"eval", query}}, result)
 	return result, err
 }
+
+// BeginTransaction starts emulated transaction bound to current call context
+//   - documents state is journaled before modification and restored on rollback
+func (it *DBEngine) BeginTransaction() error {
+	if context.GetContext() == nil {
+		return env.ErrorNew(ConstErrorModule, ConstErrorLevel, "1ade90ea-e96c-4246-9545-567111d84766", "transaction requires call context")
+	}
+	if getTransactionJournal() != nil {
+		return env.ErrorNew(ConstErrorModule, ConstErrorLevel, "d49b400f-1111-4f48-829d-6b9924031b60", "transaction already started")
+	}
+
+	context.SetContextValue(ConstContextKeyTransaction, &transactionJournal{
+		records:   make([]*transactionJournalRecord, 0),
+		journaled: make(map[string]bool),
+	})
+
+	return nil
+}
+
+// CommitTransaction commits transaction started within current call context
+func (it *DBEngine) CommitTransaction() error {
+	if getTransactionJournal() == nil {
+		return env.ErrorNew(ConstErrorModule, ConstErrorLevel, "2df09744-71e1-4ad6-8d5b-7cf5697f43cb", "there is no started transaction")
+	}
+	context.SetContextValue(ConstContextKeyTransaction, nil)
+
+	return nil
+}
+
+// RollbackTransaction restores documents modified within current call context transaction
+func (it *DBEngine) RollbackTransaction() error {
+	journal := getTransactionJournal()
+	if journal == nil {
+		return env.ErrorNew(ConstErrorModule, ConstErrorLevel, "271e9c01-315c-41d3-b650-37b3f3f7d205", "there is no started transaction")
+	}
+	context.SetContextValue(ConstContextKeyTransaction, nil)
+
+	var result error
+	for i := len(journal.records) - 1; i >= 0; i-- {
+		record := journal.records[i]
+		collection := it.database.C(record.collection)
+
+		var err error
+		if record.document == nil {
+			err = collection.RemoveId(record.id)
+			if err == mgo.ErrNotFound {
+				err = nil
+			}
+		} else {
+			_, err = collection.UpsertId(record.id, record.document)
+		}
+
+		if err != nil {
+			result = env.ErrorDispatch(err)
+		}
+	}
+
+	return result
+}
@@ -58,8 +58,9 @@ func (it *DBCollection) Load() ([]map[string]interface{}, error) {
 func (it *DBCollection) Iterate(iteratorFunc func(record map[string]interface{}) bool) error {
 
 	SQL := it.getSelectSQL()
+	it.lastRecord = nil
 
-	rows, err := connectionQuery(SQL)
+	rows, err := connectionQueryRead(SQL)
 	defer closeCursor(rows)
 
 	if err == nil {
@@ -67,6 +68,10 @@ func (it *DBCollection) Iterate(iteratorFunc func(record map[string]interface{})
 			if row, err := getRowAsStringMap(rows); err == nil {
 				it.modifyResultRow(row)
 
+				if it.isCursorSet {
+					it.lastRecord = row
+				}
+
 				if !iteratorFunc(row) {
 					break
 				}
@@ -95,7 +100,7 @@ func (it *DBCollection) Distinct(columnName string) ([]interface{}, error) {
 
 	it.ResultColumns = prevResultColumns
 
-	rows, err := connectionQuery(SQL)
+	rows, err := connectionQueryRead(SQL)
 	defer closeCursor(rows)
 
 	var result []interface{}
@@ -156,7 +161,7 @@ func (it *DBCollection) Count() (int, error) {
 
 	SQL := "SELECT COUNT(*) AS cnt FROM `" + it.Name + "`" + sqlLoadFilter
 
-	rows, err := connectionQuery(SQL)
+	rows, err := connectionQueryRead(SQL)
 	defer closeCursor(rows)
 
 	if err == nil {
@@ -175,6 +180,36 @@ func (it *DBCollection) Count() (int, error) {
 	return 0, err
 }
 
+// Aggregate calculates aggregate functions over records matching current select statement grouped by given keys
+//   - result is ordered by group keys, sort and limit of collection are not applied
+func (it *DBCollection) Aggregate(groupBy []db.StructAggregateGroup, aggregates []db.StructAggregate) ([]map[string]interface{}, error) {
+	groupBy, aggregates, err := db.PrepareAggregate(it, groupBy, aggregates)
+	if err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
+	SQL := it.getAggregateSQL(groupBy, aggregates)
+
+	rows, err := connectionQueryRead(SQL)
+	defer closeCursor(rows)
+
+	var result []map[string]interface{}
+	if err == nil {
+		for ok := rows.Next(); ok == true; ok = rows.Next() {
+			if row, err := getRowAsStringMap(rows); err == nil {
+				result = append(result, db.ConvertAggregateRow(it, groupBy, aggregates, row))
+			}
+		}
+		err = rows.Err()
+	}
+
+	if err != nil {
+		err = sqlError(SQL, err)
+	}
+
+	return result, env.ErrorDispatch(err)
+}
+
 // Save stores record in DB for current collection
 func (it *DBCollection) Save(item map[string]interface{}) (string, error) {
 
@@ -214,6 +249,14 @@ func (it *DBCollection) Save(item map[string]interface{}) (string, error) {
 		}
 	}
 
+	// optimistic concurrency control, ref. to db.AddVersionColumn
+	isVersioned := it.HasColumn(db.ConstVersionColumn)
+	expectedVersion := 0
+	if isVersioned {
+		expectedVersion = db.GetRecordVersion(item)
+		delete(item, db.ConstVersionColumn)
+	}
+
 	// SQL generation
 	columns := make([]string, 0, len(item))
 	args := make([]string, 0, len(item))
@@ -235,15 +278,48 @@ func (it *DBCollection) Save(item map[string]interface{}) (string, error) {
 		}
 	}
 
+	if isVersioned {
+		columns = append(columns, "`"+db.ConstVersionColumn+"`")
+		args = append(args, "1")
+		columnEqArg = append(columnEqArg, "`"+db.ConstVersionColumn+"`=IFNULL(`"+db.ConstVersionColumn+"`, 0)+1")
+
+		// versioned update should not touch record changed by someone else
+		if expectedVersion != 0 && item["_id"] != nil {
+			SQL := "UPDATE `" + it.Name + "` SET " + strings.Join(columnEqArg, ", ") +
+				" WHERE `_id`=" + convertValueForSQL(item["_id"]) +
+				" AND IFNULL(`" + db.ConstVersionColumn + "`, 0)=" + strconv.Itoa(expectedVersion)
+
+			affected, err := connectionExecWAffected(SQL)
+			if err != nil {
+				return "", sqlError(SQL, err)
+			}
+			if affected > 0 {
+				item["_id"] = utils.InterfaceToString(item["_id"])
+				item[db.ConstVersionColumn] = expectedVersion + 1
+				return item["_id"].(string), nil
+			}
+
+			if _, isStored, err := it.getStoredVersion(item["_id"]); err != nil {
+				return "", env.ErrorDispatch(err)
+			} else if isStored {
+				return "", db.NewVersionConflictError(it.Name, utils.InterfaceToString(item["_id"]))
+			}
+		}
+	}
+
 	SQL := "INSERT INTO `" + it.Name + "`" +
 		" (" + strings.Join(columns, ",") + ") VALUES" +
-		" (" + strings.Join(args, ",") + ")" +
-		" ON DUPLICATE KEY UPDATE " + strings.Join(columnEqArg, ", ")
+		" (" + strings.Join(args, ",") + ")"
+
+	// versioned item which have no stored record should not overwrite concurrently inserted one
+	if expectedVersion == 0 || item["_id"] == nil {
+		SQL += " ON DUPLICATE KEY UPDATE " + strings.Join(columnEqArg, ", ")
+	}
 
 	if !ConstUseUUIDids {
 		newIDInt64, err := connectionExecWLastInsertID(SQL, values...)
 		if err != nil {
-			return "", sqlError(SQL, err)
+			return "", it.saveError(SQL, item, err)
 		}
 
 		// auto-incremented _id back to string
@@ -252,10 +328,18 @@ func (it *DBCollection) Save(item map[string]interface{}) (string, error) {
 	} else {
 		err := connectionExec(SQL, values...)
 		if err != nil {
-			return "", sqlError(SQL, err)
+			return "", it.saveError(SQL, item, err)
 		}
 	}
 
+	if isVersioned {
+		storedVersion, _, err := it.getStoredVersion(item["_id"])
+		if err != nil {
+			return "", env.ErrorDispatch(err)
+		}
+		item[db.ConstVersionColumn] = storedVersion
+	}
+
 	return item["_id"].(string), nil
 }
 
@@ -389,6 +473,32 @@ func (it *DBCollection) SetLimit(Offset int, Limit int) error {
 	return nil
 }
 
+// SetCursor switches collection to keyset pagination, so records following the cursor are selected
+//   - "_id" column is added to sort order to make it unique
+//   - blank cursor means first page
+func (it *DBCollection) SetCursor(cursor string) error {
+	if !utils.IsInListStr("_id", it.getSortColumns()) && !utils.IsInListStr("-_id", it.getSortColumns()) {
+		if err := it.AddSort("_id", false); err != nil {
+			return env.ErrorDispatch(err)
+		}
+	}
+	it.isCursorSet = true
+
+	if cursor == "" {
+		return nil
+	}
+
+	return db.ApplyCursor(it, it.getSortColumns(), cursor)
+}
+
+// GetNextCursor returns cursor pointing to the last loaded record or blank string if there were no records
+func (it *DBCollection) GetNextCursor() string {
+	if !it.isCursorSet {
+		return ""
+	}
+	return db.EncodeCursor(it.getSortColumns(), it.lastRecord)
+}
+
 // ListColumns returns attributes(columns) available for current collection(table)
 func (it *DBCollection) ListColumns() map[string]string {
 
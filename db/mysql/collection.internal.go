@@ -7,6 +7,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/go-sql-driver/mysql"
 	"github.com/ottemo/commerce/db"
 	"github.com/ottemo/commerce/env"
 	"github.com/ottemo/commerce/utils"
@@ -80,10 +81,82 @@ func (it *DBCollection) makeSQLFilterString(ColumnName string, Operator string,
 
 // returns SQL select statement for current collection
 func (it *DBCollection) getSelectSQL() string {
-	SQL := "SELECT " + it.getSQLResultColumns() + " FROM " + it.Name + it.getSQLFilters() + it.getSQLOrder() + it.Limit
+	resultColumns := it.getSQLResultColumns()
+
+	// sort columns are required to make next cursor
+	if it.isCursorSet && len(it.ResultColumns) > 0 {
+		for _, sortColumn := range it.getSortColumns() {
+			sortColumn = strings.TrimPrefix(sortColumn, "-")
+			if !utils.IsInListStr(sortColumn, it.ResultColumns) {
+				resultColumns += ", `" + sortColumn + "`"
+			}
+		}
+	}
+
+	SQL := "SELECT " + resultColumns + " FROM " + it.Name + it.getSQLFilters() + it.getSQLOrder() + it.Limit
+	return SQL
+}
+
+// returns SQL select statement calculating aggregates over current collection
+func (it *DBCollection) getAggregateSQL(groupBy []db.StructAggregateGroup, aggregates []db.StructAggregate) string {
+	var sqlColumns, sqlGroups []string
+
+	for _, group := range groupBy {
+		sqlColumns = append(sqlColumns, it.getSQLGroupExpression(group)+" AS `"+group.Name+"`")
+		sqlGroups = append(sqlGroups, "`"+group.Name+"`")
+	}
+
+	for _, aggregate := range aggregates {
+		expression := "COUNT(*)"
+		if aggregate.Column != "" {
+			expression = strings.ToUpper(aggregate.Function) + "(`" + aggregate.Column + "`)"
+		}
+		sqlColumns = append(sqlColumns, expression+" AS `"+aggregate.Name+"`")
+	}
+
+	SQL := "SELECT " + strings.Join(sqlColumns, ", ") + " FROM `" + it.Name + "`" + it.getSQLFilters()
+	if len(sqlGroups) > 0 {
+		SQL += " GROUP BY " + strings.Join(sqlGroups, ", ") + " ORDER BY " + strings.Join(sqlGroups, ", ")
+	}
+
 	return SQL
 }
 
+// returns SQL expression for aggregation group key, datetime columns are holding unix time
+//   - month and year buckets are calculated without session time zone
+func (it *DBCollection) getSQLGroupExpression(group db.StructAggregateGroup) string {
+	if columnPath := strings.SplitN(group.Column, ".", 2); len(columnPath) > 1 {
+		return "JSON_UNQUOTE(JSON_EXTRACT(`" + columnPath[0] + "`, '$." + columnPath[1] + "'))"
+	}
+
+	column := "`" + group.Column + "`"
+	switch group.Bucket {
+	case db.ConstAggregateBucketHour:
+		return "(" + column + " - " + column + " % 3600)"
+	case db.ConstAggregateBucketDay:
+		return "(" + column + " - " + column + " % 86400)"
+	case db.ConstAggregateBucketMonth:
+		return "TIMESTAMPDIFF(SECOND, '1970-01-01', DATE_FORMAT('1970-01-01' + INTERVAL " + column + " SECOND, '%Y-%m-01'))"
+	case db.ConstAggregateBucketYear:
+		return "TIMESTAMPDIFF(SECOND, '1970-01-01', DATE_FORMAT('1970-01-01' + INTERVAL " + column + " SECOND, '%Y-01-01'))"
+	}
+
+	return column
+}
+
+// returns sort columns in db.EncodeCursor(...) format
+func (it *DBCollection) getSortColumns() []string {
+	result := make([]string, 0, len(it.Order))
+	for _, orderColumn := range it.Order {
+		if strings.HasSuffix(orderColumn, " DESC") {
+			result = append(result, "-"+strings.TrimSuffix(orderColumn, " DESC"))
+		} else {
+			result = append(result, orderColumn)
+		}
+	}
+	return result
+}
+
 // un-serialize object values
 func (it *DBCollection) modifyResultRow(row RowMap) RowMap {
 
@@ -187,6 +260,38 @@ func (it *DBCollection) updateFilterGroup(groupName string, columnName string, o
 	return nil
 }
 
+// getStoredVersion returns version of stored record, isStored is false if there is no record with given id
+func (it *DBCollection) getStoredVersion(id interface{}) (version int, isStored bool, err error) {
+	SQL := "SELECT IFNULL(`" + db.ConstVersionColumn + "`, 0) FROM `" + it.Name + "` WHERE `_id`=" + convertValueForSQL(id)
+
+	rows, err := connectionQuery(SQL)
+	defer closeCursor(rows)
+	if err != nil {
+		return 0, false, sqlError(SQL, err)
+	}
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return 0, false, sqlError(SQL, err)
+		}
+		return 0, false, nil
+	}
+	if err := rows.Scan(&version); err != nil {
+		return 0, false, sqlError(SQL, err)
+	}
+
+	return version, true, nil
+}
+
+// saveError converts error of record insert to returned by Save
+//   - duplicate key error of versioned insert means record was concurrently inserted, so it is a version conflict
+func (it *DBCollection) saveError(SQL string, item map[string]interface{}, err error) error {
+	if mysqlError, ok := err.(*mysql.MySQLError); ok && mysqlError.Number == ConstErrorDuplicateKey && item["_id"] != nil {
+		return db.NewVersionConflictError(it.Name, utils.InterfaceToString(item["_id"]))
+	}
+	return sqlError(SQL, err)
+}
+
 // generates new UUID for _id column
 func (it *DBCollection) makeUUID(id string) string {
 
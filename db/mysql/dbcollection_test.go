@@ -1,11 +1,13 @@
 package mysql
 
 import (
+	"database/sql"
 	"fmt"
 	"io"
 	"testing"
 
 	"github.com/ottemo/commerce/api"
+	"github.com/ottemo/commerce/db"
 
 	"github.com/ottemo/commerce/app/models"
 )
@@ -207,3 +209,98 @@ func TestApplyFilters(t *testing.T) {
 		fmt.Println(SQL)
 	}
 }
+
+func TestAggregateSQL(t *testing.T) {
+	var dbCollection = &DBCollection{
+		Name:         "testOrder",
+		FilterGroups: make(map[string]*StructDBFilterGroup),
+	}
+
+	dbEngine.attributeTypes = map[string]map[string]string{
+		"testOrder": {
+			"_id":             "id",
+			"status":          "varchar(50)",
+			"grand_total":     "money",
+			"billing_address": "json",
+			"created_at":      "datetime",
+		},
+	}
+
+	if err := dbCollection.AddFilter("status", "=", "completed"); err != nil {
+		t.Fatal(err)
+	}
+
+	groupBy, aggregates, err := db.PrepareAggregate(dbCollection,
+		[]db.StructAggregateGroup{
+			{Column: "created_at", Bucket: db.ConstAggregateBucketDay, Name: "day"},
+			{Column: "billing_address.country"},
+		},
+		[]db.StructAggregate{
+			{Function: db.ConstAggregateSum, Column: "grand_total"},
+			{Function: db.ConstAggregateCount, Name: "orders"},
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "SELECT (`created_at` - `created_at` % 86400) AS `day`, " +
+		"JSON_UNQUOTE(JSON_EXTRACT(`billing_address`, '$.country')) AS `billing_address_country`, " +
+		"SUM(`grand_total`) AS `sum_grand_total`, COUNT(*) AS `orders` " +
+		"FROM `testOrder` WHERE (`status` = 'completed') " +
+		"GROUP BY `day`, `billing_address_country` ORDER BY `day`, `billing_address_country`"
+
+	if SQL := dbCollection.getAggregateSQL(groupBy, aggregates); SQL != expected {
+		t.Errorf("unexpected aggregate SQL:\n%s\n%s", SQL, expected)
+	}
+
+	for _, invalidGroup := range []db.StructAggregateGroup{
+		{Column: "status", Bucket: db.ConstAggregateBucketDay},
+		{Column: "status.code"},
+		{Column: "billing_address.country') OR ('"},
+	} {
+		if _, _, err := db.PrepareAggregate(dbCollection, []db.StructAggregateGroup{invalidGroup}, aggregates); err == nil {
+			t.Errorf("group %v should not be accepted", invalidGroup)
+		}
+	}
+}
+
+func TestMakeReplicaDSN(t *testing.T) {
+	for uri, expected := range map[string]string{
+		"root@/":                                    "root@/ottemo",
+		"user:pass@tcp(replica:3306)/":              "user:pass@tcp(replica:3306)/ottemo",
+		"user:pass@tcp(replica:3306)/?loc=UTC":      "user:pass@tcp(replica:3306)/ottemo?loc=UTC",
+		"user:pass@tcp(replica:3306)/other?loc=UTC": "user:pass@tcp(replica:3306)/other?loc=UTC",
+		"user@tcp(replica:3306)/?loc=Europe/Paris":  "user@tcp(replica:3306)/ottemo?loc=Europe/Paris",
+		"user@tcp(replica:3306)":                    "user@tcp(replica:3306)/ottemo",
+	} {
+		if dsn := makeReplicaDSN(uri, "ottemo"); dsn != expected {
+			t.Errorf("unexpected replica DSN for %s: %s, expected %s", uri, dsn, expected)
+		}
+	}
+}
+
+func TestReadExecutor(t *testing.T) {
+	primary, replica := &sql.DB{}, &sql.DB{}
+
+	savedEngine := dbEngine
+	dbEngine = &DBEngine{connection: primary, replicas: []*replicaConnection{{connection: replica, isAvailable: true}}}
+	defer func() { dbEngine = savedEngine }()
+
+	if executor, replicaIdx := getReadExecutor(); executor != replica || replicaIdx != 0 {
+		t.Error("read is not routed to replica")
+	}
+
+	err := db.ReadFromPrimary(func() error {
+		if executor, replicaIdx := getReadExecutor(); executor != primary || replicaIdx != -1 {
+			t.Error("read within ReadFromPrimary is not routed to primary")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Error(err)
+	}
+
+	if executor, _ := getReadExecutor(); executor != replica {
+		t.Error("read after ReadFromPrimary is not routed to replica")
+	}
+}
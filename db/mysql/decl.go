@@ -22,6 +22,10 @@ const (
 
 	ConstCollectionNameColumnInfo = "collection_column_info" // table name to hold Ottemo types of columns
 
+	ConstContextKeyTransaction = "db.mysql.transaction" // call context key to hold started transaction
+
+	ConstErrorDuplicateKey = 1062 // MySQL error number of unique key violation
+
 	ConstErrorModule = "db/mysql"
 	ConstErrorLevel  = env.ConstErrorLevelService
 )
@@ -55,6 +59,9 @@ type DBCollection struct {
 	Order         []string
 
 	Limit string
+
+	isCursorSet bool                   // keyset pagination mode, ref. to SetCursor(...)
+	lastRecord  map[string]interface{} // last iterated record, used to make next cursor
 }
 
 // DBEngine is a InterfaceDBEngine implementer
@@ -64,13 +71,25 @@ type DBEngine struct {
 	attributeTypes      map[string]map[string]string
 	attributeTypesMutex sync.RWMutex
 
+	replicas       []*replicaConnection // read replicas, ref. to InterfaceDBReplicaConnector
+	replicasMutex  sync.RWMutex
+	replicaCounter uint32 // round-robin counter of replica reads
+
 	isConnected bool
 }
 
+// replicaConnection is a read replica connection
+type replicaConnection struct {
+	connection  *sql.DB
+	isAvailable bool
+}
+
 // connectionParamsType describes params required to connect to DB
 type connectionParamsType struct {
 	uri 		string
 	dbName 		string
 	poolConnections int
 	maxConnections 	int
+	connMaxLifetime time.Duration
+	replicas        []string
 }
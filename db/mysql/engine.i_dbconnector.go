@@ -3,6 +3,7 @@ package mysql
 import (
 	"errors"
 	"database/sql"
+	"strings"
 	"time"
 
 	"github.com/ottemo/commerce/env"
@@ -38,6 +39,18 @@ func (it *DBEngine) GetConnectionParams() interface{} {
 		if iniValue := iniConfig.GetValue("db.mysql.poolConnections", ""); iniValue != "" {
 			connectionParams.poolConnections = utils.InterfaceToInt(iniValue)
 		}
+
+		if iniValue := iniConfig.GetValue("db.mysql.connMaxLifetime", ""); iniValue != "" {
+			connectionParams.connMaxLifetime = time.Duration(utils.InterfaceToInt(iniValue)) * time.Second
+		}
+
+		if iniValue := iniConfig.GetValue("db.mysql.replicas", ""); iniValue != "" {
+			for _, replicaURI := range strings.Split(iniValue, ",") {
+				if replicaURI = strings.TrimSpace(replicaURI); replicaURI != "" {
+					connectionParams.replicas = append(connectionParams.replicas, replicaURI)
+				}
+			}
+		}
 	}
 
 	return connectionParams
@@ -56,13 +69,7 @@ func (it *DBEngine) Connect(srcConnectionParams interface{}) error {
 		return err
 	}
 
-	if connectionParams.poolConnections > 0 {
-		it.connection.SetMaxIdleConns(connectionParams.poolConnections)
-	}
-
-	if connectionParams.maxConnections > 0 {
-		it.connection.SetMaxOpenConns(connectionParams.maxConnections)
-	}
+	setupConnectionPool(it.connection, connectionParams)
 
 	// making sure DB selected otherwise trying to obtain DB
 	rows, err := it.connection.Query("SELECT DATABASE()")
@@ -3,6 +3,7 @@ package mysql
 import (
 	"strconv"
 
+	"github.com/ottemo/commerce/api/context"
 	"github.com/ottemo/commerce/db"
 	"github.com/ottemo/commerce/env"
 )
@@ -100,3 +101,43 @@ func (it *DBEngine) RawQuery(query string) (map[string]interface{}, error) {
 
 	return result[0], nil
 }
+
+// BeginTransaction starts a transaction bound to current call context
+func (it *DBEngine) BeginTransaction() error {
+	if context.GetContext() == nil {
+		return env.ErrorNew(ConstErrorModule, ConstErrorLevel, "d93abdd3-4242-45de-bfe2-69a6e03a4be1", "transaction requires call context")
+	}
+	if getTransaction() != nil {
+		return env.ErrorNew(ConstErrorModule, ConstErrorLevel, "baf8adea-2a6f-485c-b3c8-1bd6d7ee70be", "transaction already started")
+	}
+
+	transaction, err := it.connection.Begin()
+	if err != nil {
+		return env.ErrorDispatch(err)
+	}
+	context.SetContextValue(ConstContextKeyTransaction, transaction)
+
+	return nil
+}
+
+// CommitTransaction commits transaction started within current call context
+func (it *DBEngine) CommitTransaction() error {
+	transaction := getTransaction()
+	if transaction == nil {
+		return env.ErrorNew(ConstErrorModule, ConstErrorLevel, "ca38328a-dad0-40ac-b443-a6d66ed9b8e4", "there is no started transaction")
+	}
+	context.SetContextValue(ConstContextKeyTransaction, nil)
+
+	return env.ErrorDispatch(transaction.Commit())
+}
+
+// RollbackTransaction rolls back transaction started within current call context
+func (it *DBEngine) RollbackTransaction() error {
+	transaction := getTransaction()
+	if transaction == nil {
+		return env.ErrorNew(ConstErrorModule, ConstErrorLevel, "b406ddde-985d-409b-a35c-362f659e6ab1", "there is no started transaction")
+	}
+	context.SetContextValue(ConstContextKeyTransaction, nil)
+
+	return env.ErrorDispatch(transaction.Rollback())
+}
@@ -11,6 +11,7 @@ func init() {
 	dbEngine.attributeTypes = make(map[string]map[string]string)
 
 	var _ db.InterfaceDBEngine = dbEngine
+	var _ db.InterfaceDBReplicaConnector = dbEngine
 
 	var dbConnector = db.NewDBConnector(dbEngine)
 	env.RegisterOnConfigIniStart(dbConnector.ConnectAsync)
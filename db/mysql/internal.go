@@ -6,16 +6,72 @@ import (
 	"database/sql"
 
 	"fmt"
+	"github.com/ottemo/commerce/api/context"
 	"github.com/ottemo/commerce/db"
 	"github.com/ottemo/commerce/env"
 	"github.com/ottemo/commerce/utils"
 	"time"
 )
 
+// sqlExecutor is a common interface for sql.DB and sql.Tx statements execution
+type sqlExecutor interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// getTransaction returns transaction started within current call context or nil
+func getTransaction() *sql.Tx {
+	if transaction, ok := context.GetContextValue(ConstContextKeyTransaction).(*sql.Tx); ok {
+		return transaction
+	}
+	return nil
+}
+
+// getExecutor returns current call context transaction if it was started or connection otherwise
+func getExecutor() sqlExecutor {
+	if transaction := getTransaction(); transaction != nil {
+		return transaction
+	}
+	return dbEngine.connection
+}
+
+// getReadExecutor returns executor for read statements, read replica is used if it is available and there is no
+// transaction started within current call context
+//   - primary connection is used for reads within db.ReadFromPrimary(...)
+//   - returns index of used replica or -1 if it is not a replica
+func getReadExecutor() (sqlExecutor, int) {
+	if transaction := getTransaction(); transaction != nil {
+		return transaction, -1
+	}
+	if db.IsReadingFromPrimary() {
+		return dbEngine.connection, -1
+	}
+	if replicaIdx, replica := dbEngine.getReplica(); replica != nil {
+		return replica, replicaIdx
+	}
+	return dbEngine.connection, -1
+}
+
+// setupConnectionPool applies pool settings to connection
+func setupConnectionPool(connection *sql.DB, connectionParams connectionParamsType) {
+	if connectionParams.poolConnections > 0 {
+		connection.SetMaxIdleConns(connectionParams.poolConnections)
+	}
+
+	if connectionParams.maxConnections > 0 {
+		connection.SetMaxOpenConns(connectionParams.maxConnections)
+	}
+
+	if connectionParams.connMaxLifetime > 0 {
+		connection.SetConnMaxLifetime(connectionParams.connMaxLifetime)
+	}
+}
+
 // exec routines
 func connectionExecWLastInsertID(SQL string, args ...interface{}) (int64, error) {
+	defer db.ObserveQuery(dbEngine.GetName(), db.GetQueryOperation(SQL), time.Now())
 
-	result, err := dbEngine.connection.Exec(SQL, args...)
+	result, err := getExecutor().Exec(SQL, args...)
 	if err != nil {
 		return -1, err
 	}
@@ -25,12 +81,13 @@ func connectionExecWLastInsertID(SQL string, args ...interface{}) (int64, error)
 
 // exec routines
 func connectionExecWAffected(SQL string, args ...interface{}) (int64, error) {
+	defer db.ObserveQuery(dbEngine.GetName(), db.GetQueryOperation(SQL), time.Now())
 
 	if ConstDebugSQL {
 		env.Log(ConstDebugFile, env.ConstLogPrefixInfo, SQL)
 	}
 
-	result, err := dbEngine.connection.Exec(SQL, args...)
+	result, err := getExecutor().Exec(SQL, args...)
 	if err != nil {
 		return 0, err
 	}
@@ -40,23 +97,48 @@ func connectionExecWAffected(SQL string, args ...interface{}) (int64, error) {
 
 // exec routines
 func connectionExec(SQL string, args ...interface{}) error {
+	defer db.ObserveQuery(dbEngine.GetName(), db.GetQueryOperation(SQL), time.Now())
 
 	if ConstDebugSQL {
 		env.Log(ConstDebugFile, env.ConstLogPrefixInfo, SQL)
 	}
 
-	_, err := dbEngine.connection.Exec(SQL, args...)
+	_, err := getExecutor().Exec(SQL, args...)
 
 	return err
 }
 
 // query routines
 func connectionQuery(SQL string) (*sql.Rows, error) {
+	defer db.ObserveQuery(dbEngine.GetName(), db.GetQueryOperation(SQL), time.Now())
+
+	if ConstDebugSQL {
+		env.Log(ConstDebugFile, env.ConstLogPrefixInfo, SQL)
+	}
+
+	return getExecutor().Query(SQL)
+}
+
+// query routines for reads which could be made on replica
+//   - replica failed to respond is excluded from reads and query is repeated on primary
+func connectionQueryRead(SQL string) (*sql.Rows, error) {
+	defer db.ObserveQuery(dbEngine.GetName(), db.GetQueryOperation(SQL), time.Now())
+
 	if ConstDebugSQL {
 		env.Log(ConstDebugFile, env.ConstLogPrefixInfo, SQL)
 	}
 
-	return dbEngine.connection.Query(SQL)
+	executor, replicaIdx := getReadExecutor()
+
+	rows, err := executor.Query(SQL)
+	if err != nil && replicaIdx != -1 && dbEngine.PingReplica(replicaIdx) != nil {
+		dbEngine.SetReplicaAvailable(replicaIdx, false)
+		dbEngine.LogConnection("DB replica " + utils.InterfaceToString(replicaIdx) + " failed, query is made on primary: " + err.Error())
+
+		return dbEngine.connection.Query(SQL)
+	}
+
+	return rows, err
 }
 
 // closeCursor closes cursor statement routine
@@ -0,0 +1,618 @@
+package postgres
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/ottemo/commerce/db"
+	"github.com/ottemo/commerce/env"
+	"github.com/ottemo/commerce/utils"
+)
+
+// LoadByID loads record from DB by it's id
+func (it *DBCollection) LoadByID(id string) (map[string]interface{}, error) {
+	var result map[string]interface{}
+
+	if err := it.AddFilter("_id", "=", id); err != nil {
+		return result, env.ErrorDispatch(err)
+	}
+
+	err := it.Iterate(func(row map[string]interface{}) bool {
+		result = row
+		return false
+	})
+
+	if len(result) == 0 {
+		err = env.ErrorNew(ConstErrorModule, ConstErrorLevel, "19fbfd4d-5052-4aa7-a5ab-efc9f76d36cf", "not found")
+	}
+	return result, err
+}
+
+// Load loads records from DB for current collection and filter if it set
+func (it *DBCollection) Load() ([]map[string]interface{}, error) {
+	var result []map[string]interface{}
+
+	err := it.Iterate(func(row map[string]interface{}) bool {
+		result = append(result, row)
+		return true
+	})
+
+	return result, env.ErrorDispatch(err)
+}
+
+// Iterate applies [iterator] function to each record, stops on return false
+func (it *DBCollection) Iterate(iteratorFunc func(record map[string]interface{}) bool) error {
+
+	SQL := it.getSelectSQL()
+	it.lastRecord = nil
+
+	rows, err := connectionQueryRead(SQL)
+	defer closeCursor(rows)
+
+	if err == nil {
+		for rows.Next() {
+			row, err := getRowAsStringMap(rows)
+			if err != nil {
+				return env.ErrorDispatch(err)
+			}
+
+			it.modifyResultRow(row)
+
+			if it.isCursorSet {
+				it.lastRecord = row
+			}
+
+			if !iteratorFunc(row) {
+				break
+			}
+		}
+		err = rows.Err()
+	}
+
+	if err != nil {
+		return sqlError(SQL, err)
+	}
+
+	return nil
+}
+
+// Distinct returns distinct values of specified attribute
+//   - only the attribute sort order is applied as postgres requires sort expressions to be selected
+func (it *DBCollection) Distinct(columnName string) ([]interface{}, error) {
+	if !it.HasColumn(columnName) {
+		return nil, env.ErrorNew(ConstErrorModule, ConstErrorLevel, "b636d89c-bdc8-41fb-986c-a4a09fd7cf0d", "there is no column "+columnName+" found")
+	}
+
+	var order []string
+	for _, sortColumn := range it.Order {
+		if strings.TrimPrefix(sortColumn, "-") == columnName {
+			order = append(order, sortColumn)
+		}
+	}
+
+	SQL := "SELECT DISTINCT " + quoteName(columnName) + " FROM " + quoteName(it.Name) + it.getSQLFilters() + it.getSQLOrder(order) + it.Limit
+
+	rows, err := connectionQueryRead(SQL)
+	defer closeCursor(rows)
+
+	var result []interface{}
+	if err == nil {
+		for rows.Next() {
+			row, err := getRowAsStringMap(rows)
+			if err != nil {
+				return result, env.ErrorDispatch(err)
+			}
+
+			if row[columnName] == nil {
+				continue
+			}
+
+			it.modifyResultRow(row)
+
+			// if value is array then we need to make distinct within array by self
+			if arrayValue, ok := row[columnName].([]interface{}); ok {
+				for _, arrayItem := range arrayValue {
+					isAlreadyInResult := false
+					// looking for array item value in result array
+					for _, resultItem := range result {
+						if arrayItem == resultItem {
+							isAlreadyInResult = true
+							break
+						}
+					}
+					if !isAlreadyInResult {
+						result = append(result, arrayItem)
+					}
+				}
+			} else {
+				// if value is not array then SQL did distinct work for us
+				result = append(result, row[columnName])
+			}
+		}
+		err = rows.Err()
+	}
+
+	if err != nil {
+		err = sqlError(SQL, err)
+	}
+
+	return result, env.ErrorDispatch(err)
+}
+
+// Count returns count of rows matching current select statement
+func (it *DBCollection) Count() (int, error) {
+	SQL := "SELECT COUNT(*) AS cnt FROM " + quoteName(it.Name) + it.getSQLFilters()
+
+	rows, err := connectionQueryRead(SQL)
+	defer closeCursor(rows)
+
+	if err != nil {
+		return 0, sqlError(SQL, err)
+	}
+
+	if !rows.Next() {
+		return 0, env.ErrorDispatch(rows.Err())
+	}
+
+	row, err := getRowAsStringMap(rows)
+	if err != nil {
+		return 0, env.ErrorDispatch(err)
+	}
+
+	return utils.InterfaceToInt(row["cnt"]), nil
+}
+
+// Aggregate calculates aggregate functions over records matching current select statement grouped by given keys
+//   - result is ordered by group keys, sort and limit of collection are not applied
+func (it *DBCollection) Aggregate(groupBy []db.StructAggregateGroup, aggregates []db.StructAggregate) ([]map[string]interface{}, error) {
+	groupBy, aggregates, err := db.PrepareAggregate(it, groupBy, aggregates)
+	if err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
+	SQL := it.getAggregateSQL(groupBy, aggregates)
+
+	rows, err := connectionQueryRead(SQL)
+	defer closeCursor(rows)
+
+	var result []map[string]interface{}
+	if err == nil {
+		for rows.Next() {
+			row, err := getRowAsStringMap(rows)
+			if err != nil {
+				return result, env.ErrorDispatch(err)
+			}
+			result = append(result, db.ConvertAggregateRow(it, groupBy, aggregates, row))
+		}
+		err = rows.Err()
+	}
+
+	if err != nil {
+		err = sqlError(SQL, err)
+	}
+
+	return result, env.ErrorDispatch(err)
+}
+
+// Save stores record in DB for current collection
+//   - record with existing "_id" is updated with not nil values, otherwise new record inserted
+func (it *DBCollection) Save(item map[string]interface{}) (string, error) {
+
+	// prevents saving of blank records
+	if len(item) == 0 {
+		return "", nil
+	}
+
+	// we should make new _id column if it was not set
+	if idValue, present := item["_id"]; present && idValue != nil {
+		item["_id"] = it.makeUUID(utils.InterfaceToString(idValue))
+	} else {
+		item["_id"] = it.makeUUID("")
+	}
+
+	// optimistic concurrency control, ref. to db.AddVersionColumn
+	if !it.HasColumn(db.ConstVersionColumn) {
+		SQL := it.getSaveSQL(item)
+
+		if err := connectionExec(SQL); err != nil {
+			return "", sqlError(SQL, err)
+		}
+
+		return item["_id"].(string), nil
+	}
+
+	expectedVersion := db.GetRecordVersion(item)
+	delete(item, db.ConstVersionColumn)
+
+	SQL := it.getVersionedSaveSQL(item, expectedVersion)
+
+	rows, err := connectionQuery(SQL)
+	defer closeCursor(rows)
+	if err != nil {
+		return "", sqlError(SQL, err)
+	}
+
+	// conflicting update have no returning row
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return "", sqlError(SQL, err)
+		}
+		return "", db.NewVersionConflictError(it.Name, item["_id"].(string))
+	}
+
+	var storedVersion int
+	if err := rows.Scan(&storedVersion); err != nil {
+		return "", sqlError(SQL, err)
+	}
+	item[db.ConstVersionColumn] = storedVersion
+
+	return item["_id"].(string), nil
+}
+
+// getVersionedSaveSQL returns upsert SQL statement for the item which increments record version
+//   - update is skipped when expectedVersion is not 0 and differs from stored one
+//   - statement returns new version of record
+func (it *DBCollection) getVersionedSaveSQL(item map[string]interface{}, expectedVersion int) string {
+	table := quoteName(it.Name)
+	column := quoteName(db.ConstVersionColumn)
+
+	versionedItem := make(map[string]interface{}, len(item)+1)
+	for key, value := range item {
+		versionedItem[key] = value
+	}
+	versionedItem[db.ConstVersionColumn] = 1
+
+	SQL := it.getSaveSQL(versionedItem)
+	SQL = strings.Replace(SQL, column+" = EXCLUDED."+column, column+" = COALESCE("+table+"."+column+", 0) + 1", 1)
+
+	if expectedVersion != 0 {
+		SQL += " WHERE COALESCE(" + table + "." + column + ", 0) = " + utils.InterfaceToString(expectedVersion)
+	}
+
+	return SQL + " RETURNING " + column
+}
+
+// getSaveSQL returns upsert SQL statement for the item, nil values are skipped
+func (it *DBCollection) getSaveSQL(item map[string]interface{}) string {
+	keys := make([]string, 0, len(item))
+	for key, value := range item {
+		if value != nil {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	columns := make([]string, 0, len(keys))
+	args := make([]string, 0, len(keys))
+	columnEqArg := make([]string, 0, len(keys))
+
+	for _, key := range keys {
+		column := quoteName(key)
+
+		columns = append(columns, column)
+		args = append(args, convertValueForSQL(item[key], it.GetColumnType(key)))
+
+		if key != "_id" {
+			columnEqArg = append(columnEqArg, column+" = EXCLUDED."+column)
+		}
+	}
+
+	SQL := "INSERT INTO " + quoteName(it.Name) +
+		" (" + strings.Join(columns, ", ") + ") VALUES" +
+		" (" + strings.Join(args, ", ") + ")"
+
+	if len(columnEqArg) > 0 {
+		SQL += " ON CONFLICT (\"_id\") DO UPDATE SET " + strings.Join(columnEqArg, ", ")
+	} else {
+		SQL += " ON CONFLICT (\"_id\") DO NOTHING"
+	}
+
+	return SQL
+}
+
+// Delete removes records that matches current select statement from DB
+//   - returns amount of affected rows
+func (it *DBCollection) Delete() (int, error) {
+	SQL := "DELETE FROM " + quoteName(it.Name) + it.getSQLFilters()
+
+	affected, err := connectionExecWAffected(SQL)
+	if err != nil {
+		return 0, sqlError(SQL, err)
+	}
+
+	return int(affected), nil
+}
+
+// DeleteByID removes record from DB by is's id
+func (it *DBCollection) DeleteByID(id string) error {
+	SQL := "DELETE FROM " + quoteName(it.Name) + " WHERE \"_id\" = " + quoteString(id)
+
+	if err := connectionExec(SQL); err != nil {
+		return sqlError(SQL, err)
+	}
+
+	return nil
+}
+
+// SetupFilterGroup setups filter group params for collection
+func (it *DBCollection) SetupFilterGroup(groupName string, orSequence bool, parentGroup string) error {
+	if _, present := it.FilterGroups[parentGroup]; !present && parentGroup != "" {
+		if parentGroup == ConstFilterGroupDefault {
+			// create default group if not present and required as parent
+			it.getFilterGroup(ConstFilterGroupDefault)
+		} else {
+			return env.ErrorNew(ConstErrorModule, ConstErrorLevel, "c7406fe3-497f-4927-92ff-8195273ee135", "invalid parent group")
+		}
+	}
+
+	filterGroup := it.getFilterGroup(groupName)
+	filterGroup.OrSequence = orSequence
+	filterGroup.ParentGroup = parentGroup
+
+	return nil
+}
+
+// RemoveFilterGroup removes filter group for collection
+func (it *DBCollection) RemoveFilterGroup(groupName string) error {
+	if _, present := it.FilterGroups[groupName]; !present {
+		return env.ErrorNew(ConstErrorModule, ConstErrorLevel, "10951fa1-e7f3-4dc6-b691-b63665eb7fc0", "invalid group name")
+	}
+
+	delete(it.FilterGroups, groupName)
+	return nil
+}
+
+// AddGroupFilter adds selection filter to specific filter group (all filter groups will be joined before db query)
+func (it *DBCollection) AddGroupFilter(groupName string, columnName string, operator string, value interface{}) error {
+	return it.updateFilterGroup(groupName, columnName, operator, value)
+}
+
+// AddStaticFilter adds selection filter that will not be cleared by ClearFilters() function
+func (it *DBCollection) AddStaticFilter(columnName string, operator string, value interface{}) error {
+	return it.updateFilterGroup(ConstFilterGroupStatic, columnName, operator, value)
+}
+
+// AddFilter adds selection filter to current collection(table) object
+func (it *DBCollection) AddFilter(columnName string, operator string, value interface{}) error {
+	return it.updateFilterGroup(ConstFilterGroupDefault, columnName, operator, value)
+}
+
+// ClearFilters removes all filters that were set for current collection, except static
+func (it *DBCollection) ClearFilters() error {
+	for filterGroup := range it.FilterGroups {
+		if filterGroup != ConstFilterGroupStatic {
+			delete(it.FilterGroups, filterGroup)
+		}
+	}
+
+	return nil
+}
+
+// AddSort adds sorting for current collection
+func (it *DBCollection) AddSort(columnName string, desc bool) error {
+	if !it.HasColumn(columnName) {
+		return env.ErrorNew(ConstErrorModule, ConstErrorLevel, "27cc4413-0611-4375-ab41-574091524eff", "can't find column '"+columnName+"'")
+	}
+
+	if desc {
+		it.Order = append(it.Order, "-"+columnName)
+	} else {
+		it.Order = append(it.Order, columnName)
+	}
+
+	return nil
+}
+
+// ClearSort removes any sorting that was set for current collection
+func (it *DBCollection) ClearSort() error {
+	it.Order = make([]string, 0)
+	return nil
+}
+
+// SetResultColumns limits column selection for Load() and LoadByID()function
+func (it *DBCollection) SetResultColumns(columns ...string) error {
+	for _, columnName := range columns {
+		if !it.HasColumn(columnName) {
+			return env.ErrorNew(ConstErrorModule, ConstErrorLevel, "7d2c8e89-735f-469b-86af-2fbf19f783d6", "there is no column "+columnName+" found")
+		}
+
+		it.ResultColumns = append(it.ResultColumns, columnName)
+	}
+
+	return nil
+}
+
+// SetLimit results pagination
+func (it *DBCollection) SetLimit(offset int, limit int) error {
+	if limit == 0 {
+		it.Limit = ""
+	} else {
+		it.Limit = " LIMIT " + utils.InterfaceToString(limit) + " OFFSET " + utils.InterfaceToString(offset)
+	}
+
+	return nil
+}
+
+// SetCursor switches collection to keyset pagination, so records following the cursor are selected
+//   - "_id" column is added to sort order to make it unique
+//   - blank cursor means first page
+func (it *DBCollection) SetCursor(cursor string) error {
+	if !utils.IsInListStr("_id", it.getSortColumns()) && !utils.IsInListStr("-_id", it.getSortColumns()) {
+		if err := it.AddSort("_id", false); err != nil {
+			return env.ErrorDispatch(err)
+		}
+	}
+	it.isCursorSet = true
+
+	if cursor == "" {
+		return nil
+	}
+
+	return db.ApplyCursor(it, it.getSortColumns(), cursor)
+}
+
+// GetNextCursor returns cursor pointing to the last loaded record or blank string if there were no records
+func (it *DBCollection) GetNextCursor() string {
+	if !it.isCursorSet {
+		return ""
+	}
+	return db.EncodeCursor(it.getSortColumns(), it.lastRecord)
+}
+
+// ListColumns returns attributes(columns) available for current collection(table)
+func (it *DBCollection) ListColumns() map[string]string {
+
+	result := map[string]string{"_id": db.ConstTypeID}
+
+	// updating column into collection
+	SQL := "SELECT \"column\", \"type\" FROM " + quoteName(ConstCollectionNameColumnInfo) + " WHERE \"collection\" = " + quoteString(it.Name)
+	rows, err := connectionQuery(SQL)
+	defer closeCursor(rows)
+
+	if err != nil {
+		_ = sqlError(SQL, err)
+	} else {
+		for rows.Next() {
+			row, err := getRowAsStringMap(rows)
+			if err != nil {
+				_ = env.ErrorDispatch(err)
+				continue
+			}
+
+			result[utils.InterfaceToString(row["column"])] = utils.InterfaceToString(row["type"])
+		}
+	}
+
+	// updating cached attribute types information
+	dbEngine.attributeTypesMutex.Lock()
+	if _, present := dbEngine.attributeTypes[it.Name]; !present {
+		dbEngine.attributeTypes[it.Name] = make(map[string]string)
+	}
+	for attributeName, attributeType := range result {
+		dbEngine.attributeTypes[it.Name][attributeName] = attributeType
+	}
+	dbEngine.attributeTypesMutex.Unlock()
+
+	return result
+}
+
+// GetColumnType returns SQL like type of attribute in current collection, or if not present ""
+func (it *DBCollection) GetColumnType(columnName string) string {
+	if columnName == "_id" {
+		return db.ConstTypeID
+	}
+
+	// looking in cache first
+	attributeType, present := it.getCachedColumnType(columnName)
+	if !present {
+		// updating cache, and looking again
+		it.ListColumns()
+		attributeType, _ = it.getCachedColumnType(columnName)
+	}
+
+	return attributeType
+}
+
+// HasColumn checks attribute(column) presence in current collection
+func (it *DBCollection) HasColumn(columnName string) bool {
+	// looking in cache first
+	_, present := it.getCachedColumnType(columnName)
+	if !present {
+		// updating cache, and looking again
+		it.ListColumns()
+		_, present = it.getCachedColumnType(columnName)
+	}
+
+	return present
+}
+
+// getCachedColumnType returns column type from cached attribute types information
+func (it *DBCollection) getCachedColumnType(columnName string) (string, bool) {
+	dbEngine.attributeTypesMutex.RLock()
+	defer dbEngine.attributeTypesMutex.RUnlock()
+
+	attributeType, present := dbEngine.attributeTypes[it.Name][columnName]
+	return attributeType, present
+}
+
+// AddColumn adds new attribute(column) to current collection(table)
+func (it *DBCollection) AddColumn(columnName string, columnType string, indexed bool) error {
+
+	// checking column name
+	if !ConstSQLNameValidator.MatchString(columnName) {
+		return env.ErrorNew(ConstErrorModule, ConstErrorLevel, "8beadf84-619f-4663-8620-d68594a400b7", "not valid column name for DB engine: "+columnName)
+	}
+
+	// checking if column already present
+	if it.HasColumn(columnName) {
+		if currentType := it.GetColumnType(columnName); currentType != columnType {
+			return env.ErrorNew(ConstErrorModule, ConstErrorLevel, "ac972a92-780e-486b-a048-94708f3aebed", "column '"+columnName+"' already exists with type '"+currentType+"' for '"+it.Name+"' collection. Requested type '"+columnType+"'")
+		}
+		return nil
+	}
+
+	ColumnType, err := GetDBType(columnType)
+	if err != nil {
+		return env.ErrorDispatch(err)
+	}
+
+	// updating collection info table
+	//--------------------------------
+	SQL := "INSERT INTO " + quoteName(ConstCollectionNameColumnInfo) + " (\"collection\", \"column\", \"type\", \"indexed\") VALUES (" +
+		quoteString(it.Name) + ", " +
+		quoteString(columnName) + ", " +
+		quoteString(columnType) + ", " +
+		convertValueForSQL(indexed, "") + ")"
+
+	if err := connectionExec(SQL); err != nil {
+		return sqlError(SQL, err)
+	}
+
+	// updating physical table
+	//-------------------------
+	SQL = "ALTER TABLE " + quoteName(it.Name) + " ADD COLUMN " + quoteName(columnName) + " " + ColumnType
+
+	if err := connectionExec(SQL); err != nil {
+		return sqlError(SQL, err)
+	}
+
+	// updating collection columns list
+	it.ListColumns()
+
+	return nil
+}
+
+// RemoveColumn removes attribute(column) to current collection(table)
+func (it *DBCollection) RemoveColumn(columnName string) error {
+
+	// checking column in table
+	//-------------------------
+	if columnName == "_id" {
+		return env.ErrorNew(ConstErrorModule, ConstErrorLevel, "d5e51062-ff39-45ea-81dd-5ada19d46ed0", "you can't remove _id column")
+	}
+
+	if !it.HasColumn(columnName) {
+		return env.ErrorNew(ConstErrorModule, ConstErrorLevel, "283177b9-3db9-4440-8698-8a298b0af41b", "column '"+columnName+"' not exists in '"+it.Name+"' collection")
+	}
+
+	SQL := "DELETE FROM " + quoteName(ConstCollectionNameColumnInfo) + " WHERE \"collection\" = " + quoteString(it.Name) + " AND \"column\" = " + quoteString(columnName)
+	if err := connectionExec(SQL); err != nil {
+		return sqlError(SQL, err)
+	}
+
+	// updating physical table
+	//-------------------------
+	SQL = "ALTER TABLE " + quoteName(it.Name) + " DROP COLUMN " + quoteName(columnName)
+
+	if err := connectionExec(SQL); err != nil {
+		return sqlError(SQL, err)
+	}
+
+	dbEngine.attributeTypesMutex.Lock()
+	delete(dbEngine.attributeTypes[it.Name], columnName)
+	dbEngine.attributeTypesMutex.Unlock()
+
+	it.ListColumns()
+
+	return nil
+}
@@ -0,0 +1,271 @@
+package postgres
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ottemo/commerce/db"
+	"github.com/ottemo/commerce/env"
+	"github.com/ottemo/commerce/utils"
+)
+
+// makes SQL filter string based on ColumnName, Operator and Value parameters or returns nil
+//   - internal usage function for AddFilter and AddStaticFilter routines
+//   - LIKE is case insensitive as it is for other SQL engines
+func (it *DBCollection) makeSQLFilterString(ColumnName string, Operator string, Value interface{}) (string, error) {
+	if !it.HasColumn(ColumnName) {
+		return "", env.ErrorNew(ConstErrorModule, ConstErrorLevel, "70548395-d193-4b17-ae75-ee1278b5f0c9", "can't find column '"+ColumnName+"'")
+	}
+
+	Operator = strings.ToUpper(Operator)
+	allowedOperators := []string{"=", "!=", "<>", ">", ">=", "<", "<=", "LIKE", "IN"}
+
+	if !utils.IsInListStr(Operator, allowedOperators) {
+		return "", env.ErrorNew(ConstErrorModule, ConstErrorLevel, "17e48427-710c-47f1-b9e8-be6dc1e3da6b", "unknown operator '"+Operator+"' for column '"+ColumnName+"', allowed: '"+strings.Join(allowedOperators, "', ")+"'")
+	}
+
+	columnType := it.GetColumnType(ColumnName)
+	column := quoteName(ColumnName)
+
+	// array column - special case
+	if db.TypeIsArray(columnType) {
+		var resultItems []string
+		for _, arrayItem := range strings.Split(convertArrayToString(Value), ", ") {
+			resultItems = append(resultItems, "(', ' || "+column+" || ',') ILIKE "+quoteString("%, "+arrayItem+",%"))
+		}
+
+		if len(resultItems) == 1 {
+			return resultItems[0], nil
+		}
+		return "(" + strings.Join(resultItems, " OR ") + ")", nil
+	}
+
+	// regular columns - default case
+	switch Operator {
+	case "LIKE":
+		pattern := ""
+		if typedValue, ok := Value.(string); ok {
+			if !strings.Contains(typedValue, "%") {
+				pattern = "%" + typedValue + "%"
+			} else {
+				pattern = strings.Trim(strings.Trim(typedValue, "'"), "\"")
+			}
+		}
+		return column + "::text ILIKE " + quoteString(pattern), nil
+
+	case "IN":
+		if typedValue, ok := Value.(*DBCollection); ok {
+			return column + " IN (" + typedValue.getSelectSQL() + ")", nil
+		}
+
+		var items []string
+		for _, arrayItem := range utils.InterfaceToArray(Value) {
+			items = append(items, convertValueForSQL(arrayItem, columnType))
+		}
+		if len(items) == 0 {
+			items = append(items, "NULL")
+		}
+		return column + " IN (" + strings.Join(items, ", ") + ")", nil
+	}
+
+	return column + " " + Operator + " " + convertValueForSQL(Value, columnType), nil
+}
+
+// returns SQL select statement for current collection
+func (it *DBCollection) getSelectSQL() string {
+	resultColumns := it.getSQLResultColumns()
+
+	// sort columns are required to make next cursor
+	if it.isCursorSet && len(it.ResultColumns) > 0 {
+		for _, sortColumn := range it.getSortColumns() {
+			sortColumn = strings.TrimPrefix(sortColumn, "-")
+			if !utils.IsInListStr(sortColumn, it.ResultColumns) {
+				resultColumns += ", " + quoteName(sortColumn)
+			}
+		}
+	}
+
+	SQL := "SELECT " + resultColumns + " FROM " + quoteName(it.Name) + it.getSQLFilters() + it.getSQLOrder(it.Order) + it.Limit
+	return SQL
+}
+
+// returns SQL select statement calculating aggregates over current collection
+//   - groups are referenced by position as group name could be the same as column name
+func (it *DBCollection) getAggregateSQL(groupBy []db.StructAggregateGroup, aggregates []db.StructAggregate) string {
+	var sqlColumns, sqlGroups []string
+
+	for idx, group := range groupBy {
+		sqlColumns = append(sqlColumns, it.getSQLGroupExpression(group)+" AS "+quoteName(group.Name))
+		sqlGroups = append(sqlGroups, strconv.Itoa(idx+1))
+	}
+
+	for _, aggregate := range aggregates {
+		expression := "COUNT(*)"
+		if aggregate.Column != "" {
+			expression = strings.ToUpper(aggregate.Function) + "(" + quoteName(aggregate.Column) + ")"
+		}
+		sqlColumns = append(sqlColumns, expression+" AS "+quoteName(aggregate.Name))
+	}
+
+	SQL := "SELECT " + strings.Join(sqlColumns, ", ") + " FROM " + quoteName(it.Name) + it.getSQLFilters()
+	if len(sqlGroups) > 0 {
+		SQL += " GROUP BY " + strings.Join(sqlGroups, ", ") + " ORDER BY " + strings.Join(sqlGroups, ", ")
+	}
+
+	return SQL
+}
+
+// returns SQL expression for aggregation group key, date buckets are truncated in UTC
+func (it *DBCollection) getSQLGroupExpression(group db.StructAggregateGroup) string {
+	if columnPath := strings.SplitN(group.Column, ".", 2); len(columnPath) > 1 {
+		return quoteName(columnPath[0]) + " #>> '{" + strings.Replace(columnPath[1], ".", ",", -1) + "}'"
+	}
+
+	column := quoteName(group.Column)
+	if group.Bucket != "" {
+		return "(date_trunc('" + group.Bucket + "', " + column + " AT TIME ZONE 'UTC') AT TIME ZONE 'UTC')"
+	}
+
+	return column
+}
+
+// returns sort columns in db.EncodeCursor(...) format
+func (it *DBCollection) getSortColumns() []string {
+	return append([]string{}, it.Order...)
+}
+
+// un-serialize object values
+func (it *DBCollection) modifyResultRow(row RowMap) RowMap {
+
+	dbEngine.attributeTypesMutex.RLock()
+	attributeTypes := dbEngine.attributeTypes[it.Name]
+	dbEngine.attributeTypesMutex.RUnlock()
+
+	for columnName, columnValue := range row {
+		columnType, present := attributeTypes[columnName]
+		if !present {
+			columnType = ""
+		}
+
+		if columnName != "_id" && columnType != "" {
+			row[columnName] = db.ConvertTypeFromDbToGo(columnValue, columnType)
+		}
+	}
+
+	if _, present := row["_id"]; present {
+		row["_id"] = utils.InterfaceToString(row["_id"])
+	}
+
+	return row
+}
+
+// joins result columns in string
+func (it *DBCollection) getSQLResultColumns() string {
+	if len(it.ResultColumns) == 0 {
+		return "*"
+	}
+
+	sqlColumns := make([]string, 0, len(it.ResultColumns))
+	for _, columnName := range it.ResultColumns {
+		sqlColumns = append(sqlColumns, quoteName(columnName))
+	}
+
+	return strings.Join(sqlColumns, ", ")
+}
+
+// joins order columns in one string with preceding keyword, nulls are going first as for other SQL engines
+func (it *DBCollection) getSQLOrder(order []string) string {
+	sqlOrder := make([]string, 0, len(order))
+	for _, orderColumn := range order {
+		if strings.HasPrefix(orderColumn, "-") {
+			sqlOrder = append(sqlOrder, quoteName(strings.TrimPrefix(orderColumn, "-"))+" DESC NULLS LAST")
+		} else {
+			sqlOrder = append(sqlOrder, quoteName(orderColumn)+" NULLS FIRST")
+		}
+	}
+
+	if len(sqlOrder) == 0 {
+		return ""
+	}
+	return " ORDER BY " + strings.Join(sqlOrder, ", ")
+}
+
+// collects all filters in a single string (for internal usage)
+func (it *DBCollection) getSQLFilters() string {
+
+	var collectSubfilters func(string) []string
+
+	collectSubfilters = func(parentGroupName string) []string {
+		var result []string
+
+		for filterGroupName, filterGroup := range it.FilterGroups {
+			if filterGroup.ParentGroup == parentGroupName && filterGroupName != parentGroupName {
+				joinOperator := " AND "
+				if filterGroup.OrSequence {
+					joinOperator = " OR "
+				}
+				subFilters := collectSubfilters(filterGroupName)
+				subFilters = append(subFilters, filterGroup.FilterValues...)
+				if len(subFilters) == 0 {
+					continue
+				}
+				result = append(result, "("+strings.Join(subFilters, joinOperator)+")")
+			}
+		}
+
+		return result
+	}
+
+	sqlFilters := strings.Join(collectSubfilters(""), " AND ")
+	if sqlFilters != "" {
+		sqlFilters = " WHERE " + sqlFilters
+	}
+
+	return sqlFilters
+}
+
+// returns filter group, creates new one if not exists
+func (it *DBCollection) getFilterGroup(groupName string) *StructDBFilterGroup {
+	filterGroup, present := it.FilterGroups[groupName]
+	if !present {
+		filterGroup = &StructDBFilterGroup{Name: groupName, FilterValues: make([]string, 0)}
+		it.FilterGroups[groupName] = filterGroup
+	}
+	return filterGroup
+}
+
+// adds filter(combination of [column, operator, value]) in named filter group
+func (it *DBCollection) updateFilterGroup(groupName string, columnName string, operator string, value interface{}) error {
+	newValue, err := it.makeSQLFilterString(columnName, operator, value)
+	if err != nil {
+		return err
+	}
+
+	filterGroup := it.getFilterGroup(groupName)
+	filterGroup.FilterValues = append(filterGroup.FilterValues, newValue)
+
+	return nil
+}
+
+// generates new UUID for _id column
+func (it *DBCollection) makeUUID(id string) string {
+
+	if len(id) != 24 {
+		timeStamp := strconv.FormatInt(time.Now().Unix(), 16)
+
+		randomBytes := make([]byte, 8)
+		if _, err := rand.Reader.Read(randomBytes); err != nil {
+			_ = env.ErrorDispatch(err)
+		}
+
+		randomHex := make([]byte, 16)
+		hex.Encode(randomHex, randomBytes)
+
+		id = timeStamp + string(randomHex)
+	}
+
+	return id
+}
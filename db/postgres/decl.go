@@ -0,0 +1,91 @@
+package postgres
+
+import (
+	"database/sql"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/ottemo/commerce/env"
+)
+
+// Package global constants
+const (
+	ConstConnectionValidateInterval = time.Second * 10 // timer interval to ping connection and refresh it by perforce
+
+	ConstDebugSQL  = false // flag which indicates to perform log on each SQL operation
+	ConstDebugFile = "postgres.log"
+
+	ConstFilterGroupStatic  = "static"  // name for static filter, ref. to AddStaticFilter(...)
+	ConstFilterGroupDefault = "default" // name for default filter, ref. to by AddFilter(...)
+
+	ConstCollectionNameColumnInfo = "collection_column_info" // table name to hold Ottemo types of columns
+
+	ConstContextKeyTransaction = "db.postgres.transaction" // call context key to hold started transaction
+
+	ConstErrorModule = "db/postgres"
+	ConstErrorLevel  = env.ConstErrorLevelService
+)
+
+// Package global variables
+var (
+	// dbEngine is an instance of database engine (one per application)
+	dbEngine *DBEngine
+
+	// ConstSQLNameValidator is a regex expression used to check names used within SQL queries
+	ConstSQLNameValidator = regexp.MustCompile("^[A-Za-z_][A-Za-z0-9_]*$")
+)
+
+// RowMap - represents row of data from database
+type RowMap map[string]interface{}
+
+// StructDBFilterGroup is a structure to hold information of named collection filter
+type StructDBFilterGroup struct {
+	Name         string
+	FilterValues []string
+	ParentGroup  string
+	OrSequence   bool
+}
+
+// DBCollection is a InterfaceDBCollection implementer
+type DBCollection struct {
+	Name string
+
+	ResultColumns []string
+	FilterGroups  map[string]*StructDBFilterGroup
+	Order         []string // sort columns, descending order columns are prefixed with "-"
+
+	Limit string
+
+	isCursorSet bool                   // keyset pagination mode, ref. to SetCursor(...)
+	lastRecord  map[string]interface{} // last iterated record, used to make next cursor
+}
+
+// DBEngine is a InterfaceDBEngine implementer
+type DBEngine struct {
+	connection *sql.DB
+
+	attributeTypes      map[string]map[string]string
+	attributeTypesMutex sync.RWMutex
+
+	replicas       []*replicaConnection // read replicas, ref. to InterfaceDBReplicaConnector
+	replicasMutex  sync.RWMutex
+	replicaCounter uint32 // round-robin counter of replica reads
+
+	isConnected bool
+}
+
+// replicaConnection is a read replica connection
+type replicaConnection struct {
+	connection  *sql.DB
+	isAvailable bool
+}
+
+// connectionParamsType describes params required to connect to DB
+type connectionParamsType struct {
+	uri             string
+	poolConnections int
+	maxConnections  int
+	connMaxLifetime time.Duration
+	replicas        []string
+}
@@ -0,0 +1,10 @@
+// Copyright 2019 Ottemo. All rights reserved.
+
+/*
+Package postgres is a PostgreSQL implementation for Ottemo. It provides "InterfaceDBEngine" implementation declared in
+"github.com/ottemo/commerce/db" package.
+
+Package stands on a top of "github.com/lib/pq" package. JSON columns are stored as JSONB, money and decimal columns as
+NUMERIC and datetime columns as TIMESTAMPTZ.
+*/
+package postgres
@@ -0,0 +1,129 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/ottemo/commerce/env"
+	"github.com/ottemo/commerce/utils"
+)
+
+// ------------------------------------------------------------------------------------
+// InterfaceDBConnector implementation (package "github.com/ottemo/commerce/db/interfaces")
+// ------------------------------------------------------------------------------------
+
+// GetConnectionParams returns configured DB connection params
+func (it *DBEngine) GetConnectionParams() interface{} {
+	var connectionParams = connectionParamsType{
+		uri:             "postgres://localhost:5432/ottemo?sslmode=disable",
+		poolConnections: 10,
+		maxConnections:  0,
+	}
+
+	if iniConfig := env.GetIniConfig(); iniConfig != nil {
+		if iniValue := iniConfig.GetValue("db.postgres.uri", connectionParams.uri); iniValue != "" {
+			connectionParams.uri = iniValue
+		}
+
+		if iniValue := iniConfig.GetValue("db.postgres.maxConnections", ""); iniValue != "" {
+			connectionParams.maxConnections = utils.InterfaceToInt(iniValue)
+		}
+
+		if iniValue := iniConfig.GetValue("db.postgres.poolConnections", ""); iniValue != "" {
+			connectionParams.poolConnections = utils.InterfaceToInt(iniValue)
+		}
+
+		if iniValue := iniConfig.GetValue("db.postgres.connMaxLifetime", ""); iniValue != "" {
+			connectionParams.connMaxLifetime = time.Duration(utils.InterfaceToInt(iniValue)) * time.Second
+		}
+
+		if iniValue := iniConfig.GetValue("db.postgres.replicas", ""); iniValue != "" {
+			for _, replicaURI := range strings.Split(iniValue, ",") {
+				if replicaURI = strings.TrimSpace(replicaURI); replicaURI != "" {
+					connectionParams.replicas = append(connectionParams.replicas, replicaURI)
+				}
+			}
+		}
+	}
+
+	return connectionParams
+}
+
+// Connect establishes DB connection
+func (it *DBEngine) Connect(srcConnectionParams interface{}) error {
+	connectionParams, ok := srcConnectionParams.(connectionParamsType)
+	if !ok {
+		return errors.New("Wrong connection parameters type.")
+	}
+
+	newConnection, err := sql.Open("postgres", connectionParams.uri)
+	if err != nil {
+		return err
+	}
+
+	setupConnectionPool(newConnection, connectionParams)
+
+	if err := newConnection.Ping(); err != nil {
+		if closeErr := newConnection.Close(); closeErr != nil {
+			it.LogConnection(closeErr.Error())
+		}
+		return err
+	}
+
+	it.connection = newConnection
+
+	return nil
+}
+
+// AfterConnect makes initialization of DB engine
+func (it *DBEngine) AfterConnect(srcConnectionParams interface{}) error {
+	SQL := "CREATE TABLE IF NOT EXISTS " + quoteName(ConstCollectionNameColumnInfo) + " (" +
+		"\"_id\"        SERIAL PRIMARY KEY," +
+		"\"collection\" VARCHAR(255)," +
+		"\"column\"     VARCHAR(255)," +
+		"\"type\"       VARCHAR(255)," +
+		"\"indexed\"    BOOLEAN)"
+
+	if _, err := it.connection.Exec(SQL); err != nil {
+		return sqlError(SQL, err)
+	}
+
+	return nil
+}
+
+// Reconnect tries to reconnect to DB
+func (it *DBEngine) Reconnect(connectionParams interface{}) error {
+	return it.Connect(connectionParams)
+}
+
+// IsConnected returns connection status
+func (it *DBEngine) IsConnected() bool {
+	return it.isConnected
+}
+
+// SetConnected sets connection status
+func (it *DBEngine) SetConnected(connected bool) {
+	it.isConnected = connected
+}
+
+// Ping checks connection alive
+func (it *DBEngine) Ping() error {
+	return it.connection.Ping()
+}
+
+// GetValidationInterval returns delay between Ping
+func (it *DBEngine) GetValidationInterval() time.Duration {
+	return ConstConnectionValidateInterval
+}
+
+// GetEngineName returns DBEngine name (InterfaceDBConnector)
+func (it *DBEngine) GetEngineName() string {
+	return it.GetName()
+}
+
+// LogConnection outputs message to log
+func (it *DBEngine) LogConnection(message string) {
+	env.Log(ConstDebugFile, "DEBUG", message)
+}
@@ -0,0 +1,128 @@
+package postgres
+
+import (
+	"github.com/ottemo/commerce/api/context"
+	"github.com/ottemo/commerce/db"
+	"github.com/ottemo/commerce/env"
+)
+
+// GetName returns current DB engine name
+func (it *DBEngine) GetName() string {
+	return "PostgreSQL"
+}
+
+// HasCollection checks if collection(table) already exists
+func (it *DBEngine) HasCollection(collectionName string) bool {
+	SQL := "SELECT table_name FROM information_schema.tables WHERE table_schema = current_schema() AND table_name = " + quoteString(collectionName)
+
+	rows, err := connectionQuery(SQL)
+	defer closeCursor(rows)
+
+	if err == nil && rows.Next() {
+		return true
+	}
+
+	return false
+}
+
+// CreateCollection creates collection(table) by it's name
+func (it *DBEngine) CreateCollection(collectionName string) error {
+	SQL := "CREATE TABLE " + quoteName(collectionName) + " (\"_id\" VARCHAR(24) NOT NULL PRIMARY KEY)"
+
+	if err := connectionExec(SQL); err != nil {
+		return sqlError(SQL, err)
+	}
+
+	return nil
+}
+
+// GetCollection returns collection(table) by name or creates new one
+func (it *DBEngine) GetCollection(collectionName string) (db.InterfaceDBCollection, error) {
+	if !ConstSQLNameValidator.MatchString(collectionName) {
+		return nil, env.ErrorNew(ConstErrorModule, ConstErrorLevel, "340af221-ae5c-4465-8747-250bf20dfbd0", "not valid collection name for DB engine")
+	}
+
+	if !it.HasCollection(collectionName) {
+		if err := it.CreateCollection(collectionName); err != nil {
+			return nil, env.ErrorDispatch(err)
+		}
+	}
+
+	collection := &DBCollection{
+		Name:          collectionName,
+		FilterGroups:  make(map[string]*StructDBFilterGroup),
+		Order:         make([]string, 0),
+		ResultColumns: make([]string, 0),
+	}
+
+	it.attributeTypesMutex.RLock()
+	_, present := it.attributeTypes[collectionName]
+	it.attributeTypesMutex.RUnlock()
+
+	if !present {
+		collection.ListColumns()
+	}
+
+	return collection, nil
+}
+
+// RawQuery executes given query and returns first row of result
+func (it *DBEngine) RawQuery(query string) (map[string]interface{}, error) {
+	rows, err := connectionQuery(query)
+	defer closeCursor(rows)
+
+	if err != nil {
+		return nil, sqlError(query, err)
+	}
+
+	if !rows.Next() {
+		return nil, env.ErrorDispatch(rows.Err())
+	}
+
+	row, err := getRowAsStringMap(rows)
+	if err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
+	return row, nil
+}
+
+// BeginTransaction starts a transaction bound to current call context
+func (it *DBEngine) BeginTransaction() error {
+	if context.GetContext() == nil {
+		return env.ErrorNew(ConstErrorModule, ConstErrorLevel, "6b083a2a-d9b4-4abb-b434-d5ea7add33bc", "transaction requires call context")
+	}
+	if getTransaction() != nil {
+		return env.ErrorNew(ConstErrorModule, ConstErrorLevel, "2e22c266-56f0-4d49-adee-6a4037706379", "transaction already started")
+	}
+
+	transaction, err := it.connection.Begin()
+	if err != nil {
+		return env.ErrorDispatch(err)
+	}
+	context.SetContextValue(ConstContextKeyTransaction, transaction)
+
+	return nil
+}
+
+// CommitTransaction commits transaction started within current call context
+func (it *DBEngine) CommitTransaction() error {
+	transaction := getTransaction()
+	if transaction == nil {
+		return env.ErrorNew(ConstErrorModule, ConstErrorLevel, "48ec65d0-c9e4-4949-8e85-b886a0fcf690", "there is no started transaction")
+	}
+	context.SetContextValue(ConstContextKeyTransaction, nil)
+
+	return env.ErrorDispatch(transaction.Commit())
+}
+
+// RollbackTransaction rolls back transaction started within current call context
+func (it *DBEngine) RollbackTransaction() error {
+	transaction := getTransaction()
+	if transaction == nil {
+		return env.ErrorNew(ConstErrorModule, ConstErrorLevel, "402a24e6-3aaa-447c-996f-ee22a3553d22", "there is no started transaction")
+	}
+	context.SetContextValue(ConstContextKeyTransaction, nil)
+
+	return env.ErrorDispatch(transaction.Rollback())
+}
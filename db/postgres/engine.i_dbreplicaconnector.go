@@ -0,0 +1,118 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"sync/atomic"
+)
+
+// ------------------------------------------------------------------------------------------
+// InterfaceDBReplicaConnector implementation (package "github.com/ottemo/commerce/db/interfaces")
+// ------------------------------------------------------------------------------------------
+
+// GetReplicaParams returns configured read replicas connection params
+func (it *DBEngine) GetReplicaParams(srcConnectionParams interface{}) []interface{} {
+	var result []interface{}
+
+	if connectionParams, ok := srcConnectionParams.(connectionParamsType); ok {
+		for _, replicaURI := range connectionParams.replicas {
+			replicaParams := connectionParams
+			replicaParams.uri = replicaURI
+			replicaParams.replicas = nil
+
+			result = append(result, replicaParams)
+		}
+	}
+
+	return result
+}
+
+// ConnectReplica establishes read replica connection, previous replica connection is closed
+func (it *DBEngine) ConnectReplica(replicaIdx int, srcReplicaParams interface{}) error {
+	replicaParams, ok := srcReplicaParams.(connectionParamsType)
+	if !ok {
+		return errors.New("Wrong replica connection parameters type.")
+	}
+
+	newConnection, err := sql.Open("postgres", replicaParams.uri)
+	if err != nil {
+		return err
+	}
+	setupConnectionPool(newConnection, replicaParams)
+
+	if err := newConnection.Ping(); err != nil {
+		_ = newConnection.Close()
+		return err
+	}
+
+	it.replicasMutex.Lock()
+	for len(it.replicas) <= replicaIdx {
+		it.replicas = append(it.replicas, nil)
+	}
+	oldReplica := it.replicas[replicaIdx]
+	it.replicas[replicaIdx] = &replicaConnection{connection: newConnection}
+	it.replicasMutex.Unlock()
+
+	if oldReplica != nil {
+		if err := oldReplica.connection.Close(); err != nil {
+			it.LogConnection(err.Error())
+		}
+	}
+
+	return nil
+}
+
+// PingReplica checks read replica connection alive
+func (it *DBEngine) PingReplica(replicaIdx int) error {
+	it.replicasMutex.RLock()
+	var replica *replicaConnection
+	if replicaIdx < len(it.replicas) {
+		replica = it.replicas[replicaIdx]
+	}
+	it.replicasMutex.RUnlock()
+
+	if replica == nil {
+		return errors.New("Replica is not connected.")
+	}
+
+	return replica.connection.Ping()
+}
+
+// IsReplicaAvailable returns true if read replica could be used for reads
+func (it *DBEngine) IsReplicaAvailable(replicaIdx int) bool {
+	it.replicasMutex.RLock()
+	defer it.replicasMutex.RUnlock()
+
+	return replicaIdx < len(it.replicas) && it.replicas[replicaIdx] != nil && it.replicas[replicaIdx].isAvailable
+}
+
+// SetReplicaAvailable includes read replica into reads routing or excludes it
+func (it *DBEngine) SetReplicaAvailable(replicaIdx int, available bool) {
+	it.replicasMutex.Lock()
+	defer it.replicasMutex.Unlock()
+
+	if replicaIdx < len(it.replicas) && it.replicas[replicaIdx] != nil {
+		it.replicas[replicaIdx].isAvailable = available
+	}
+}
+
+// getReplica returns next available read replica connection in turn or nil
+func (it *DBEngine) getReplica() (int, *sql.DB) {
+	it.replicasMutex.RLock()
+	defer it.replicasMutex.RUnlock()
+
+	count := len(it.replicas)
+	if count == 0 {
+		return -1, nil
+	}
+
+	start := int(atomic.AddUint32(&it.replicaCounter, 1) % uint32(count))
+	for i := 0; i < count; i++ {
+		replicaIdx := (start + i) % count
+		if replica := it.replicas[replicaIdx]; replica != nil && replica.isAvailable {
+			return replicaIdx, replica.connection
+		}
+	}
+
+	return -1, nil
+}
@@ -0,0 +1,310 @@
+package postgres
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ottemo/commerce/api/context"
+	"github.com/ottemo/commerce/db"
+	"github.com/ottemo/commerce/env"
+	"github.com/ottemo/commerce/utils"
+)
+
+// sqlExecutor is a common interface for sql.DB and sql.Tx statements execution
+type sqlExecutor interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// getTransaction returns transaction started within current call context or nil
+func getTransaction() *sql.Tx {
+	if transaction, ok := context.GetContextValue(ConstContextKeyTransaction).(*sql.Tx); ok {
+		return transaction
+	}
+	return nil
+}
+
+// getExecutor returns current call context transaction if it was started or connection otherwise
+func getExecutor() sqlExecutor {
+	if transaction := getTransaction(); transaction != nil {
+		return transaction
+	}
+	return dbEngine.connection
+}
+
+// getReadExecutor returns executor for read statements, read replica is used if it is available and there is no
+// transaction started within current call context
+//   - primary connection is used for reads within db.ReadFromPrimary(...)
+//   - returns index of used replica or -1 if it is not a replica
+func getReadExecutor() (sqlExecutor, int) {
+	if transaction := getTransaction(); transaction != nil {
+		return transaction, -1
+	}
+	if db.IsReadingFromPrimary() {
+		return dbEngine.connection, -1
+	}
+	if replicaIdx, replica := dbEngine.getReplica(); replica != nil {
+		return replica, replicaIdx
+	}
+	return dbEngine.connection, -1
+}
+
+// setupConnectionPool applies pool settings to connection
+func setupConnectionPool(connection *sql.DB, connectionParams connectionParamsType) {
+	if connectionParams.poolConnections > 0 {
+		connection.SetMaxIdleConns(connectionParams.poolConnections)
+	}
+
+	if connectionParams.maxConnections > 0 {
+		connection.SetMaxOpenConns(connectionParams.maxConnections)
+	}
+
+	if connectionParams.connMaxLifetime > 0 {
+		connection.SetConnMaxLifetime(connectionParams.connMaxLifetime)
+	}
+}
+
+// exec routines
+func connectionExecWAffected(SQL string, args ...interface{}) (int64, error) {
+	defer db.ObserveQuery(dbEngine.GetName(), db.GetQueryOperation(SQL), time.Now())
+
+	if ConstDebugSQL {
+		env.Log(ConstDebugFile, env.ConstLogPrefixInfo, SQL)
+	}
+
+	result, err := getExecutor().Exec(SQL, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// exec routines
+func connectionExec(SQL string, args ...interface{}) error {
+	defer db.ObserveQuery(dbEngine.GetName(), db.GetQueryOperation(SQL), time.Now())
+
+	if ConstDebugSQL {
+		env.Log(ConstDebugFile, env.ConstLogPrefixInfo, SQL)
+	}
+
+	_, err := getExecutor().Exec(SQL, args...)
+
+	return err
+}
+
+// query routines
+func connectionQuery(SQL string) (*sql.Rows, error) {
+	defer db.ObserveQuery(dbEngine.GetName(), db.GetQueryOperation(SQL), time.Now())
+
+	if ConstDebugSQL {
+		env.Log(ConstDebugFile, env.ConstLogPrefixInfo, SQL)
+	}
+
+	return getExecutor().Query(SQL)
+}
+
+// query routines for reads which could be made on replica
+//   - replica failed to respond is excluded from reads and query is repeated on primary
+func connectionQueryRead(SQL string) (*sql.Rows, error) {
+	defer db.ObserveQuery(dbEngine.GetName(), db.GetQueryOperation(SQL), time.Now())
+
+	if ConstDebugSQL {
+		env.Log(ConstDebugFile, env.ConstLogPrefixInfo, SQL)
+	}
+
+	executor, replicaIdx := getReadExecutor()
+
+	rows, err := executor.Query(SQL)
+	if err != nil && replicaIdx != -1 && dbEngine.PingReplica(replicaIdx) != nil {
+		dbEngine.SetReplicaAvailable(replicaIdx, false)
+		dbEngine.LogConnection("DB replica " + utils.InterfaceToString(replicaIdx) + " failed, query is made on primary: " + err.Error())
+
+		return dbEngine.connection.Query(SQL)
+	}
+
+	return rows, err
+}
+
+// closeCursor closes cursor statement routine
+func closeCursor(cursor *sql.Rows) {
+	if cursor != nil {
+		if err := cursor.Close(); err != nil {
+			_ = env.ErrorDispatch(err)
+		}
+	}
+}
+
+// formats SQL query error for output to log
+func sqlError(SQL string, err error) error {
+	return env.ErrorNew(ConstErrorModule, ConstErrorLevel, "e9541a20-c103-4132-b7a7-56f7e508db34", "SQL \""+SQL+"\" error: "+err.Error())
+}
+
+// quoteName returns quoted identifier (table or column name) for SQL query, so name case is kept
+func quoteName(name string) string {
+	return "\"" + strings.Replace(name, "\"", "\"\"", -1) + "\""
+}
+
+// quoteString returns string literal for SQL query
+func quoteString(value string) string {
+	return "'" + strings.Replace(value, "'", "''", -1) + "'"
+}
+
+// returns string that represents value for SQL query, value is converted according to the column type
+//   - blank column type means value type based conversion
+func convertValueForSQL(value interface{}, columnType string) string {
+	if subCollection, ok := value.(*DBCollection); ok {
+		return subCollection.getSelectSQL()
+	}
+
+	if value == nil {
+		return "NULL"
+	}
+
+	dataType := utils.DataTypeParse(columnType)
+	switch {
+	case dataType.IsArray:
+		return quoteString(convertArrayToString(value))
+
+	case dataType.Name == db.ConstTypeBoolean:
+		return convertValueForSQL(utils.InterfaceToBool(value), "")
+
+	case dataType.Name == db.ConstTypeInteger:
+		return strconv.Itoa(utils.InterfaceToInt(value))
+
+	case dataType.Name == db.ConstTypeFloat:
+		return strconv.FormatFloat(utils.InterfaceToFloat64(value), 'f', -1, 64)
+
+	case dataType.Name == db.ConstTypeDatetime:
+		return convertValueForSQL(utils.InterfaceToTime(value), "")
+
+	case dataType.Name == db.ConstTypeJSON:
+		if stringValue, ok := value.(string); ok && json.Valid([]byte(stringValue)) {
+			return quoteString(stringValue)
+		}
+		return quoteString(utils.EncodeToJSONString(value))
+
+	case utils.IsAmongStr(dataType.Name, db.ConstTypeID, db.ConstTypeVarchar, db.ConstTypeText, utils.ConstDataTypeHTML):
+		switch value.(type) {
+		case string, bool, int, int32, int64, float32, float64:
+			return quoteString(utils.InterfaceToString(value))
+		}
+	}
+
+	switch typedValue := value.(type) {
+	case bool:
+		if typedValue {
+			return "TRUE"
+		}
+		return "FALSE"
+
+	case string:
+		return quoteString(typedValue)
+
+	case int, int32, int64:
+		return utils.InterfaceToString(value)
+
+	case float32, float64:
+		return strconv.FormatFloat(utils.InterfaceToFloat64(value), 'f', -1, 64)
+
+	case time.Time:
+		return quoteString(typedValue.UTC().Format(time.RFC3339Nano))
+
+	case map[string]interface{}, map[string]string:
+		return quoteString(utils.EncodeToJSONString(value))
+
+	case []string, []int, []int64, []int32, []float64, []bool, []interface{}:
+		return quoteString(convertArrayToString(value))
+	}
+
+	return quoteString(utils.InterfaceToString(value))
+}
+
+// convertArrayToString returns array column representation of value, items are joined with ", "
+func convertArrayToString(value interface{}) string {
+	if stringValue, ok := value.(string); ok {
+		return stringValue
+	}
+
+	result := ""
+	for _, item := range utils.InterfaceToArray(value) {
+		if result != "" {
+			result += ", "
+		}
+		result += strings.Replace(utils.InterfaceToString(item), ",", "#2C;", -1)
+	}
+	return result
+}
+
+func getRowAsStringMap(rows *sql.Rows) (RowMap, error) {
+	row := make(RowMap)
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return row, env.ErrorDispatch(err)
+	}
+
+	values := make([]sql.NullString, len(columns))
+
+	scanArgs := make([]interface{}, len(values))
+	for i := range values {
+		scanArgs[i] = &values[i]
+	}
+
+	if err := rows.Scan(scanArgs...); err != nil {
+		return row, env.ErrorDispatch(err)
+	}
+
+	for idx, column := range columns {
+		if values[idx].Valid {
+			row[column] = values[idx].String
+		} else {
+			row[column] = nil
+		}
+	}
+
+	return row, nil
+}
+
+// GetDBType returns type used inside postgres for given general name
+func GetDBType(ColumnType string) (string, error) {
+	ColumnType = strings.ToLower(ColumnType)
+	dataType := utils.DataTypeParse(ColumnType)
+
+	switch {
+	case dataType.IsArray:
+		return "TEXT", nil
+	case ColumnType == db.ConstTypeID:
+		return "VARCHAR(24)", nil
+	case dataType.Name == db.ConstTypeBoolean:
+		return "BOOLEAN", nil
+	case dataType.Name == db.ConstTypeInteger:
+		return "BIGINT", nil
+	case strings.HasPrefix(ColumnType, db.ConstTypeDecimal) || strings.HasPrefix(ColumnType, db.ConstTypeMoney) || strings.Contains(ColumnType, "numeric"):
+		if dataType.Precision > 0 {
+			return fmt.Sprintf("NUMERIC(%d,%d)", dataType.Precision, dataType.Scale), nil
+		}
+		return "NUMERIC", nil
+	case dataType.Name == db.ConstTypeFloat || ColumnType == "real":
+		return "DOUBLE PRECISION", nil
+	case dataType.Name == db.ConstTypeVarchar || strings.Contains(ColumnType, "char"):
+		if dataType.Precision > 0 {
+			return fmt.Sprintf("VARCHAR(%d)", dataType.Precision), nil
+		}
+		return "VARCHAR", nil
+	case dataType.Name == db.ConstTypeText || dataType.Name == utils.ConstDataTypeHTML:
+		return "TEXT", nil
+	case dataType.Name == db.ConstTypeJSON:
+		return "JSONB", nil
+	case ColumnType == "blob" || ColumnType == "data":
+		return "BYTEA", nil
+	case dataType.Name == db.ConstTypeDatetime:
+		return "TIMESTAMPTZ", nil
+	}
+
+	return "?", env.ErrorNew(ConstErrorModule, ConstErrorLevel, "7cff85bc-3955-495f-8f2a-7a8f0a4996ed", "Unknown type '"+ColumnType+"'")
+}
@@ -10,6 +10,7 @@ import (
 	sqlite3 "github.com/mxk/go-sqlite/sqlite3"
 	"github.com/ottemo/commerce/db"
 	"github.com/ottemo/commerce/env"
+	"github.com/ottemo/commerce/utils"
 )
 
 // LoadByID loads record from DB by it's id
@@ -59,6 +60,7 @@ func (it *DBCollection) Load() ([]map[string]interface{}, error) {
 func (it *DBCollection) Iterate(iteratorFunc func(record map[string]interface{}) bool) error {
 
 	SQL := it.getSelectSQL()
+	it.lastRecord = nil
 
 	stmt, err := connectionQuery(SQL)
 	defer closeStatement(stmt)
@@ -69,6 +71,10 @@ func (it *DBCollection) Iterate(iteratorFunc func(record map[string]interface{})
 			if err := stmt.Scan(row); err == nil {
 				it.modifyResultRow(row)
 
+				if it.isCursorSet {
+					it.lastRecord = row
+				}
+
 				if !iteratorFunc(row) {
 					break
 				}
@@ -179,6 +185,38 @@ func (it *DBCollection) Count() (int, error) {
 	return 0, err
 }
 
+// Aggregate calculates aggregate functions over records matching current select statement grouped by given keys
+//   - result is ordered by group keys, sort and limit of collection are not applied
+func (it *DBCollection) Aggregate(groupBy []db.StructAggregateGroup, aggregates []db.StructAggregate) ([]map[string]interface{}, error) {
+	groupBy, aggregates, err := db.PrepareAggregate(it, groupBy, aggregates)
+	if err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
+	SQL := it.getAggregateSQL(groupBy, aggregates)
+
+	stmt, err := connectionQuery(SQL)
+	defer closeStatement(stmt)
+
+	var result []map[string]interface{}
+	if err == nil {
+		for ; err == nil; err = stmt.Next() {
+			row := make(sqlite3.RowMap)
+			if err := stmt.Scan(row); err == nil {
+				result = append(result, db.ConvertAggregateRow(it, groupBy, aggregates, row))
+			}
+		}
+	}
+
+	if err == io.EOF {
+		err = nil
+	} else if err != nil {
+		err = sqlError(SQL, err)
+	}
+
+	return result, env.ErrorDispatch(err)
+}
+
 // Save stores record in DB for current collection
 func (it *DBCollection) Save(item map[string]interface{}) (string, error) {
 
@@ -218,6 +256,14 @@ func (it *DBCollection) Save(item map[string]interface{}) (string, error) {
 		}
 	}
 
+	// optimistic concurrency control, ref. to db.AddVersionColumn
+	isVersioned := it.HasColumn(db.ConstVersionColumn)
+	expectedVersion := 0
+	if isVersioned {
+		expectedVersion = db.GetRecordVersion(item)
+		delete(item, db.ConstVersionColumn)
+	}
+
 	// SQL generation
 	columns := make([]string, 0, len(item))
 	args := make([]string, 0, len(item))
@@ -243,8 +289,15 @@ func (it *DBCollection) Save(item map[string]interface{}) (string, error) {
 
 	// trying to make update first, it we have _id
 	if item["_id"] != nil && item["_id"] != "" {
-		SQL := "UPDATE " + it.Name + " SET " + strings.Join(columnEqArg, ", ") +
-			" WHERE `_id`=" + convertValueForSQL(item["_id"])
+		SQLWhere := " WHERE `_id`=" + convertValueForSQL(item["_id"])
+		if isVersioned {
+			columnEqArg = append(columnEqArg, "`"+db.ConstVersionColumn+"`=IFNULL(`"+db.ConstVersionColumn+"`, 0)+1")
+			if expectedVersion != 0 {
+				SQLWhere += " AND IFNULL(`" + db.ConstVersionColumn + "`, 0)=" + strconv.Itoa(expectedVersion)
+			}
+		}
+
+		SQL := "UPDATE " + it.Name + " SET " + strings.Join(columnEqArg, ", ") + SQLWhere
 
 		affected, err := connectionExecWAffected(SQL)
 		if err != nil {
@@ -252,11 +305,22 @@ func (it *DBCollection) Save(item map[string]interface{}) (string, error) {
 		}
 		if affected > 0 {
 			makeInsertFlag = false
+		} else if expectedVersion != 0 {
+			if _, isStored, err := it.getStoredVersion(item["_id"]); err != nil {
+				return "", env.ErrorDispatch(err)
+			} else if isStored {
+				return "", db.NewVersionConflictError(it.Name, utils.InterfaceToString(item["_id"]))
+			}
 		}
 	}
 
 	// so if update fas successful we do not need to insert
 	if makeInsertFlag {
+		if isVersioned {
+			columns = append(columns, "`"+db.ConstVersionColumn+"`")
+			args = append(args, "1")
+		}
+
 		SQL := "INSERT INTO " + it.Name +
 			" (" + strings.Join(columns, ",") + ") VALUES" +
 			" (" + strings.Join(args, ",") + ")"
@@ -278,6 +342,21 @@ func (it *DBCollection) Save(item map[string]interface{}) (string, error) {
 		}
 	}
 
+	if isVersioned {
+		switch {
+		case makeInsertFlag:
+			item[db.ConstVersionColumn] = 1
+		case expectedVersion != 0:
+			item[db.ConstVersionColumn] = expectedVersion + 1
+		default:
+			storedVersion, _, err := it.getStoredVersion(item["_id"])
+			if err != nil {
+				return "", env.ErrorDispatch(err)
+			}
+			item[db.ConstVersionColumn] = storedVersion
+		}
+	}
+
 	return item["_id"].(string), nil
 }
 
@@ -416,6 +495,32 @@ func (it *DBCollection) SetLimit(Offset int, Limit int) error {
 	return nil
 }
 
+// SetCursor switches collection to keyset pagination, so records following the cursor are selected
+//   - "_id" column is added to sort order to make it unique
+//   - blank cursor means first page
+func (it *DBCollection) SetCursor(cursor string) error {
+	if !utils.IsInListStr("_id", it.getSortColumns()) && !utils.IsInListStr("-_id", it.getSortColumns()) {
+		if err := it.AddSort("_id", false); err != nil {
+			return env.ErrorDispatch(err)
+		}
+	}
+	it.isCursorSet = true
+
+	if cursor == "" {
+		return nil
+	}
+
+	return db.ApplyCursor(it, it.getSortColumns(), cursor)
+}
+
+// GetNextCursor returns cursor pointing to the last loaded record or blank string if there were no records
+func (it *DBCollection) GetNextCursor() string {
+	if !it.isCursorSet {
+		return ""
+	}
+	return db.EncodeCursor(it.getSortColumns(), it.lastRecord)
+}
+
 // ListColumns returns attributes(columns) available for current collection(table)
 func (it *DBCollection) ListColumns() map[string]string {
 
@@ -590,7 +695,13 @@ func (it *DBCollection) RemoveColumn(columnName string) error {
 		for _, tableColumn := range tableColumnsList {
 			tableColumn = strings.Trim(tableColumn, "\n\t ")
 
-			if !strings.HasPrefix(tableColumn, columnName) && !strings.HasPrefix(tableColumn, "\""+columnName+"\"") {
+			// column definition starts with its name, which may be quoted
+			tableColumnName := tableColumn
+			if spaceIndex := strings.Index(tableColumn, " "); spaceIndex >= 0 {
+				tableColumnName = tableColumn[0:spaceIndex]
+			}
+
+			if strings.Trim(tableColumnName, "\"") != columnName {
 				if tableColumnsWTypes != "" {
 					tableColumnsWTypes += ", "
 					tableColumnsWoTypes += ", "
@@ -3,6 +3,7 @@ package sqlite
 import (
 	"crypto/rand"
 	"encoding/hex"
+	"io"
 	"strconv"
 	"strings"
 	"time"
@@ -81,10 +82,81 @@ func (it *DBCollection) makeSQLFilterString(ColumnName string, Operator string,
 
 // returns SQL select statement for current collection
 func (it *DBCollection) getSelectSQL() string {
-	SQL := "SELECT " + it.getSQLResultColumns() + " FROM " + it.Name + it.getSQLFilters() + it.getSQLOrder() + it.Limit
+	resultColumns := it.getSQLResultColumns()
+
+	// sort columns are required to make next cursor
+	if it.isCursorSet && len(it.ResultColumns) > 0 {
+		for _, sortColumn := range it.getSortColumns() {
+			sortColumn = strings.TrimPrefix(sortColumn, "-")
+			if !utils.IsInListStr(sortColumn, it.ResultColumns) {
+				resultColumns += ", `" + sortColumn + "`"
+			}
+		}
+	}
+
+	SQL := "SELECT " + resultColumns + " FROM " + it.Name + it.getSQLFilters() + it.getSQLOrder() + it.Limit
+	return SQL
+}
+
+// returns SQL select statement calculating aggregates over current collection
+func (it *DBCollection) getAggregateSQL(groupBy []db.StructAggregateGroup, aggregates []db.StructAggregate) string {
+	var sqlColumns, sqlGroups []string
+
+	for _, group := range groupBy {
+		sqlColumns = append(sqlColumns, it.getSQLGroupExpression(group)+" AS `"+group.Name+"`")
+		sqlGroups = append(sqlGroups, "`"+group.Name+"`")
+	}
+
+	for _, aggregate := range aggregates {
+		expression := "COUNT(*)"
+		if aggregate.Column != "" {
+			expression = strings.ToUpper(aggregate.Function) + "(`" + aggregate.Column + "`)"
+		}
+		sqlColumns = append(sqlColumns, expression+" AS `"+aggregate.Name+"`")
+	}
+
+	SQL := "SELECT " + strings.Join(sqlColumns, ", ") + " FROM " + it.Name + it.getSQLFilters()
+	if len(sqlGroups) > 0 {
+		SQL += " GROUP BY " + strings.Join(sqlGroups, ", ") + " ORDER BY " + strings.Join(sqlGroups, ", ")
+	}
+
 	return SQL
 }
 
+// returns SQL expression for aggregation group key, datetime columns are holding unix time
+func (it *DBCollection) getSQLGroupExpression(group db.StructAggregateGroup) string {
+	if columnPath := strings.SplitN(group.Column, ".", 2); len(columnPath) > 1 {
+		return "json_extract(`" + columnPath[0] + "`, '$." + columnPath[1] + "')"
+	}
+
+	column := "`" + group.Column + "`"
+	switch group.Bucket {
+	case db.ConstAggregateBucketHour:
+		return "(" + column + " - " + column + " % 3600)"
+	case db.ConstAggregateBucketDay:
+		return "(" + column + " - " + column + " % 86400)"
+	case db.ConstAggregateBucketMonth:
+		return "CAST(strftime('%s', " + column + ", 'unixepoch', 'start of month') AS INTEGER)"
+	case db.ConstAggregateBucketYear:
+		return "CAST(strftime('%s', " + column + ", 'unixepoch', 'start of year') AS INTEGER)"
+	}
+
+	return column
+}
+
+// returns sort columns in db.EncodeCursor(...) format
+func (it *DBCollection) getSortColumns() []string {
+	result := make([]string, 0, len(it.Order))
+	for _, orderColumn := range it.Order {
+		if strings.HasSuffix(orderColumn, " DESC") {
+			result = append(result, "-"+strings.TrimSuffix(orderColumn, " DESC"))
+		} else {
+			result = append(result, orderColumn)
+		}
+	}
+	return result
+}
+
 // un-serialize object values
 func (it *DBCollection) modifyResultRow(row sqlite3.RowMap) sqlite3.RowMap {
 
@@ -189,6 +261,27 @@ func (it *DBCollection) updateFilterGroup(groupName string, columnName string, o
 	return nil
 }
 
+// getStoredVersion returns version of stored record, isStored is false if there is no record with given id
+func (it *DBCollection) getStoredVersion(id interface{}) (version int, isStored bool, err error) {
+	SQL := "SELECT `" + db.ConstVersionColumn + "` AS version FROM " + it.Name + " WHERE `_id`=" + convertValueForSQL(id)
+
+	stmt, err := connectionQuery(SQL)
+	defer closeStatement(stmt)
+
+	if err == nil {
+		row := make(sqlite3.RowMap)
+		if err = stmt.Scan(row); err == nil {
+			return utils.InterfaceToInt(row["version"]), true, nil
+		}
+	}
+
+	if err == io.EOF {
+		return 0, false, nil
+	}
+
+	return 0, false, sqlError(SQL, err)
+}
+
 // generates new UUID for _id column
 func (it *DBCollection) makeUUID(id string) string {
 
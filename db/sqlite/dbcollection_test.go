@@ -4,10 +4,14 @@ import (
 	"fmt"
 	"io"
 	"testing"
+	"time"
 
 	"github.com/mxk/go-sqlite/sqlite3"
 
 	"github.com/ottemo/commerce/api"
+	"github.com/ottemo/commerce/api/context"
+	"github.com/ottemo/commerce/db"
+	"github.com/ottemo/commerce/utils"
 
 	"github.com/ottemo/commerce/app/models"
 )
@@ -232,3 +236,158 @@ func TestApplyFilters(t *testing.T) {
 		fmt.Println(SQL)
 	}
 }
+
+func TestTransaction(t *testing.T) {
+	newConnection, err := sqlite3.Open(":memory:")
+	if err != nil {
+		t.Fatal("sqlite3.Open", err)
+	}
+	dbEngine.connection = newConnection
+
+	if err := dbEngine.BeginTransaction(); err == nil {
+		t.Error("transaction should not start without call context")
+	}
+
+	context.MakeContext(func() {
+		if err := dbEngine.CommitTransaction(); err == nil {
+			t.Error("commit should fail without started transaction")
+		}
+
+		if err := dbEngine.BeginTransaction(); err != nil {
+			t.Fatal("dbEngine.BeginTransaction", err)
+		}
+		if err := dbEngine.BeginTransaction(); err == nil {
+			t.Error("transaction should not start twice")
+		}
+
+		// statements outside of transaction should wait for it to finish
+		isCommitted := false
+		outsideDone := make(chan bool)
+		go func() {
+			if err := connectionExec("SELECT 1"); err != nil {
+				t.Error("connectionExec", err)
+			}
+			outsideDone <- isCommitted
+		}()
+
+		if err := connectionExec("SELECT 1"); err != nil {
+			t.Error("connectionExec", err)
+		}
+
+		isCommitted = true
+		if err := dbEngine.CommitTransaction(); err != nil {
+			t.Error("dbEngine.CommitTransaction", err)
+		}
+
+		if !<-outsideDone {
+			t.Error("statement outside of transaction was executed before commit")
+		}
+	})
+}
+
+func TestAggregate(t *testing.T) {
+	newConnection, err := sqlite3.Open(":memory:")
+	if err != nil {
+		t.Fatal("sqlite3.Open", err)
+	}
+	dbEngine.connection = newConnection
+
+	if err := dbEngine.AfterConnect(nil); err != nil {
+		t.Fatal("dbEngine.AfterConnect", err)
+	}
+	if err := dbEngine.CreateCollection("testAggregate"); err != nil {
+		t.Fatal("dbEngine.CreateCollection", err)
+	}
+
+	var dbCollection = &DBCollection{
+		Name:         "testAggregate",
+		FilterGroups: make(map[string]*StructDBFilterGroup),
+	}
+
+	for column, columnType := range map[string]string{"sku": "varchar(100)", "qty": "int", "price": "money", "created_at": "datetime"} {
+		if err := dbCollection.AddColumn(column, columnType, false); err != nil {
+			t.Fatal("dbCollection.AddColumn", err)
+		}
+	}
+
+	dayStart := time.Date(2019, 3, 10, 0, 0, 0, 0, time.UTC)
+	for _, record := range []map[string]interface{}{
+		{"sku": "a", "qty": 1, "price": 1.5, "created_at": dayStart.Add(time.Hour)},
+		{"sku": "a", "qty": 2, "price": 2.5, "created_at": dayStart.Add(2 * time.Hour)},
+		{"sku": "b", "qty": 5, "price": 10.0, "created_at": dayStart.Add(26 * time.Hour)},
+	} {
+		if _, err := dbCollection.Save(record); err != nil {
+			t.Fatal("dbCollection.Save", err)
+		}
+	}
+
+	result, err := dbCollection.Aggregate(
+		[]db.StructAggregateGroup{{Column: "created_at", Bucket: db.ConstAggregateBucketDay, Name: "day"}},
+		[]db.StructAggregate{{Function: db.ConstAggregateSum, Column: "qty"}, {Function: db.ConstAggregateCount}, {Function: db.ConstAggregateMax, Column: "sku"}})
+	if err != nil {
+		t.Fatal("dbCollection.Aggregate", err)
+	}
+
+	if len(result) != 2 {
+		t.Fatalf("expected 2 groups, got %v", result)
+	}
+	if day := utils.InterfaceToTime(result[0]["day"]); !day.Equal(dayStart) {
+		t.Errorf("unexpected day bucket %v", day)
+	}
+	if result[0]["sum_qty"] != 3.0 || result[0]["count"] != 2 || result[0]["max_sku"] != "a" {
+		t.Errorf("unexpected aggregation result %v", result[0])
+	}
+	if result[1]["sum_qty"] != 5.0 || result[1]["count"] != 1 {
+		t.Errorf("unexpected aggregation result %v", result[1])
+	}
+
+	if _, err := dbCollection.Aggregate(nil, []db.StructAggregate{{Function: "median", Column: "qty"}}); err == nil {
+		t.Error("unknown aggregate function should fail")
+	}
+}
+
+func TestRenameColumn(t *testing.T) {
+	newConnection, err := sqlite3.Open(":memory:")
+	if err != nil {
+		t.Fatal("sqlite3.Open", err)
+	}
+	dbEngine.connection = newConnection
+
+	if err := dbEngine.AfterConnect(nil); err != nil {
+		t.Fatal("dbEngine.AfterConnect", err)
+	}
+	if err := dbEngine.CreateCollection("testRename"); err != nil {
+		t.Fatal("dbEngine.CreateCollection", err)
+	}
+
+	var dbCollection = &DBCollection{
+		Name:         "testRename",
+		FilterGroups: make(map[string]*StructDBFilterGroup),
+	}
+
+	for _, column := range []string{"name", "name_short"} {
+		if err := dbCollection.AddColumn(column, "varchar(100)", false); err != nil {
+			t.Fatal("dbCollection.AddColumn", err)
+		}
+	}
+	if _, err := dbCollection.Save(map[string]interface{}{"name": "full", "name_short": "short"}); err != nil {
+		t.Fatal("dbCollection.Save", err)
+	}
+
+	// new column name starts with the old one, it should not be removed along with it
+	if err := db.RenameColumn(dbCollection, "name", "name_full"); err != nil {
+		t.Fatal("db.RenameColumn", err)
+	}
+
+	if dbCollection.HasColumn("name") || !dbCollection.HasColumn("name_full") || !dbCollection.HasColumn("name_short") {
+		t.Fatal("unexpected columns after rename:", dbCollection.ListColumns())
+	}
+
+	records, err := dbCollection.Load()
+	if err != nil {
+		t.Fatal("dbCollection.Load", err)
+	}
+	if len(records) != 1 || records[0]["name_full"] != "full" || records[0]["name_short"] != "short" {
+		t.Error("unexpected records after rename:", records)
+	}
+}
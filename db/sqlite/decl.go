@@ -21,6 +21,8 @@ const (
 
 	ConstCollectionNameColumnInfo = "collection_column_info" // table name to hold Ottemo types of columns
 
+	ConstContextKeyTransaction = "db.sqlite.transaction" // call context key to mark transaction owner
+
 	ConstErrorModule = "db/sqlite"
 	ConstErrorLevel  = env.ConstErrorLevelService
 )
@@ -51,6 +53,9 @@ type DBCollection struct {
 	Order         []string
 
 	Limit string
+
+	isCursorSet bool                   // keyset pagination mode, ref. to SetCursor(...)
+	lastRecord  map[string]interface{} // last iterated record, used to make next cursor
 }
 
 // DBEngine is a InterfaceDBEngine implementer
@@ -58,6 +63,9 @@ type DBEngine struct {
 	connection      *sqlite3.Conn
 	connectionMutex sync.RWMutex
 
+	// transactionMutex is exclusively locked while transaction is running
+	transactionMutex sync.RWMutex
+
 	attributeTypes      map[string]map[string]string
 	attributeTypesMutex sync.RWMutex
 
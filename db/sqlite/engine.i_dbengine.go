@@ -4,6 +4,7 @@ import (
 	"strconv"
 
 	"github.com/mxk/go-sqlite/sqlite3"
+	"github.com/ottemo/commerce/api/context"
 	"github.com/ottemo/commerce/db"
 	"github.com/ottemo/commerce/env"
 )
@@ -103,3 +104,58 @@ func (it *DBEngine) RawQuery(query string) (map[string]interface{}, error) {
 
 	return result[0], nil
 }
+
+// BeginTransaction starts a transaction bound to current call context, other routines statements are
+// waiting for the transaction to finish
+func (it *DBEngine) BeginTransaction() error {
+	if context.GetContext() == nil {
+		return env.ErrorNew(ConstErrorModule, ConstErrorLevel, "12837efe-6024-46b7-9561-1af74d48f27d", "transaction requires call context")
+	}
+	if isInTransaction() {
+		return env.ErrorNew(ConstErrorModule, ConstErrorLevel, "21eefb9b-71a7-4888-b7ca-53b8fef9fc87", "transaction already started")
+	}
+
+	it.transactionMutex.Lock()
+	context.SetContextValue(ConstContextKeyTransaction, true)
+
+	if err := connectionExec("BEGIN TRANSACTION"); err != nil {
+		context.SetContextValue(ConstContextKeyTransaction, false)
+		it.transactionMutex.Unlock()
+		return env.ErrorDispatch(err)
+	}
+
+	return nil
+}
+
+// CommitTransaction commits transaction started within current call context
+func (it *DBEngine) CommitTransaction() error {
+	if !isInTransaction() {
+		return env.ErrorNew(ConstErrorModule, ConstErrorLevel, "eacf3992-5941-4226-a4a1-1576dcb1fb9c", "there is no started transaction")
+	}
+
+	err := connectionExec("COMMIT TRANSACTION")
+	if err != nil {
+		if rollbackErr := connectionExec("ROLLBACK TRANSACTION"); rollbackErr != nil {
+			_ = env.ErrorDispatch(rollbackErr)
+		}
+	}
+
+	context.SetContextValue(ConstContextKeyTransaction, false)
+	it.transactionMutex.Unlock()
+
+	return env.ErrorDispatch(err)
+}
+
+// RollbackTransaction rolls back transaction started within current call context
+func (it *DBEngine) RollbackTransaction() error {
+	if !isInTransaction() {
+		return env.ErrorNew(ConstErrorModule, ConstErrorLevel, "e052bfb7-7ce5-468c-ae5d-73d9b0508eb8", "there is no started transaction")
+	}
+
+	err := connectionExec("ROLLBACK TRANSACTION")
+
+	context.SetContextValue(ConstContextKeyTransaction, false)
+	it.transactionMutex.Unlock()
+
+	return env.ErrorDispatch(err)
+}
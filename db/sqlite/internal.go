@@ -4,16 +4,41 @@ import (
 	"strings"
 
 	sqlite3 "github.com/mxk/go-sqlite/sqlite3"
+	"github.com/ottemo/commerce/api/context"
 	"github.com/ottemo/commerce/db"
 	"github.com/ottemo/commerce/env"
 	"github.com/ottemo/commerce/utils"
 	"time"
 )
 
+// isInTransaction checks if current call context have started a transaction
+func isInTransaction() bool {
+	return utils.InterfaceToBool(context.GetContextValue(ConstContextKeyTransaction))
+}
+
+// acquireConnection locks connection for exclusive usage, statements made outside of started transaction
+// are waiting for the transaction to finish as sqlite have only one connection shared between routines
+func acquireConnection() {
+	if !isInTransaction() {
+		dbEngine.transactionMutex.RLock()
+	}
+	dbEngine.connectionMutex.Lock()
+}
+
+// releaseConnection unlocks connection previously locked by acquireConnection
+func releaseConnection() {
+	dbEngine.connectionMutex.Unlock()
+	if !isInTransaction() {
+		dbEngine.transactionMutex.RUnlock()
+	}
+}
+
 // exec routines
 func connectionExecWLastInsertID(SQL string, args ...interface{}) (int64, error) {
-	dbEngine.connectionMutex.Lock()
-	defer dbEngine.connectionMutex.Unlock()
+	defer db.ObserveQuery(dbEngine.GetName(), db.GetQueryOperation(SQL), time.Now())
+
+	acquireConnection()
+	defer releaseConnection()
 
 	err := dbEngine.connection.Exec(SQL, args...)
 	if err != nil {
@@ -25,8 +50,10 @@ func connectionExecWLastInsertID(SQL string, args ...interface{}) (int64, error)
 
 // exec routines
 func connectionExecWAffected(SQL string, args ...interface{}) (int, error) {
-	dbEngine.connectionMutex.Lock()
-	defer dbEngine.connectionMutex.Unlock()
+	defer db.ObserveQuery(dbEngine.GetName(), db.GetQueryOperation(SQL), time.Now())
+
+	acquireConnection()
+	defer releaseConnection()
 
 	if ConstDebugSQL {
 		env.Log("sqlite.log", env.ConstLogPrefixInfo, SQL)
@@ -42,8 +69,10 @@ func connectionExecWAffected(SQL string, args ...interface{}) (int, error) {
 
 // exec routines
 func connectionExec(SQL string, args ...interface{}) error {
-	dbEngine.connectionMutex.Lock()
-	defer dbEngine.connectionMutex.Unlock()
+	defer db.ObserveQuery(dbEngine.GetName(), db.GetQueryOperation(SQL), time.Now())
+
+	acquireConnection()
+	defer releaseConnection()
 
 	if ConstDebugSQL {
 		env.Log("sqlite.log", env.ConstLogPrefixInfo, SQL)
@@ -54,7 +83,9 @@ func connectionExec(SQL string, args ...interface{}) error {
 
 // query routines
 func connectionQuery(SQL string) (*sqlite3.Stmt, error) {
-	dbEngine.connectionMutex.Lock()
+	defer db.ObserveQuery(dbEngine.GetName(), db.GetQueryOperation(SQL), time.Now())
+
+	acquireConnection()
 
 	if ConstDebugSQL {
 		env.Log("sqlite.log", env.ConstLogPrefixInfo, SQL)
@@ -70,7 +101,7 @@ func closeStatement(statement *sqlite3.Stmt) {
 			_ = env.ErrorDispatch(err)
 		}
 	}
-	dbEngine.connectionMutex.Unlock()
+	releaseConnection()
 }
 
 // formats SQL query error for output to log
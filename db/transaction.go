@@ -0,0 +1,136 @@
+package db
+
+import (
+	"github.com/ottemo/commerce/api/context"
+	"github.com/ottemo/commerce/env"
+	"github.com/ottemo/commerce/utils"
+)
+
+// InTransaction executes given function within a database transaction, the transaction commits if function
+// returns nil and rolls back on error or panic
+//   - transaction is bound to the call context (refer "api/context" package), so all the collection operations
+//     made within the function and its sub-calls are the part of transaction
+//   - nested InTransaction calls are joining the outer transaction, an error within nested call makes
+//     the outer transaction to roll back even if the error was not passed through
+func InTransaction(transactionFunc func() error) error {
+	dbEngine := GetDBEngine()
+	if dbEngine == nil {
+		return env.ErrorNew(ConstErrorModule, ConstErrorLevel, "2f6ae986-0f06-4975-94d0-f040567de441", "Can't get DBEngine")
+	}
+
+	// transaction requires the call context to be bound to
+	if context.GetContext() == nil {
+		var err error
+		context.MakeContext(func() {
+			err = InTransaction(transactionFunc)
+		})
+		return err
+	}
+
+	// joining already started transaction
+	depth := utils.InterfaceToInt(context.GetContextValue(ConstContextKeyTransactionDepth))
+	if depth > 0 {
+		context.SetContextValue(ConstContextKeyTransactionDepth, depth+1)
+		defer context.SetContextValue(ConstContextKeyTransactionDepth, depth)
+
+		err := transactionFunc()
+		if err != nil {
+			context.SetContextValue(ConstContextKeyTransactionRollback, true)
+		}
+		return err
+	}
+
+	if err := dbEngine.BeginTransaction(); err != nil {
+		return env.ErrorDispatch(err)
+	}
+
+	context.SetContextValue(ConstContextKeyTransactionDepth, 1)
+	context.SetContextValue(ConstContextKeyTransactionRollback, false)
+	context.SetContextValue(ConstContextKeyTransactionCommit, nil)
+
+	isFinished := false
+	defer func() {
+		context.SetContextValue(ConstContextKeyTransactionDepth, 0)
+		context.SetContextValue(ConstContextKeyTransactionCommit, nil)
+
+		// function panic - rolling back transaction and passing panic further
+		if !isFinished {
+			if err := dbEngine.RollbackTransaction(); err != nil {
+				_ = env.ErrorDispatch(err)
+			}
+		}
+	}()
+
+	err := transactionFunc()
+	isFinished = true
+
+	if err == nil && utils.InterfaceToBool(context.GetContextValue(ConstContextKeyTransactionRollback)) {
+		err = env.ErrorNew(ConstErrorModule, ConstErrorLevel, "6863b1df-0141-47be-b557-5801d08ce705", "transaction was marked for rollback by a nested call")
+	}
+
+	if err != nil {
+		if rollbackErr := dbEngine.RollbackTransaction(); rollbackErr != nil {
+			_ = env.ErrorDispatch(rollbackErr)
+		}
+		return err
+	}
+
+	if err := dbEngine.CommitTransaction(); err != nil {
+		return env.ErrorDispatch(err)
+	}
+
+	// functions could start new transactions, so the finished one is unbound from context before call
+	afterCommit, _ := context.GetContextValue(ConstContextKeyTransactionCommit).([]func())
+	context.SetContextValue(ConstContextKeyTransactionCommit, nil)
+	context.SetContextValue(ConstContextKeyTransactionDepth, 0)
+	for _, afterCommitFunc := range afterCommit {
+		afterCommitFunc()
+	}
+
+	return nil
+}
+
+// AfterCommit calls given function after current transaction commit, function is not called if transaction rolls back
+//   - function is called immediately if there is no started transaction
+//   - it is a place for notifications and other effects which should not be visible for not committed changes
+func AfterCommit(afterCommitFunc func()) {
+	if !IsInTransaction() {
+		afterCommitFunc()
+		return
+	}
+
+	afterCommit, _ := context.GetContextValue(ConstContextKeyTransactionCommit).([]func())
+	context.SetContextValue(ConstContextKeyTransactionCommit, append(afterCommit, afterCommitFunc))
+}
+
+// IsInTransaction returns true if current call context has started transaction
+func IsInTransaction() bool {
+	return utils.InterfaceToInt(context.GetContextValue(ConstContextKeyTransactionDepth)) > 0
+}
+
+// ReadFromPrimary executes given function with collection reads routed to primary database instead of read replicas
+//   - to be used by read-modify-write code which has to see the latest writes but does not need a transaction
+//   - reads within transaction are made on primary anyway
+func ReadFromPrimary(readFunc func() error) error {
+	if context.GetContext() == nil {
+		var err error
+		context.MakeContext(func() {
+			err = ReadFromPrimary(readFunc)
+		})
+		return err
+	}
+
+	if IsReadingFromPrimary() {
+		return readFunc()
+	}
+
+	context.SetContextValue(ConstContextKeyReadPrimary, true)
+	defer context.SetContextValue(ConstContextKeyReadPrimary, false)
+
+	return readFunc()
+}
+
+// IsReadingFromPrimary returns true if current call context reads have to be made on primary database
+func IsReadingFromPrimary() bool {
+	return IsInTransaction() || utils.InterfaceToBool(context.GetContextValue(ConstContextKeyReadPrimary))
+}
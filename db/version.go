@@ -0,0 +1,32 @@
+package db
+
+import (
+	"github.com/ottemo/commerce/env"
+	"github.com/ottemo/commerce/utils"
+)
+
+// AddVersionColumn enables optimistic concurrency control for collection records
+//   - collection "Save" maintains ConstVersionColumn increasing it on each record write and returns it within item
+//   - item with non zero version is saved only if stored record has the same version, otherwise version conflict
+//     error is returned, ref. to IsVersionConflict(...)
+//   - item without version overwrites stored record
+//   - item with ConstVersionAbsent version is saved only if there is no stored record
+func AddVersionColumn(collection InterfaceDBCollection) error {
+	return collection.AddColumn(ConstVersionColumn, ConstTypeInteger, false)
+}
+
+// GetRecordVersion returns version record was read with, 0 means record has no version
+func GetRecordVersion(record map[string]interface{}) int {
+	return utils.InterfaceToInt(record[ConstVersionColumn])
+}
+
+// NewVersionConflictError returns error about collection record which was changed after it was read
+func NewVersionConflictError(collectionName string, id string) error {
+	return env.ErrorNew(ConstErrorModule, env.ConstErrorLevelAPI, ConstErrorCodeVersionConflict,
+		"'"+collectionName+"' record '"+id+"' was changed by someone else, please reload it and try again")
+}
+
+// IsVersionConflict checks error to be caused by a write over outdated record version
+func IsVersionConflict(err error) bool {
+	return err != nil && env.ErrorCode(err) == ConstErrorCodeVersionConflict
+}
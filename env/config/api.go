@@ -1,9 +1,13 @@
 package config
 
 import (
+	"encoding/json"
+	"io/ioutil"
+	"sort"
 	"strings"
 
 	"github.com/ottemo/commerce/api"
+	"github.com/ottemo/commerce/app/models"
 	"github.com/ottemo/commerce/env"
 	"github.com/ottemo/commerce/utils"
 )
@@ -17,12 +21,18 @@ func setupAPI() error {
 	service.GET("config/value/:path", restConfigGet)
 
 	// Admin Only
-	service.GET("config/item/:path", api.IsAdminHandler(restConfigInfo))
-	service.GET("config/values", api.IsAdminHandler(restConfigList))
-	service.GET("config/values/refresh", api.IsAdminHandler(restConfigReload))
-	service.POST("config/value/:path", api.IsAdminHandler(restConfigRegister))
-	service.PUT("config/value/:path", api.IsAdminHandler(restConfigSet))
-	service.DELETE("config/value/:path", api.IsAdminHandler(restConfigUnRegister))
+	service.GET("config/item/:path", api.PermissionHandler(ConstPermissionRead, restConfigInfo))
+	service.GET("config/values", api.PermissionHandler(ConstPermissionRead, restConfigList))
+	service.GET("config/values/refresh", api.PermissionHandler(ConstPermissionWrite, restConfigReload))
+	service.POST("config/value/:path", api.PermissionHandler(ConstPermissionWrite, restConfigRegister))
+	service.PUT("config/value/:path", api.PermissionHandler(ConstPermissionWrite, restConfigSet))
+	service.DELETE("config/value/:path", api.PermissionHandler(ConstPermissionWrite, restConfigUnRegister))
+	service.GET("config/history/:path", api.PermissionHandler(ConstPermissionRead, restConfigHistory))
+	service.POST("config/revert/:historyID", api.PermissionHandler(ConstPermissionWrite, restConfigRevert))
+	service.GET("config/export", api.PermissionHandler(ConstPermissionRead, restConfigExport))
+	service.POST("config/export", api.PermissionHandler(ConstPermissionRead, restConfigExport))
+	service.POST("config/import", api.PermissionHandler(ConstPermissionWrite, restConfigImport))
+	service.GET("config/sources", api.PermissionHandler(ConstPermissionRead, restConfigSources))
 
 	return nil
 }
@@ -64,7 +74,7 @@ func restConfigGet(context api.InterfaceApplicationContext) (interface{}, error)
 			strings.Contains(itemInfo.Path, "admin") {
 
 			// check rights
-			if !api.IsAdminSession(context) {
+			if !api.HasPermission(context, ConstPermissionRead) {
 				return nil, env.ErrorNew(ConstErrorModule, env.ConstErrorLevelAPI, "c7724469-8acb-41f4-a031-08b016053b58", "Operation not allowed.")
 			}
 		}
@@ -154,3 +164,167 @@ func restConfigReload(context api.InterfaceApplicationContext) (interface{}, err
 
 	return "ok", nil
 }
+
+// WEB REST API used to get changes history of particular config item, recent first
+//   - "limit" argument limits result, "offset,limit" form is also supported
+func restConfigHistory(context api.InterfaceApplicationContext) (interface{}, error) {
+	offset, limit := models.GetListLimit(context)
+
+	result, err := getHistory(context.GetRequestArgument("path"), offset, limit)
+	if err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
+	return result, nil
+}
+
+// WEB REST API used to revert config item to the value it had before particular change
+func restConfigRevert(context api.InterfaceApplicationContext) (interface{}, error) {
+	config, ok := env.GetConfig().(*DefaultConfig)
+	if !ok {
+		return nil, env.ErrorNew(ConstErrorModule, ConstErrorLevel, "3c2b53a0-39a1-41e6-857d-5d0735028d95", "config history is not supported by current config service")
+	}
+
+	path, err := config.revertChange(context.GetRequestArgument("historyID"))
+	if err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
+	return config.GetValue(path), nil
+}
+
+// WEB REST API used to export config values to a document which could be imported to other environment
+//   - "path" argument limits export to config subtree, whole config is exported if it is blank
+//   - "format" argument could be "json" (default) or "yaml"
+//   - secret values are exported encrypted with "passphrase" content value, they are skipped without it
+func restConfigExport(context api.InterfaceApplicationContext) (interface{}, error) {
+	config, ok := env.GetConfig().(*DefaultConfig)
+	if !ok {
+		return nil, env.ErrorNew(ConstErrorModule, ConstErrorLevel, "951236a0-f022-4e97-b27c-e6910bf67d4b", "config export is not supported by current config service")
+	}
+
+	format := strings.ToLower(utils.InterfaceToString(api.GetArgumentOrContentValue(context, "format")))
+	path := utils.InterfaceToString(api.GetArgumentOrContentValue(context, "path"))
+	passphrase := utils.InterfaceToString(api.GetContentValue(context, "passphrase"))
+
+	result, err := config.exportDocument(path, format, passphrase)
+	if err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
+	contentType, extension := "application/json", ConstFormatJSON
+	if format == ConstFormatYAML {
+		contentType, extension = "application/x-yaml", ConstFormatYAML
+	}
+
+	if err := context.SetResponseContentType(contentType); err != nil {
+		_ = env.ErrorDispatch(err)
+	}
+	if err := context.SetResponseSetting("Content-disposition", "attachment;filename=config_export."+extension); err != nil {
+		_ = env.ErrorDispatch(err)
+	}
+
+	return result, nil
+}
+
+// WEB REST API used to preview and apply config document made by export
+//   - document is taken from attached file or "document" content value, raw request content is used otherwise
+//   - "format" argument could be "json" (default) or "yaml"
+//   - "passphrase" is required if document has secret values
+//   - changes are applied only if "apply" argument is true, otherwise the changes document would make are returned
+func restConfigImport(context api.InterfaceApplicationContext) (interface{}, error) {
+	config, ok := env.GetConfig().(*DefaultConfig)
+	if !ok {
+		return nil, env.ErrorNew(ConstErrorModule, ConstErrorLevel, "bebf6aed-b91a-4f29-90a9-e13908ae6688", "config import is not supported by current config service")
+	}
+
+	format := strings.ToLower(utils.InterfaceToString(api.GetArgumentOrContentValue(context, "format")))
+	passphrase := utils.InterfaceToString(api.GetArgumentOrContentValue(context, "passphrase"))
+	apply := utils.InterfaceToBool(api.GetArgumentOrContentValue(context, "apply"))
+
+	var data []byte
+	for fileName, attachedFile := range context.GetRequestFiles() {
+		content, err := ioutil.ReadAll(attachedFile)
+		if err != nil {
+			return nil, env.ErrorDispatch(err)
+		}
+		if format == "" && (strings.HasSuffix(fileName, ".yaml") || strings.HasSuffix(fileName, ".yml")) {
+			format = ConstFormatYAML
+		}
+		data = content
+		break
+	}
+
+	if data == nil {
+		content := context.GetRequestContent()
+		if document := api.GetContentValue(context, "document"); document != nil {
+			content = document
+		}
+
+		switch typedContent := content.(type) {
+		case string:
+			data = []byte(typedContent)
+		case []byte:
+			data = typedContent
+		default:
+			var err error
+			if data, err = json.Marshal(typedContent); err != nil {
+				return nil, env.ErrorDispatch(err)
+			}
+			format = ConstFormatJSON
+		}
+	}
+
+	document, err := parseDocument(data, format)
+	if err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
+	changes, err := config.importDocument(document, passphrase, apply)
+	if err != nil {
+		context.SetResponseStatusBadRequest()
+		return map[string]interface{}{"changes": changes, "applied": false}, env.ErrorDispatch(err)
+	}
+
+	return map[string]interface{}{"changes": changes, "applied": apply}, nil
+}
+
+// WEB REST API used to show where effective ini and config values are taken from
+//   - values are not shown, only their source and environment variable which could override them
+func restConfigSources(context api.InterfaceApplicationContext) (interface{}, error) {
+	var iniSources []map[string]interface{}
+	if iniConfig := env.GetIniConfig(); iniConfig != nil {
+		names := iniConfig.ListItems()
+		sort.Strings(names)
+
+		for _, name := range names {
+			item := map[string]interface{}{
+				"name":     name,
+				"variable": env.GetEnvironmentVariableName(env.ConstEnvironmentPrefixIni, name),
+			}
+			if sources, ok := iniConfig.(env.InterfaceValueSources); ok {
+				item["source"] = sources.GetValueSource(name)
+			}
+			iniSources = append(iniSources, item)
+		}
+	}
+
+	var configSources []map[string]interface{}
+	if config := env.GetConfig(); config != nil {
+		paths := config.ListPathes()
+		sort.Strings(paths)
+
+		for _, path := range paths {
+			item := map[string]interface{}{
+				"path":     path,
+				"variable": env.GetEnvironmentVariableName(env.ConstEnvironmentPrefixConfig, path),
+			}
+			if sources, ok := config.(env.InterfaceValueSources); ok {
+				item["source"] = sources.GetValueSource(path)
+			}
+			configSources = append(configSources, item)
+		}
+	}
+
+	return map[string]interface{}{"ini": iniSources, "config": configSources}, nil
+}
@@ -0,0 +1,250 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/ottemo/commerce/db"
+	"github.com/ottemo/commerce/env"
+
+	_ "github.com/ottemo/commerce/db/memory"
+)
+
+// newTestConfig makes config over empty db collections with given items registered
+func newTestConfig(t *testing.T, items ...env.StructConfigItem) *DefaultConfig {
+	if err := setupDB(); err != nil {
+		t.Fatal("setupDB", err)
+	}
+
+	for _, collectionName := range []string{ConstCollectionNameConfig, ConstCollectionNameConfigHistory} {
+		collection, err := db.GetCollection(collectionName)
+		if err != nil {
+			t.Fatal("db.GetCollection", err)
+		}
+		if _, err := collection.Delete(); err != nil {
+			t.Fatal("collection.Delete", err)
+		}
+	}
+
+	config := &DefaultConfig{
+		configValues:     make(map[string]interface{}),
+		configTypes:      make(map[string]string),
+		configValidators: make(map[string]env.FuncConfigValueValidator),
+		configOverrides:  make(map[string]interface{})}
+
+	for _, item := range items {
+		if err := config.RegisterItem(item, nil); err != nil {
+			t.Fatal("RegisterItem", err)
+		}
+	}
+
+	return config
+}
+
+func TestHistoryAndRevert(t *testing.T) {
+	config := newTestConfig(t, env.StructConfigItem{Path: "test.rate", Value: "1", Type: env.ConstConfigTypeVarchar})
+
+	for _, value := range []string{"2", "2", "3"} {
+		if err := config.SetValue("test.rate", value); err != nil {
+			t.Fatal("SetValue", err)
+		}
+	}
+
+	history, err := getHistory("test.rate", 0, 0)
+	if err != nil {
+		t.Fatal("getHistory", err)
+	}
+	if len(history) != 2 {
+		t.Fatalf("unexpected history %v", history)
+	}
+
+	var lastChangeID string
+	for _, record := range history {
+		if record["new_value"] == "3" {
+			lastChangeID, _ = record["_id"].(string)
+		}
+	}
+	if lastChangeID == "" {
+		t.Fatalf("last change not found in %v", history)
+	}
+
+	path, err := config.revertChange(lastChangeID)
+	if err != nil {
+		t.Fatal("revertChange", err)
+	}
+	if path != "test.rate" || config.GetValue(path) != "2" {
+		t.Errorf("unexpected value %v after revert of %s", config.GetValue(path), path)
+	}
+
+	if history, _ := getHistory("test.rate", 0, 0); len(history) != 3 {
+		t.Errorf("revert is not recorded to history %v", history)
+	}
+}
+
+func TestHistorySecretMask(t *testing.T) {
+	config := newTestConfig(t, env.StructConfigItem{Path: "test.key", Value: "old", Type: env.ConstConfigTypeSecret})
+
+	if err := config.SetValue("test.key", "new"); err != nil {
+		t.Fatal("SetValue", err)
+	}
+
+	history, err := getHistory("test.key", 0, 0)
+	if err != nil {
+		t.Fatal("getHistory", err)
+	}
+	if len(history) != 1 || history[0]["old_value"] != ConstSecretValueMask || history[0]["new_value"] != ConstSecretValueMask {
+		t.Fatalf("unexpected history %v", history)
+	}
+
+	if _, err := config.revertChange(history[0]["_id"].(string)); err != nil {
+		t.Fatal("revertChange", err)
+	}
+	if value := config.GetValue("test.key"); value != "old" {
+		t.Errorf("unexpected secret value %v after revert", value)
+	}
+}
+
+func TestExportImport(t *testing.T) {
+	items := []env.StructConfigItem{
+		{Path: "shipping", Type: env.ConstConfigTypeGroup},
+		{Path: "shipping.rate", Value: 5, Type: env.ConstConfigTypeInteger},
+		{Path: "shipping.key", Value: "staging key", Type: env.ConstConfigTypeSecret},
+		{Path: "tax.priority", Value: "low", Type: env.ConstConfigTypeVarchar},
+	}
+
+	for _, format := range []string{ConstFormatJSON, ConstFormatYAML} {
+		source := newTestConfig(t, items...)
+		data, err := source.exportDocument("shipping", format, "pass")
+		if err != nil {
+			t.Fatal("exportDocument", err)
+		}
+
+		document, err := parseDocument(data, format)
+		if err != nil {
+			t.Fatal("parseDocument", err)
+		}
+		if len(document.Items) != 1 || len(document.Secrets) != 1 {
+			t.Fatalf("unexpected %s document %s", format, data)
+		}
+
+		target := newTestConfig(t, items...)
+		if err := target.SetValue("shipping.rate", 7); err != nil {
+			t.Fatal("SetValue", err)
+		}
+		if err := target.SetValue("shipping.key", "production key"); err != nil {
+			t.Fatal("SetValue", err)
+		}
+
+		if _, err := target.importDocument(document, "wrong", false); err == nil {
+			t.Error("secrets were decrypted with wrong passphrase")
+		}
+
+		changes, err := target.importDocument(document, "pass", false)
+		if err != nil {
+			t.Fatal("importDocument", err)
+		}
+		if len(changes) != 2 || changes[0].Path != "shipping.key" || changes[0].NewValue != ConstSecretValueMask || changes[1].NewValue != 5 {
+			t.Errorf("unexpected changes %v", changes)
+		}
+		if target.GetValue("shipping.rate") != 7 {
+			t.Error("preview changed config value")
+		}
+
+		if _, err := target.importDocument(document, "pass", true); err != nil {
+			t.Fatal("importDocument", err)
+		}
+		if target.GetValue("shipping.rate") != 5 || target.GetValue("shipping.key") != "staging key" {
+			t.Errorf("values were not imported: %v, %v", target.GetValue("shipping.rate"), target.GetValue("shipping.key"))
+		}
+	}
+}
+
+func TestImportValidation(t *testing.T) {
+	config := newTestConfig(t, env.StructConfigItem{Path: "tax.priority", Value: "low", Type: env.ConstConfigTypeVarchar})
+	config.configValidators["tax.priority"] = func(value interface{}) (interface{}, error) {
+		if value != "low" && value != "high" {
+			return nil, errors.New("unknown priority")
+		}
+		return value, nil
+	}
+
+	document := &StructConfigDocument{Items: map[string]interface{}{"tax.priority": "medium", "unknown.path": 1}}
+	changes, err := config.importDocument(document, "", true)
+	if err == nil || len(changes) != 2 || changes[0].Error == "" || changes[1].Error == "" {
+		t.Fatalf("invalid document was not rejected: %v, %v", changes, err)
+	}
+	if config.GetValue("tax.priority") != "low" {
+		t.Error("invalid document changed config value")
+	}
+}
+
+func TestEnvironmentOverride(t *testing.T) {
+	variableName := env.GetEnvironmentVariableName(env.ConstEnvironmentPrefixConfig, "payment.test-gateway.rate")
+	if variableName != "OTTEMO_CONFIG_PAYMENT_TEST_GATEWAY_RATE" {
+		t.Fatalf("unexpected variable name %s", variableName)
+	}
+
+	if err := os.Setenv(variableName, "15"); err != nil {
+		t.Fatal("os.Setenv", err)
+	}
+	defer os.Unsetenv(variableName)
+
+	config := newTestConfig(t, env.StructConfigItem{Path: "payment.test-gateway.rate", Value: 10, Type: env.ConstConfigTypeInteger})
+	if value := config.GetValue("payment.test-gateway.rate"); value != 15 {
+		t.Errorf("unexpected overridden value %v", value)
+	}
+	if source := config.GetValueSource("payment.test-gateway.rate"); source != env.ConstValueSourceEnvironment {
+		t.Errorf("unexpected value source %s", source)
+	}
+
+	if err := config.SetValue("payment.test-gateway.rate", 20); err != nil {
+		t.Fatal("SetValue", err)
+	}
+	if value := config.GetValue("payment.test-gateway.rate"); value != 15 {
+		t.Errorf("stored value %v takes precedence over environment", value)
+	}
+
+	if err := os.Unsetenv(variableName); err != nil {
+		t.Fatal("os.Unsetenv", err)
+	}
+	config.loadOverrides()
+	if value := config.GetValue("payment.test-gateway.rate"); value != 20 || config.GetValueSource("payment.test-gateway.rate") != env.ConstValueSourceDatabase {
+		t.Errorf("unexpected stored value %v", value)
+	}
+}
+
+func TestImportRollback(t *testing.T) {
+	config := newTestConfig(t,
+		env.StructConfigItem{Path: "a.first", Value: "old", Type: env.ConstConfigTypeVarchar},
+		env.StructConfigItem{Path: "b.second", Value: "old", Type: env.ConstConfigTypeVarchar})
+
+	// validator accepts value on import check and fails when value is applied
+	calls := 0
+	config.configValidators["b.second"] = func(value interface{}) (interface{}, error) {
+		if calls++; calls > 1 {
+			return nil, errors.New("storage failure")
+		}
+		return value, nil
+	}
+
+	document := &StructConfigDocument{Items: map[string]interface{}{"a.first": "new", "b.second": "new"}}
+	if _, err := config.importDocument(document, "", true); err == nil {
+		t.Fatal("import failure expected")
+	}
+
+	if config.GetValue("a.first") != "old" {
+		t.Errorf("partially applied import was not reverted: %v", config.GetValue("a.first"))
+	}
+
+	collection, err := db.GetCollection(ConstCollectionNameConfig)
+	if err != nil {
+		t.Fatal("db.GetCollection", err)
+	}
+	if err := collection.AddFilter("path", "=", "a.first"); err != nil {
+		t.Fatal("collection.AddFilter", err)
+	}
+	if records, err := collection.Load(); err != nil || len(records) != 1 || records[0]["value"] != "old" {
+		t.Errorf("partially applied import was not reverted in db: %v (%v)", records, err)
+	}
+}
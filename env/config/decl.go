@@ -1,12 +1,32 @@
 package config
 
 import (
+	"time"
+
 	"github.com/ottemo/commerce/env"
 )
 
 // Package global constants
 const (
-	ConstCollectionNameConfig = "config"
+	ConstCollectionNameConfig        = "config"
+	ConstCollectionNameConfigHistory = "config_history"
+
+	ConstSessionKeyVisitorID = "visitor_id" // session key of signed in visitor, the same as "app/models/visitor" uses
+	ConstSecretValueMask     = "******"     // replacement of secret values within change history and import preview
+
+	ConstFormatJSON      = "json"
+	ConstFormatYAML      = "yaml"
+	ConstDocumentVersion = 2 // version of export document structure
+
+	// secrets of export document are encrypted with AES-GCM key derived from passphrase and random salt by scrypt
+	ConstSecretSaltSize = 16
+	ConstSecretKeySize  = 32
+	ConstSecretScryptN  = 32768
+	ConstSecretScryptR  = 8
+	ConstSecretScryptP  = 1
+
+	ConstPermissionRead  = "config.read"
+	ConstPermissionWrite = "config.write"
 
 	ConstErrorModule = "env/config"
 	ConstErrorLevel  = env.ConstErrorLevelService
@@ -17,4 +37,24 @@ type DefaultConfig struct {
 	configValues     map[string]interface{}
 	configTypes      map[string]string
 	configValidators map[string]env.FuncConfigValueValidator
+	configOverrides  map[string]interface{} // values taken from environment variables
+}
+
+// StructConfigDocument is a config export document, it could be imported to other environment
+//   - secret values are encrypted with passphrase given on export, they are absent if passphrase was not given
+type StructConfigDocument struct {
+	Version    int                    `json:"version" yaml:"version"`
+	ExportedAt time.Time              `json:"exported_at" yaml:"exported_at"`
+	Items      map[string]interface{} `json:"items" yaml:"items"`
+	Secrets    map[string]string      `json:"secrets,omitempty" yaml:"secrets,omitempty"`
+
+	SecretsSalt string `json:"secrets_salt,omitempty" yaml:"secrets_salt,omitempty"` // base64 encoded salt of secrets key
+}
+
+// StructConfigChange is a change config document import makes, or error preventing the import
+type StructConfigChange struct {
+	Path     string      `json:"path"`
+	OldValue interface{} `json:"old_value"`
+	NewValue interface{} `json:"new_value"`
+	Error    string      `json:"error,omitempty"`
 }
@@ -26,6 +26,20 @@ of functions config.GetGroupItems() and config.GetItemsInfo() you can filter gro
 
 Each config value can have validator function associated, which can also modify value puring verification.
 
+Each value change is stored to "config_history" collection with old and new values, visitor made the change and time,
+so history of config path could be reviewed ("config/history/:path") and a change could be reverted ("config/revert/:id").
+
+Config value could be overridden with environment variable named in the same way as for ini values but with
+"OTTEMO_CONFIG_" prefix, so "payment.paypal.user" is overridden with OTTEMO_CONFIG_PAYMENT_PAYPAL_USER. Variable value
+is converted to config item type and checked with item validator. It takes precedence over value stored in database,
+which still could be changed and becomes effective once variable is removed. Source of each effective ini and config
+value is shown by "config/sources" API.
+
+Config values (or a subtree of them) could be exported to JSON or YAML document ("config/export") and imported to other
+environment ("config/import"). Secret values are exported only if passphrase is given, they are encrypted with a key
+derived from it by scrypt with random salt kept in the document. Import returns changes document would make and applies
+them only if requested, values are checked with item validators before and applied all together within transaction.
+
 To be more consistent and clear it is highly recommended to declare config value paths as a package constants.
 
     Example 1:
@@ -0,0 +1,59 @@
+package config
+
+import (
+	"os"
+
+	"github.com/ottemo/commerce/db"
+	"github.com/ottemo/commerce/env"
+)
+
+// loadOverride takes config item value from environment variable if it is set
+//   - value is converted to config item type and checked with item validator, invalid value is ignored
+func (it *DefaultConfig) loadOverride(path string) {
+	delete(it.configOverrides, path)
+
+	variableName := env.GetEnvironmentVariableName(env.ConstEnvironmentPrefixConfig, path)
+	variableValue, present := os.LookupEnv(variableName)
+	if !present {
+		return
+	}
+
+	valueType, present := it.configTypes[path]
+	if !present || valueType == env.ConstConfigTypeGroup {
+		return
+	}
+
+	var value interface{} = variableValue
+	if valueType != env.ConstConfigTypeSecret {
+		value = db.ConvertTypeFromDbToGo(variableValue, valueType)
+	}
+
+	if validator, present := it.configValidators[path]; present && validator != nil {
+		newValue, err := validator(value)
+		if err != nil {
+			_ = env.ErrorNew(ConstErrorModule, ConstErrorLevel, "c174dabc-7531-4c7c-a6f3-995a9427b7c2", "environment variable "+variableName+" is ignored: "+err.Error())
+			return
+		}
+		value = newValue
+	}
+
+	it.configOverrides[path] = value
+}
+
+// loadOverrides takes values of all config items from environment variables
+func (it *DefaultConfig) loadOverrides() {
+	for path := range it.configTypes {
+		it.loadOverride(path)
+	}
+}
+
+// GetValueSource returns where GetValue takes value from: environment or database, blank for unknown path
+func (it *DefaultConfig) GetValueSource(path string) string {
+	if _, present := it.configOverrides[path]; present {
+		return env.ConstValueSourceEnvironment
+	}
+	if _, present := it.configValues[path]; present {
+		return env.ConstValueSourceDatabase
+	}
+	return ""
+}
@@ -0,0 +1,117 @@
+package config
+
+import (
+	"time"
+
+	"github.com/ottemo/commerce/api"
+	"github.com/ottemo/commerce/api/context"
+	"github.com/ottemo/commerce/db"
+	"github.com/ottemo/commerce/env"
+	"github.com/ottemo/commerce/utils"
+)
+
+// recordChange stores config value change to history
+//   - values are stored as they are kept in config, so secret values are encrypted
+//   - change author is taken from API request current call is made for, it is blank for system changes
+func (it *DefaultConfig) recordChange(path string, oldValue interface{}, newValue interface{}) error {
+	valueType := it.configTypes[path]
+
+	if valueType == env.ConstConfigTypeSecret {
+		if utils.DecryptString(utils.InterfaceToString(oldValue)) == utils.DecryptString(utils.InterfaceToString(newValue)) {
+			return nil
+		}
+	} else if utils.InterfaceToString(oldValue) == utils.InterfaceToString(newValue) {
+		return nil
+	}
+
+	collection, err := db.GetCollection(ConstCollectionNameConfigHistory)
+	if err != nil {
+		return env.ErrorDispatch(err)
+	}
+
+	record := map[string]interface{}{
+		"path":       path,
+		"type":       valueType,
+		"old_value":  oldValue,
+		"new_value":  newValue,
+		"request_id": context.GetRequestID(),
+		"created_at": time.Now(),
+	}
+
+	if applicationContext := api.GetCurrentApplicationContext(); applicationContext != nil {
+		if session := applicationContext.GetSession(); session != nil {
+			record["visitor_id"] = utils.InterfaceToString(session.Get(ConstSessionKeyVisitorID))
+		}
+		record["admin"] = api.IsAdminSession(applicationContext)
+	}
+
+	if _, err := collection.Save(record); err != nil {
+		return env.ErrorDispatch(err)
+	}
+
+	return nil
+}
+
+// getHistory returns changes of config path, recent first
+//   - secret values are masked
+func getHistory(path string, offset int, limit int) ([]map[string]interface{}, error) {
+	collection, err := db.GetCollection(ConstCollectionNameConfigHistory)
+	if err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
+	if err := collection.AddFilter("path", "=", path); err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+	if err := collection.AddSort("created_at", true); err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+	if limit > 0 {
+		if err := collection.SetLimit(offset, limit); err != nil {
+			return nil, env.ErrorDispatch(err)
+		}
+	}
+
+	records, err := collection.Load()
+	if err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
+	for _, record := range records {
+		if record["type"] == env.ConstConfigTypeSecret {
+			record["old_value"] = ConstSecretValueMask
+			record["new_value"] = ConstSecretValueMask
+		}
+	}
+
+	return records, nil
+}
+
+// revertChange sets config path of history record back to value it had before the change
+//   - revert is a config change itself, so it is recorded to history too
+//   - returns reverted config path
+func (it *DefaultConfig) revertChange(historyID string) (string, error) {
+	collection, err := db.GetCollection(ConstCollectionNameConfigHistory)
+	if err != nil {
+		return "", env.ErrorDispatch(err)
+	}
+
+	record, err := collection.LoadByID(historyID)
+	if err != nil || len(record) == 0 {
+		return "", env.ErrorNew(ConstErrorModule, env.ConstErrorLevelAPI, "3ce4a6f7-e545-44e3-9738-9f144aa965a2", "config change '"+historyID+"' not found")
+	}
+
+	path := utils.InterfaceToString(record["path"])
+	valueType := utils.InterfaceToString(record["type"])
+
+	value := record["old_value"]
+	if valueType != env.ConstConfigTypeSecret {
+		value = db.ConvertTypeFromDbToGo(value, valueType)
+	}
+
+	if err := it.SetValue(path, value); err != nil {
+		return "", env.ErrorDispatch(err)
+	}
+
+	return path, nil
+}
@@ -63,6 +63,8 @@ func (it *DefaultConfig) RegisterItem(Item env.StructConfigItem, Validator env.F
 		it.configValidators[Item.Path] = Validator
 	}
 
+	it.loadOverride(Item.Path)
+
 	// validating current set value
 	if validator, present := it.configValidators[Item.Path]; present && validator != nil {
 		newValue, err := validator(it.configValues[Item.Path])
@@ -103,7 +105,16 @@ func (it *DefaultConfig) UnregisterItem(Path string) error {
 }
 
 // GetValue returns value for config item of nil if not present
+//   - environment variable named by env.GetEnvironmentVariableName() with "OTTEMO_CONFIG_" prefix takes precedence
 func (it *DefaultConfig) GetValue(Path string) interface{} {
+	if value, present := it.configOverrides[Path]; present {
+		return value
+	}
+	return it.getStoredValue(Path)
+}
+
+// getStoredValue returns config item value stored in database (decrypted for secrets) or nil if not present
+func (it *DefaultConfig) getStoredValue(Path string) interface{} {
 	if value, present := it.configValues[Path]; present {
 
 		if it.configTypes[Path] == env.ConstConfigTypeSecret {
@@ -117,8 +128,9 @@ func (it *DefaultConfig) GetValue(Path string) interface{} {
 }
 
 // SetValue updates config item with new value, returns error if not possible
+//   - change is recorded to config history along with visitor made it
 func (it *DefaultConfig) SetValue(Path string, Value interface{}) error {
-	if _, present := it.configValues[Path]; present {
+	if oldValue, present := it.configValues[Path]; present {
 
 		// updating value on GO side
 		//--------------------------
@@ -173,6 +185,10 @@ func (it *DefaultConfig) SetValue(Path string, Value interface{}) error {
 			return env.ErrorDispatch(err)
 		}
 
+		if err := it.recordChange(Path, oldValue, it.configValues[Path]); err != nil {
+			_ = env.ErrorDispatch(err)
+		}
+
 	} else {
 		return env.ErrorNew(ConstErrorModule, ConstErrorLevel, "6984f1ce-1fb1-40d5-b674-9d88956164c0", "can not find config item '"+Path+"' ")
 	}
@@ -299,6 +315,7 @@ func (it *DefaultConfig) Load() error {
 func (it *DefaultConfig) Reload() error {
 	it.configValues = make(map[string]interface{})
 	it.configTypes = make(map[string]string)
+	it.configOverrides = make(map[string]interface{})
 
 	collection, err := db.GetCollection(ConstCollectionNameConfig)
 	if err != nil {
@@ -327,5 +344,7 @@ func (it *DefaultConfig) Reload() error {
 		it.configTypes[valuePath] = valueType
 	}
 
+	it.loadOverrides()
+
 	return nil
 }
@@ -12,13 +12,16 @@ func init() {
 	instance := &DefaultConfig{
 		configValues:     make(map[string]interface{}),
 		configTypes:      make(map[string]string),
-		configValidators: make(map[string]env.FuncConfigValueValidator)}
+		configValidators: make(map[string]env.FuncConfigValueValidator),
+		configOverrides:  make(map[string]interface{})}
 
 	db.RegisterOnDatabaseStart(setupDB)
 	db.RegisterOnDatabaseStart(instance.Load)
 
 	api.RegisterOnRestServiceStart(setupAPI)
 
+	var _ env.InterfaceValueSources = instance
+
 	if err := env.RegisterConfig(instance); err != nil {
 		_ = env.ErrorDispatch(err)
 	}
@@ -64,5 +67,35 @@ func setupDB() error {
 		return env.ErrorDispatch(err)
 	}
 
+	collection, err = db.GetCollection(ConstCollectionNameConfigHistory)
+	if err != nil {
+		return env.ErrorDispatch(err)
+	}
+
+	if err := collection.AddColumn("path", db.ConstTypeVarchar, true); err != nil {
+		return env.ErrorDispatch(err)
+	}
+	if err := collection.AddColumn("type", db.ConstTypeVarchar, false); err != nil {
+		return env.ErrorDispatch(err)
+	}
+	if err := collection.AddColumn("old_value", db.ConstTypeText, false); err != nil {
+		return env.ErrorDispatch(err)
+	}
+	if err := collection.AddColumn("new_value", db.ConstTypeText, false); err != nil {
+		return env.ErrorDispatch(err)
+	}
+	if err := collection.AddColumn("visitor_id", db.ConstTypeID, true); err != nil {
+		return env.ErrorDispatch(err)
+	}
+	if err := collection.AddColumn("admin", db.ConstTypeBoolean, false); err != nil {
+		return env.ErrorDispatch(err)
+	}
+	if err := collection.AddColumn("request_id", db.ConstTypeVarchar, false); err != nil {
+		return env.ErrorDispatch(err)
+	}
+	if err := collection.AddColumn("created_at", db.ConstTypeDatetime, true); err != nil {
+		return env.ErrorDispatch(err)
+	}
+
 	return nil
 }
@@ -0,0 +1,281 @@
+package config
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/scrypt"
+	"gopkg.in/yaml.v3"
+
+	"github.com/ottemo/commerce/db"
+	"github.com/ottemo/commerce/env"
+	"github.com/ottemo/commerce/utils"
+)
+
+// isPathInTree checks config path to be a given subtree root or its descendant, blank root matches any path
+func isPathInTree(path string, root string) bool {
+	return root == "" || path == root || strings.HasPrefix(path, root+".")
+}
+
+// exportDocument returns config values of subtree as a document in given format
+//   - values stored in database are exported, environment variable overrides are not a part of config
+//   - secret values are encrypted with passphrase, they are excluded if passphrase is blank
+func (it *DefaultConfig) exportDocument(root string, format string, passphrase string) ([]byte, error) {
+	document := StructConfigDocument{
+		Version:    ConstDocumentVersion,
+		ExportedAt: time.Now().UTC(),
+		Items:      make(map[string]interface{}),
+	}
+
+	var gcm cipher.AEAD
+	if passphrase != "" {
+		salt := make([]byte, ConstSecretSaltSize)
+		if _, err := rand.Read(salt); err != nil {
+			return nil, env.ErrorDispatch(err)
+		}
+		document.SecretsSalt = base64.StdEncoding.EncodeToString(salt)
+
+		var err error
+		if gcm, err = makeSecretCipher(passphrase, salt); err != nil {
+			return nil, env.ErrorDispatch(err)
+		}
+	}
+
+	for path, valueType := range it.configTypes {
+		if valueType == env.ConstConfigTypeGroup || !isPathInTree(path, root) {
+			continue
+		}
+
+		if valueType != env.ConstConfigTypeSecret {
+			document.Items[path] = it.getStoredValue(path)
+			continue
+		}
+
+		if gcm != nil {
+			encrypted, err := encryptSecret(utils.InterfaceToString(it.getStoredValue(path)), gcm)
+			if err != nil {
+				return nil, env.ErrorDispatch(err)
+			}
+			if document.Secrets == nil {
+				document.Secrets = make(map[string]string)
+			}
+			document.Secrets[path] = encrypted
+		}
+	}
+
+	switch format {
+	case ConstFormatYAML:
+		result, err := yaml.Marshal(document)
+		if err != nil {
+			return nil, env.ErrorDispatch(err)
+		}
+		return result, nil
+
+	case ConstFormatJSON, "":
+		result, err := json.MarshalIndent(document, "", "  ")
+		if err != nil {
+			return nil, env.ErrorDispatch(err)
+		}
+		return result, nil
+	}
+
+	return nil, env.ErrorNew(ConstErrorModule, env.ConstErrorLevelAPI, "59909e3a-966e-4f60-a829-e8b5699aaa43", "unknown config document format '"+format+"'")
+}
+
+// parseDocument reads config document in given format
+func parseDocument(data []byte, format string) (*StructConfigDocument, error) {
+	document := new(StructConfigDocument)
+
+	var err error
+	switch format {
+	case ConstFormatYAML:
+		err = yaml.Unmarshal(data, document)
+	case ConstFormatJSON, "":
+		err = json.Unmarshal(data, document)
+	default:
+		return nil, env.ErrorNew(ConstErrorModule, env.ConstErrorLevelAPI, "47779c41-1c52-4c60-9f19-3d0a4269fadd", "unknown config document format '"+format+"'")
+	}
+	if err != nil {
+		return nil, env.ErrorNew(ConstErrorModule, env.ConstErrorLevelAPI, "18106cc8-345f-4956-9aca-ba8f6e227555", "config document is malformed: "+err.Error())
+	}
+
+	if document.Version > ConstDocumentVersion {
+		return nil, env.ErrorNew(ConstErrorModule, env.ConstErrorLevelAPI, "64785ad8-2873-4753-904e-9e31aa949e91", "config document version "+utils.InterfaceToString(document.Version)+" is not supported")
+	}
+
+	// secrets of first version documents were encrypted with unsalted passphrase hash
+	if len(document.Secrets) > 0 && document.SecretsSalt == "" {
+		return nil, env.ErrorNew(ConstErrorModule, env.ConstErrorLevelAPI, "4a5694eb-d0e0-4380-a632-4ac956636341", "config document secrets have no key salt, export them again")
+	}
+
+	return document, nil
+}
+
+// prepareImportValue converts and validates document value for config item, returns value config would store
+func (it *DefaultConfig) prepareImportValue(path string, value interface{}) (interface{}, error) {
+	valueType, present := it.configTypes[path]
+	if !present {
+		return nil, env.ErrorNew(ConstErrorModule, env.ConstErrorLevelAPI, "d2e11c2f-4310-4093-b8d2-2b927051d125", "config item '"+path+"' is not registered")
+	}
+	if valueType == env.ConstConfigTypeGroup {
+		return nil, env.ErrorNew(ConstErrorModule, env.ConstErrorLevelAPI, "cba87f59-1005-4d99-a540-6d7ed6bf1e9f", "config item '"+path+"' is a group")
+	}
+
+	if _, isArray := value.([]interface{}); !isArray && valueType != env.ConstConfigTypeSecret {
+		value = db.ConvertTypeFromDbToGo(value, valueType)
+	}
+
+	if validator, present := it.configValidators[path]; present && validator != nil {
+		newValue, err := validator(value)
+		if err != nil {
+			return nil, env.ErrorDispatch(err)
+		}
+		value = newValue
+	}
+
+	return value, nil
+}
+
+// importDocument compares document values with current config and applies them if requested
+//   - returns changes document makes, secret values within changes are masked
+//   - values are validated by config item validators, nothing is applied if any of values is not valid
+//   - values are applied within transaction, so failure of any of them reverts the others
+func (it *DefaultConfig) importDocument(document *StructConfigDocument, passphrase string, apply bool) ([]StructConfigChange, error) {
+	values := make(map[string]interface{}, len(document.Items)+len(document.Secrets))
+	for path, value := range document.Items {
+		values[path] = value
+	}
+
+	var gcm cipher.AEAD
+	if len(document.Secrets) > 0 {
+		if passphrase == "" {
+			return nil, env.ErrorNew(ConstErrorModule, env.ConstErrorLevelAPI, "be20235a-646b-4133-8bda-3ae3c8ccfcc0", "passphrase is required to import secret values")
+		}
+
+		salt, err := base64.StdEncoding.DecodeString(document.SecretsSalt)
+		if err != nil {
+			return nil, env.ErrorNew(ConstErrorModule, env.ConstErrorLevelAPI, "97cddfc9-1240-4379-95a2-411aedcf128a", "config document secrets salt is malformed")
+		}
+		if gcm, err = makeSecretCipher(passphrase, salt); err != nil {
+			return nil, env.ErrorDispatch(err)
+		}
+	}
+	for path, encrypted := range document.Secrets {
+		value, err := decryptSecret(encrypted, gcm)
+		if err != nil {
+			return nil, env.ErrorNew(ConstErrorModule, env.ConstErrorLevelAPI, "b200809b-ae74-4024-9b5c-410092c9cfbd", "secret value of '"+path+"' can not be decrypted with given passphrase")
+		}
+		values[path] = value
+	}
+
+	paths := make([]string, 0, len(values))
+	for path := range values {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var changes []StructConfigChange
+	newValues := make(map[string]interface{})
+	isValid := true
+
+	for _, path := range paths {
+		change := StructConfigChange{Path: path, NewValue: values[path]}
+
+		newValue, err := it.prepareImportValue(path, values[path])
+		if err != nil {
+			change.Error = env.ErrorMessage(err)
+			changes = append(changes, change)
+			isValid = false
+			continue
+		}
+
+		oldValue := it.getStoredValue(path)
+		if utils.InterfaceToString(oldValue) == utils.InterfaceToString(newValue) {
+			continue
+		}
+
+		change.OldValue, change.NewValue = oldValue, newValue
+		if it.configTypes[path] == env.ConstConfigTypeSecret {
+			change.OldValue, change.NewValue = ConstSecretValueMask, ConstSecretValueMask
+		}
+
+		changes = append(changes, change)
+		newValues[path] = newValue
+	}
+
+	if !isValid {
+		return changes, env.ErrorNew(ConstErrorModule, env.ConstErrorLevelAPI, "1a5c8608-5a13-4157-a27b-2f49154bdf0f", "config document has invalid values")
+	}
+
+	if apply {
+		previousValues := make(map[string]interface{})
+		err := db.InTransaction(func() error {
+			for _, path := range paths {
+				if newValue, present := newValues[path]; present {
+					previousValues[path] = it.configValues[path]
+					if err := it.SetValue(path, newValue); err != nil {
+						return env.ErrorDispatch(err)
+					}
+				}
+			}
+			return nil
+		})
+
+		// stored values are reverted by transaction rollback, cached ones have to be restored
+		if err != nil {
+			for path, value := range previousValues {
+				it.configValues[path] = value
+			}
+			return changes, err
+		}
+	}
+
+	return changes, nil
+}
+
+// makeSecretCipher returns AES-GCM cipher with a key derived from passphrase and salt with scrypt
+func makeSecretCipher(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(passphrase), salt, ConstSecretScryptN, ConstSecretScryptR, ConstSecretScryptP, ConstSecretKeySize)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// encryptSecret encrypts value with passphrase cipher, result is base64 encoded nonce followed by cipher text
+func encryptSecret(value string, gcm cipher.AEAD) (string, error) {
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(gcm.Seal(nonce, nonce, []byte(value), nil)), nil
+}
+
+// decryptSecret decrypts value made by encryptSecret
+func decryptSecret(value string, gcm cipher.AEAD) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return "", err
+	}
+	if len(data) < gcm.NonceSize() {
+		return "", env.ErrorNew(ConstErrorModule, ConstErrorLevel, "a5f0a19b-75a8-4075-9d32-1937f9fcad8a", "encrypted value is too short")
+	}
+
+	result, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", err
+	}
+	return string(result), nil
+}
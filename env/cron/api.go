@@ -2,6 +2,8 @@ package cron
 
 import (
 	"github.com/ottemo/commerce/api"
+	"github.com/ottemo/commerce/app/models"
+	"github.com/ottemo/commerce/db"
 	"github.com/ottemo/commerce/env"
 	"github.com/ottemo/commerce/utils"
 )
@@ -12,17 +14,29 @@ func setupAPI() error {
 	service := api.GetRestService()
 
 	// Admin Only
-	service.GET("cron/schedule", api.IsAdminHandler(getSchedule))
-	service.POST("cron/task", api.IsAdminHandler(createTask))
-	service.GET("cron/task", api.IsAdminHandler(getTasks))
-	service.GET("cron/task/enable/:taskIndex", api.IsAdminHandler(enableTask))
-	service.GET("cron/task/disable/:taskIndex", api.IsAdminHandler(disableTask))
-	service.PUT("cron/task/:taskIndex", api.IsAdminHandler(updateTask))
-	service.GET("cron/task/run/:taskIndex", api.IsAdminHandler(runTask))
+	service.GET("cron/schedule", api.PermissionHandler(ConstPermissionRead, getSchedule))
+	service.POST("cron/task", api.PermissionHandler(ConstPermissionWrite, createTask))
+	service.GET("cron/task", api.PermissionHandler(ConstPermissionRead, getTasks))
+	service.GET("cron/task/enable/:taskIndex", api.PermissionHandler(ConstPermissionWrite, enableTask))
+	service.GET("cron/task/disable/:taskIndex", api.PermissionHandler(ConstPermissionWrite, disableTask))
+	service.PUT("cron/task/:taskIndex", api.PermissionHandler(ConstPermissionWrite, updateTask))
+	service.GET("cron/task/run/:taskIndex", api.PermissionHandler(ConstPermissionWrite, runTask))
+
+	service.GET("cron/history", api.PermissionHandler(ConstPermissionRead, getHistory))
+	service.GET("cron/history/:historyID", api.PermissionHandler(ConstPermissionRead, getHistoryRecord))
 
 	return nil
 }
 
+// storeSchedule saves schedule to DB, so it will be restored after application restart
+//   - schedules created by modules on startup are not stored unless isNew flag is set
+func storeSchedule(schedule env.InterfaceSchedule, isNew bool) error {
+	if cronSchedule, ok := schedule.(*DefaultCronSchedule); ok && (isNew || cronSchedule.id != "") {
+		return cronSchedule.save()
+	}
+	return nil
+}
+
 // runTask - allows to execute task of schedule without updating of it
 // taskIndex - need to be specified in request argument
 func runTask(context api.InterfaceApplicationContext) (interface{}, error) {
@@ -118,6 +132,10 @@ func updateTask(context api.InterfaceApplicationContext) (interface{}, error) {
 					}
 				}
 			}
+
+			if err := storeSchedule(schedule, false); err != nil {
+				return nil, env.ErrorDispatch(err)
+			}
 		}
 	}
 
@@ -176,6 +194,10 @@ func createTask(context api.InterfaceApplicationContext) (interface{}, error) {
 		}
 	}
 
+	if err := storeSchedule(newSchedule, true); err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
 	return newSchedule, nil
 }
 
@@ -206,6 +228,9 @@ func enableTask(context api.InterfaceApplicationContext) (interface{}, error) {
 			if err != nil {
 				return nil, env.ErrorDispatch(err)
 			}
+			if err := storeSchedule(schedule, false); err != nil {
+				return nil, env.ErrorDispatch(err)
+			}
 			break
 		}
 	}
@@ -240,9 +265,69 @@ func disableTask(context api.InterfaceApplicationContext) (interface{}, error) {
 			if err != nil {
 				return nil, env.ErrorDispatch(err)
 			}
+			if err := storeSchedule(schedule, false); err != nil {
+				return nil, env.ErrorDispatch(err)
+			}
 			break
 		}
 	}
 
 	return currentSchedules[taskIndex].GetInfo(), nil
 }
+
+// getHistory returns task executions history, recent first
+//   - "task", "status" and "schedule" arguments could be used to filter results
+//   - "limit" argument limits result, "offset,limit" form is also supported
+func getHistory(context api.InterfaceApplicationContext) (interface{}, error) {
+	collection, err := db.GetCollection(ConstCollectionNameCronHistory)
+	if err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
+	for argument, column := range map[string]string{"task": "task", "status": "status", "schedule": "schedule_id"} {
+		if value := context.GetRequestArgument(argument); value != "" {
+			if err := collection.AddFilter(column, "=", value); err != nil {
+				return nil, env.ErrorDispatch(err)
+			}
+		}
+	}
+
+	if err := collection.AddSort("started_at", true); err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
+	if offset, limit := models.GetListLimit(context); limit > 0 {
+		if err := collection.SetLimit(offset, limit); err != nil {
+			return nil, env.ErrorDispatch(err)
+		}
+	}
+
+	records, err := collection.Load()
+	if err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
+	return records, nil
+}
+
+// getHistoryRecord returns task execution details
+//   - "historyID" should be specified in request argument
+func getHistoryRecord(context api.InterfaceApplicationContext) (interface{}, error) {
+	historyID := context.GetRequestArgument("historyID")
+	if historyID == "" {
+		return nil, env.ErrorNew(ConstErrorModule, env.ConstErrorLevelAPI, "028914e9-5940-45d1-8182-e9fc64494a4a", "history record id should be specified")
+	}
+
+	collection, err := db.GetCollection(ConstCollectionNameCronHistory)
+	if err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
+	record, err := collection.LoadByID(historyID)
+	if err != nil {
+		context.SetResponseStatusNotFound()
+		return nil, env.ErrorDispatch(err)
+	}
+
+	return record, nil
+}
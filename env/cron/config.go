@@ -0,0 +1,58 @@
+package cron
+
+import (
+	"github.com/ottemo/commerce/env"
+	"github.com/ottemo/commerce/utils"
+)
+
+// setupConfig setups package configuration values for a system
+func setupConfig() error {
+	config := env.GetConfig()
+	if config == nil {
+		err := env.ErrorNew(ConstErrorModule, env.ConstErrorLevelStartStop, "7d19731b-ad8a-4a60-aeb5-a8060e1a0c52", "can't obtain config")
+		return env.ErrorDispatch(err)
+	}
+
+	err := config.RegisterItem(env.StructConfigItem{
+		Path:        ConstConfigPathCron,
+		Value:       nil,
+		Type:        env.ConstConfigTypeGroup,
+		Editor:      "",
+		Options:     nil,
+		Label:       "Cron",
+		Description: "scheduled tasks settings",
+		Image:       "",
+	}, nil)
+
+	if err != nil {
+		return env.ErrorDispatch(err)
+	}
+
+	// History retention
+	historyRetentionValidator := func(newValue interface{}) (interface{}, error) {
+		newRetention := utils.InterfaceToInt(newValue)
+		if newRetention < 0 {
+			err := env.ErrorNew(ConstErrorModule, ConstErrorLevel, "b1a48051-4c86-47ae-81e5-c0fab49371fa", "'History retention' config value should not be negative")
+			return historyRetention, env.ErrorDispatch(err)
+		}
+		historyRetention = newRetention
+
+		return historyRetention, nil
+	}
+	err = config.RegisterItem(env.StructConfigItem{
+		Path:        ConstConfigPathCronHistoryRetention,
+		Value:       ConstDefaultHistoryRetention,
+		Type:        env.ConstConfigTypeInteger,
+		Editor:      "integer",
+		Options:     nil,
+		Label:       "History retention",
+		Description: "days task run history is kept for, 0 keeps history forever",
+		Image:       "",
+	}, historyRetentionValidator)
+
+	if err != nil {
+		return env.ErrorDispatch(err)
+	}
+
+	return nil
+}
@@ -1,28 +1,82 @@
 package cron
 
 import (
+	"sync"
 	"time"
 
 	"github.com/gorhill/cronexpr"
 	"github.com/ottemo/commerce/env"
+	"github.com/ottemo/commerce/metrics"
 )
 
 // Package global constants
 const (
+	ConstCollectionNameCronSchedule = "cron_schedule"
+	ConstCollectionNameCronHistory  = "cron_history"
+	ConstCollectionNameCronLock     = "cron_lock"
+
+	ConstLockLease           = time.Minute // time task lock is held by an instance without renewal
+	ConstLockReleaseAttempts = 3           // lock release attempts made on concurrent lock changes
+
+	ConstHistoryStatusRunning = "running"
+	ConstHistoryStatusSuccess = "success"
+	ConstHistoryStatusFailed  = "failed"
+
+	ConstLogStorage = "cron.log"
+
+	ConstConfigPathCron                 = "general.cron"
+	ConstConfigPathCronHistoryRetention = "general.cron.history_retention"
+
+	ConstDefaultHistoryRetention = 30 // days run history is kept for, 0 keeps it forever
+
+	ConstHistoryPruneTaskName = "cronHistoryPrune"
+	ConstHistoryPruneCronExpr = "0 3 * * *"
+
+	ConstPermissionRead  = "cron.read"
+	ConstPermissionWrite = "cron.write"
+
 	ConstErrorModule = "env/cron"
 	ConstErrorLevel  = env.ConstErrorLevelService
 )
 
+// Package global variables
+var (
+	lockBackend InterfaceLockBackend = new(DefaultLockBackend)
+	lockOwner                        = makeLockOwner()
+
+	historyRetention = ConstDefaultHistoryRetention
+
+	taskRuns     = metrics.NewCounter("cron_task_runs_total", "Number of cron task runs by result.", "task", "status")
+	taskDuration = metrics.NewHistogram("cron_task_duration_seconds", "Cron task run duration in seconds.", nil, "task")
+)
+
+// InterfaceLockBackend is an interface to task locks storage shared by application instances
+//   - lock is a lease given to owner for a while, it should be renewed by owner to be kept
+//   - each schedule occurrence is run only once, so lock released after run could not be acquired for same occurrence
+type InterfaceLockBackend interface {
+	Acquire(key string, owner string, occurrence time.Time, lease time.Duration) (bool, error)
+	Renew(key string, owner string, lease time.Duration) (bool, error)
+	Release(key string, owner string) error
+}
+
+// DefaultLockBackend is a default implementer of InterfaceLockBackend which stores locks through db engine
+type DefaultLockBackend struct{}
+
 // DefaultCronScheduler is a default implementer of InterfaceIniConfig
 type DefaultCronScheduler struct {
 	tasks     map[string]env.FuncCronTask
 	schedules []*DefaultCronSchedule
 
+	schedulesMutex sync.RWMutex
+
 	appStarted bool
 }
 
 // DefaultCronSchedule structure to hold schedule information (for internal usage)
+//   - schedule with non blank id is stored in ConstCollectionNameCronSchedule collection
 type DefaultCronSchedule struct {
+	id string
+
 	CronExpr string
 	TaskName string
 	Params   map[string]interface{}
@@ -19,6 +19,19 @@ The API allows you to:
         * Disable a task
         * Update the specified task
         * Run the specified task now
+        * Obtain a history of task executions
+
+Schedules created through the API are stored in "cron_schedule" collection and
+restored on application start. Each task execution is recorded to "cron_history"
+collection with its start and end time, status and error. History records older
+than "general.cron.history_retention" days are removed by daily "cronHistoryPrune"
+task, zero value keeps history forever.
+
+When several application instances share a database, each schedule occurrence is
+run by one instance only. Instance takes a lease lock in "cron_lock" collection
+before the run and renews it while task is running, lock of died instance is
+taken over after lease expiration. Other lock storage could be provided with
+"SetLockBackend".
 
 //TODO: add link to api documentation
 
@@ -26,16 +26,21 @@ func (it *DefaultCronSchedule) Execute() {
 			}
 		}
 
-		err := it.task(it.Params)
+		err := it.runScheduledTask()
 		if err != nil {
 			err = env.ErrorDispatch(err)
-			env.Log("cron.log", env.ConstLogPrefixError, err.Error())
+			env.Log(ConstLogStorage, env.ConstLogPrefixError, err.Error())
 		}
 
 		if it.Repeat {
 			go it.Execute()
 		} else {
 			it.active = false
+			if it.id != "" {
+				if err := it.save(); err != nil {
+					env.LogError(err)
+				}
+			}
 		}
 
 	} else {
@@ -57,8 +62,8 @@ func (it *DefaultCronSchedule) Enable() error {
 	//		it.scheduler.schedules = append(it.scheduler.schedules, it)
 	//	}
 	if !it.active {
-		it.Execute()
 		it.active = true
+		go it.Execute()
 	}
 
 	return nil
@@ -137,6 +142,7 @@ func (it *DefaultCronSchedule) Get(param string) interface{} {
 // GetInfo - return set of settings for schedule
 func (it *DefaultCronSchedule) GetInfo() map[string]interface{} {
 	return map[string]interface{}{
+		"id":     it.id,
 		"expr":   it.CronExpr,
 		"time":   it.Time,
 		"task":   it.TaskName,
@@ -150,8 +156,8 @@ func (it *DefaultCronSchedule) GetInfo() map[string]interface{} {
 // otherwise schedule params will be used
 func (it *DefaultCronSchedule) RunTask(params map[string]interface{}) error {
 	if params != nil {
-		return it.task(params)
+		return it.runTask(params)
 	}
 
-	return it.task(it.Params)
+	return it.runTask(it.Params)
 }
@@ -47,7 +47,7 @@ func (it *DefaultCronScheduler) ScheduleAtTime(scheduleTime time.Time, taskName
 		expr:      nil,
 		scheduler: it}
 
-	it.schedules = append(it.schedules, schedule)
+	it.addSchedule(schedule)
 
 	go schedule.Execute()
 
@@ -76,7 +76,7 @@ func (it *DefaultCronScheduler) ScheduleOnce(cronExpr string, taskName string, p
 		expr:      expr,
 		scheduler: it}
 
-	it.schedules = append(it.schedules, schedule)
+	it.addSchedule(schedule)
 
 	go schedule.Execute()
 
@@ -106,7 +106,7 @@ func (it *DefaultCronScheduler) ScheduleRepeat(cronExpr string, taskName string,
 		expr:      expr,
 		scheduler: it}
 
-	it.schedules = append(it.schedules, schedule)
+	it.addSchedule(schedule)
 
 	go schedule.Execute()
 
@@ -115,6 +115,9 @@ func (it *DefaultCronScheduler) ScheduleRepeat(cronExpr string, taskName string,
 
 // ListSchedules returns list of currently registered schedules
 func (it *DefaultCronScheduler) ListSchedules() []env.InterfaceSchedule {
+	it.schedulesMutex.RLock()
+	defer it.schedulesMutex.RUnlock()
+
 	var result []env.InterfaceSchedule
 	for _, item := range it.schedules {
 		result = append(result, item)
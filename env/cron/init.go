@@ -3,6 +3,7 @@ package cron
 import (
 	"github.com/ottemo/commerce/api"
 	"github.com/ottemo/commerce/app"
+	"github.com/ottemo/commerce/db"
 	"github.com/ottemo/commerce/env"
 )
 
@@ -12,29 +13,116 @@ func init() {
 	var _ env.InterfaceScheduler = instance
 	var _ env.InterfaceSchedule = new(DefaultCronSchedule)
 
-	instance.tasks = make(map[string]env.FuncCronTask)
+	instance.tasks = map[string]env.FuncCronTask{ConstHistoryPruneTaskName: pruneHistory}
 	instance.schedules = make([]*DefaultCronSchedule, 0)
 
 	app.OnAppInit(instance.appInitEvent)
 	app.OnAppEnd(instance.appEndEvent)
 	api.RegisterOnRestServiceStart(setupAPI)
+	env.RegisterOnConfigStart(setupConfig)
+
+	db.RegisterOnDatabaseStart(setupDB)
+	db.RegisterOnDatabaseStart(instance.loadSchedules)
 
 	if err := env.RegisterScheduler(instance); err != nil {
 		_ = env.ErrorDispatch(err)
 	}
 }
 
+// setupDB prepares system database for package usage
+func setupDB() error {
+	collection, err := db.GetCollection(ConstCollectionNameCronSchedule)
+	if err != nil {
+		return env.ErrorDispatch(err)
+	}
+
+	if err := collection.AddColumn("task", db.ConstTypeVarchar, true); err != nil {
+		return env.ErrorDispatch(err)
+	}
+	if err := collection.AddColumn("expr", db.ConstTypeVarchar, false); err != nil {
+		return env.ErrorDispatch(err)
+	}
+	if err := collection.AddColumn("time", db.ConstTypeDatetime, false); err != nil {
+		return env.ErrorDispatch(err)
+	}
+	if err := collection.AddColumn("repeat", db.ConstTypeBoolean, false); err != nil {
+		return env.ErrorDispatch(err)
+	}
+	if err := collection.AddColumn("params", db.ConstTypeJSON, false); err != nil {
+		return env.ErrorDispatch(err)
+	}
+	if err := collection.AddColumn("active", db.ConstTypeBoolean, false); err != nil {
+		return env.ErrorDispatch(err)
+	}
+
+	collection, err = db.GetCollection(ConstCollectionNameCronHistory)
+	if err != nil {
+		return env.ErrorDispatch(err)
+	}
+
+	if err := collection.AddColumn("schedule_id", db.ConstTypeID, true); err != nil {
+		return env.ErrorDispatch(err)
+	}
+	if err := collection.AddColumn("task", db.ConstTypeVarchar, true); err != nil {
+		return env.ErrorDispatch(err)
+	}
+	if err := collection.AddColumn("params", db.ConstTypeJSON, false); err != nil {
+		return env.ErrorDispatch(err)
+	}
+	if err := collection.AddColumn("status", db.ConstTypeVarchar, true); err != nil {
+		return env.ErrorDispatch(err)
+	}
+	if err := collection.AddColumn("error", db.ConstTypeText, false); err != nil {
+		return env.ErrorDispatch(err)
+	}
+	if err := collection.AddColumn("started_at", db.ConstTypeDatetime, true); err != nil {
+		return env.ErrorDispatch(err)
+	}
+	if err := collection.AddColumn("finished_at", db.ConstTypeDatetime, false); err != nil {
+		return env.ErrorDispatch(err)
+	}
+
+	collection, err = db.GetCollection(ConstCollectionNameCronLock)
+	if err != nil {
+		return env.ErrorDispatch(err)
+	}
+
+	if err := collection.AddColumn("key", db.ConstTypeVarchar, false); err != nil {
+		return env.ErrorDispatch(err)
+	}
+	if err := collection.AddColumn("owner", db.ConstTypeVarchar, false); err != nil {
+		return env.ErrorDispatch(err)
+	}
+	if err := collection.AddColumn("occurrence", db.ConstTypeDatetime, false); err != nil {
+		return env.ErrorDispatch(err)
+	}
+	if err := collection.AddColumn("expires_at", db.ConstTypeDatetime, false); err != nil {
+		return env.ErrorDispatch(err)
+	}
+	if err := collection.AddColumn("completed", db.ConstTypeBoolean, false); err != nil {
+		return env.ErrorDispatch(err)
+	}
+	if err := db.AddVersionColumn(collection); err != nil {
+		return env.ErrorDispatch(err)
+	}
+
+	return nil
+}
+
 // routines before application end
 func (it *DefaultCronScheduler) appEndEvent() error {
 	return nil
 }
 
 // routines before application start (on init phase)
+//   - schedules stored in DB are reloaded as soon as database starts within init phase, ref. to loadSchedules()
+//   - run history pruning is scheduled daily
 func (it *DefaultCronScheduler) appInitEvent() error {
-
-	// TODO: load manually specified tasks from DB
-
 	it.appStarted = true
 
+	if _, err := it.ScheduleRepeat(ConstHistoryPruneCronExpr, ConstHistoryPruneTaskName, nil); err != nil {
+		return env.ErrorDispatch(err)
+	}
+
 	return nil
 }
@@ -0,0 +1,261 @@
+package cron
+
+import (
+	"time"
+
+	"github.com/gorhill/cronexpr"
+	"github.com/ottemo/commerce/db"
+	"github.com/ottemo/commerce/env"
+	"github.com/ottemo/commerce/utils"
+)
+
+// addSchedule appends schedule to the list of scheduler schedules
+func (it *DefaultCronScheduler) addSchedule(schedule *DefaultCronSchedule) {
+	it.schedulesMutex.Lock()
+	defer it.schedulesMutex.Unlock()
+
+	it.schedules = append(it.schedules, schedule)
+}
+
+// getStoredSchedule returns schedule for given DB record id or nil
+func (it *DefaultCronScheduler) getStoredSchedule(id string) *DefaultCronSchedule {
+	it.schedulesMutex.RLock()
+	defer it.schedulesMutex.RUnlock()
+
+	for _, schedule := range it.schedules {
+		if schedule.id == id {
+			return schedule
+		}
+	}
+	return nil
+}
+
+// getTask returns task routine registered for given name or nil
+func (it *DefaultCronScheduler) getTask(taskName string) env.FuncCronTask {
+	if task, present := it.tasks[taskName]; present {
+		return task
+	}
+	return nil
+}
+
+// loadSchedules restores schedules stored in DB
+//   - schedule task is resolved on execution, so modules could register tasks later
+func (it *DefaultCronScheduler) loadSchedules() error {
+	collection, err := db.GetCollection(ConstCollectionNameCronSchedule)
+	if err != nil {
+		return env.ErrorDispatch(err)
+	}
+
+	records, err := collection.Load()
+	if err != nil {
+		return env.ErrorDispatch(err)
+	}
+
+	for _, record := range records {
+		id := utils.InterfaceToString(record["_id"])
+		if it.getStoredSchedule(id) != nil {
+			continue
+		}
+
+		schedule := &DefaultCronSchedule{
+			id:        id,
+			TaskName:  utils.InterfaceToString(record["task"]),
+			Repeat:    utils.InterfaceToBool(record["repeat"]),
+			Time:      utils.InterfaceToTime(record["time"]),
+			scheduler: it}
+
+		if params, present := record["params"]; present && params != nil {
+			schedule.Params = utils.InterfaceToMap(params)
+		}
+
+		if cronExpr := utils.InterfaceToString(record["expr"]); cronExpr != "" {
+			expr, err := cronexpr.Parse(cronExpr)
+			if err != nil {
+				env.Log(ConstLogStorage, env.ConstLogPrefixError, "schedule '"+id+"' skipped, "+err.Error())
+				continue
+			}
+			schedule.CronExpr = cronExpr
+			schedule.expr = expr
+		}
+
+		it.addSchedule(schedule)
+
+		if utils.InterfaceToBool(record["active"]) {
+			go schedule.Execute()
+		}
+	}
+
+	return nil
+}
+
+// save stores schedule to DB, so it will be restored after application restart
+func (it *DefaultCronSchedule) save() error {
+	collection, err := db.GetCollection(ConstCollectionNameCronSchedule)
+	if err != nil {
+		return env.ErrorDispatch(err)
+	}
+
+	record := map[string]interface{}{
+		"task":   it.TaskName,
+		"expr":   it.CronExpr,
+		"time":   it.Time,
+		"repeat": it.Repeat,
+		"params": it.Params,
+		"active": it.active,
+	}
+	if it.id != "" {
+		record["_id"] = it.id
+	}
+
+	id, err := collection.Save(record)
+	if err != nil {
+		return env.ErrorDispatch(err)
+	}
+	it.id = id
+
+	return nil
+}
+
+// getLockKey returns key of lock guarding schedule occurrences across application instances
+//   - instances have same schedules made by modules, so key of not stored schedule is based on its settings
+func (it *DefaultCronSchedule) getLockKey() string {
+	if it.id != "" {
+		return "schedule " + it.id
+	}
+
+	key := it.TaskName + " " + it.CronExpr + " " + utils.EncodeToJSONString(it.Params)
+	if it.expr == nil {
+		key += " " + it.Time.UTC().Format(time.RFC3339)
+	}
+	return key
+}
+
+// runScheduledTask executes current schedule occurrence if no other application instance took it
+//   - lock is renewed while task is running, so other instances could take it over only if current one died
+func (it *DefaultCronSchedule) runScheduledTask() error {
+	backend := GetLockBackend()
+	if backend == nil {
+		return it.runTask(it.Params)
+	}
+
+	key := it.getLockKey()
+	isAcquired, err := backend.Acquire(key, lockOwner, it.Time, ConstLockLease)
+	if err != nil {
+		return env.ErrorDispatch(err)
+	}
+	if !isAcquired {
+		env.Log(ConstLogStorage, env.ConstLogPrefixInfo, "task '"+it.TaskName+"' occurrence at "+it.Time.String()+" is taken by other instance")
+		return nil
+	}
+
+	stopRenewal := make(chan bool)
+	go func() {
+		ticker := time.NewTicker(ConstLockLease / 3)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stopRenewal:
+				return
+			case <-ticker.C:
+				if isRenewed, err := backend.Renew(key, lockOwner, ConstLockLease); err != nil || !isRenewed {
+					env.Log(ConstLogStorage, env.ConstLogPrefixWarning, "task '"+it.TaskName+"' lock was not renewed")
+				}
+			}
+		}
+	}()
+
+	err = it.runTask(it.Params)
+	close(stopRenewal)
+
+	if releaseErr := backend.Release(key, lockOwner); releaseErr != nil {
+		env.LogError(releaseErr)
+	}
+
+	return err
+}
+
+// runTask executes schedule task with given params and records execution to run history
+func (it *DefaultCronSchedule) runTask(params map[string]interface{}) error {
+	task := it.task
+	if task == nil {
+		task = it.scheduler.getTask(it.TaskName)
+		it.task = task
+	}
+
+	historyRecord := map[string]interface{}{
+		"task":       it.TaskName,
+		"params":     params,
+		"status":     ConstHistoryStatusRunning,
+		"started_at": time.Now(),
+	}
+	if it.id != "" {
+		historyRecord["schedule_id"] = it.id
+	}
+
+	historyCollection, err := db.GetCollection(ConstCollectionNameCronHistory)
+	if err == nil {
+		historyRecord["_id"], err = historyCollection.Save(historyRecord)
+	}
+	if err != nil {
+		env.Log(ConstLogStorage, env.ConstLogPrefixWarning, "task '"+it.TaskName+"' run history was not stored, "+err.Error())
+		historyCollection = nil
+	}
+
+	var taskErr error
+	if task != nil {
+		startTime := time.Now()
+		taskErr = task(params)
+		taskDuration.ObserveDuration(startTime, it.TaskName)
+	} else {
+		taskErr = env.ErrorNew(ConstErrorModule, ConstErrorLevel, "f07d6c18-8f0e-42b2-8850-48044056fe29", "task '"+it.TaskName+"' is not registered")
+	}
+
+	if taskErr != nil {
+		taskRuns.Inc(it.TaskName, ConstHistoryStatusFailed)
+	} else {
+		taskRuns.Inc(it.TaskName, ConstHistoryStatusSuccess)
+	}
+
+	if historyCollection != nil {
+		historyRecord["finished_at"] = time.Now()
+		historyRecord["status"] = ConstHistoryStatusSuccess
+		if taskErr != nil {
+			historyRecord["status"] = ConstHistoryStatusFailed
+			historyRecord["error"] = taskErr.Error()
+		}
+
+		if _, err := historyCollection.Save(historyRecord); err != nil {
+			env.Log(ConstLogStorage, env.ConstLogPrefixWarning, "task '"+it.TaskName+"' run history was not stored, "+err.Error())
+		}
+	}
+
+	return taskErr
+}
+
+// pruneHistory is a task which removes run history records started before retention period
+func pruneHistory(params map[string]interface{}) error {
+	if historyRetention <= 0 {
+		return nil
+	}
+
+	historyCollection, err := db.GetCollection(ConstCollectionNameCronHistory)
+	if err != nil {
+		return env.ErrorDispatch(err)
+	}
+
+	cutoff := time.Now().Add(-time.Duration(historyRetention) * 24 * time.Hour)
+	if err := historyCollection.AddFilter("started_at", "<", cutoff); err != nil {
+		return env.ErrorDispatch(err)
+	}
+
+	removed, err := historyCollection.Delete()
+	if err != nil {
+		return env.ErrorDispatch(err)
+	}
+	if removed > 0 {
+		env.Log(ConstLogStorage, env.ConstLogPrefixInfo, "removed "+utils.InterfaceToString(removed)+" task run history records")
+	}
+
+	return nil
+}
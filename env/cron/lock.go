@@ -0,0 +1,176 @@
+package cron
+
+import (
+	"crypto/md5"
+	"crypto/rand"
+	"encoding/hex"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/ottemo/commerce/db"
+	"github.com/ottemo/commerce/env"
+	"github.com/ottemo/commerce/utils"
+)
+
+// SetLockBackend replaces storage of task locks shared by application instances
+//   - nil backend disables locking, so each instance runs all its schedules
+func SetLockBackend(backend InterfaceLockBackend) {
+	lockBackend = backend
+}
+
+// GetLockBackend returns storage of task locks shared by application instances
+func GetLockBackend() InterfaceLockBackend {
+	return lockBackend
+}
+
+// makeLockOwner makes name of current application instance to be lock owner
+func makeLockOwner() string {
+	hostname, _ := os.Hostname()
+
+	randomBytes := make([]byte, 4)
+	if _, err := rand.Read(randomBytes); err != nil {
+		_ = env.ErrorDispatch(err)
+	}
+
+	return hostname + ":" + strconv.Itoa(os.Getpid()) + ":" + hex.EncodeToString(randomBytes)
+}
+
+// getLockRecordID makes record id for a lock key, so all instances address the same record
+func getLockRecordID(key string) string {
+	hash := md5.Sum([]byte(key))
+	return hex.EncodeToString(hash[:12])
+}
+
+// loadLock returns lock collection and stored lock record or nil if lock was not stored yet
+//   - should be called within transaction, so the record is read from primary database
+func (it *DefaultLockBackend) loadLock(key string) (db.InterfaceDBCollection, map[string]interface{}, error) {
+	collection, err := db.GetCollection(ConstCollectionNameCronLock)
+	if err != nil {
+		return nil, nil, env.ErrorDispatch(err)
+	}
+
+	if err := collection.AddFilter("_id", "=", getLockRecordID(key)); err != nil {
+		return nil, nil, env.ErrorDispatch(err)
+	}
+
+	records, err := collection.Load()
+	if err != nil {
+		return nil, nil, env.ErrorDispatch(err)
+	}
+
+	if len(records) == 0 {
+		return collection, nil, nil
+	}
+	return collection, records[0], nil
+}
+
+// saveLock stores lock record, returns false if record was changed by other instance since it was loaded
+func (it *DefaultLockBackend) saveLock(collection db.InterfaceDBCollection, record map[string]interface{}) (bool, error) {
+	_, err := collection.Save(record)
+	if db.IsVersionConflict(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, env.ErrorDispatch(err)
+	}
+	return true, nil
+}
+
+// Acquire takes lock for a schedule occurrence if lock is free and occurrence was not run yet
+//   - lock which owner did not renew it in time is taken over
+func (it *DefaultLockBackend) Acquire(key string, owner string, occurrence time.Time, lease time.Duration) (bool, error) {
+	result := false
+	err := db.InTransaction(func() error {
+		collection, record, err := it.loadLock(key)
+		if err != nil {
+			return env.ErrorDispatch(err)
+		}
+
+		currentTime := time.Now()
+		occurrence = occurrence.Truncate(time.Second)
+
+		if record == nil {
+			record = map[string]interface{}{
+				"_id":                 getLockRecordID(key),
+				"key":                 key,
+				db.ConstVersionColumn: db.ConstVersionAbsent,
+			}
+		} else {
+			if utils.InterfaceToString(record["owner"]) != "" && utils.InterfaceToTime(record["expires_at"]).After(currentTime) {
+				return nil
+			}
+
+			storedOccurrence := utils.InterfaceToTime(record["occurrence"])
+			if storedOccurrence.After(occurrence) || storedOccurrence.Equal(occurrence) && utils.InterfaceToBool(record["completed"]) {
+				return nil
+			}
+		}
+
+		record["owner"] = owner
+		record["occurrence"] = occurrence
+		record["expires_at"] = currentTime.Add(lease)
+		record["completed"] = false
+
+		result, err = it.saveLock(collection, record)
+		return err
+	})
+
+	return result, err
+}
+
+// Renew prolongs lock held by owner, returns false if lock was lost
+func (it *DefaultLockBackend) Renew(key string, owner string, lease time.Duration) (bool, error) {
+	result := false
+	err := db.InTransaction(func() error {
+		collection, record, err := it.loadLock(key)
+		if err != nil {
+			return env.ErrorDispatch(err)
+		}
+
+		if record == nil || utils.InterfaceToString(record["owner"]) != owner {
+			return nil
+		}
+
+		record["expires_at"] = time.Now().Add(lease)
+
+		result, err = it.saveLock(collection, record)
+		return err
+	})
+
+	return result, err
+}
+
+// Release frees lock held by owner and marks its occurrence as completed
+//   - lock could be changed concurrently by owner renewal, so release is retried with reloaded record
+func (it *DefaultLockBackend) Release(key string, owner string) error {
+	for attempt := 0; attempt < ConstLockReleaseAttempts; attempt++ {
+		released := false
+		err := db.InTransaction(func() error {
+			collection, record, err := it.loadLock(key)
+			if err != nil {
+				return env.ErrorDispatch(err)
+			}
+
+			if record == nil || utils.InterfaceToString(record["owner"]) != owner {
+				released = true
+				return nil
+			}
+
+			record["owner"] = ""
+			record["expires_at"] = time.Now()
+			record["completed"] = true
+
+			released, err = it.saveLock(collection, record)
+			return err
+		})
+		if err != nil {
+			return env.ErrorDispatch(err)
+		}
+		if released {
+			return nil
+		}
+	}
+
+	return env.ErrorNew(ConstErrorModule, ConstErrorLevel, "a04bdbb2-795f-4b63-9373-2104fcec4cca", "can't release lock '"+key+"', it was changed concurrently")
+}
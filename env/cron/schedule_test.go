@@ -0,0 +1,162 @@
+package cron
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ottemo/commerce/db"
+	"github.com/ottemo/commerce/env"
+
+	_ "github.com/ottemo/commerce/db/memory"
+)
+
+// newTestScheduler makes scheduler with given tasks over prepared db collections
+func newTestScheduler(t *testing.T, tasks map[string]env.FuncCronTask) *DefaultCronScheduler {
+	if err := setupDB(); err != nil {
+		t.Fatal("setupDB", err)
+	}
+
+	return &DefaultCronScheduler{
+		tasks:      tasks,
+		schedules:  make([]*DefaultCronSchedule, 0),
+		appStarted: true,
+	}
+}
+
+func TestRunHistory(t *testing.T) {
+	scheduler := newTestScheduler(t, map[string]env.FuncCronTask{
+		"testSuccess": func(params map[string]interface{}) error { return nil },
+		"testFailure": func(params map[string]interface{}) error { return errors.New("task failed") },
+	})
+
+	for _, taskName := range []string{"testSuccess", "testFailure"} {
+		schedule := &DefaultCronSchedule{TaskName: taskName, Time: time.Now(), scheduler: scheduler}
+		schedule.Execute()
+	}
+
+	collection, err := db.GetCollection(ConstCollectionNameCronHistory)
+	if err != nil {
+		t.Fatal("db.GetCollection", err)
+	}
+	records, err := collection.Load()
+	if err != nil {
+		t.Fatal("collection.Load", err)
+	}
+
+	statuses := make(map[string]map[string]interface{})
+	for _, record := range records {
+		statuses[record["task"].(string)] = record
+	}
+
+	if record := statuses["testSuccess"]; record == nil || record["status"] != ConstHistoryStatusSuccess {
+		t.Errorf("unexpected testSuccess history record: %v", record)
+	}
+	if record := statuses["testFailure"]; record == nil || record["status"] != ConstHistoryStatusFailed || record["error"] != "task failed" {
+		t.Errorf("unexpected testFailure history record: %v", record)
+	}
+}
+
+func TestLoadSchedules(t *testing.T) {
+	scheduler := newTestScheduler(t, map[string]env.FuncCronTask{})
+
+	schedule := &DefaultCronSchedule{
+		TaskName:  "testStored",
+		CronExpr:  "0 * * * *",
+		Repeat:    true,
+		Params:    map[string]interface{}{"key": "value"},
+		scheduler: scheduler}
+	if err := schedule.save(); err != nil {
+		t.Fatal("schedule.save", err)
+	}
+	if schedule.id == "" {
+		t.Fatal("stored schedule have no id")
+	}
+
+	restarted := newTestScheduler(t, map[string]env.FuncCronTask{})
+	if err := restarted.loadSchedules(); err != nil {
+		t.Fatal("loadSchedules", err)
+	}
+
+	restored := restarted.getStoredSchedule(schedule.id)
+	if restored == nil {
+		t.Fatal("schedule was not restored")
+	}
+	if restored.TaskName != "testStored" || restored.CronExpr != "0 * * * *" || !restored.Repeat || restored.Params["key"] != "value" || restored.expr == nil {
+		t.Errorf("unexpected restored schedule: %v", restored.GetInfo())
+	}
+	if restored.active {
+		t.Error("inactive schedule was activated")
+	}
+}
+
+func TestLock(t *testing.T) {
+	newTestScheduler(t, map[string]env.FuncCronTask{})
+
+	backend := new(DefaultLockBackend)
+	key := "testLock"
+	occurrence := time.Date(2019, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	if isAcquired, err := backend.Acquire(key, "first", occurrence, time.Minute); err != nil || !isAcquired {
+		t.Fatal("first instance should acquire lock", err)
+	}
+	if isAcquired, err := backend.Acquire(key, "second", occurrence, time.Minute); err != nil || isAcquired {
+		t.Fatal("second instance should not acquire held lock", err)
+	}
+	if isRenewed, err := backend.Renew(key, "second", time.Minute); err != nil || isRenewed {
+		t.Fatal("second instance should not renew lock it does not hold", err)
+	}
+	if isRenewed, err := backend.Renew(key, "first", time.Minute); err != nil || !isRenewed {
+		t.Fatal("first instance should renew its lock", err)
+	}
+
+	if err := backend.Release(key, "first"); err != nil {
+		t.Fatal("backend.Release", err)
+	}
+	if isAcquired, err := backend.Acquire(key, "second", occurrence, time.Minute); err != nil || isAcquired {
+		t.Fatal("completed occurrence should not be run again", err)
+	}
+
+	// lock of died instance is taken over after its lease expiration
+	occurrence = occurrence.Add(time.Hour)
+	if isAcquired, err := backend.Acquire(key, "second", occurrence, -time.Second); err != nil || !isAcquired {
+		t.Fatal("second instance should acquire lock for next occurrence", err)
+	}
+	if isAcquired, err := backend.Acquire(key, "first", occurrence, time.Minute); err != nil || !isAcquired {
+		t.Fatal("first instance should take over expired lock", err)
+	}
+}
+
+func TestPruneHistory(t *testing.T) {
+	newTestScheduler(t, nil)
+
+	collection, err := db.GetCollection(ConstCollectionNameCronHistory)
+	if err != nil {
+		t.Fatal("db.GetCollection", err)
+	}
+	if _, err := collection.Delete(); err != nil {
+		t.Fatal("collection.Delete", err)
+	}
+
+	oldID, err := collection.Save(map[string]interface{}{"task": "testPrune", "status": ConstHistoryStatusSuccess,
+		"started_at": time.Now().Add(-time.Duration(historyRetention+1) * 24 * time.Hour)})
+	if err != nil {
+		t.Fatal("collection.Save", err)
+	}
+	recentID, err := collection.Save(map[string]interface{}{"task": "testPrune", "status": ConstHistoryStatusSuccess,
+		"started_at": time.Now()})
+	if err != nil {
+		t.Fatal("collection.Save", err)
+	}
+
+	if err := pruneHistory(nil); err != nil {
+		t.Fatal("pruneHistory", err)
+	}
+
+	if record, err := collection.LoadByID(oldID); err == nil && record != nil && len(record) > 0 {
+		t.Error("history record older than retention was not removed")
+	}
+	if record, err := collection.LoadByID(recentID); err != nil || len(record) == 0 {
+		t.Error("recent history record was removed", err)
+	}
+}
@@ -0,0 +1,213 @@
+package errorbus
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ottemo/commerce/env"
+	"github.com/ottemo/commerce/utils"
+)
+
+// String returns rule in the same form it is configured
+func (it StructAlertRule) String() string {
+	return it.Module + ":" + it.Code + " " + strconv.Itoa(it.Count) + "/" + it.Period.String()
+}
+
+// matches checks if rule is applicable to given error statistic
+func (it StructAlertRule) matches(aggregate *StructErrorAggregate) bool {
+	return (it.Module == "*" || it.Module == aggregate.Module) && (it.Code == "*" || it.Code == aggregate.Code)
+}
+
+// parseAlertRules converts config value to alert rules, rules are separated by new line or ";"
+//   - rule form is "[module:]code count/period", "*" matches any module or code
+//   - period is a number of minutes or a duration like "30s", "1h"
+//   - lines started with "#" are comments
+func parseAlertRules(value string) ([]StructAlertRule, error) {
+	var result []StructAlertRule
+
+	for _, line := range strings.FieldsFunc(value, func(r rune) bool { return r == '\n' || r == ';' }) {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, env.ErrorNew(ConstErrorModule, ConstErrorLevel, "27dbe798-517d-4d34-8087-4f68ce21a1f6", "alert rule '"+line+"' should be in form '[module:]code count/period'")
+		}
+
+		rule := StructAlertRule{Module: "*", Code: fields[0]}
+		if parts := strings.SplitN(fields[0], ":", 2); len(parts) == 2 {
+			rule.Module, rule.Code = parts[0], parts[1]
+		}
+		if rule.Module == "" || rule.Code == "" {
+			return nil, env.ErrorNew(ConstErrorModule, ConstErrorLevel, "d935d06a-992a-4535-9ad1-bede74c367a1", "alert rule '"+line+"' has blank module or code")
+		}
+
+		threshold := strings.SplitN(fields[1], "/", 2)
+		if len(threshold) != 2 {
+			return nil, env.ErrorNew(ConstErrorModule, ConstErrorLevel, "2f6b8888-7c8e-45da-b2ed-3e99ec630893", "alert rule '"+line+"' threshold should be in form 'count/period'")
+		}
+
+		count, err := strconv.Atoi(threshold[0])
+		if err != nil || count < 1 {
+			return nil, env.ErrorNew(ConstErrorModule, ConstErrorLevel, "c4348eae-c26d-4077-b33b-5ea3ac138312", "alert rule '"+line+"' count should be a positive number")
+		}
+		if count > ConstMaxAlertRuleCount {
+			return nil, env.ErrorNew(ConstErrorModule, ConstErrorLevel, "d0ec5de6-0b24-41bc-8f35-8a9b9f23933c", "alert rule '"+line+"' count should not exceed "+strconv.Itoa(ConstMaxAlertRuleCount))
+		}
+		rule.Count = count
+
+		if minutes, err := strconv.Atoi(threshold[1]); err == nil {
+			rule.Period = time.Duration(minutes) * time.Minute
+		} else if rule.Period, err = time.ParseDuration(threshold[1]); err != nil {
+			return nil, env.ErrorNew(ConstErrorModule, ConstErrorLevel, "8f0f1f3e-22d5-495c-b287-d34eb618fef2", "alert rule '"+line+"' period should be a number of minutes or duration")
+		}
+		if rule.Period <= 0 {
+			return nil, env.ErrorNew(ConstErrorModule, ConstErrorLevel, "7eb0f8c2-d813-438d-927e-7adbbc9863a4", "alert rule '"+line+"' period should be positive")
+		}
+
+		result = append(result, rule)
+	}
+
+	return result, nil
+}
+
+// setAlertRules replaces alert rules, alerts sent before are forgotten
+func setAlertRules(rules []StructAlertRule) {
+	aggregatesMutex.Lock()
+	defer aggregatesMutex.Unlock()
+
+	alertRules = rules
+	alertLastSent = make(map[string]time.Time)
+
+	occurrencesLimit = 0
+	for _, rule := range rules {
+		if rule.Count > occurrencesLimit {
+			occurrencesLimit = rule.Count
+		}
+	}
+}
+
+// aggregate updates error statistic with given error and returns statistic snapshot along with rules it triggered
+//   - rule triggers once within its period for same module+code pair
+func aggregate(ottemoErr *OttemoError) (StructErrorAggregate, []StructAlertRule) {
+	aggregatesMutex.Lock()
+	defer aggregatesMutex.Unlock()
+
+	now := time.Now()
+	key := ottemoErr.Module + ":" + ottemoErr.Code
+
+	item, present := aggregates[key]
+	if !present {
+		if len(aggregates) >= ConstMaxAggregates {
+			evictAggregate()
+		}
+
+		item = &StructErrorAggregate{Module: ottemoErr.Module, Code: ottemoErr.Code, FirstSeen: now}
+		aggregates[key] = item
+	}
+
+	item.Level = ottemoErr.Level
+	item.Message = ottemoErr.Message
+	item.CallStack = ottemoErr.CallStack
+	item.RequestID = ottemoErr.RequestID
+	item.LastSeen = now
+	item.Count++
+
+	item.occurrences = append(item.occurrences, now)
+	if extra := len(item.occurrences) - occurrencesLimit; extra > 0 {
+		item.occurrences = item.occurrences[extra:]
+	}
+
+	var triggered []StructAlertRule
+	for index, rule := range alertRules {
+		if !rule.matches(item) || len(item.occurrences) < rule.Count {
+			continue
+		}
+		if now.Sub(item.occurrences[len(item.occurrences)-rule.Count]) > rule.Period {
+			continue
+		}
+
+		alertKey := strconv.Itoa(index) + "|" + key
+		if lastSent, present := alertLastSent[alertKey]; present && now.Sub(lastSent) < rule.Period {
+			continue
+		}
+		alertLastSent[alertKey] = now
+
+		triggered = append(triggered, rule)
+	}
+
+	snapshot := *item
+	snapshot.occurrences = nil
+
+	return snapshot, triggered
+}
+
+// evictAggregate removes least recently seen error statistic, should be called under aggregatesMutex
+func evictAggregate() {
+	var oldestKey string
+	var oldestTime time.Time
+
+	for key, item := range aggregates {
+		if oldestKey == "" || item.LastSeen.Before(oldestTime) {
+			oldestKey, oldestTime = key, item.LastSeen
+		}
+	}
+
+	delete(aggregates, oldestKey)
+	for index := range alertRules {
+		delete(alertLastSent, strconv.Itoa(index)+"|"+oldestKey)
+	}
+}
+
+// getAggregates returns error statistic snapshot, recently seen first
+//   - blank module or code means any, errors above maxLevel are skipped if it is not negative
+func getAggregates(module string, code string, maxLevel int) []StructErrorAggregate {
+	aggregatesMutex.Lock()
+	defer aggregatesMutex.Unlock()
+
+	result := make([]StructErrorAggregate, 0, len(aggregates))
+	for _, item := range aggregates {
+		if (module != "" && item.Module != module) || (code != "" && item.Code != code) {
+			continue
+		}
+		if maxLevel >= 0 && item.Level > maxLevel {
+			continue
+		}
+
+		snapshot := *item
+		snapshot.occurrences = nil
+		result = append(result, snapshot)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].LastSeen.After(result[j].LastSeen)
+	})
+
+	return result
+}
+
+// resetAggregates clears error statistic
+func resetAggregates() {
+	aggregatesMutex.Lock()
+	defer aggregatesMutex.Unlock()
+
+	aggregates = make(map[string]*StructErrorAggregate)
+	alertLastSent = make(map[string]time.Time)
+}
+
+// alertRulesValidator is a config validator for alert rules value
+func alertRulesValidator(newValue interface{}) (interface{}, error) {
+	value := utils.InterfaceToString(newValue)
+
+	rules, err := parseAlertRules(value)
+	if err != nil {
+		return "", env.ErrorDispatch(err)
+	}
+	setAlertRules(rules)
+
+	return value, nil
+}
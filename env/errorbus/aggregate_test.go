@@ -0,0 +1,71 @@
+package errorbus
+
+import (
+	"testing"
+	"time"
+)
+
+// TestParseAlertRules checks alert rules config value parsing
+func TestParseAlertRules(t *testing.T) {
+	rules, err := parseAlertRules("# comment\n* 100/5\ncheckout:* 5/30s; env/config:1f29fe4c 1/1h")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []StructAlertRule{
+		{Module: "*", Code: "*", Count: 100, Period: 5 * time.Minute},
+		{Module: "checkout", Code: "*", Count: 5, Period: 30 * time.Second},
+		{Module: "env/config", Code: "1f29fe4c", Count: 1, Period: time.Hour},
+	}
+	if len(rules) != len(expected) {
+		t.Fatalf("unexpected rules: %v", rules)
+	}
+	for index, rule := range rules {
+		if rule != expected[index] {
+			t.Errorf("rule %d is %v, expected %v", index, rule, expected[index])
+		}
+	}
+
+	for _, value := range []string{"*", "* 5", "* 0/5", "* x/5", "* 5/-1", ":code 1/1", "* 1/1 extra", "* 1001/1"} {
+		if _, err := parseAlertRules(value); err == nil {
+			t.Errorf("rule '%s' should not be valid", value)
+		}
+	}
+}
+
+// TestAggregate checks errors statistic and alert rules triggering
+func TestAggregate(t *testing.T) {
+	resetAggregates()
+	setAlertRules([]StructAlertRule{
+		{Module: "test", Code: "*", Count: 3, Period: time.Minute},
+		{Module: "other", Code: "*", Count: 1, Period: time.Minute},
+	})
+	defer setAlertRules(nil)
+
+	var triggered int
+	for i := 0; i < 5; i++ {
+		snapshot, rules := aggregate(&OttemoError{Module: "test", Code: "code", Level: 4, Message: "message", CallStack: "stack"})
+		if snapshot.Count != i+1 {
+			t.Errorf("count is %d, expected %d", snapshot.Count, i+1)
+		}
+		triggered += len(rules)
+	}
+	aggregate(&OttemoError{Module: "test", Code: "another", Level: 8})
+
+	if triggered != 1 {
+		t.Errorf("rule triggered %d times, expected once within period", triggered)
+	}
+
+	result := getAggregates("test", "", -1)
+	if len(result) != 2 || result[0].Code != "another" || result[1].Count != 5 || result[1].CallStack != "stack" {
+		t.Errorf("unexpected statistic: %v", result)
+	}
+	if result := getAggregates("", "", 5); len(result) != 1 || result[0].Code != "code" {
+		t.Errorf("level filter is not applied: %v", result)
+	}
+
+	resetAggregates()
+	if result := getAggregates("", "", -1); len(result) != 0 {
+		t.Errorf("statistic is not reset: %v", result)
+	}
+}
@@ -0,0 +1,82 @@
+package errorbus
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html"
+	"net/http"
+	"strings"
+
+	"github.com/ottemo/commerce/api/context"
+	"github.com/ottemo/commerce/app"
+	"github.com/ottemo/commerce/env"
+	"github.com/ottemo/commerce/utils"
+)
+
+// isAlertRoutine checks if current routine delivers alert, errors happened there should not trigger alerts
+func isAlertRoutine() bool {
+	return context.GetContextValue(ConstContextKeyAlert) != nil
+}
+
+// sendAlert notifies about error rate spike to configured emails and webhook
+func sendAlert(aggregate StructErrorAggregate, rule StructAlertRule) {
+	context.RunInContext(func() {
+		subject := fmt.Sprintf("Error %s:%s happened %d times within %s", aggregate.Module, aggregate.Code, rule.Count, rule.Period)
+
+		emails := utils.InterfaceToString(env.ConfigGetValue(ConstConfigPathErrorAlertEmail))
+		for _, email := range strings.Split(emails, ",") {
+			if email = strings.TrimSpace(email); email == "" {
+				continue
+			}
+			if err := app.SendMail(email, subject, formatAlertMail(aggregate, rule)); err != nil {
+				_ = env.ErrorDispatch(err)
+			}
+		}
+
+		if webhook := utils.InterfaceToString(env.ConfigGetValue(ConstConfigPathErrorAlertWebhook)); webhook != "" {
+			if err := postAlertWebhook(webhook, subject, aggregate, rule); err != nil {
+				_ = env.ErrorDispatch(err)
+			}
+		}
+	}, map[string]interface{}{ConstContextKeyAlert: true})
+}
+
+// formatAlertMail returns alert email body
+func formatAlertMail(aggregate StructErrorAggregate, rule StructAlertRule) string {
+	return fmt.Sprintf("Alert rule <b>%s</b> triggered.<br/><br/>"+
+		"Module: %s<br/>Code: %s<br/>Level: %d<br/>Message: %s<br/>"+
+		"Total count: %d<br/>First seen: %s<br/>Last seen: %s<br/>Request ID: %s<br/><br/>"+
+		"<pre>%s</pre>",
+		html.EscapeString(rule.String()),
+		html.EscapeString(aggregate.Module), html.EscapeString(aggregate.Code), aggregate.Level, html.EscapeString(aggregate.Message),
+		aggregate.Count, aggregate.FirstSeen.UTC(), aggregate.LastSeen.UTC(), html.EscapeString(aggregate.RequestID),
+		html.EscapeString(aggregate.CallStack))
+}
+
+// postAlertWebhook sends alert as JSON document to given URL
+func postAlertWebhook(url string, subject string, aggregate StructErrorAggregate, rule StructAlertRule) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"subject": subject,
+		"rule":    rule.String(),
+		"error":   aggregate,
+	})
+	if err != nil {
+		return env.ErrorDispatch(err)
+	}
+
+	client := &http.Client{Timeout: ConstAlertWebhookTimeout}
+	response, err := client.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return env.ErrorDispatch(err)
+	}
+	defer func() {
+		_ = response.Body.Close()
+	}()
+
+	if response.StatusCode >= 300 {
+		return env.ErrorNew(ConstErrorModule, ConstErrorLevel, "745e163a-5201-420f-b4b2-80d0da00b2c7", "alert webhook responded with '"+response.Status+"'")
+	}
+
+	return nil
+}
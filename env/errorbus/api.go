@@ -0,0 +1,73 @@
+package errorbus
+
+import (
+	"github.com/ottemo/commerce/api"
+	"github.com/ottemo/commerce/app/models"
+	"github.com/ottemo/commerce/env"
+	"github.com/ottemo/commerce/utils"
+)
+
+// setups package related API endpoint routines
+func setupAPI() error {
+
+	service := api.GetRestService()
+
+	// Admin Only
+	service.GET("errorbus/errors", api.PermissionHandler(ConstPermissionRead, getErrors))
+	service.GET("errorbus/errors/:code", api.PermissionHandler(ConstPermissionRead, getError))
+	service.DELETE("errorbus/errors", api.PermissionHandler(ConstPermissionWrite, deleteErrors))
+
+	return nil
+}
+
+// getErrors returns errors statistic aggregated by module and code, recently seen first
+//   - "module" argument could be used to filter results, "level" argument skips errors above given level
+//   - "limit" argument limits result, "offset,limit" form is also supported
+//   - call stack is not included, use "errorbus/errors/:code" to get it
+func getErrors(context api.InterfaceApplicationContext) (interface{}, error) {
+	maxLevel := -1
+	if value := context.GetRequestArgument("level"); value != "" {
+		maxLevel = utils.InterfaceToInt(value)
+	}
+
+	result := getAggregates(context.GetRequestArgument("module"), "", maxLevel)
+	for index := range result {
+		result[index].CallStack = ""
+	}
+
+	if offset, limit := models.GetListLimit(context); limit > 0 {
+		if offset > len(result) {
+			offset = len(result)
+		}
+		if offset+limit < len(result) {
+			result = result[offset : offset+limit]
+		} else {
+			result = result[offset:]
+		}
+	}
+
+	return result, nil
+}
+
+// getError returns statistic with sample call stack for given error code
+//   - "module" argument could be used if same code is used by different modules
+func getError(context api.InterfaceApplicationContext) (interface{}, error) {
+	code := context.GetRequestArgument("code")
+	if code == "" {
+		return nil, env.ErrorNew(ConstErrorModule, env.ConstErrorLevelAPI, "13d0ecf6-29af-4310-b2f1-ba508575ebbb", "error code should be specified")
+	}
+
+	result := getAggregates(context.GetRequestArgument("module"), code, -1)
+	if len(result) == 0 {
+		context.SetResponseStatusNotFound()
+		return nil, env.ErrorNew(ConstErrorModule, env.ConstErrorLevelAPI, "b91748a6-f92d-4c60-be3c-8f1970828735", "error '"+code+"' was not registered")
+	}
+
+	return result, nil
+}
+
+// deleteErrors resets errors statistic
+func deleteErrors(context api.InterfaceApplicationContext) (interface{}, error) {
+	resetAggregates()
+	return "ok", nil
+}
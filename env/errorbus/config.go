@@ -2,6 +2,9 @@ package errorbus
 
 import (
 	"errors"
+	"net/url"
+	"strings"
+
 	"github.com/ottemo/commerce/env"
 	"github.com/ottemo/commerce/utils"
 )
@@ -80,5 +83,63 @@ func setupConfig() error {
 		return env.ErrorDispatch(err)
 	}
 
+	// Alert email
+	err = config.RegisterItem(env.StructConfigItem{
+		Path:        ConstConfigPathErrorAlertEmail,
+		Value:       "",
+		Type:        env.ConstConfigTypeVarchar,
+		Editor:      "line_text",
+		Options:     nil,
+		Label:       "Alert email",
+		Description: "comma separated emails to notify when error alert rule triggers",
+		Image:       "",
+	}, nil)
+
+	if err != nil {
+		return env.ErrorDispatch(err)
+	}
+
+	// Alert webhook
+	alertWebhookValidator := func(newValue interface{}) (interface{}, error) {
+		value := strings.TrimSpace(utils.InterfaceToString(newValue))
+		if value != "" {
+			if parsedURL, err := url.Parse(value); err != nil || (parsedURL.Scheme != "http" && parsedURL.Scheme != "https") || parsedURL.Host == "" {
+				err := errors.New("'Alert webhook' config value should be http(s) URL")
+				return "", env.ErrorDispatch(err)
+			}
+		}
+		return value, nil
+	}
+	err = config.RegisterItem(env.StructConfigItem{
+		Path:        ConstConfigPathErrorAlertWebhook,
+		Value:       "",
+		Type:        env.ConstConfigTypeVarchar,
+		Editor:      "line_text",
+		Options:     nil,
+		Label:       "Alert webhook",
+		Description: "URL alert is posted to as JSON document when error alert rule triggers",
+		Image:       "",
+	}, alertWebhookValidator)
+
+	if err != nil {
+		return env.ErrorDispatch(err)
+	}
+
+	// Alert rules
+	err = config.RegisterItem(env.StructConfigItem{
+		Path:        ConstConfigPathErrorAlertRules,
+		Value:       "",
+		Type:        env.ConstConfigTypeText,
+		Editor:      "multiline_text",
+		Options:     nil,
+		Label:       "Alert rules",
+		Description: "rule per line in form '[module:]code count/minutes', '*' matches any module or code, e.g. '* 100/5' or 'checkout:* 5/1'",
+		Image:       "",
+	}, alertRulesValidator)
+
+	if err != nil {
+		return env.ErrorDispatch(err)
+	}
+
 	return nil
 }
@@ -2,7 +2,10 @@ package errorbus
 
 import (
 	"github.com/ottemo/commerce/env"
+	"github.com/ottemo/commerce/metrics"
 	"regexp"
+	"sync"
+	"time"
 )
 
 // Package global constants
@@ -13,6 +16,18 @@ const (
 	ConstConfigPathErrorHideLevel   = "general.error.hide_level"
 	ConstConfigPathErrorHideMessage = "general.error.hide_message"
 
+	ConstConfigPathErrorAlertEmail   = "general.error.alert_email"
+	ConstConfigPathErrorAlertWebhook = "general.error.alert_webhook"
+	ConstConfigPathErrorAlertRules   = "general.error.alert_rules"
+
+	ConstMaxAggregates       = 1000             // maximum amount of module+code pairs error statistic kept for
+	ConstMaxAlertRuleCount   = 1000             // maximum alert rule count, each error statistic keeps that many occurrence times
+	ConstAlertWebhookTimeout = 10 * time.Second // timeout for alert webhook request
+	ConstContextKeyAlert     = "errorbus_alert" // call context key marking alert delivery routine
+
+	ConstPermissionRead  = "errors.read"
+	ConstPermissionWrite = "errors.write"
+
 	ConstErrorModule = "env/errorbus"
 	ConstErrorLevel  = env.ConstErrorLevelService
 )
@@ -25,6 +40,14 @@ var (
 	debug       = true
 	hideLevel   = 5
 	hideMessage = "System error has occured"
+
+	errorsTotal = metrics.NewCounter("errors_total", "Number of errors handled by error bus.", "module", "level")
+
+	aggregates       = make(map[string]*StructErrorAggregate) // error statistic by "module:code" key
+	alertRules       []StructAlertRule
+	alertLastSent    = make(map[string]time.Time) // last alert time by rule index and aggregate key
+	occurrencesLimit = 0                          // amount of recent occurrence times rules need
+	aggregatesMutex  sync.Mutex
 )
 
 // DefaultErrorBus InterfaceErrorBus implementer class
@@ -40,7 +63,32 @@ type OttemoError struct {
 	Level   int
 
 	CallStack string
+	RequestID string
 
 	handled bool
 	logged  bool
 }
+
+// StructErrorAggregate holds statistic of errors dispatched with same module and code
+type StructErrorAggregate struct {
+	Module    string    `json:"module"`
+	Code      string    `json:"code"`
+	Level     int       `json:"level"`
+	Message   string    `json:"message"`
+	Count     int       `json:"count"`
+	FirstSeen time.Time `json:"first_seen"`
+	LastSeen  time.Time `json:"last_seen"`
+	CallStack string    `json:"call_stack,omitempty"`
+	RequestID string    `json:"request_id,omitempty"`
+
+	occurrences []time.Time
+}
+
+// StructAlertRule is an alert threshold, rule triggers once module+code error happens Count times within Period
+//   - "*" Module or Code matches any value
+type StructAlertRule struct {
+	Module string
+	Code   string
+	Count  int
+	Period time.Duration
+}
@@ -35,6 +35,14 @@ constants for them, like:
 	ConstErrorModule = "rts"
 	ConstErrorLevel  = env.ConstErrorLevelActor
 
+Error bus keeps statistic of handled errors aggregated by module and code: count, first and last occurrence time, last
+message and call stack. It is available to administrator with "errorbus/errors" API. Alert rules ("general.error.alert_rules"
+config value) could be set to notify by email and/or webhook when error rate spikes, rule per line in a form:
+	[module:]code count/period
+where "*" matches any module or code and period is a number of minutes or a duration ("30s", "2h"). So "checkout:* 5/1"
+alerts once any "checkout" module error happened 5 times within a minute. Alert is not repeated within rule period.
+Rule count is limited to 1000 ("ConstMaxAlertRuleCount").
+
 There are two main usage approaches:
 
     Example 1: (Handling external errors)
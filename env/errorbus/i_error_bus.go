@@ -3,6 +3,7 @@ package errorbus
 import (
 	"crypto/md5"
 	"encoding/hex"
+	"github.com/ottemo/commerce/api/context"
 	"github.com/ottemo/commerce/env"
 	"runtime"
 	"strconv"
@@ -40,9 +41,20 @@ func (it *DefaultErrorBus) process(ottemoErr *OttemoError) *OttemoError {
 	}
 
 	ottemoErr.handled = true
+	if ottemoErr.RequestID == "" {
+		ottemoErr.RequestID = context.GetRequestID()
+	}
+	errorsTotal.Inc(ottemoErr.Module, strconv.Itoa(ottemoErr.Level))
 
 	it.backtrace(ottemoErr)
 
+	snapshot, triggeredRules := aggregate(ottemoErr)
+	if !isAlertRoutine() {
+		for _, rule := range triggeredRules {
+			go sendAlert(snapshot, rule)
+		}
+	}
+
 	for _, listener := range it.listeners {
 		if listener(ottemoErr) {
 			break
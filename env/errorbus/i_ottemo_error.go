@@ -47,6 +47,11 @@ func (it *OttemoError) ErrorCallStack() string {
 	return it.CallStack
 }
 
+// ErrorRequestID returns identifier of API request error was handled within
+func (it *OttemoError) ErrorRequestID() string {
+	return it.RequestID
+}
+
 // IsHandled returns handled flag
 func (it *OttemoError) IsHandled() bool {
 	return it.handled
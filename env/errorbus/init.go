@@ -1,6 +1,7 @@
 package errorbus
 
 import (
+	"github.com/ottemo/commerce/api"
 	"github.com/ottemo/commerce/env"
 	"github.com/ottemo/commerce/utils"
 )
@@ -17,6 +18,7 @@ func init() {
 	}
 	env.RegisterOnConfigIniStart(setupOnIniConfigStart)
 	env.RegisterOnConfigStart(setupConfig)
+	api.RegisterOnRestServiceStart(setupAPI)
 }
 
 // setupOnIniConfigStart is a initialization based on ini config service
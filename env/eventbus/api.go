@@ -0,0 +1,122 @@
+package eventbus
+
+import (
+	"time"
+
+	"github.com/ottemo/commerce/api"
+	"github.com/ottemo/commerce/app/models"
+	"github.com/ottemo/commerce/db"
+	"github.com/ottemo/commerce/env"
+	"github.com/ottemo/commerce/utils"
+)
+
+// setups package related API endpoint routines
+func setupAPI() error {
+
+	service := api.GetRestService()
+
+	// Admin Only
+	service.GET("eventbus/dead", api.PermissionHandler(ConstPermissionRead, getDeadEvents))
+	service.POST("eventbus/dead/:eventID/retry", api.PermissionHandler(ConstPermissionWrite, retryDeadEvent))
+	service.DELETE("eventbus/dead/:eventID", api.PermissionHandler(ConstPermissionWrite, deleteDeadEvent))
+
+	return nil
+}
+
+// loadDeadEvent loads dead letter outbox record by "eventID" request argument
+func loadDeadEvent(context api.InterfaceApplicationContext) (db.InterfaceDBCollection, map[string]interface{}, error) {
+	eventID := context.GetRequestArgument("eventID")
+	if eventID == "" {
+		return nil, nil, env.ErrorNew(ConstErrorModule, env.ConstErrorLevelAPI, "4dd21250-ad24-475f-8936-a59c1c545119", "event id should be specified")
+	}
+
+	collection, err := db.GetCollection(ConstCollectionNameEventOutbox)
+	if err != nil {
+		return nil, nil, env.ErrorDispatch(err)
+	}
+
+	record, err := collection.LoadByID(eventID)
+	if err != nil || record["status"] != ConstOutboxStatusDead {
+		context.SetResponseStatusNotFound()
+		return nil, nil, env.ErrorNew(ConstErrorModule, env.ConstErrorLevelAPI, "5d19fa93-544d-4ded-98f9-d175211c8a89", "dead event '"+eventID+"' not found")
+	}
+
+	return collection, record, nil
+}
+
+// getDeadEvents returns events listeners failed to handle within allowed attempts, recent first
+//   - "event" and "listener" arguments could be used to filter results
+//   - "limit" argument limits result, "offset,limit" form is also supported
+func getDeadEvents(context api.InterfaceApplicationContext) (interface{}, error) {
+	collection, err := db.GetCollection(ConstCollectionNameEventOutbox)
+	if err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
+	if err := collection.AddFilter("status", "=", ConstOutboxStatusDead); err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
+	for _, argument := range []string{"event", "listener"} {
+		if value := context.GetRequestArgument(argument); value != "" {
+			if err := collection.AddFilter(argument, "=", value); err != nil {
+				return nil, env.ErrorDispatch(err)
+			}
+		}
+	}
+
+	if err := collection.AddSort("created_at", true); err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
+	if offset, limit := models.GetListLimit(context); limit > 0 {
+		if err := collection.SetLimit(offset, limit); err != nil {
+			return nil, env.ErrorDispatch(err)
+		}
+	}
+
+	records, err := collection.Load()
+	if err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
+	return records, nil
+}
+
+// retryDeadEvent returns dead event back to outbox, so listener will handle it again with full amount of attempts
+//   - "eventID" should be specified in request argument
+func retryDeadEvent(context api.InterfaceApplicationContext) (interface{}, error) {
+	collection, record, err := loadDeadEvent(context)
+	if err != nil {
+		return nil, err
+	}
+
+	record["status"] = ConstOutboxStatusPending
+	record["attempts"] = 0
+	record["next_attempt_at"] = time.Now()
+
+	if _, err := collection.Save(record); err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
+	if eventBus, ok := env.GetEventBus().(*DefaultEventBus); ok {
+		eventBus.notifyWorkers()
+	}
+
+	return record, nil
+}
+
+// deleteDeadEvent removes dead event from outbox
+//   - "eventID" should be specified in request argument
+func deleteDeadEvent(context api.InterfaceApplicationContext) (interface{}, error) {
+	collection, record, err := loadDeadEvent(context)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := collection.DeleteByID(utils.InterfaceToString(record["_id"])); err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
+	return "ok", nil
+}
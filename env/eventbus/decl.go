@@ -1,10 +1,59 @@
 package eventbus
 
 import (
+	"sync"
+	"time"
+
 	"github.com/ottemo/commerce/env"
 )
 
+// Package global constants
+const (
+	ConstCollectionNameEventOutbox = "event_outbox"
+
+	ConstOutboxStatusPending    = "pending"    // event is waiting for listener handling
+	ConstOutboxStatusProcessing = "processing" // event is being handled by listener
+	ConstOutboxStatusDead       = "dead"       // listener failed to handle event within allowed attempts
+
+	ConstDefaultWorkers     = 4
+	ConstDefaultMaxAttempts = 5
+	ConstDefaultRetryDelay  = 10 * time.Second // delay before first retry, it doubles with each next attempt
+
+	ConstMaxRetryDelay      = time.Hour
+	ConstPollInterval       = 5 * time.Second  // interval of checking outbox for events to retry
+	ConstPollBatchSize      = 100              // maximal amount of events claimed by one outbox check
+	ConstProcessingDeadline = 10 * time.Minute // time after which event being handled by died instance is handled again
+
+	ConstLogStorage = "eventbus.log"
+
+	ConstPermissionRead  = "events.read"
+	ConstPermissionWrite = "events.write"
+
+	ConstErrorModule = "env/eventbus"
+	ConstErrorLevel  = env.ConstErrorLevelService
+)
+
 // DefaultEventBus InterfaceEventBus implementer class
 type DefaultEventBus struct {
-	listeners map[string][]env.FuncEventListener
+	listeners      map[string][]env.FuncEventListener
+	listenersMutex sync.RWMutex
+
+	asyncListeners      map[string][]*asyncListener
+	asyncListenersMutex sync.RWMutex
+
+	isAsync     bool
+	workers     int
+	maxAttempts int
+	retryDelay  time.Duration
+
+	wakeUp       chan bool
+	stop         chan bool
+	started      bool
+	startedMutex sync.RWMutex
+}
+
+// asyncListener is a named background listener of an event
+type asyncListener struct {
+	name     string
+	listener env.FuncAsyncEventListener
 }
@@ -15,6 +15,15 @@ could be added to a data map, during event processing.
 To be more consistent and clear, event names should be declared as a package constants with description about providing
 event data map.
 
+Listeners registered with env.EventRegisterListener are called synchronously in the emitting go routine, so they could
+stop event propagation by returning false. Listeners which are slow or do not need to veto an event should be registered
+with env.EventRegisterAsyncListener under a unique name. These listeners are called after synchronous ones: event data is
+converted to JSON compatible values (models become their ToHashMap() result) and stored to "event_outbox" collection for
+each async listener, then pool of workers handles stored events in background. Listener returning an error is called
+again after a delay which doubles with each attempt, event is moved to dead letters after "eventbus.maxAttempts" failed
+attempts. Dead letters are available over REST API ("eventbus/dead") to be inspected, retried or removed. Async mode
+could be turned off with "eventbus.async = false" ini setting, async listeners are called synchronously then.
+
     Example 1:
     ----------
         return env.ErrorNew(ConstErrorModule, ConstErrorLevel, "004e9f7b-bb97-4356-bbc2-5e084736983b", "unknown cmd '"+args[0]+"'")
@@ -26,5 +35,13 @@ event data map.
             env.LogMessage( fmt.Sprintf("%+v", eventData) )
         }
         env.EventRegisterListener("checkout.success", salesHandler)
+
+    Example 3:
+    ----------
+        mailHandler := func(event string, eventData map[string]interface{}) error {
+            orderID := utils.InterfaceToString(utils.InterfaceToMap(eventData["order"])["_id"])
+            return sendOrderMail(orderID)
+        }
+        env.EventRegisterAsyncListener("checkout.success", "mymodule.sendOrderMail", mailHandler)
 */
 package eventbus
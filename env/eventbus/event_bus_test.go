@@ -0,0 +1,158 @@
+package eventbus
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ottemo/commerce/db"
+	"github.com/ottemo/commerce/env"
+
+	_ "github.com/ottemo/commerce/db/memory"
+)
+
+// newTestEventBus makes event bus in async mode over empty db collection, workers are not started so test handles
+// outbox events itself
+func newTestEventBus(t *testing.T) *DefaultEventBus {
+	if err := setupDB(); err != nil {
+		t.Fatal("setupDB", err)
+	}
+
+	collection, err := db.GetCollection(ConstCollectionNameEventOutbox)
+	if err != nil {
+		t.Fatal("db.GetCollection", err)
+	}
+	if _, err := collection.Delete(); err != nil {
+		t.Fatal("collection.Delete", err)
+	}
+
+	return &DefaultEventBus{
+		listeners:      make(map[string][]env.FuncEventListener),
+		asyncListeners: make(map[string][]*asyncListener),
+		wakeUp:         make(chan bool, 1),
+		isAsync:        true,
+		workers:        1,
+		maxAttempts:    2,
+		started:        true,
+	}
+}
+
+// loadOutbox returns outbox records of given listener
+func loadOutbox(t *testing.T, listenerName string) []map[string]interface{} {
+	collection, err := db.GetCollection(ConstCollectionNameEventOutbox)
+	if err != nil {
+		t.Fatal("db.GetCollection", err)
+	}
+	if err := collection.AddFilter("listener", "=", listenerName); err != nil {
+		t.Fatal("collection.AddFilter", err)
+	}
+	records, err := collection.Load()
+	if err != nil {
+		t.Fatal("collection.Load", err)
+	}
+	return records
+}
+
+// handleOutbox claims ready outbox events and handles them
+func handleOutbox(t *testing.T, eventBus *DefaultEventBus) {
+	jobs := make(chan map[string]interface{}, ConstPollBatchSize)
+	if _, err := eventBus.claimEvents(jobs, make(chan bool)); err != nil {
+		t.Fatal("claimEvents", err)
+	}
+	close(jobs)
+
+	for record := range jobs {
+		eventBus.handleEvent(record)
+	}
+}
+
+func TestSyncVeto(t *testing.T) {
+	eventBus := newTestEventBus(t)
+
+	var calls []string
+	eventBus.RegisterListener("test", func(event string, data map[string]interface{}) bool {
+		calls = append(calls, "veto")
+		return false
+	})
+	eventBus.RegisterListener("test.veto", func(event string, data map[string]interface{}) bool {
+		calls = append(calls, "sync")
+		return true
+	})
+	eventBus.RegisterAsyncListener("test.veto", "test.vetoAsync", func(event string, data map[string]interface{}) error {
+		calls = append(calls, "async")
+		return nil
+	})
+
+	eventBus.New("test.veto", map[string]interface{}{})
+
+	if len(calls) != 1 || calls[0] != "veto" {
+		t.Errorf("unexpected listeners calls after veto: %v", calls)
+	}
+	if records := loadOutbox(t, "test.vetoAsync"); len(records) != 0 {
+		t.Errorf("vetoed event was stored to outbox: %v", records)
+	}
+}
+
+func TestAsyncSuccess(t *testing.T) {
+	eventBus := newTestEventBus(t)
+
+	var received map[string]interface{}
+	eventBus.RegisterAsyncListener("test.success", "test.successAsync", func(event string, data map[string]interface{}) error {
+		received = data
+		return nil
+	})
+
+	eventBus.New("test.success", map[string]interface{}{"key": "value", "skipped": make(chan bool)})
+
+	if received != nil {
+		t.Fatal("async listener was called synchronously")
+	}
+	if records := loadOutbox(t, "test.successAsync"); len(records) != 1 || records[0]["status"] != ConstOutboxStatusPending {
+		t.Fatalf("unexpected outbox records: %v", records)
+	}
+
+	handleOutbox(t, eventBus)
+
+	if received == nil || received["key"] != "value" {
+		t.Errorf("unexpected listener data: %v", received)
+	}
+	if _, present := received["skipped"]; present {
+		t.Error("not storable value was passed to listener")
+	}
+	if records := loadOutbox(t, "test.successAsync"); len(records) != 0 {
+		t.Errorf("handled event was not removed from outbox: %v", records)
+	}
+}
+
+func TestAsyncDeadLetter(t *testing.T) {
+	eventBus := newTestEventBus(t)
+
+	attempts := 0
+	eventBus.RegisterAsyncListener("test.failure", "test.failureAsync", func(event string, data map[string]interface{}) error {
+		attempts++
+		return errors.New("listener failed")
+	})
+
+	eventBus.New("test.failure", map[string]interface{}{})
+
+	handleOutbox(t, eventBus)
+
+	records := loadOutbox(t, "test.failureAsync")
+	if len(records) != 1 || records[0]["status"] != ConstOutboxStatusPending || records[0]["last_error"] != "listener failed" {
+		t.Fatalf("failed event was not scheduled for retry: %v", records)
+	}
+
+	handleOutbox(t, eventBus)
+
+	if attempts != 2 {
+		t.Errorf("listener was called %d times, expected 2", attempts)
+	}
+	if records := loadOutbox(t, "test.failureAsync"); len(records) != 1 || records[0]["status"] != ConstOutboxStatusDead {
+		t.Errorf("event was not moved to dead letters: %v", records)
+	}
+
+	handleOutbox(t, eventBus)
+
+	if attempts != 2 {
+		t.Error("dead event was handled again")
+	}
+}
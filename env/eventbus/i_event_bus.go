@@ -7,6 +7,9 @@ import (
 // RegisterListener adds listener to event handling stack
 //   - event listening is patch based, "" - global listener on any event, "api.product" - will listen for app events starts with api.product.[...])
 func (it *DefaultEventBus) RegisterListener(event string, listener env.FuncEventListener) {
+	it.listenersMutex.Lock()
+	defer it.listenersMutex.Unlock()
+
 	if value, present := it.listeners[event]; present {
 		it.listeners[event] = append(value, listener)
 	} else {
@@ -14,11 +17,51 @@ func (it *DefaultEventBus) RegisterListener(event string, listener env.FuncEvent
 	}
 }
 
+// RegisterAsyncListener adds background listener to event handling stack
+//   - event listening is patch based same as for RegisterListener
+//   - listener name identifies listener for events stored in outbox, so it should be unique and stable
+func (it *DefaultEventBus) RegisterAsyncListener(event string, name string, listener env.FuncAsyncEventListener) {
+	it.asyncListenersMutex.Lock()
+	defer it.asyncListenersMutex.Unlock()
+
+	it.asyncListeners[event] = append(it.asyncListeners[event], &asyncListener{name: name, listener: listener})
+}
+
 // New generates new event, with following dispatching
+//   - sync listeners are called first, any of them could stop event propagation
+//   - async listeners are called in background if async mode is on, otherwise they are called after sync ones
 func (it *DefaultEventBus) New(event string, args map[string]interface{}) {
+	levelEvents := getLevelEvents(event)
+
+	for _, levelEvent := range levelEvents {
+
+		// processing listeners withing level if present
+		it.listenersMutex.RLock()
+		listeners, present := it.listeners[levelEvent]
+		it.listenersMutex.RUnlock()
+
+		if present {
+			for _, listener := range listeners {
+
+				// processing listener, if it wants to stop handling - doing this
+				if listener(event, args) == false {
+					return
+				}
+
+			}
+		}
+	}
+
+	if asyncListeners := it.getAsyncListeners(levelEvents); len(asyncListeners) > 0 {
+		it.dispatchAsync(event, args, asyncListeners)
+	}
+}
+
+// getLevelEvents returns top level events listeners of which are notified about an event
+// (i.e. "api.checkout.success" event will notify following listeners: "", "api", "api.checkout", "api.checkout.success")
+func getLevelEvents(event string) []string {
+	var result []string
 
-	// loop over top level events
-	// (i.e. "api.checkout.success" event will notify following listeners: "", "api", "api.checkout", "api.checkout.success")
 	lastChar := len(event) - 1
 	for charIdx, char := range event {
 		if charIdx == 0 || charIdx == lastChar || char == '.' {
@@ -26,19 +69,36 @@ func (it *DefaultEventBus) New(event string, args map[string]interface{}) {
 			if charIdx != lastChar {
 				levelEvent = event[0:charIdx]
 			}
+			result = append(result, levelEvent)
+		}
+	}
 
-			// processing listeners withing level if present
-			if listeners, present := it.listeners[levelEvent]; present {
-				for _, listener := range listeners {
+	return result
+}
 
-					// processing listener, if it wants to stop handling - doing this
-					if listener(event, args) == false {
-						return
-					}
+// getAsyncListeners returns async listeners of given level events
+func (it *DefaultEventBus) getAsyncListeners(levelEvents []string) []*asyncListener {
+	it.asyncListenersMutex.RLock()
+	defer it.asyncListenersMutex.RUnlock()
 
-				}
+	var result []*asyncListener
+	for _, levelEvent := range levelEvents {
+		result = append(result, it.asyncListeners[levelEvent]...)
+	}
+	return result
+}
+
+// getAsyncListener returns async listener by its name or nil
+func (it *DefaultEventBus) getAsyncListener(name string) *asyncListener {
+	it.asyncListenersMutex.RLock()
+	defer it.asyncListenersMutex.RUnlock()
+
+	for _, listeners := range it.asyncListeners {
+		for _, listener := range listeners {
+			if listener.name == name {
+				return listener
 			}
 		}
 	}
-
+	return nil
 }
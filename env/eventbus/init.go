@@ -1,17 +1,102 @@
 package eventbus
 
 import (
+	"time"
+
+	"github.com/ottemo/commerce/api"
+	"github.com/ottemo/commerce/app"
+	"github.com/ottemo/commerce/db"
 	"github.com/ottemo/commerce/env"
+	"github.com/ottemo/commerce/utils"
 )
 
 // init makes package self-initialization routine
 func init() {
 	instance := new(DefaultEventBus)
 	instance.listeners = make(map[string][]env.FuncEventListener)
+	instance.asyncListeners = make(map[string][]*asyncListener)
+	instance.wakeUp = make(chan bool, 1)
+
+	instance.isAsync = true
+	instance.workers = ConstDefaultWorkers
+	instance.maxAttempts = ConstDefaultMaxAttempts
+	instance.retryDelay = ConstDefaultRetryDelay
 
 	var _ env.InterfaceEventBus = instance
+	var _ env.InterfaceAsyncEventBus = instance
+
+	env.RegisterOnConfigIniStart(instance.setupIniConfig)
+	db.RegisterOnDatabaseStart(setupDB)
+	db.RegisterOnDatabaseStart(instance.startWorkers)
+	app.OnAppEnd(instance.stopWorkers)
+	api.RegisterOnRestServiceStart(setupAPI)
 
 	if err := env.RegisterEventBus(instance); err != nil {
 		_ = env.ErrorDispatch(err)
 	}
 }
+
+// setupIniConfig reads async mode settings from ini config
+//   - "eventbus.async" turns async mode off with "false" value, so async listeners are called synchronously
+//   - "eventbus.workers", "eventbus.maxAttempts" and "eventbus.retryDelay" (seconds) tune outbox handling
+func (it *DefaultEventBus) setupIniConfig() error {
+	iniConfig := env.GetIniConfig()
+	if iniConfig == nil {
+		return nil
+	}
+
+	it.isAsync = utils.InterfaceToBool(iniConfig.GetValue("eventbus.async", "true"))
+
+	if value := utils.InterfaceToInt(iniConfig.GetValue("eventbus.workers", "")); value > 0 {
+		it.workers = value
+	}
+	if value := utils.InterfaceToInt(iniConfig.GetValue("eventbus.maxAttempts", "")); value > 0 {
+		it.maxAttempts = value
+	}
+	if value := utils.InterfaceToInt(iniConfig.GetValue("eventbus.retryDelay", "")); value > 0 {
+		it.retryDelay = time.Duration(value) * time.Second
+	}
+
+	return nil
+}
+
+// setupDB prepares system database for package usage
+func setupDB() error {
+	collection, err := db.GetCollection(ConstCollectionNameEventOutbox)
+	if err != nil {
+		return env.ErrorDispatch(err)
+	}
+
+	if err := collection.AddColumn("event", db.ConstTypeVarchar, true); err != nil {
+		return env.ErrorDispatch(err)
+	}
+	if err := collection.AddColumn("listener", db.ConstTypeVarchar, true); err != nil {
+		return env.ErrorDispatch(err)
+	}
+	if err := collection.AddColumn("data", db.ConstTypeJSON, false); err != nil {
+		return env.ErrorDispatch(err)
+	}
+	if err := collection.AddColumn("status", db.ConstTypeVarchar, true); err != nil {
+		return env.ErrorDispatch(err)
+	}
+	if err := collection.AddColumn("attempts", db.ConstTypeInteger, false); err != nil {
+		return env.ErrorDispatch(err)
+	}
+	if err := collection.AddColumn("last_error", db.ConstTypeText, false); err != nil {
+		return env.ErrorDispatch(err)
+	}
+	if err := collection.AddColumn("next_attempt_at", db.ConstTypeDatetime, true); err != nil {
+		return env.ErrorDispatch(err)
+	}
+	if err := collection.AddColumn("created_at", db.ConstTypeDatetime, false); err != nil {
+		return env.ErrorDispatch(err)
+	}
+	if err := collection.AddColumn("request_id", db.ConstTypeVarchar, false); err != nil {
+		return env.ErrorDispatch(err)
+	}
+	if err := db.AddVersionColumn(collection); err != nil {
+		return env.ErrorDispatch(err)
+	}
+
+	return nil
+}
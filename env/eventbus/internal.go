@@ -0,0 +1,327 @@
+package eventbus
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/ottemo/commerce/api/context"
+	"github.com/ottemo/commerce/db"
+	"github.com/ottemo/commerce/env"
+	"github.com/ottemo/commerce/utils"
+)
+
+// makeStorableData converts event data to JSON compatible values, so it could be stored to outbox
+//   - models are converted with their ToHashMap() method, or to {"_id": id} map if they have no such method
+//   - other structs (except time) and values could not be converted to JSON are skipped
+func makeStorableData(args map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(args))
+
+	for key, value := range args {
+		switch typedValue := value.(type) {
+		case interface {
+			ToHashMap() map[string]interface{}
+		}:
+			value = typedValue.ToHashMap()
+		case interface {
+			GetID() string
+		}:
+			value = map[string]interface{}{"_id": typedValue.GetID()}
+		}
+
+		if _, ok := value.(time.Time); !ok {
+			if kind := reflect.Indirect(reflect.ValueOf(value)).Kind(); kind == reflect.Struct || kind == reflect.Func || kind == reflect.Chan {
+				continue
+			}
+		}
+
+		jsonValue, err := json.Marshal(value)
+		if err != nil {
+			continue
+		}
+
+		var storableValue interface{}
+		if err := json.Unmarshal(jsonValue, &storableValue); err == nil {
+			result[key] = storableValue
+		}
+	}
+
+	return result
+}
+
+// callListener calls async listener converting its panic to an error
+func callListener(item *asyncListener, event string, data map[string]interface{}) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = env.ErrorNew(ConstErrorModule, ConstErrorLevel, "94963422-8473-4389-81b0-9caffde5ad72", "event listener '"+item.name+"' panic: "+fmt.Sprint(recovered))
+		}
+	}()
+
+	return item.listener(event, data)
+}
+
+// callListenerForRequest calls listener within context of API request event was emitted for
+func callListenerForRequest(requestID string, item *asyncListener, event string, data map[string]interface{}) (err error) {
+	context.RunInContext(func() {
+		err = callListener(item, event, data)
+	}, map[string]interface{}{context.ConstKeyRequestID: requestID})
+
+	return err
+}
+
+// dispatchAsync stores event to outbox for each of async listeners and notifies workers
+//   - listeners are called synchronously if async mode is off or workers were not started yet
+//   - listener is called in separate go routine if event could not be stored
+//   - identifier of current API request is kept with event, so listener logs could be related to request
+func (it *DefaultEventBus) dispatchAsync(event string, args map[string]interface{}, listeners []*asyncListener) {
+	data := makeStorableData(args)
+	requestID := context.GetRequestID()
+
+	if !it.isAsync || !it.isStarted() {
+		for _, item := range listeners {
+			if err := callListener(item, event, data); err != nil {
+				env.LogError(err)
+			}
+		}
+		return
+	}
+
+	for _, item := range listeners {
+		if err := it.storeEvent(event, item.name, data, requestID); err != nil {
+			env.LogError(err)
+
+			go func(item *asyncListener) {
+				if err := callListenerForRequest(requestID, item, event, data); err != nil {
+					env.LogError(err)
+				}
+			}(item)
+		}
+	}
+
+	it.notifyWorkers()
+}
+
+// notifyWorkers makes outbox to be checked for ready events without waiting for poll interval
+func (it *DefaultEventBus) notifyWorkers() {
+	select {
+	case it.wakeUp <- true:
+	default:
+	}
+}
+
+// storeEvent adds event record for a listener to outbox
+func (it *DefaultEventBus) storeEvent(event string, listenerName string, data map[string]interface{}, requestID string) error {
+	collection, err := db.GetCollection(ConstCollectionNameEventOutbox)
+	if err != nil {
+		return env.ErrorDispatch(err)
+	}
+
+	currentTime := time.Now()
+	record := map[string]interface{}{
+		"event":           event,
+		"listener":        listenerName,
+		"data":            data,
+		"status":          ConstOutboxStatusPending,
+		"attempts":        0,
+		"next_attempt_at": currentTime,
+		"created_at":      currentTime,
+		"request_id":      requestID,
+	}
+
+	if _, err := collection.Save(record); err != nil {
+		return env.ErrorDispatch(err)
+	}
+
+	return nil
+}
+
+// isStarted checks workers to be handling outbox events
+func (it *DefaultEventBus) isStarted() bool {
+	it.startedMutex.RLock()
+	defer it.startedMutex.RUnlock()
+
+	return it.started
+}
+
+// startWorkers starts outbox polling and workers pool handling claimed events
+func (it *DefaultEventBus) startWorkers() error {
+	it.startedMutex.Lock()
+	defer it.startedMutex.Unlock()
+
+	if it.started || !it.isAsync {
+		return nil
+	}
+
+	jobs := make(chan map[string]interface{})
+	it.stop = make(chan bool)
+
+	for i := 0; i < it.workers; i++ {
+		go it.work(jobs, it.stop)
+	}
+	go it.poll(jobs, it.stop)
+
+	it.started = true
+
+	return nil
+}
+
+// stopWorkers stops outbox polling and workers pool, events being handled are finished in background
+func (it *DefaultEventBus) stopWorkers() error {
+	it.startedMutex.Lock()
+	defer it.startedMutex.Unlock()
+
+	if it.started {
+		it.started = false
+		close(it.stop)
+	}
+
+	return nil
+}
+
+// work handles events sent by poll() until workers stop
+func (it *DefaultEventBus) work(jobs chan map[string]interface{}, stop chan bool) {
+	for {
+		select {
+		case <-stop:
+			return
+		case record := <-jobs:
+			it.handleEvent(record)
+		}
+	}
+}
+
+// poll periodically claims outbox events ready to be handled and sends them to workers
+//   - outbox is also checked right after new event was stored
+func (it *DefaultEventBus) poll(jobs chan map[string]interface{}, stop chan bool) {
+	ticker := time.NewTicker(ConstPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		case <-it.wakeUp:
+		}
+
+		for {
+			claimed, err := it.claimEvents(jobs, stop)
+			if err != nil {
+				env.LogError(err)
+			}
+			if err != nil || claimed < ConstPollBatchSize {
+				break
+			}
+		}
+	}
+}
+
+// claimEvents marks outbox events ready to be handled as being processed by current instance and sends them to workers
+//   - event is claimed with version check, so other instances sharing outbox would not handle it twice
+func (it *DefaultEventBus) claimEvents(jobs chan map[string]interface{}, stop chan bool) (int, error) {
+	collection, err := db.GetCollection(ConstCollectionNameEventOutbox)
+	if err != nil {
+		return 0, env.ErrorDispatch(err)
+	}
+
+	currentTime := time.Now()
+
+	if err := collection.AddFilter("status", "in", []string{ConstOutboxStatusPending, ConstOutboxStatusProcessing}); err != nil {
+		return 0, env.ErrorDispatch(err)
+	}
+	if err := collection.AddFilter("next_attempt_at", "<=", currentTime); err != nil {
+		return 0, env.ErrorDispatch(err)
+	}
+	if err := collection.AddSort("next_attempt_at", false); err != nil {
+		return 0, env.ErrorDispatch(err)
+	}
+	if err := collection.SetLimit(0, ConstPollBatchSize); err != nil {
+		return 0, env.ErrorDispatch(err)
+	}
+
+	// records are claimed with version check, so they are read from primary to not conflict with stale replica
+	var records []map[string]interface{}
+	err = db.ReadFromPrimary(func() error {
+		records, err = collection.Load()
+		return err
+	})
+	if err != nil {
+		return 0, env.ErrorDispatch(err)
+	}
+
+	for _, record := range records {
+		record["status"] = ConstOutboxStatusProcessing
+		record["next_attempt_at"] = currentTime.Add(ConstProcessingDeadline)
+
+		if _, err := collection.Save(record); err != nil {
+			if !db.IsVersionConflict(err) {
+				env.LogError(err)
+			}
+			continue
+		}
+
+		select {
+		case jobs <- record:
+		case <-stop:
+			return 0, nil
+		}
+	}
+
+	return len(records), nil
+}
+
+// handleEvent calls listener for claimed outbox event
+//   - handled event is removed from outbox, failed one is scheduled for retry or marked as dead after last attempt
+func (it *DefaultEventBus) handleEvent(record map[string]interface{}) {
+	collection, err := db.GetCollection(ConstCollectionNameEventOutbox)
+	if err != nil {
+		env.LogError(err)
+		return
+	}
+
+	listenerName := utils.InterfaceToString(record["listener"])
+	event := utils.InterfaceToString(record["event"])
+
+	if item := it.getAsyncListener(listenerName); item != nil {
+		err = callListenerForRequest(utils.InterfaceToString(record["request_id"]), item, event, utils.InterfaceToMap(record["data"]))
+	} else {
+		err = env.ErrorNew(ConstErrorModule, ConstErrorLevel, "b049d7af-914e-4623-9ced-61af4004aeaf", "event listener '"+listenerName+"' is not registered")
+	}
+
+	if err == nil {
+		if err := collection.DeleteByID(utils.InterfaceToString(record["_id"])); err != nil {
+			env.LogError(err)
+		}
+		return
+	}
+
+	attempts := utils.InterfaceToInt(record["attempts"]) + 1
+	record["attempts"] = attempts
+	record["last_error"] = env.ErrorMessage(err)
+
+	if attempts >= it.maxAttempts {
+		record["status"] = ConstOutboxStatusDead
+		env.Log(ConstLogStorage, env.ConstLogPrefixError, "event '"+event+"' listener '"+listenerName+"' failed, event moved to dead letters: "+env.ErrorMessage(err))
+	} else {
+		record["status"] = ConstOutboxStatusPending
+		record["next_attempt_at"] = time.Now().Add(it.getRetryDelay(attempts))
+	}
+
+	if _, err := collection.Save(record); err != nil {
+		env.LogError(err)
+	}
+}
+
+// getRetryDelay returns delay before next attempt, it doubles with each failed attempt
+func (it *DefaultEventBus) getRetryDelay(attempts int) time.Duration {
+	delay := it.retryDelay
+	for i := 1; i < attempts && delay < ConstMaxRetryDelay; i++ {
+		delay *= 2
+	}
+
+	if delay > ConstMaxRetryDelay {
+		delay = ConstMaxRetryDelay
+	}
+	return delay
+}
@@ -2,6 +2,7 @@ package env
 
 import (
 	"errors"
+	"strings"
 
 	"github.com/ottemo/commerce/utils"
 )
@@ -23,6 +24,19 @@ func IniValue(Path string) string {
 	return ""
 }
 
+// GetEnvironmentVariableName returns name of environment variable overriding ini value or config path
+//   - name is upper cased prefixed value name where symbols other than letters and digits are replaced with "_",
+//     so "db.mongo.uri" ini value is overridden with "OTTEMO_INI_DB_MONGO_URI" variable
+func GetEnvironmentVariableName(prefix string, name string) string {
+	result := []byte(strings.ToUpper(name))
+	for idx, symbol := range result {
+		if (symbol < 'A' || symbol > 'Z') && (symbol < '0' || symbol > '9') {
+			result[idx] = '_'
+		}
+	}
+	return prefix + string(result)
+}
+
 // Log logs general purpose message
 func Log(storage string, prefix string, message string) {
 	if logger := GetLogger(); logger != nil {
@@ -30,6 +44,14 @@ func Log(storage string, prefix string, message string) {
 	}
 }
 
+// LogWithFields logs message of given level with structured data attached
+//   - level is one of ConstLogLevel... values, messages below logger level are skipped
+func LogWithFields(storage string, level int, message string, fields LogFields) {
+	if logger := GetLogger(); logger != nil {
+		logger.LogWithFields(storage, level, message, fields)
+	}
+}
+
 // LogError logs an error message
 func LogError(err error) {
 	err = ErrorDispatch(err)
@@ -123,6 +145,23 @@ func EventRegisterListener(event string, listener FuncEventListener) {
 	}
 }
 
+// EventRegisterAsyncListener registers background listener for event bus
+//   - listener name should be unique and stable, as it identifies listener for stored events
+//   - event bus without background handling support calls listener synchronously
+func EventRegisterAsyncListener(event string, name string, listener FuncAsyncEventListener) {
+	eventBus := GetEventBus()
+	if asyncEventBus, ok := eventBus.(InterfaceAsyncEventBus); ok {
+		asyncEventBus.RegisterAsyncListener(event, name, listener)
+	} else if eventBus != nil {
+		eventBus.RegisterListener(event, func(event string, eventData map[string]interface{}) bool {
+			if err := listener(event, eventData); err != nil {
+				LogError(err)
+			}
+			return true
+		})
+	}
+}
+
 // Event emits new event for registered listeners
 func Event(event string, args map[string]interface{}) {
 	if eventBus := GetEventBus(); eventBus != nil {
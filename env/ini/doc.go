@@ -10,6 +10,11 @@ if not found in there - looking for a global section.
 Special section [ConstTestSectionName] ("test") used for "test mode" application startup. To start application in that
 mode [ConstCmdArgTestFlag] "--test" should be used.
 
+Any ini value could be overridden with environment variable, it takes precedence over ini file sections and default
+value. Variable name is "OTTEMO_INI_" prefixed value name in upper case with symbols other than letters and digits
+replaced by "_", so "db.mongo.uri" is overridden with OTTEMO_INI_DB_MONGO_URI. Overridden values are not stored to
+ini file. Source of each value is shown by "config/sources" API.
+
     Example 1:
     ----------
         if iniConfig := env.GetIniConfig(); iniConfig != nil {
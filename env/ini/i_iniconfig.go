@@ -2,7 +2,10 @@ package ini
 
 import (
 	"fmt"
+	"os"
 	"strings"
+
+	"github.com/ottemo/commerce/env"
 )
 
 // ListItems returns all ini file values, for current and global sections
@@ -29,8 +32,14 @@ func (it *DefaultIniConfig) ListItems() []string {
 }
 
 // GetValue returns specified value from ini file, looks for value in current section then in global
+//   - environment variable named by env.GetEnvironmentVariableName() with "OTTEMO_INI_" prefix takes precedence
 func (it *DefaultIniConfig) GetValue(valueName string, defaultValue string) string {
 
+	// looking for value in environment variables
+	if value, present := os.LookupEnv(env.GetEnvironmentVariableName(env.ConstEnvironmentPrefixIni, valueName)); present {
+		return value
+	}
+
 	// looking for value in current section and global section
 	for _, sectionName := range []string{it.currentSection, ConstIniGlobalSection} {
 		if sectionValues, present := it.iniFileValues[sectionName]; present {
@@ -108,3 +117,21 @@ func (it *DefaultIniConfig) SetValue(valueName string, value string) error {
 
 	return nil
 }
+
+// GetValueSource returns where GetValue takes value from: environment, file or default, blank if value was not requested
+func (it *DefaultIniConfig) GetValueSource(valueName string) string {
+	if _, present := os.LookupEnv(env.GetEnvironmentVariableName(env.ConstEnvironmentPrefixIni, valueName)); present {
+		return env.ConstValueSourceEnvironment
+	}
+
+	for _, sectionName := range []string{it.currentSection, ConstIniGlobalSection} {
+		if _, present := it.iniFileValues[sectionName][valueName]; present {
+			if it.keysToStore[valueName] {
+				return env.ConstValueSourceFile
+			}
+			return env.ConstValueSourceDefault
+		}
+	}
+
+	return ""
+}
@@ -16,6 +16,7 @@ import (
 func init() {
 	instance := new(DefaultIniConfig)
 	var _ env.InterfaceIniConfig = instance
+	var _ env.InterfaceValueSources = instance
 
 	instance.iniFileValues = make(map[string]map[string]string)
 	instance.keysToStore = make(map[string]bool)
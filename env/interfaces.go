@@ -27,6 +27,19 @@ const (
 	ConstLogPrefixDebug   = "DEBUG"
 	ConstLogPrefixInfo    = "INFO"
 
+	ConstLogLevelDebug   = 0
+	ConstLogLevelInfo    = 1
+	ConstLogLevelWarning = 2
+	ConstLogLevelError   = 3
+
+	ConstEnvironmentPrefixIni    = "OTTEMO_INI_"    // prefix of environment variables overriding ini values
+	ConstEnvironmentPrefixConfig = "OTTEMO_CONFIG_" // prefix of environment variables overriding config values
+
+	ConstValueSourceEnvironment = "environment"
+	ConstValueSourceFile        = "file"
+	ConstValueSourceDatabase    = "database"
+	ConstValueSourceDefault     = "default"
+
 	ConstErrorLevelAPI        = 10
 	ConstErrorLevelModel      = 9
 	ConstErrorLevelActor      = 8
@@ -73,6 +86,12 @@ type InterfaceEventBus interface {
 	New(event string, eventData map[string]interface{})
 }
 
+// InterfaceAsyncEventBus is an optional interface to event processor able to handle events in background
+//   - async listener is called after sync ones and could not stop event propagation
+type InterfaceAsyncEventBus interface {
+	RegisterAsyncListener(event string, name string, listener FuncAsyncEventListener)
+}
+
 // InterfaceErrorBus is an interface to system error processor
 type InterfaceErrorBus interface {
 	GetErrorLevel(error) int
@@ -92,11 +111,16 @@ type InterfaceErrorBus interface {
 // InterfaceLogger is an interface to system logging service
 type InterfaceLogger interface {
 	Log(storage string, prefix string, message string)
+	LogWithFields(storage string, level int, message string, fields LogFields)
 
 	LogError(err error)
 	LogEvent(f LogFields, eventName string)
+
+	GetLevel() int
+	Flush() error
 }
 
+// LogFields is a structured data attached to log message
 type LogFields map[string]interface{}
 
 // InterfaceIniConfig is an interface to startup configuration predefined values service
@@ -112,6 +136,11 @@ type InterfaceIniConfig interface {
 	ListSectionItems(sectionName string) []string
 }
 
+// InterfaceValueSources is an interface to ini or config service able to tell where effective value was taken from
+type InterfaceValueSources interface {
+	GetValueSource(name string) string
+}
+
 // InterfaceConfig is an interface to configuration values managing service
 type InterfaceConfig interface {
 	RegisterItem(Item StructConfigItem, Validator FuncConfigValueValidator) error
@@ -135,6 +164,7 @@ type InterfaceOttemoError interface {
 	ErrorCode() string
 	ErrorMessage() string
 	ErrorCallStack() string
+	ErrorRequestID() string
 
 	IsHandled() bool
 	MarkHandled() bool
@@ -152,6 +182,11 @@ type FuncConfigValueValidator func(interface{}) (interface{}, error)
 //   - return value is continue flag, so listener should return false to stop event propagation
 type FuncEventListener func(string, map[string]interface{}) bool
 
+// FuncAsyncEventListener is a background event listener callback function prototype
+//   - event data is stored until listener succeeds, so it holds only JSON compatible values
+//   - returned error makes event to be handled by listener again later
+type FuncAsyncEventListener func(string, map[string]interface{}) error
+
 // FuncErrorListener is an error listener callback function prototype
 type FuncErrorListener func(error) bool
 
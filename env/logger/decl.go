@@ -1,6 +1,13 @@
 package logger
 
-import "github.com/ottemo/commerce/env"
+import (
+	"bufio"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ottemo/commerce/env"
+)
 
 // Package global constants
 const (
@@ -9,6 +16,23 @@ const (
 	ConstConfigPathError         = "general.error"
 	ConstConfigPathErrorLogLevel = "general.error.log_level"
 
+	ConstSinkFile   = "file"   // plain files within log folder, one file per storage
+	ConstSinkStdout = "stdout" // JSON lines to standard output
+	ConstSinkSyslog = "syslog" // JSON messages to syslog daemon over local socket
+
+	ConstFormatText = "text"
+	ConstFormatJSON = "json"
+
+	ConstRotateDaily  = "daily"
+	ConstRotateHourly = "hourly"
+
+	ConstBufferSize        = 1024        // amount of log entries could be queued before logging calls block
+	ConstFlushInterval     = time.Second // interval buffered entries are flushed to sinks
+	ConstDefaultMaxSize    = 100         // size of log file in megabytes it is rotated after
+	ConstDefaultMaxBackups = 10          // amount of rotated files kept for each storage
+
+	ConstEventsStorage = "events.log" // storage of LogEvent() entries, always written in JSON for logstash consumption
+
 	ConstErrorModule = "env/logger"
 	ConstErrorLevel  = env.ConstErrorLevelService
 )
@@ -20,7 +44,66 @@ var (
 	defaultErrorsFile = "errors.log" // filename for errors log
 
 	errorLogLevel = 5
+
+	levelNames = map[int]string{
+		env.ConstLogLevelDebug:   env.ConstLogPrefixDebug,
+		env.ConstLogLevelInfo:    env.ConstLogPrefixInfo,
+		env.ConstLogLevelWarning: env.ConstLogPrefixWarning,
+		env.ConstLogLevelError:   env.ConstLogPrefixError,
+	}
 )
 
 // DefaultLogger is a default implementer of InterfaceLogger
-type DefaultLogger struct{}
+//   - entries are queued and written to sinks by separate go routine, so logging does not wait for IO
+type DefaultLogger struct {
+	level      int
+	sinks      []InterfaceSink
+	sinksMutex sync.RWMutex
+
+	entries       chan *StructLogEntry
+	flushRequests chan chan error
+}
+
+// StructLogEntry is a log message with its attributes
+type StructLogEntry struct {
+	Time      time.Time
+	Level     int
+	Prefix    string
+	Storage   string
+	Message   string
+	Fields    env.LogFields
+	RequestID string // identifier of API request entry was made within
+}
+
+// InterfaceSink is an interface to log entries destination
+type InterfaceSink interface {
+	Write(entry *StructLogEntry) error
+	Flush() error
+	Close() error
+}
+
+// FileSink is a log sink writing entries to files within log folder
+//   - files are rotated after reaching maximal size and/or on rotation period change
+type FileSink struct {
+	folder     string
+	format     string
+	maxSize    int64
+	rotate     string
+	maxBackups int
+
+	files map[string]*logFile
+}
+
+// logFile is an opened log file of FileSink
+type logFile struct {
+	path   string
+	file   *os.File
+	writer *bufio.Writer
+	size   int64
+	period string
+}
+
+// StdoutSink is a log sink writing entries to standard output as JSON lines
+type StdoutSink struct {
+	writer *bufio.Writer
+}
@@ -3,8 +3,20 @@
 /*
 Package logger is a default implementation of InterfaceLogger declared in "github.com/ottemo/commerce/env" package.
 
-Default logger is pretty simple implementation, which takes an message and puts it into a "storage" (a file with specific
-name in this case). If for some reason message can not be places in file (file access denied, etc.) message will be
-printed to stdout. Message time (in RFC3339 format) and specified prefix adds to message before output.
+Default logger takes a message and puts it into a "storage" (a file with specific name for file sink). Messages are
+queued and written by separate go routine, so logging call does not wait for IO, buffered data is flushed each second,
+after error messages and on application end. If for some reason message can not be placed in storage (file access
+denied, etc.) message will be printed to stdout. Message time (in RFC3339 format) and specified prefix adds to message
+before output.
+
+Each message has a level (debug, info, warning or error) taken from prefix or specified explicitly with LogWithFields()
+along with structured fields. Messages below "logger.level" ini value are skipped.
+
+Messages are written to sinks listed in "logger.sinks" ini value:
+    file   - files within "logger.folder", rotated by size ("logger.file.maxSize" megabytes) and/or period
+             ("logger.file.rotate" = daily|hourly), "logger.file.maxBackups" rotated files are kept;
+             "logger.file.format" = text|json, events storage is always written as logstash JSON
+    stdout - logstash JSON lines to standard output
+    syslog - logstash JSON messages to local syslog socket ("logger.syslog.address" for custom socket path)
 */
 package logger
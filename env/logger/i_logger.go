@@ -1,32 +1,34 @@
 package logger
 
 import (
-	"encoding/json"
-	"fmt"
-	"os"
 	"time"
 
 	"github.com/ottemo/commerce/env"
-	"io"
+	"github.com/ottemo/commerce/utils"
 )
 
 // Log is a general case logging function
+//   - message level is taken from prefix, unknown prefixes are info messages
 func (it *DefaultLogger) Log(storage string, prefix string, msg string) {
-	message := time.Now().Format(time.RFC3339) + " [" + prefix + "]: " + msg + "\n"
-
-	logFile, err := os.OpenFile(baseDirectory+storage, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0660)
-	if err != nil {
-		fmt.Println(message)
-		return
-	}
-
-	if _, err := logFile.Write([]byte(message)); err != nil {
-		fmt.Println(err)
-	}
+	it.log(&StructLogEntry{
+		Time:    time.Now(),
+		Level:   getPrefixLevel(prefix),
+		Prefix:  prefix,
+		Storage: storage,
+		Message: msg,
+	})
+}
 
-	if err := logFile.Close(); err != nil {
-		fmt.Println(err)
-	}
+// LogWithFields logs message of given level with structured data attached
+func (it *DefaultLogger) LogWithFields(storage string, level int, message string, fields env.LogFields) {
+	it.log(&StructLogEntry{
+		Time:    time.Now(),
+		Level:   level,
+		Prefix:  getLevelName(level),
+		Storage: storage,
+		Message: message,
+		Fields:  fields,
+	})
 }
 
 // LogError makes error log
@@ -34,7 +36,14 @@ func (it *DefaultLogger) LogError(err error) {
 	if err != nil {
 		if ottemoErr, ok := err.(env.InterfaceOttemoError); ok {
 			if ottemoErr.ErrorLevel() <= errorLogLevel && !ottemoErr.IsLogged() {
-				it.Log(defaultErrorsFile, env.ConstLogPrefixError, ottemoErr.ErrorFull())
+				it.log(&StructLogEntry{
+					Time:      time.Now(),
+					Level:     env.ConstLogLevelError,
+					Prefix:    env.ConstLogPrefixError,
+					Storage:   defaultErrorsFile,
+					Message:   ottemoErr.ErrorFull(),
+					RequestID: ottemoErr.ErrorRequestID(),
+				})
 				ottemoErr.MarkLogged()
 			}
 		} else {
@@ -43,46 +52,29 @@ func (it *DefaultLogger) LogError(err error) {
 	}
 }
 
-// LogEvent Saves log details out to a file for logstash consumption
+// LogEvent saves log details to events storage for logstash consumption
+//   - "level" field could be used to specify message level, it is info by default
 func (it *DefaultLogger) LogEvent(fields env.LogFields, eventName string) {
-	f, err := os.OpenFile(baseDirectory+"events.log", os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0660)
-	if err != nil {
-		fmt.Println(err)
-	}
-	defer func(c io.Closer){
-		if err := c.Close(); err != nil {
-			fmt.Println(err)
-		}
-	}(f)
-
-	msg, err := logstashFormatter(fields, eventName)
-	if err != nil {
-		fmt.Println(err)
+	level := env.ConstLogLevelInfo
+	if value, present := fields["level"]; present {
+		level = getPrefixLevel(utils.InterfaceToString(value))
 	}
 
-	if _, err := f.Write(msg); err != nil {
-		fmt.Println(err)
-	}
+	it.LogWithFields(ConstEventsStorage, level, eventName, fields)
 }
 
-func logstashFormatter(fields env.LogFields, eventName string) ([]byte, error) {
-	// Attach the message
-	fields["message"] = eventName
-
-	// Logstash required fields
-	fields["@version"] = 1
-	fields["@timestamp"] = time.Now().Format(time.RFC3339)
+// GetLevel returns minimal level of messages being logged
+func (it *DefaultLogger) GetLevel() int {
+	it.sinksMutex.RLock()
+	defer it.sinksMutex.RUnlock()
 
-	// default to info level
-	_, ok := fields["level"]
-	if !ok {
-		fields["level"] = "INFO"
-	}
+	return it.level
+}
 
-	serialized, err := json.Marshal(fields)
-	if err != nil {
-		return nil, err
-	}
+// Flush waits for queued entries to be written to sinks
+func (it *DefaultLogger) Flush() error {
+	done := make(chan error)
+	it.flushRequests <- done
 
-	return append(serialized, '\n'), nil
+	return <-done
 }
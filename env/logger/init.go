@@ -1,30 +1,56 @@
 package logger
 
 import (
-	"os"
-
 	"fmt"
+	"os"
+	"strings"
 
 	logrus_logstash "github.com/bshuster-repo/logrus-logstash-hook"
+	"github.com/ottemo/commerce/app"
 	"github.com/ottemo/commerce/env"
+	"github.com/ottemo/commerce/utils"
 	log "github.com/sirupsen/logrus"
 )
 
 // init makes package self-initialization routine
 func init() {
 	log.SetFormatter(&logrus_logstash.LogstashFormatter{Type: "ottemo_api"})
-	instance := new(DefaultLogger)
+	instance := &DefaultLogger{
+		level:         env.ConstLogLevelDebug,
+		sinks:         []InterfaceSink{NewFileSink(baseDirectory, ConstFormatText, ConstDefaultMaxSize, "", ConstDefaultMaxBackups)},
+		entries:       make(chan *StructLogEntry, ConstBufferSize),
+		flushRequests: make(chan chan error),
+	}
 	var _ env.InterfaceLogger = instance
 
+	go instance.run()
+
 	if err := env.RegisterLogger(instance); err != nil {
 		fmt.Println(err.Error())
 	}
-	env.RegisterOnConfigIniStart(startup)
+	env.RegisterOnConfigIniStart(instance.startup)
 	env.RegisterOnConfigStart(setupConfig)
+	app.OnAppEnd(instance.Flush)
 }
 
-// startup is a service pre-initialization stuff
-func startup() error {
+// startup is a service pre-initialization stuff, it setups logger sinks from ini config
+//   - "logger.sinks" is a comma separated list of "file", "stdout" and "syslog" values, "file" by default
+//   - "logger.level" is a minimal level of logged messages: "debug", "info", "warning" or "error"
+//   - "logger.folder", "logger.file.format" ("text" or "json"), "logger.file.maxSize" (megabytes),
+//     "logger.file.rotate" ("daily" or "hourly") and "logger.file.maxBackups" setup file sink
+//   - "logger.syslog.network", "logger.syslog.address" and "logger.syslog.tag" setup syslog sink
+func (it *DefaultLogger) startup() error {
+	iniValue := func(name string, defaultValue string) string {
+		if iniConfig := env.GetIniConfig(); iniConfig != nil {
+			return iniConfig.GetValue(name, defaultValue)
+		}
+		return defaultValue
+	}
+
+	if folder := iniValue("logger.folder", ""); folder != "" {
+		baseDirectory = strings.TrimSuffix(folder, "/") + "/"
+	}
+
 	if _, err := os.Stat(baseDirectory); !os.IsExist(err) {
 		err := os.MkdirAll(baseDirectory, os.ModePerm)
 		if err != nil {
@@ -32,5 +58,43 @@ func startup() error {
 		}
 	}
 
+	level := getPrefixLevel(iniValue("logger.level", env.ConstLogPrefixDebug))
+
+	var sinks []InterfaceSink
+	for _, sinkName := range utils.Explode(iniValue("logger.sinks", ConstSinkFile), ",") {
+		switch sinkName {
+		case ConstSinkFile:
+			sinks = append(sinks, NewFileSink(
+				baseDirectory,
+				iniValue("logger.file.format", ConstFormatText),
+				utils.InterfaceToInt(iniValue("logger.file.maxSize", utils.InterfaceToString(ConstDefaultMaxSize))),
+				iniValue("logger.file.rotate", ""),
+				utils.InterfaceToInt(iniValue("logger.file.maxBackups", utils.InterfaceToString(ConstDefaultMaxBackups)))))
+
+		case ConstSinkStdout:
+			sinks = append(sinks, NewStdoutSink())
+
+		case ConstSinkSyslog:
+			sink, err := NewSyslogSink(iniValue("logger.syslog.network", ""), iniValue("logger.syslog.address", ""), iniValue("logger.syslog.tag", "ottemo"))
+			if err != nil {
+				fmt.Println(err)
+				continue
+			}
+			sinks = append(sinks, sink)
+
+		default:
+			fmt.Println("unknown logger sink '" + sinkName + "'")
+		}
+	}
+
+	if len(sinks) == 0 {
+		sinks = append(sinks, NewStdoutSink())
+	}
+
+	if err := it.Flush(); err != nil {
+		fmt.Println(err)
+	}
+	it.setSinks(sinks, level)
+
 	return nil
 }
@@ -0,0 +1,170 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ottemo/commerce/api/context"
+	"github.com/ottemo/commerce/env"
+)
+
+// getPrefixLevel returns level of message by its prefix, unknown prefixes are info level
+func getPrefixLevel(prefix string) int {
+	prefix = strings.ToUpper(prefix)
+
+	switch {
+	case strings.HasPrefix(prefix, env.ConstLogPrefixError):
+		return env.ConstLogLevelError
+	case strings.HasPrefix(prefix, "WARN"):
+		return env.ConstLogLevelWarning
+	case strings.HasPrefix(prefix, env.ConstLogPrefixDebug):
+		return env.ConstLogLevelDebug
+	}
+	return env.ConstLogLevelInfo
+}
+
+// getLevelName returns prefix used for messages of given level
+func getLevelName(level int) string {
+	if name, present := levelNames[level]; present {
+		return name
+	}
+	return env.ConstLogPrefixInfo
+}
+
+// log queues entry to be written by sinks, entries below logger level are skipped
+//   - request identifier is taken from call stack context, so it should be called by logging routine directly
+func (it *DefaultLogger) log(entry *StructLogEntry) {
+	if entry.Level < it.GetLevel() {
+		return
+	}
+	if entry.RequestID == "" {
+		entry.RequestID = context.GetRequestID()
+	}
+	it.entries <- entry
+}
+
+// run writes queued entries to sinks, flushes them periodically and by request
+func (it *DefaultLogger) run() {
+	ticker := time.NewTicker(ConstFlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case entry := <-it.entries:
+			it.write(entry)
+
+		case <-ticker.C:
+			it.flushSinks()
+
+		case done := <-it.flushRequests:
+			for isEmpty := false; !isEmpty; {
+				select {
+				case entry := <-it.entries:
+					it.write(entry)
+				default:
+					isEmpty = true
+				}
+			}
+			done <- it.flushSinks()
+		}
+	}
+}
+
+// write passes entry to each of sinks, error entries are flushed immediately
+//   - entry is printed to stdout if sink fails to write it, sinks should not use env errors as it would make
+//     logging from the logger routine
+func (it *DefaultLogger) write(entry *StructLogEntry) {
+	it.sinksMutex.RLock()
+	defer it.sinksMutex.RUnlock()
+
+	for _, sink := range it.sinks {
+		if err := sink.Write(entry); err != nil {
+			fmt.Println(err)
+			fmt.Print(string(formatText(entry)))
+		}
+	}
+
+	if entry.Level >= env.ConstLogLevelError {
+		for _, sink := range it.sinks {
+			if err := sink.Flush(); err != nil {
+				fmt.Println(err)
+			}
+		}
+	}
+}
+
+// flushSinks makes sinks to write buffered data
+func (it *DefaultLogger) flushSinks() error {
+	it.sinksMutex.RLock()
+	defer it.sinksMutex.RUnlock()
+
+	var result error
+	for _, sink := range it.sinks {
+		if err := sink.Flush(); err != nil {
+			fmt.Println(err)
+			result = err
+		}
+	}
+	return result
+}
+
+// setSinks replaces logger sinks and level, previous sinks are closed
+func (it *DefaultLogger) setSinks(sinks []InterfaceSink, level int) {
+	it.sinksMutex.Lock()
+	defer it.sinksMutex.Unlock()
+
+	for _, sink := range it.sinks {
+		if err := sink.Close(); err != nil {
+			fmt.Println(err)
+		}
+	}
+
+	it.sinks = sinks
+	it.level = level
+}
+
+// formatText represents entry as a text line: time, prefix, message and JSON of fields if any
+func formatText(entry *StructLogEntry) []byte {
+	line := entry.Time.Format(time.RFC3339) + " [" + entry.Prefix + "]"
+	if entry.RequestID != "" {
+		line += " [" + entry.RequestID + "]"
+	}
+	line += ": " + entry.Message
+
+	if len(entry.Fields) > 0 {
+		if fields, err := json.Marshal(entry.Fields); err == nil {
+			line += " " + string(fields)
+		}
+	}
+
+	return []byte(line + "\n")
+}
+
+// formatJSON represents entry as a logstash compatible JSON line
+//   - entry fields are placed on top level, "level" field overrides entry level name
+func formatJSON(entry *StructLogEntry) ([]byte, error) {
+	values := make(map[string]interface{}, len(entry.Fields)+5)
+	for key, value := range entry.Fields {
+		values[key] = value
+	}
+
+	values["message"] = entry.Message
+	values["storage"] = entry.Storage
+	values["@version"] = 1
+	values["@timestamp"] = entry.Time.Format(time.RFC3339)
+	if _, present := values["request_id"]; !present && entry.RequestID != "" {
+		values["request_id"] = entry.RequestID
+	}
+	if _, present := values["level"]; !present {
+		values["level"] = getLevelName(entry.Level)
+	}
+
+	serialized, err := json.Marshal(values)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(serialized, '\n'), nil
+}
@@ -0,0 +1,158 @@
+package logger
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ottemo/commerce/api/context"
+	"github.com/ottemo/commerce/env"
+)
+
+// newTestLogger makes running logger with given sinks
+func newTestLogger(level int, sinks ...InterfaceSink) *DefaultLogger {
+	instance := &DefaultLogger{
+		level:         level,
+		sinks:         sinks,
+		entries:       make(chan *StructLogEntry, ConstBufferSize),
+		flushRequests: make(chan chan error),
+	}
+	go instance.run()
+
+	return instance
+}
+
+// readLines returns lines of log file
+func readLines(t *testing.T, path string) []string {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal("ioutil.ReadFile", err)
+	}
+	return strings.Split(strings.TrimSpace(string(content)), "\n")
+}
+
+func TestLevelsAndFormats(t *testing.T) {
+	folder, err := ioutil.TempDir("", "logger")
+	if err != nil {
+		t.Fatal("ioutil.TempDir", err)
+	}
+	defer os.RemoveAll(folder)
+
+	logger := newTestLogger(env.ConstLogLevelInfo, NewFileSink(folder, ConstFormatText, 0, "", 0))
+
+	logger.Log("test.log", env.ConstLogPrefixDebug, "skipped")
+	logger.Log("test.log", env.ConstLogPrefixWarning, "warning message")
+	logger.LogWithFields("test.log", env.ConstLogLevelError, "error message", env.LogFields{"key": "value"})
+	logger.LogEvent(env.LogFields{"count": 1}, "event")
+
+	if err := logger.Flush(); err != nil {
+		t.Fatal("Flush", err)
+	}
+
+	lines := readLines(t, filepath.Join(folder, "test.log"))
+	if len(lines) != 2 {
+		t.Fatalf("unexpected log lines: %v", lines)
+	}
+	if !strings.HasSuffix(lines[0], " [WARNING]: warning message") {
+		t.Errorf("unexpected text line %q", lines[0])
+	}
+	if !strings.HasSuffix(lines[1], ` [ERROR]: error message {"key":"value"}`) {
+		t.Errorf("unexpected text line with fields %q", lines[1])
+	}
+
+	var event map[string]interface{}
+	if err := json.Unmarshal([]byte(readLines(t, filepath.Join(folder, ConstEventsStorage))[0]), &event); err != nil {
+		t.Fatal("json.Unmarshal", err)
+	}
+	if event["message"] != "event" || event["level"] != env.ConstLogPrefixInfo || event["count"] != 1.0 || event["@version"] != 1.0 {
+		t.Errorf("unexpected event line %v", event)
+	}
+}
+
+func TestRequestID(t *testing.T) {
+	folder, err := ioutil.TempDir("", "logger")
+	if err != nil {
+		t.Fatal("ioutil.TempDir", err)
+	}
+	defer os.RemoveAll(folder)
+
+	logger := newTestLogger(env.ConstLogLevelInfo, NewFileSink(folder, ConstFormatText, 0, "", 0))
+
+	context.RunInContext(func() {
+		logger.Log("test.log", env.ConstLogPrefixInfo, "request message")
+		logger.LogEvent(env.LogFields{}, "request")
+	}, map[string]interface{}{context.ConstKeyRequestID: "test-request"})
+	logger.Log("test.log", env.ConstLogPrefixInfo, "other message")
+
+	if err := logger.Flush(); err != nil {
+		t.Fatal("Flush", err)
+	}
+
+	lines := readLines(t, filepath.Join(folder, "test.log"))
+	if len(lines) != 2 {
+		t.Fatalf("unexpected log lines: %v", lines)
+	}
+	if !strings.HasSuffix(lines[0], " [INFO] [test-request]: request message") {
+		t.Errorf("unexpected text line with request id %q", lines[0])
+	}
+	if !strings.HasSuffix(lines[1], " [INFO]: other message") {
+		t.Errorf("unexpected text line without request id %q", lines[1])
+	}
+
+	var event map[string]interface{}
+	if err := json.Unmarshal([]byte(readLines(t, filepath.Join(folder, ConstEventsStorage))[0]), &event); err != nil {
+		t.Fatal("json.Unmarshal", err)
+	}
+	if event["request_id"] != "test-request" {
+		t.Errorf("unexpected event line %v", event)
+	}
+}
+
+func TestRotation(t *testing.T) {
+	folder, err := ioutil.TempDir("", "logger")
+	if err != nil {
+		t.Fatal("ioutil.TempDir", err)
+	}
+	defer os.RemoveAll(folder)
+
+	sink := NewFileSink(folder, ConstFormatJSON, 0, ConstRotateHourly, 2)
+	sink.maxSize = 150
+
+	moment := time.Date(2019, 3, 10, 12, 0, 0, 0, time.UTC)
+	write := func(message string) {
+		if err := sink.Write(&StructLogEntry{Time: moment, Storage: "test.log", Message: message}); err != nil {
+			t.Fatal("Write", err)
+		}
+	}
+
+	// size based rotation
+	write("first")
+	write("second")
+
+	// time based rotation
+	moment = moment.Add(time.Hour)
+	write("third")
+	moment = moment.Add(time.Hour)
+	write("fourth")
+
+	if err := sink.Close(); err != nil {
+		t.Fatal("Close", err)
+	}
+
+	lines := readLines(t, filepath.Join(folder, "test.log"))
+	if len(lines) != 1 || !strings.Contains(lines[0], `"message":"fourth"`) {
+		t.Errorf("unexpected current file content %v", lines)
+	}
+
+	backups, err := filepath.Glob(filepath.Join(folder, "test.log.*"))
+	if err != nil {
+		t.Fatal("filepath.Glob", err)
+	}
+	if len(backups) != 2 {
+		t.Errorf("unexpected backups %v", backups)
+	}
+}
@@ -0,0 +1,173 @@
+package logger
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// NewFileSink makes sink writing entries to files within given folder
+//   - format is ConstFormatText or ConstFormatJSON, events storage is always written in JSON
+//   - maxSize is a file size in megabytes it is rotated after, 0 - no size limit
+//   - rotate is ConstRotateDaily, ConstRotateHourly or "" for no time based rotation
+//   - maxBackups is an amount of rotated files kept for each storage, 0 - keep all
+func NewFileSink(folder string, format string, maxSize int, rotate string, maxBackups int) *FileSink {
+	return &FileSink{
+		folder:     folder,
+		format:     format,
+		maxSize:    int64(maxSize) * 1024 * 1024,
+		rotate:     rotate,
+		maxBackups: maxBackups,
+		files:      make(map[string]*logFile),
+	}
+}
+
+// Write puts entry to file of entry storage, rotating it if needed
+func (it *FileSink) Write(entry *StructLogEntry) error {
+	line := formatText(entry)
+	if it.format == ConstFormatJSON || entry.Storage == ConstEventsStorage {
+		jsonLine, err := formatJSON(entry)
+		if err != nil {
+			return err
+		}
+		line = jsonLine
+	}
+
+	file, err := it.getFile(entry.Storage, entry.Time, int64(len(line)))
+	if err != nil {
+		return err
+	}
+
+	written, err := file.writer.Write(line)
+	file.size += int64(written)
+
+	return err
+}
+
+// Flush writes buffered data of opened files
+func (it *FileSink) Flush() error {
+	var result error
+	for _, file := range it.files {
+		if err := file.writer.Flush(); err != nil {
+			result = err
+		}
+	}
+	return result
+}
+
+// Close flushes and closes opened files
+func (it *FileSink) Close() error {
+	var result error
+	for storage, file := range it.files {
+		if err := file.close(); err != nil {
+			result = err
+		}
+		delete(it.files, storage)
+	}
+	return result
+}
+
+// getPeriod returns rotation period given time belongs to
+func (it *FileSink) getPeriod(moment time.Time) string {
+	switch it.rotate {
+	case ConstRotateDaily:
+		return moment.Format("2006-01-02")
+	case ConstRotateHourly:
+		return moment.Format("2006-01-02T15")
+	}
+	return ""
+}
+
+// getFile returns opened file for storage, file is rotated if it would exceed size limit or rotation period changed
+func (it *FileSink) getFile(storage string, moment time.Time, size int64) (*logFile, error) {
+	file, present := it.files[storage]
+	if !present {
+		var err error
+		if file, err = it.openFile(filepath.Join(it.folder, storage)); err != nil {
+			return nil, err
+		}
+		it.files[storage] = file
+	}
+
+	period := it.getPeriod(moment)
+	if file.size > 0 && (period != file.period || (it.maxSize > 0 && file.size+size > it.maxSize)) {
+		if err := file.close(); err != nil {
+			return nil, err
+		}
+		delete(it.files, storage)
+
+		if err := it.rotateFile(file.path); err != nil {
+			return nil, err
+		}
+
+		newFile, err := it.openFile(file.path)
+		if err != nil {
+			return nil, err
+		}
+		it.files[storage] = newFile
+		file = newFile
+	}
+	file.period = period
+
+	return file, nil
+}
+
+// openFile opens log file for appending, period of existing file is taken from its modification time
+func (it *FileSink) openFile(path string) (*logFile, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0660)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+
+	return &logFile{
+		path:   path,
+		file:   file,
+		writer: bufio.NewWriter(file),
+		size:   info.Size(),
+		period: it.getPeriod(info.ModTime()),
+	}, nil
+}
+
+// rotateFile renames log file to timestamped backup and removes backups above the limit
+func (it *FileSink) rotateFile(path string) error {
+	backupPath := path + "." + time.Now().Format("20060102-150405.000000000")
+	if err := os.Rename(path, backupPath); err != nil {
+		return err
+	}
+
+	if it.maxBackups <= 0 {
+		return nil
+	}
+
+	backups, err := filepath.Glob(path + ".*")
+	if err != nil {
+		return err
+	}
+	sort.Strings(backups)
+
+	for len(backups) > it.maxBackups {
+		if err := os.Remove(backups[0]); err != nil {
+			return err
+		}
+		backups = backups[1:]
+	}
+
+	return nil
+}
+
+// close flushes and closes log file
+func (it *logFile) close() error {
+	if err := it.writer.Flush(); err != nil {
+		_ = it.file.Close()
+		return err
+	}
+	return it.file.Close()
+}
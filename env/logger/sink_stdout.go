@@ -0,0 +1,32 @@
+package logger
+
+import (
+	"bufio"
+	"os"
+)
+
+// NewStdoutSink makes sink writing entries to standard output as JSON lines
+func NewStdoutSink() *StdoutSink {
+	return &StdoutSink{writer: bufio.NewWriter(os.Stdout)}
+}
+
+// Write puts entry to standard output buffer
+func (it *StdoutSink) Write(entry *StructLogEntry) error {
+	line, err := formatJSON(entry)
+	if err != nil {
+		return err
+	}
+
+	_, err = it.writer.Write(line)
+	return err
+}
+
+// Flush writes buffered entries to standard output
+func (it *StdoutSink) Flush() error {
+	return it.writer.Flush()
+}
+
+// Close flushes buffered entries, standard output stays opened
+func (it *StdoutSink) Close() error {
+	return it.writer.Flush()
+}
@@ -0,0 +1,58 @@
+// +build !windows,!plan9
+
+package logger
+
+import (
+	"log/syslog"
+
+	"github.com/ottemo/commerce/env"
+)
+
+// SyslogSink is a log sink sending entries to syslog daemon as JSON messages
+type SyslogSink struct {
+	writer *syslog.Writer
+}
+
+// NewSyslogSink makes sink connected to syslog daemon
+//   - empty network and address connects to local syslog socket, "unixgram" network is used for custom socket path
+func NewSyslogSink(network string, address string, tag string) (*SyslogSink, error) {
+	if address != "" && network == "" {
+		network = "unixgram"
+	}
+
+	writer, err := syslog.Dial(network, address, syslog.LOG_INFO|syslog.LOG_USER, tag)
+	if err != nil {
+		return nil, env.ErrorDispatch(err)
+	}
+
+	return &SyslogSink{writer: writer}, nil
+}
+
+// Write sends entry to syslog with severity of entry level
+func (it *SyslogSink) Write(entry *StructLogEntry) error {
+	line, err := formatJSON(entry)
+	if err != nil {
+		return err
+	}
+	message := string(line[:len(line)-1])
+
+	switch entry.Level {
+	case env.ConstLogLevelDebug:
+		return it.writer.Debug(message)
+	case env.ConstLogLevelWarning:
+		return it.writer.Warning(message)
+	case env.ConstLogLevelError:
+		return it.writer.Err(message)
+	}
+	return it.writer.Info(message)
+}
+
+// Flush does nothing as entries are sent immediately
+func (it *SyslogSink) Flush() error {
+	return nil
+}
+
+// Close closes connection to syslog daemon
+func (it *SyslogSink) Close() error {
+	return it.writer.Close()
+}
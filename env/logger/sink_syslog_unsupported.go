@@ -0,0 +1,30 @@
+// +build windows plan9
+
+package logger
+
+import (
+	"github.com/ottemo/commerce/env"
+)
+
+// SyslogSink is a log sink sending entries to syslog daemon, it is not supported on current platform
+type SyslogSink struct{}
+
+// NewSyslogSink returns an error as syslog is not supported on current platform
+func NewSyslogSink(network string, address string, tag string) (*SyslogSink, error) {
+	return nil, env.ErrorNew(ConstErrorModule, ConstErrorLevel, "59fd2121-0a47-4478-b45a-765bf712b045", "syslog is not supported on this platform")
+}
+
+// Write does nothing
+func (it *SyslogSink) Write(entry *StructLogEntry) error {
+	return nil
+}
+
+// Flush does nothing
+func (it *SyslogSink) Flush() error {
+	return nil
+}
+
+// Close does nothing
+func (it *SyslogSink) Close() error {
+	return nil
+}
@@ -8,6 +8,8 @@ import (
 	"time"
 
 	"github.com/ottemo/commerce/app"
+	"github.com/ottemo/commerce/db"
+	"github.com/ottemo/commerce/utils"
 
 	// using standard set of packages
 	_ "github.com/ottemo/commerce/basebuild"
@@ -51,6 +53,18 @@ func main() {
 
 	fmt.Println("Ottemo " + app.GetVerboseVersion())
 
+	// migrations mode - database migrations are applied (or listed on dry run) on database start, then exiting
+	if utils.IsInListStr(db.ConstCmdArgMigrate, os.Args) || db.IsMigrationsDryRun() {
+		migrations, err := db.WaitMigrations()
+		for _, migration := range migrations {
+			fmt.Printf("%s v%d: %s (applied: %t)\n", migration.Module, migration.Version, migration.Description, migration.IsApplied)
+		}
+		if err != nil {
+			fmt.Println(err.Error())
+		}
+		return
+	}
+
 	// starting HTTP server
 	if err := app.Serve(); err != nil {
 		fmt.Println(err.Error())
@@ -9,7 +9,7 @@ import (
 // configures package related API endpoint routines
 func setupAPI() error {
 
-	api.GetRestService().GET("media", api.IsAdminHandler(APIGetMediaInfo))
+	api.GetRestService().GET("media", api.PermissionHandler(ConstPermissionRead, APIGetMediaInfo))
 
 	return nil
 }
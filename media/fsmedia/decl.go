@@ -19,6 +19,8 @@ const (
 
 	ConstConfigPathMediaBaseURL = "general.app.media_base_url"
 
+	ConstPermissionRead = "cms.read"
+
 	ConstErrorModule = "media/fsmedia"
 	ConstErrorLevel  = env.ConstErrorLevelService
 )
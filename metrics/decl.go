@@ -0,0 +1,98 @@
+// Package metrics is a light-weight collector of application metrics exposed in Prometheus text format.
+//
+// Metrics are package level values created once with NewCounter, NewGauge, NewGaugeFunc or NewHistogram, each of them
+// could have labels, values of which are given in the same order on each update.
+package metrics
+
+import (
+	"sync"
+	"time"
+)
+
+// Package global constants
+const (
+	ConstNamespace = "ottemo" // prefix of all metric names
+
+	ConstTypeCounter   = "counter"
+	ConstTypeGauge     = "gauge"
+	ConstTypeHistogram = "histogram"
+
+	ConstContentType = "text/plain; version=0.0.4; charset=utf-8"
+)
+
+// Package global variables
+var (
+	// DefaultBuckets are histogram buckets suitable for request and query durations in seconds
+	DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+	registered      = make(map[string]InterfaceMetric)
+	registeredMutex sync.RWMutex
+
+	startTime = time.Now()
+)
+
+// InterfaceMetric is an interface to metric able to represent itself in Prometheus text format
+type InterfaceMetric interface {
+	GetName() string
+	GetHelp() string
+	GetType() string
+
+	Collect() []StructSample
+}
+
+// StructSample is a single value of metric with its labels
+type StructSample struct {
+	Name        string // metric name with suffix if any (i.e. "_bucket" for histograms)
+	LabelNames  []string
+	LabelValues []string
+	Value       float64
+}
+
+// metric is a base of labeled metrics
+type metric struct {
+	name   string
+	help   string
+	labels []string
+}
+
+// Counter is a metric which value only increases
+type Counter struct {
+	metric
+	values map[string]*floatSample
+	mutex  sync.RWMutex
+}
+
+// Gauge is a metric which value could go up and down
+type Gauge struct {
+	metric
+	values map[string]*floatSample
+	mutex  sync.RWMutex
+}
+
+// GaugeFunc is a gauge which value is taken from a function on each collection
+type GaugeFunc struct {
+	metric
+	function func() float64
+}
+
+// Histogram is a metric which counts observed values within buckets
+type Histogram struct {
+	metric
+	buckets []float64
+	values  map[string]*histogramSample
+	mutex   sync.RWMutex
+}
+
+// floatSample is a value of labeled counter or gauge
+type floatSample struct {
+	labelValues []string
+	value       float64
+}
+
+// histogramSample is a value of labeled histogram
+type histogramSample struct {
+	labelValues []string
+	counts      []uint64 // not cumulative counts within buckets
+	count       uint64
+	sum         float64
+}
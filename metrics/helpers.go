@@ -0,0 +1,95 @@
+package metrics
+
+import (
+	"bufio"
+	"io"
+	"net/http"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+)
+
+// init registers process metrics
+func init() {
+	NewGaugeFunc("uptime_seconds", "Time since application start in seconds.", func() float64 {
+		return time.Since(startTime).Seconds()
+	})
+	NewGaugeFunc("goroutines", "Number of running go routines.", func() float64 {
+		return float64(runtime.NumGoroutine())
+	})
+}
+
+// Register adds metric to collected ones, metric with the same name is replaced
+func Register(metric InterfaceMetric) {
+	registeredMutex.Lock()
+	defer registeredMutex.Unlock()
+
+	registered[metric.GetName()] = metric
+}
+
+// GetMetric returns registered metric by its full name or nil
+func GetMetric(name string) InterfaceMetric {
+	registeredMutex.RLock()
+	defer registeredMutex.RUnlock()
+
+	return registered[name]
+}
+
+// Write outputs registered metrics in Prometheus text format ordered by name
+func Write(writer io.Writer) error {
+	registeredMutex.RLock()
+	metrics := make([]InterfaceMetric, 0, len(registered))
+	for _, metric := range registered {
+		metrics = append(metrics, metric)
+	}
+	registeredMutex.RUnlock()
+
+	sort.Slice(metrics, func(i, j int) bool { return metrics[i].GetName() < metrics[j].GetName() })
+
+	buffer := bufio.NewWriter(writer)
+	for _, metric := range metrics {
+		samples := metric.Collect()
+		if len(samples) == 0 {
+			continue
+		}
+
+		buffer.WriteString("# HELP " + metric.GetName() + " " + escapeHelp(metric.GetHelp()) + "\n")
+		buffer.WriteString("# TYPE " + metric.GetName() + " " + metric.GetType() + "\n")
+
+		for _, sample := range samples {
+			buffer.WriteString(sample.Name)
+			if len(sample.LabelNames) > 0 {
+				buffer.WriteString("{")
+				for idx, labelName := range sample.LabelNames {
+					if idx > 0 {
+						buffer.WriteString(",")
+					}
+					buffer.WriteString(labelName + `="` + escapeLabelValue(sample.LabelValues[idx]) + `"`)
+				}
+				buffer.WriteString("}")
+			}
+			buffer.WriteString(" " + formatValue(sample.Value) + "\n")
+		}
+	}
+
+	return buffer.Flush()
+}
+
+// Handler returns HTTP handler which outputs registered metrics
+func Handler() http.Handler {
+	return http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
+		responseWriter.Header().Set("Content-Type", ConstContentType)
+		_ = Write(responseWriter)
+	})
+}
+
+// escapeHelp escapes backslashes and line feeds of metric description
+func escapeHelp(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(value)
+}
+
+// escapeLabelValue escapes backslashes, quotes and line feeds of label value
+func escapeLabelValue(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
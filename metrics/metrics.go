@@ -0,0 +1,296 @@
+package metrics
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// newMetric makes metric base with namespace prefixed name
+func newMetric(name string, help string, labels []string) metric {
+	return metric{name: ConstNamespace + "_" + name, help: help, labels: labels}
+}
+
+// GetName returns metric name
+func (it *metric) GetName() string {
+	return it.name
+}
+
+// GetHelp returns metric description
+func (it *metric) GetHelp() string {
+	return it.help
+}
+
+// getKey returns key of labeled value, missing label values are empty
+func (it *metric) getKey(labelValues []string) (string, []string) {
+	if len(labelValues) != len(it.labels) {
+		values := make([]string, len(it.labels))
+		copy(values, labelValues)
+		labelValues = values
+	}
+	return strings.Join(labelValues, "\xff"), labelValues
+}
+
+// NewCounter makes and registers counter with given labels
+//   - name is prefixed with namespace, so "http_requests_total" becomes "ottemo_http_requests_total"
+func NewCounter(name string, help string, labels ...string) *Counter {
+	counter := &Counter{metric: newMetric(name, help, labels), values: make(map[string]*floatSample)}
+	Register(counter)
+	return counter
+}
+
+// GetType returns metric type
+func (it *Counter) GetType() string {
+	return ConstTypeCounter
+}
+
+// Add increases counter value for given label values, negative values are ignored
+func (it *Counter) Add(value float64, labelValues ...string) {
+	if value < 0 {
+		return
+	}
+
+	key, labelValues := it.getKey(labelValues)
+
+	it.mutex.Lock()
+	defer it.mutex.Unlock()
+
+	sample, present := it.values[key]
+	if !present {
+		sample = &floatSample{labelValues: labelValues}
+		it.values[key] = sample
+	}
+	sample.value += value
+}
+
+// Inc increases counter value for given label values by one
+func (it *Counter) Inc(labelValues ...string) {
+	it.Add(1, labelValues...)
+}
+
+// Get returns counter value for given label values
+func (it *Counter) Get(labelValues ...string) float64 {
+	key, _ := it.getKey(labelValues)
+
+	it.mutex.RLock()
+	defer it.mutex.RUnlock()
+
+	if sample, present := it.values[key]; present {
+		return sample.value
+	}
+	return 0
+}
+
+// Collect returns counter samples
+func (it *Counter) Collect() []StructSample {
+	it.mutex.RLock()
+	defer it.mutex.RUnlock()
+
+	return collectFloatSamples(it.name, it.labels, it.values)
+}
+
+// NewGauge makes and registers gauge with given labels
+func NewGauge(name string, help string, labels ...string) *Gauge {
+	gauge := &Gauge{metric: newMetric(name, help, labels), values: make(map[string]*floatSample)}
+	Register(gauge)
+	return gauge
+}
+
+// GetType returns metric type
+func (it *Gauge) GetType() string {
+	return ConstTypeGauge
+}
+
+// update changes gauge value for given label values with a function
+func (it *Gauge) update(labelValues []string, function func(float64) float64) {
+	key, labelValues := it.getKey(labelValues)
+
+	it.mutex.Lock()
+	defer it.mutex.Unlock()
+
+	sample, present := it.values[key]
+	if !present {
+		sample = &floatSample{labelValues: labelValues}
+		it.values[key] = sample
+	}
+	sample.value = function(sample.value)
+}
+
+// Set sets gauge value for given label values
+func (it *Gauge) Set(value float64, labelValues ...string) {
+	it.update(labelValues, func(float64) float64 { return value })
+}
+
+// Add changes gauge value for given label values by delta
+func (it *Gauge) Add(delta float64, labelValues ...string) {
+	it.update(labelValues, func(value float64) float64 { return value + delta })
+}
+
+// Inc increases gauge value for given label values by one
+func (it *Gauge) Inc(labelValues ...string) {
+	it.Add(1, labelValues...)
+}
+
+// Dec decreases gauge value for given label values by one
+func (it *Gauge) Dec(labelValues ...string) {
+	it.Add(-1, labelValues...)
+}
+
+// Collect returns gauge samples
+func (it *Gauge) Collect() []StructSample {
+	it.mutex.RLock()
+	defer it.mutex.RUnlock()
+
+	return collectFloatSamples(it.name, it.labels, it.values)
+}
+
+// NewGaugeFunc makes and registers gauge which value is returned by function on each collection
+func NewGaugeFunc(name string, help string, function func() float64) *GaugeFunc {
+	gauge := &GaugeFunc{metric: newMetric(name, help, nil), function: function}
+	Register(gauge)
+	return gauge
+}
+
+// GetType returns metric type
+func (it *GaugeFunc) GetType() string {
+	return ConstTypeGauge
+}
+
+// Collect returns gauge sample
+func (it *GaugeFunc) Collect() []StructSample {
+	return []StructSample{{Name: it.name, Value: it.function()}}
+}
+
+// NewHistogram makes and registers histogram with given upper bounds of buckets and labels
+//   - DefaultBuckets are used if buckets are not specified
+func NewHistogram(name string, help string, buckets []float64, labels ...string) *Histogram {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+	buckets = append([]float64{}, buckets...)
+	sort.Float64s(buckets)
+
+	histogram := &Histogram{metric: newMetric(name, help, labels), buckets: buckets, values: make(map[string]*histogramSample)}
+	Register(histogram)
+	return histogram
+}
+
+// GetType returns metric type
+func (it *Histogram) GetType() string {
+	return ConstTypeHistogram
+}
+
+// Observe adds value to histogram of given label values
+func (it *Histogram) Observe(value float64, labelValues ...string) {
+	key, labelValues := it.getKey(labelValues)
+	bucketIdx := sort.SearchFloat64s(it.buckets, value)
+
+	it.mutex.Lock()
+	defer it.mutex.Unlock()
+
+	sample, present := it.values[key]
+	if !present {
+		sample = &histogramSample{labelValues: labelValues, counts: make([]uint64, len(it.buckets))}
+		it.values[key] = sample
+	}
+
+	if bucketIdx < len(it.buckets) {
+		sample.counts[bucketIdx]++
+	}
+	sample.count++
+	sample.sum += value
+}
+
+// ObserveDuration adds time passed since given start time in seconds to histogram of given label values
+func (it *Histogram) ObserveDuration(startTime time.Time, labelValues ...string) {
+	it.Observe(time.Since(startTime).Seconds(), labelValues...)
+}
+
+// GetCount returns amount of observed values for given label values
+func (it *Histogram) GetCount(labelValues ...string) uint64 {
+	key, _ := it.getKey(labelValues)
+
+	it.mutex.RLock()
+	defer it.mutex.RUnlock()
+
+	if sample, present := it.values[key]; present {
+		return sample.count
+	}
+	return 0
+}
+
+// Collect returns histogram samples: cumulative buckets, sum and count for each of label values
+func (it *Histogram) Collect() []StructSample {
+	it.mutex.RLock()
+	defer it.mutex.RUnlock()
+
+	var result []StructSample
+	for _, key := range getSortedKeys(it.values) {
+		sample := it.values[key]
+		bucketLabels := append(append([]string{}, it.labels...), "le")
+
+		var cumulative uint64
+		for idx, bound := range it.buckets {
+			cumulative += sample.counts[idx]
+			result = append(result, StructSample{
+				Name:        it.name + "_bucket",
+				LabelNames:  bucketLabels,
+				LabelValues: append(append([]string{}, sample.labelValues...), formatValue(bound)),
+				Value:       float64(cumulative),
+			})
+		}
+		result = append(result,
+			StructSample{
+				Name:        it.name + "_bucket",
+				LabelNames:  bucketLabels,
+				LabelValues: append(append([]string{}, sample.labelValues...), formatValue(math.Inf(1))),
+				Value:       float64(sample.count),
+			},
+			StructSample{Name: it.name + "_sum", LabelNames: it.labels, LabelValues: sample.labelValues, Value: sample.sum},
+			StructSample{Name: it.name + "_count", LabelNames: it.labels, LabelValues: sample.labelValues, Value: float64(sample.count)})
+	}
+
+	return result
+}
+
+// collectFloatSamples returns samples of counter or gauge values ordered by labels
+func collectFloatSamples(name string, labels []string, values map[string]*floatSample) []StructSample {
+	var result []StructSample
+	for _, key := range getSortedKeys(values) {
+		sample := values[key]
+		result = append(result, StructSample{Name: name, LabelNames: labels, LabelValues: sample.labelValues, Value: sample.value})
+	}
+	return result
+}
+
+// getSortedKeys returns sorted keys of samples map
+func getSortedKeys(values interface{}) []string {
+	var result []string
+	switch typedValues := values.(type) {
+	case map[string]*floatSample:
+		for key := range typedValues {
+			result = append(result, key)
+		}
+	case map[string]*histogramSample:
+		for key := range typedValues {
+			result = append(result, key)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// formatValue represents sample value in Prometheus text format
+func formatValue(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
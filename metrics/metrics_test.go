@@ -0,0 +1,73 @@
+package metrics
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestCounterAndGauge(t *testing.T) {
+	counter := NewCounter("test_counter_total", "Test \"counter\".", "kind")
+	counter.Inc("a")
+	counter.Add(2, "a")
+	counter.Add(-5, "a")
+	counter.Inc(`b"`)
+
+	if value := counter.Get("a"); value != 3 {
+		t.Error("unexpected counter value:", value)
+	}
+
+	gauge := NewGauge("test_gauge", "Test gauge.")
+	gauge.Set(5)
+	gauge.Dec()
+
+	buffer := new(bytes.Buffer)
+	if err := Write(buffer); err != nil {
+		t.Fatal(err)
+	}
+	output := buffer.String()
+
+	for _, line := range []string{
+		"# HELP ottemo_test_counter_total Test \"counter\".",
+		"# TYPE ottemo_test_counter_total counter",
+		`ottemo_test_counter_total{kind="a"} 3`,
+		`ottemo_test_counter_total{kind="b\""} 1`,
+		"# TYPE ottemo_test_gauge gauge",
+		"ottemo_test_gauge 4",
+		"# TYPE ottemo_goroutines gauge",
+	} {
+		if !strings.Contains(output, line+"\n") {
+			t.Errorf("output has no line %q:\n%s", line, output)
+		}
+	}
+}
+
+func TestHistogram(t *testing.T) {
+	histogram := NewHistogram("test_duration_seconds", "Test histogram.", []float64{1, 0.1}, "route")
+	for _, value := range []float64{0.05, 0.1, 0.5, 3} {
+		histogram.Observe(value, "/test")
+	}
+
+	if count := histogram.GetCount("/test"); count != 4 {
+		t.Error("unexpected histogram count:", count)
+	}
+
+	buffer := new(bytes.Buffer)
+	if err := Write(buffer); err != nil {
+		t.Fatal(err)
+	}
+	output := buffer.String()
+
+	for _, line := range []string{
+		"# TYPE ottemo_test_duration_seconds histogram",
+		`ottemo_test_duration_seconds_bucket{route="/test",le="0.1"} 2`,
+		`ottemo_test_duration_seconds_bucket{route="/test",le="1"} 3`,
+		`ottemo_test_duration_seconds_bucket{route="/test",le="+Inf"} 4`,
+		`ottemo_test_duration_seconds_sum{route="/test"} 3.65`,
+		`ottemo_test_duration_seconds_count{route="/test"} 4`,
+	} {
+		if !strings.Contains(output, line+"\n") {
+			t.Errorf("output has no line %q:\n%s", line, output)
+		}
+	}
+}
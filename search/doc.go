@@ -0,0 +1,14 @@
+// Copyright 2019 Ottemo. All rights reserved.
+
+/*
+Package search represents full-text search abstraction layer. It provides a set of interfaces and helpers to interact
+with search index services.
+
+Search index stores documents of different types (products, cms pages, blog posts, ...) and returns ranked hits with
+facet counts for a text query. Documents are supposed to be passed to index by the package which owns them, usually
+within event bus listeners on entity save/delete.
+
+Providing Ottemo with a new search index supposing "InterfaceSearchIndex" implementation with following registration
+for search package.
+*/
+package search
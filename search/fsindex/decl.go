@@ -0,0 +1,50 @@
+package fsindex
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ottemo/commerce/env"
+)
+
+// Package global constants
+const (
+	ConstDefaultFolder = "./search/" // filesystem folder path to store index file in there
+	ConstIndexFileName = "index.json"
+
+	ConstFlushDelay = 5 * time.Second // delay of writing changed index to disk
+
+	ConstTitleWeight     = 3  // title term occurrence weight, content term weight is 1
+	ConstMinPrefixLength = 2  // minimal length of query term to be matched as a prefix
+	ConstMaxPrefixTerms  = 50 // maximal amount of index terms query term prefix expands to
+
+	ConstBM25K1 = 1.2
+	ConstBM25B  = 0.75
+
+	ConstErrorModule = "search/fsindex"
+	ConstErrorLevel  = env.ConstErrorLevelService
+)
+
+// FilesystemSearchIndex is a filesystem persisted implementer of InterfaceSearchIndex
+type FilesystemSearchIndex struct {
+	filePath string
+
+	documents   map[string]*indexedDocument   // documents by "type:id" key
+	postings    map[string]map[string]float64 // term weights within documents by term and "type:id" key
+	totalLength float64
+
+	mutex      sync.RWMutex
+	flushMutex sync.Mutex
+	flushTimer *time.Timer
+	isChanged  bool
+}
+
+// indexedDocument is a search document representation stored within index
+type indexedDocument struct {
+	Type   string                 `json:"type"`
+	ID     string                 `json:"id"`
+	Length float64                `json:"length"`
+	Terms  map[string]float64     `json:"terms"`
+	Facets map[string][]string    `json:"facets"`
+	Data   map[string]interface{} `json:"data"`
+}
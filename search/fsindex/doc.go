@@ -0,0 +1,14 @@
+// Copyright 2019 Ottemo. All rights reserved.
+
+/*
+Package fsindex is a default implementation of InterfaceSearchIndex declared in "github.com/ottemo/commerce/search"
+package.
+
+It is an in-memory inverted index with BM25 ranking which is persisted to a single JSON file within a folder specified
+by "search.fsindex.folder" ini value ("./search/" by default). Changes are written to disk in background shortly after
+index update, so the index survives application restarts without re-indexing.
+
+Query terms are joined with AND, the last query term is also matched as a prefix if there is no exact term for it in
+index, so the index could be used for "search as you type".
+*/
+package fsindex
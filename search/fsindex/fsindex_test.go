@@ -0,0 +1,129 @@
+package fsindex
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ottemo/commerce/search"
+)
+
+func getTestIndex(t *testing.T) *FilesystemSearchIndex {
+	index := newIndex()
+
+	documents := []search.StructSearchDocument{
+		{Type: search.ConstDocumentTypeProduct, ID: "1", Title: "Red Shirt", Content: "cotton shirt for summer",
+			Facets: map[string][]string{"color": {"red"}}, Data: map[string]interface{}{"name": "Red Shirt"}},
+		{Type: search.ConstDocumentTypeProduct, ID: "2", Title: "Blue Jeans", Content: "denim jeans, goes well with a shirt",
+			Facets: map[string][]string{"color": {"blue"}}},
+		{Type: search.ConstDocumentTypeProduct, ID: "3", Title: "Blue Shirt", Content: "linen shirt",
+			Facets: map[string][]string{"color": {"blue"}}},
+		{Type: search.ConstDocumentTypeBlogPost, ID: "1", Title: "Summer collection", Content: "<p>Our new shirts are here</p>",
+			Facets: map[string][]string{"tags": {"news", "summer"}}},
+	}
+
+	for _, document := range documents {
+		if err := index.Index(document); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return index
+}
+
+func getHitIDs(result *search.StructSearchResult) []string {
+	var ids []string
+	for _, hit := range result.Hits {
+		ids = append(ids, hit.Type+":"+hit.ID)
+	}
+	return ids
+}
+
+func checkSearch(t *testing.T, index *FilesystemSearchIndex, query search.StructSearchQuery, expected ...string) *search.StructSearchResult {
+	result, err := index.Search(query)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ids := getHitIDs(result)
+	if len(ids) != len(expected) {
+		t.Fatalf("query %+v: unexpected hits %v, expected %v", query, ids, expected)
+	}
+	for idx := range ids {
+		if ids[idx] != expected[idx] {
+			t.Fatalf("query %+v: unexpected hits %v, expected %v", query, ids, expected)
+		}
+	}
+
+	return result
+}
+
+func TestSearch(t *testing.T) {
+	index := getTestIndex(t)
+
+	// title matches and shorter documents are ranked higher, all the terms are required
+	checkSearch(t, index, search.StructSearchQuery{Text: "shirt"}, "product:3", "product:1", "product:2")
+	checkSearch(t, index, search.StructSearchQuery{Text: "Blue SHIRT"}, "product:3", "product:2")
+	checkSearch(t, index, search.StructSearchQuery{Text: "shirt wool"})
+
+	// last term is matched as a prefix, html is not a part of text
+	checkSearch(t, index, search.StructSearchQuery{Text: "summer coll"}, "blog_post:1")
+	checkSearch(t, index, search.StructSearchQuery{Text: "shirts"}, "blog_post:1")
+
+	// type and facet filters, facet counts
+	result := checkSearch(t, index, search.StructSearchQuery{Text: "shirt", Types: []string{search.ConstDocumentTypeProduct},
+		Facets: map[string][]string{"color": {"blue"}}}, "product:3", "product:2")
+	if result.Total != 2 || result.Facets["color"]["blue"] != 2 || result.Facets["color"]["red"] != 0 {
+		t.Errorf("unexpected result %+v", result)
+	}
+
+	result = checkSearch(t, index, search.StructSearchQuery{Limit: 2, Offset: 1}, "product:1", "product:2")
+	if result.Total != 4 || result.Facets[search.ConstFacetType][search.ConstDocumentTypeProduct] != 3 {
+		t.Errorf("unexpected result %+v", result)
+	}
+
+	// re-indexing replaces document, removing drops it
+	if err := index.Index(search.StructSearchDocument{Type: search.ConstDocumentTypeProduct, ID: "1", Title: "Red Hat"}); err != nil {
+		t.Fatal(err)
+	}
+	checkSearch(t, index, search.StructSearchQuery{Text: "shirt"}, "product:3", "product:2")
+
+	if err := index.Remove(search.ConstDocumentTypeProduct, "3"); err != nil {
+		t.Fatal(err)
+	}
+	checkSearch(t, index, search.StructSearchQuery{Text: "blue"}, "product:2")
+
+	if err := index.Clear(search.ConstDocumentTypeProduct); err != nil {
+		t.Fatal(err)
+	}
+	if index.Count("") != 1 || len(index.postings["blue"]) != 0 {
+		t.Errorf("index was not cleared properly")
+	}
+}
+
+func TestPersistence(t *testing.T) {
+	folder, err := ioutil.TempDir("", "fsindex")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.RemoveAll(folder) }()
+
+	filePath := filepath.Join(folder, ConstIndexFileName)
+
+	index := getTestIndex(t)
+	index.filePath = filePath
+	if err := index.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	loadedIndex := newIndex()
+	if err := loadedIndex.load(filePath); err != nil {
+		t.Fatal(err)
+	}
+
+	result := checkSearch(t, loadedIndex, search.StructSearchQuery{Text: "red"}, "product:1")
+	if result.Hits[0].Data["name"] != "Red Shirt" {
+		t.Errorf("unexpected hit data %+v", result.Hits[0].Data)
+	}
+}
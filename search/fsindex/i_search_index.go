@@ -0,0 +1,195 @@
+package fsindex
+
+import (
+	"math"
+	"sort"
+
+	"github.com/ottemo/commerce/env"
+	"github.com/ottemo/commerce/search"
+	"github.com/ottemo/commerce/utils"
+)
+
+// GetName returns search index implementation name
+func (it *FilesystemSearchIndex) GetName() string {
+	return "FilesystemSearchIndex"
+}
+
+// Index adds document to index, or replaces previously indexed one with same type and id
+func (it *FilesystemSearchIndex) Index(document search.StructSearchDocument) error {
+	if document.Type == "" || document.ID == "" {
+		return env.ErrorNew(ConstErrorModule, ConstErrorLevel, "54b351ee-ab85-441b-b7b0-f2e682141e00", "document type and id should be specified")
+	}
+
+	indexed := &indexedDocument{
+		Type:   document.Type,
+		ID:     document.ID,
+		Terms:  make(map[string]float64),
+		Facets: make(map[string][]string),
+		Data:   document.Data,
+	}
+
+	for _, term := range search.Tokenize(document.Title) {
+		indexed.Terms[term] += ConstTitleWeight
+		indexed.Length += ConstTitleWeight
+	}
+	for _, term := range search.Tokenize(document.Content) {
+		indexed.Terms[term]++
+		indexed.Length++
+	}
+
+	for facet, values := range document.Facets {
+		for _, value := range values {
+			if value != "" && !utils.IsInListStr(value, indexed.Facets[facet]) {
+				indexed.Facets[facet] = append(indexed.Facets[facet], value)
+			}
+		}
+	}
+	indexed.Facets[search.ConstFacetType] = []string{document.Type}
+
+	it.mutex.Lock()
+	it.removeDocument(documentKey(document.Type, document.ID))
+	it.addDocument(indexed)
+	it.mutex.Unlock()
+
+	it.scheduleFlush()
+
+	return nil
+}
+
+// Remove removes document from index, not indexed document is not an error
+func (it *FilesystemSearchIndex) Remove(documentType string, documentID string) error {
+	it.mutex.Lock()
+	isRemoved := it.removeDocument(documentKey(documentType, documentID))
+	it.mutex.Unlock()
+
+	if isRemoved {
+		it.scheduleFlush()
+	}
+
+	return nil
+}
+
+// Clear removes all documents of given type from index, blank type clears whole index
+func (it *FilesystemSearchIndex) Clear(documentType string) error {
+	it.mutex.Lock()
+	for key, document := range it.documents {
+		if documentType == "" || document.Type == documentType {
+			it.removeDocument(key)
+		}
+	}
+	it.mutex.Unlock()
+
+	it.scheduleFlush()
+
+	return nil
+}
+
+// Count returns amount of indexed documents of given type, blank type counts all documents
+func (it *FilesystemSearchIndex) Count(documentType string) int {
+	it.mutex.RLock()
+	defer it.mutex.RUnlock()
+
+	if documentType == "" {
+		return len(it.documents)
+	}
+
+	result := 0
+	for _, document := range it.documents {
+		if document.Type == documentType {
+			result++
+		}
+	}
+	return result
+}
+
+// Search returns documents matching query ordered by relevance
+func (it *FilesystemSearchIndex) Search(query search.StructSearchQuery) (*search.StructSearchResult, error) {
+	if query.Offset < 0 || query.Limit < 0 {
+		return nil, env.ErrorNew(ConstErrorModule, ConstErrorLevel, "eb4dca85-b6ae-4afe-9950-096a611e7fbe", "offset and limit should not be negative")
+	}
+
+	facets := make(map[string][]string)
+	for facet, values := range query.Facets {
+		if len(values) > 0 {
+			facets[facet] = values
+		}
+	}
+	if len(query.Types) > 0 {
+		facets[search.ConstFacetType] = query.Types
+	}
+
+	it.mutex.RLock()
+	defer it.mutex.RUnlock()
+
+	result := &search.StructSearchResult{
+		Hits:   make([]search.StructSearchHit, 0),
+		Facets: make(map[string]map[string]int),
+	}
+
+	var keys []string
+	scores := it.matchDocuments(search.Tokenize(query.Text))
+	for key := range scores {
+		document := it.documents[key]
+		if !matchFacets(document, facets) {
+			continue
+		}
+
+		keys = append(keys, key)
+		for facet, values := range document.Facets {
+			if _, present := result.Facets[facet]; !present {
+				result.Facets[facet] = make(map[string]int)
+			}
+			for _, value := range values {
+				result.Facets[facet][value]++
+			}
+		}
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if scores[keys[i]] != scores[keys[j]] {
+			return scores[keys[i]] > scores[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+
+	result.Total = len(keys)
+	if query.Offset >= len(keys) {
+		return result, nil
+	}
+	keys = keys[query.Offset:]
+	if query.Limit > 0 && query.Limit < len(keys) {
+		keys = keys[:query.Limit]
+	}
+
+	for _, key := range keys {
+		document := it.documents[key]
+		result.Hits = append(result.Hits, search.StructSearchHit{
+			Type:  document.Type,
+			ID:    document.ID,
+			Score: math.Round(scores[key]*1000) / 1000,
+			Data:  document.Data,
+		})
+	}
+
+	return result, nil
+}
+
+// Flush writes index changes to disk
+func (it *FilesystemSearchIndex) Flush() error {
+	it.flushMutex.Lock()
+	defer it.flushMutex.Unlock()
+
+	it.mutex.Lock()
+	if it.flushTimer != nil {
+		it.flushTimer.Stop()
+		it.flushTimer = nil
+	}
+	if !it.isChanged || it.filePath == "" {
+		it.mutex.Unlock()
+		return nil
+	}
+	it.isChanged = false
+	it.mutex.Unlock()
+
+	return it.save()
+}
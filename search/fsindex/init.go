@@ -0,0 +1,48 @@
+package fsindex
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/ottemo/commerce/env"
+	"github.com/ottemo/commerce/search"
+)
+
+// init makes package self-initialization routine
+func init() {
+	instance := newIndex()
+
+	if err := search.RegisterSearchIndex(instance); err == nil {
+		env.RegisterOnConfigIniStart(instance.setupOnIniConfigStart)
+	}
+}
+
+// newIndex returns new blank index instance
+func newIndex() *FilesystemSearchIndex {
+	return &FilesystemSearchIndex{
+		documents: make(map[string]*indexedDocument),
+		postings:  make(map[string]map[string]float64),
+	}
+}
+
+// setupOnIniConfigStart is a initialization based on ini config service
+func (it *FilesystemSearchIndex) setupOnIniConfigStart() error {
+
+	var indexFolder = ConstDefaultFolder
+
+	if iniConfig := env.GetIniConfig(); iniConfig != nil {
+		if iniValue := iniConfig.GetValue("search.fsindex.folder", "?"+ConstDefaultFolder); iniValue != "" {
+			indexFolder = iniValue
+		}
+	}
+
+	if err := os.MkdirAll(indexFolder, os.ModePerm); err != nil {
+		return env.ErrorDispatch(err)
+	}
+
+	if err := it.load(filepath.Join(indexFolder, ConstIndexFileName)); err != nil {
+		return env.ErrorDispatch(err)
+	}
+
+	return search.OnSearchIndexStart()
+}
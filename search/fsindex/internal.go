@@ -0,0 +1,221 @@
+package fsindex
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ottemo/commerce/env"
+	"github.com/ottemo/commerce/utils"
+)
+
+// documentKey returns key document stored in index under
+func documentKey(documentType string, documentID string) string {
+	return documentType + ":" + documentID
+}
+
+// addDocument adds document to index, it should not be already present
+//   - caller should hold write lock
+func (it *FilesystemSearchIndex) addDocument(document *indexedDocument) {
+	key := documentKey(document.Type, document.ID)
+
+	it.documents[key] = document
+	it.totalLength += document.Length
+
+	for term, weight := range document.Terms {
+		if _, present := it.postings[term]; !present {
+			it.postings[term] = make(map[string]float64)
+		}
+		it.postings[term][key] = weight
+	}
+}
+
+// removeDocument removes document from index, returns false if document was not indexed
+//   - caller should hold write lock
+func (it *FilesystemSearchIndex) removeDocument(key string) bool {
+	document, present := it.documents[key]
+	if !present {
+		return false
+	}
+
+	for term := range document.Terms {
+		delete(it.postings[term], key)
+		if len(it.postings[term]) == 0 {
+			delete(it.postings, term)
+		}
+	}
+
+	it.totalLength -= document.Length
+	delete(it.documents, key)
+
+	return true
+}
+
+// expandTerm returns index terms query term matches to
+//   - exact term is preferred, prefix matching used only for last query term
+func (it *FilesystemSearchIndex) expandTerm(term string, isLast bool) []string {
+	if _, present := it.postings[term]; present {
+		return []string{term}
+	}
+
+	var result []string
+	if isLast && len(term) >= ConstMinPrefixLength {
+		for indexTerm := range it.postings {
+			if strings.HasPrefix(indexTerm, term) {
+				result = append(result, indexTerm)
+			}
+		}
+		sort.Strings(result)
+		if len(result) > ConstMaxPrefixTerms {
+			result = result[:ConstMaxPrefixTerms]
+		}
+	}
+	return result
+}
+
+// matchDocuments returns BM25 scores of documents containing all the terms, blank terms matches all documents
+//   - caller should hold read lock
+func (it *FilesystemSearchIndex) matchDocuments(terms []string) map[string]float64 {
+	result := make(map[string]float64)
+
+	if len(terms) == 0 {
+		for key := range it.documents {
+			result[key] = 0
+		}
+		return result
+	}
+
+	documentsCount := float64(len(it.documents))
+	averageLength := 1.0
+	if documentsCount > 0 && it.totalLength > 0 {
+		averageLength = it.totalLength / documentsCount
+	}
+
+	for idx, term := range terms {
+		termScores := make(map[string]float64)
+
+		for _, indexTerm := range it.expandTerm(term, idx == len(terms)-1) {
+			postings := it.postings[indexTerm]
+
+			frequency := float64(len(postings))
+			idf := math.Log(1 + (documentsCount-frequency+0.5)/(frequency+0.5))
+
+			for key, weight := range postings {
+				length := it.documents[key].Length
+				score := idf * weight * (ConstBM25K1 + 1) / (weight + ConstBM25K1*(1-ConstBM25B+ConstBM25B*length/averageLength))
+				if score > termScores[key] {
+					termScores[key] = score
+				}
+			}
+		}
+
+		if idx == 0 {
+			result = termScores
+			continue
+		}
+
+		for key := range result {
+			if score, present := termScores[key]; present {
+				result[key] += score
+			} else {
+				delete(result, key)
+			}
+		}
+	}
+
+	return result
+}
+
+// matchFacets checks document to have any of given values for each facet
+func matchFacets(document *indexedDocument, facets map[string][]string) bool {
+	for facet, values := range facets {
+		isMatched := false
+		for _, value := range document.Facets[facet] {
+			if utils.IsInListStr(value, values) {
+				isMatched = true
+				break
+			}
+		}
+		if !isMatched {
+			return false
+		}
+	}
+	return true
+}
+
+// scheduleFlush marks index as changed and schedules writing it to disk
+func (it *FilesystemSearchIndex) scheduleFlush() {
+	it.mutex.Lock()
+	defer it.mutex.Unlock()
+
+	it.isChanged = true
+	if it.flushTimer == nil && it.filePath != "" {
+		it.flushTimer = time.AfterFunc(ConstFlushDelay, func() {
+			if err := it.Flush(); err != nil {
+				_ = env.ErrorDispatch(err)
+			}
+		})
+	}
+}
+
+// save writes index documents to file, temporary file is used to not break index on failure
+func (it *FilesystemSearchIndex) save() error {
+	it.mutex.RLock()
+	documents := make([]*indexedDocument, 0, len(it.documents))
+	for _, document := range it.documents {
+		documents = append(documents, document)
+	}
+	content, err := json.Marshal(documents)
+	it.mutex.RUnlock()
+
+	if err != nil {
+		return env.ErrorDispatch(err)
+	}
+
+	tempPath := it.filePath + ".tmp"
+	if err := ioutil.WriteFile(tempPath, content, 0644); err != nil {
+		return env.ErrorDispatch(err)
+	}
+
+	if err := os.Rename(tempPath, it.filePath); err != nil {
+		return env.ErrorDispatch(err)
+	}
+
+	return nil
+}
+
+// load reads index documents from file and rebuilds postings, not existing file is a blank index
+func (it *FilesystemSearchIndex) load(filePath string) error {
+	var documents []*indexedDocument
+
+	content, err := ioutil.ReadFile(filePath)
+	if err != nil && !os.IsNotExist(err) {
+		return env.ErrorDispatch(err)
+	}
+
+	if len(content) > 0 {
+		if err := json.Unmarshal(content, &documents); err != nil {
+			return env.ErrorNew(ConstErrorModule, ConstErrorLevel, "10b78fe0-eda7-4fdd-b978-861264510d6d", "unable to read search index file "+filePath+": "+err.Error())
+		}
+	}
+
+	it.mutex.Lock()
+	defer it.mutex.Unlock()
+
+	it.filePath = filePath
+	it.documents = make(map[string]*indexedDocument)
+	it.postings = make(map[string]map[string]float64)
+	it.totalLength = 0
+
+	for _, document := range documents {
+		if document != nil && document.Type != "" && document.ID != "" {
+			it.addDocument(document)
+		}
+	}
+
+	return nil
+}
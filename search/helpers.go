@@ -0,0 +1,24 @@
+package search
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Package global variables
+var (
+	htmlTagsRegexp = regexp.MustCompile(`<[^>]*>`)
+)
+
+// Tokenize splits text to lower case terms, letters and digits sequences are considered as terms
+func Tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(char rune) bool {
+		return !unicode.IsLetter(char) && !unicode.IsNumber(char)
+	})
+}
+
+// StripHTML removes html tags from given text, so it could be indexed
+func StripHTML(text string) string {
+	return htmlTagsRegexp.ReplaceAllString(text, " ")
+}
@@ -0,0 +1,71 @@
+package search
+
+import (
+	"github.com/ottemo/commerce/env"
+)
+
+// Package global constants
+const (
+	ConstErrorModule = "search"
+	ConstErrorLevel  = env.ConstErrorLevelService
+
+	ConstDocumentTypeProduct  = "product"
+	ConstDocumentTypeCMSPage  = "cms_page"
+	ConstDocumentTypeBlogPost = "blog_post"
+
+	ConstFacetType = "type" // facet every document have, contains document type
+)
+
+// StructSearchDocument represents a document to be stored in search index
+type StructSearchDocument struct {
+	Type string
+	ID   string
+
+	Title   string // text matched with higher weight
+	Content string
+
+	Facets map[string][]string    // values document could be filtered and counted by
+	Data   map[string]interface{} // values returned with search hit as is
+}
+
+// StructSearchQuery represents a search request to search index
+//   - blank text matches all documents
+//   - document should have any of specified values for each facet in Facets
+type StructSearchQuery struct {
+	Text   string
+	Types  []string
+	Facets map[string][]string
+
+	Offset int
+	Limit  int
+}
+
+// StructSearchHit represents a document matched to search query
+type StructSearchHit struct {
+	Type  string                 `json:"type"`
+	ID    string                 `json:"_id"`
+	Score float64                `json:"score"`
+	Data  map[string]interface{} `json:"data"`
+}
+
+// StructSearchResult represents a search query result
+//   - Facets holds values counts over all matched documents, not only returned page
+type StructSearchResult struct {
+	Total  int                       `json:"total"`
+	Hits   []StructSearchHit         `json:"hits"`
+	Facets map[string]map[string]int `json:"facets"`
+}
+
+// InterfaceSearchIndex is an interface to access search index service
+type InterfaceSearchIndex interface {
+	GetName() string
+
+	Index(document StructSearchDocument) error
+	Remove(documentType string, documentID string) error
+	Clear(documentType string) error
+
+	Count(documentType string) int
+	Search(query StructSearchQuery) (*StructSearchResult, error)
+
+	Flush() error
+}
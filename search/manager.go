@@ -0,0 +1,45 @@
+package search
+
+import (
+	"github.com/ottemo/commerce/env"
+)
+
+// Package global variables
+var (
+	currentSearchIndex          InterfaceSearchIndex // currently registered search index service in system
+	callbacksOnSearchIndexStart = []func() error{}   // set of callback function on search index service start
+)
+
+// RegisterOnSearchIndexStart registers new callback on search index service start
+func RegisterOnSearchIndexStart(callback func() error) {
+	callbacksOnSearchIndexStart = append(callbacksOnSearchIndexStart, callback)
+}
+
+// OnSearchIndexStart fires search index service start event (callback handling)
+func OnSearchIndexStart() error {
+	for _, callback := range callbacksOnSearchIndexStart {
+		if err := callback(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RegisterSearchIndex registers search index service in the system
+//   - will cause error if there are couple candidates for that role
+func RegisterSearchIndex(newIndex InterfaceSearchIndex) error {
+	if currentSearchIndex == nil {
+		currentSearchIndex = newIndex
+	} else {
+		return env.ErrorNew(ConstErrorModule, ConstErrorLevel, "aeae173a-badd-40bd-9f64-a7b6abbfdaa0", "Sorry, '"+currentSearchIndex.GetName()+"' search index already registered")
+	}
+	return nil
+}
+
+// GetSearchIndex returns currently used search index service implementation
+func GetSearchIndex() (InterfaceSearchIndex, error) {
+	if currentSearchIndex != nil {
+		return currentSearchIndex, nil
+	}
+	return nil, env.ErrorNew(ConstErrorModule, ConstErrorLevel, "4d44a34b-3205-4152-bc39-4ff0e433a6e1", "no registered search index")
+}
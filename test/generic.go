@@ -123,6 +123,21 @@ func CheckTestIniDefaults() error {
 		changesMade = true
 	}
 
+	// checking test ini section for postgres
+	if iniConfig.GetSectionValue(ini.ConstTestSectionName, "db.postgres.uri", "") == "" {
+		uriValue := iniConfig.GetValue("db.postgres.uri", "postgres://localhost:5432/ottemo?sslmode=disable")
+		if queryIndex := strings.Index(uriValue, "?"); queryIndex != -1 {
+			uriValue = uriValue[0:queryIndex] + "_test" + uriValue[queryIndex:]
+		} else {
+			uriValue += "_test"
+		}
+		if err := iniConfig.SetValue("db.postgres.uri", uriValue); err != nil {
+			return err
+		}
+
+		changesMade = true
+	}
+
 	// if ini default values were updated
 	if changesMade {
 		err = app.End()